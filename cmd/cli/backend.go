@@ -0,0 +1,17 @@
+package main
+
+import (
+	"context"
+
+	"go-boilerplate/pkg/client"
+)
+
+// backend is what the commands run against
+type backend interface {
+	CreateTodo(ctx context.Context, req *client.CreateTodoRequest) (*client.Todo, error)
+	ListTodos(ctx context.Context, filter client.TodoFilter) ([]*client.Todo, error)
+	TransitionTodo(ctx context.Context, id int, req *client.TransitionTodoRequest) (*client.Todo, error)
+	DeleteTodo(ctx context.Context, id int) error
+	CreateUser(ctx context.Context, req *client.CreateUserRequest) (*client.User, error)
+	ListUsers(ctx context.Context) ([]*client.User, error)
+}
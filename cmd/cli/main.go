@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"go-boilerplate/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+// app holds the global flags and the backend the commands run against
+type app struct {
+	server  string
+	token   string
+	userID  int
+	admin   bool
+	output  string
+	timeout time.Duration
+
+	backend backend
+}
+
+// newRootCommand creates the todoctl command with all subcommands
+func newRootCommand() *cobra.Command {
+	a := &app{}
+	root := &cobra.Command{
+		Use:   "todoctl",
+		Short: "Manage todos and users from the command line",
+		Long: `todoctl manages todos and users on a go-boilerplate server over its HTTP API.
+
+With --admin it authenticates with the server's admin key, ADMIN_API_KEY on
+the server and TODOCTL_ADMIN_KEY here, and acts as one of the server's
+administrators (--user, or the first one), so operators need no token of their
+own. Admin commands still go through the server, which owns the stores and the
+audit log.
+
+Against a server, authenticate with a token from "todoctl token". --user only
+names the acting user in the X-User-ID header, which servers honor in
+development only.`,
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutput(a.output)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&a.server, "server", envOr("TODOCTL_SERVER", "http://localhost:8080"), "server base URL (env TODOCTL_SERVER)")
+	flags.StringVar(&a.token, "token", os.Getenv("TODOCTL_TOKEN"), "bearer token to authenticate with (env TODOCTL_TOKEN)")
+	flags.IntVarP(&a.userID, "user", "u", 0, "ID of the user to act as, without authentication")
+	flags.BoolVar(&a.admin, "admin", false, "act as an administrator with the admin key in TODOCTL_ADMIN_KEY")
+	flags.StringVarP(&a.output, "output", "o", outputTable, "output format: table, json or yaml")
+	flags.DurationVar(&a.timeout, "timeout", 10*time.Second, "time limit for the command")
+	_ = root.RegisterFlagCompletionFunc("output", fixedCompletions(outputFormats...))
+
+	root.AddCommand(newTodoCommand(a), newUserCommand(a), newTokenCommand())
+	return root
+}
+
+// context returns the context a command runs in, bounded by --timeout and
+// carrying the acting user
+func (a *app) context(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(cmd.Context(), a.timeout)
+	if a.userID > 0 {
+		ctx = client.ContextWithActor(ctx, a.userID)
+	}
+	return ctx, cancel
+}
+
+// client returns the backend selected by the flags, creating it on first use
+func (a *app) client() (backend, error) {
+	if a.backend != nil {
+		return a.backend, nil
+	}
+	auth := client.WithToken(a.token)
+	if a.admin {
+		key := os.Getenv("TODOCTL_ADMIN_KEY")
+		if key == "" {
+			return nil, errors.New("--admin needs TODOCTL_ADMIN_KEY, the server's ADMIN_API_KEY")
+		}
+		auth = client.WithAdminKey(key)
+	}
+	a.backend = client.New(a.server, auth)
+	return a.backend, nil
+}
+
+// envOr returns the environment variable, or def when it is unset
+func envOr(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return def
+}
+
+// fixedCompletions completes a flag with a fixed set of values
+func fixedCompletions(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
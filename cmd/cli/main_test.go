@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	httpadapter "go-boilerplate/internal/adapter/inbound/http"
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenSecret is the secret the test server verifies tokens with
+const tokenSecret = "secret"
+
+// adminKey is the test server's admin key, acting as user 3 or 4
+const adminKey = "admin-key"
+
+// newTestServer serves the REST API's todo and user routes, accepting tokens
+// signed with tokenSecret and the admin key
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	todoRepo := persistence.NewTodoRepository()
+	tagRepo := persistence.NewTagRepository()
+	userRepo := persistence.NewUserRepository()
+	tx := persistence.NewTxManager(todoRepo, tagRepo, userRepo)
+	handlers := httpadapter.Handlers{
+		Todo: httpadapter.NewTodoHandler(service.NewTodoService(todoRepo, tagRepo, tx)),
+		User: httpadapter.NewUserHandler(service.NewUserService(userRepo, tx)),
+	}
+
+	authenticator := auth.NewAuthenticator(
+		auth.WithTokens(auth.NewTokenSigner([]byte(tokenSecret), time.Hour)),
+		auth.WithAdminKey(adminKey, []int{3, 4}),
+	)
+	r := gin.New()
+	r.Use(httpadapter.ActorMiddleware(authenticator))
+	httpadapter.RegisterRoutes(r.Group("/v1"), handlers, authenticator)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// run executes todoctl with the arguments and returns what it wrote to
+// stdout and stderr
+func run(t *testing.T, server string, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	cmd := newRootCommand()
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs(append([]string{"--server", server}, args...))
+	err = cmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestCommands(t *testing.T) {
+	signer := auth.NewTokenSigner([]byte(tokenSecret), time.Hour)
+	alice := "--token=" + signer.Issue(1)
+	tests := []struct {
+		name string
+		// setup are commands run before the one under test
+		setup [][]string
+		args  []string
+		env   map[string]string
+
+		wantOut string
+		// wantJSON lists the fields of a JSON output that are checked
+		wantJSON   string
+		wantStderr string
+		wantErr    string
+	}{
+		{
+			name: "create a user",
+			args: []string{alice, "user", "create", "alice", "--email", "alice@example.com", "--name", "Alice"},
+			wantOut: "ID  USERNAME  NAME   EMAIL\n" +
+				"1   alice     Alice  alice@example.com\n",
+		},
+		{
+			name: "add a todo",
+			args: []string{alice, "todo", "add", "dishes", "--owner", "1", "-p", "high", "--due", "2099-01-31T18:00:00Z"},
+			wantOut: "ID  TITLE   STATUS  PRIORITY  DUE               OWNER\n" +
+				"1   dishes  open    high      2099-01-31 18:00  1\n",
+		},
+		{
+			name:     "list todos as JSON",
+			setup:    [][]string{{alice, "todo", "add", "dishes"}, {alice, "todo", "add", "laundry"}},
+			args:     []string{alice, "todo", "list", "-o", "json"},
+			wantJSON: `[{"id":1,"title":"dishes"},{"id":2,"title":"laundry"}]`,
+		},
+		{
+			name:    "list users as YAML",
+			setup:   [][]string{{alice, "user", "create", "alice", "--email", "alice@example.com", "--name", "Alice"}},
+			args:    []string{alice, "user", "list", "-o", "yaml"},
+			wantOut: "- id: 1\n  username: alice\n  email: alice@example.com\n  name: Alice\n",
+		},
+		{
+			name:  "complete todos",
+			setup: [][]string{{alice, "todo", "add", "dishes", "--owner", "1"}, {alice, "todo", "add", "laundry", "--owner", "1"}},
+			args:  []string{alice, "todo", "done", "1", "2"},
+			wantOut: "ID  TITLE    STATUS  PRIORITY  DUE  OWNER\n" +
+				"1   dishes   done    medium    -    1\n" +
+				"2   laundry  done    medium    -    1\n",
+		},
+		{
+			name:       "move a todo to the trash",
+			setup:      [][]string{{alice, "todo", "add", "dishes"}},
+			args:       []string{alice, "todo", "rm", "1"},
+			wantStderr: "Moved todo 1 to the trash\n",
+		},
+		{
+			name:    "missing todo",
+			args:    []string{alice, "todo", "done", "7"},
+			wantErr: "todo 7: ",
+		},
+		{
+			name:    "invalid ID",
+			args:    []string{alice, "todo", "rm", "seven"},
+			wantErr: `invalid id "seven"`,
+		},
+		{
+			name:    "unknown output format",
+			args:    []string{alice, "todo", "list", "-o", "xml"},
+			wantErr: `unknown output format "xml"`,
+		},
+		{
+			name:    "forged token",
+			args:    []string{"--token=1.9999999999.forged", "todo", "list"},
+			wantErr: "401",
+		},
+		{
+			name:     "admin acts as the first administrator",
+			env:      map[string]string{"TODOCTL_ADMIN_KEY": adminKey},
+			args:     []string{"--admin", "todo", "add", "dishes", "-o", "json"},
+			wantJSON: `[{"id":1,"title":"dishes","owner_id":3}]`,
+		},
+		{
+			name:     "admin acts as the chosen administrator",
+			env:      map[string]string{"TODOCTL_ADMIN_KEY": adminKey},
+			args:     []string{"--admin", "-u", "4", "todo", "add", "dishes", "-o", "json"},
+			wantJSON: `[{"id":1,"title":"dishes","owner_id":4}]`,
+		},
+		{
+			name:    "admin refuses other users",
+			env:     map[string]string{"TODOCTL_ADMIN_KEY": adminKey},
+			args:    []string{"--admin", "-u", "1", "todo", "list"},
+			wantErr: "only acts as an administrator",
+		},
+		{
+			name:    "admin needs the server's key",
+			env:     map[string]string{"TODOCTL_ADMIN_KEY": "guess"},
+			args:    []string{"--admin", "todo", "list"},
+			wantErr: "invalid admin key",
+		},
+		{
+			name:    "admin needs a key",
+			env:     map[string]string{"TODOCTL_ADMIN_KEY": ""},
+			args:    []string{"--admin", "todo", "list"},
+			wantErr: "--admin needs TODOCTL_ADMIN_KEY",
+		},
+		{
+			name:    "the token secret is no admin key",
+			env:     map[string]string{"TODOCTL_ADMIN_KEY": tokenSecret},
+			args:    []string{"--admin", "todo", "list"},
+			wantErr: "invalid admin key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			server := newTestServer(t)
+			for _, args := range tt.setup {
+				if _, _, err := run(t, server.URL, args...); err != nil {
+					t.Fatalf("setup %v: %v", args, err)
+				}
+			}
+
+			out, stderr, err := run(t, server.URL, tt.args...)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantJSON != "" {
+				if got, want := selectFields(t, out, tt.wantJSON); got != want {
+					t.Errorf("stdout = %s, want %s", got, want)
+				}
+			} else if out != tt.wantOut {
+				t.Errorf("stdout =\n%s\nwant\n%s", out, tt.wantOut)
+			}
+			if stderr != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr, tt.wantStderr)
+			}
+		})
+	}
+}
+
+// selectFields decodes JSON output keeping only the fields the expected output
+// names, so timestamps need not be predicted, and renders both for comparison
+func selectFields(t *testing.T, out, want string) (got, expected string) {
+	t.Helper()
+	var items, wantItems []map[string]any
+	if err := json.Unmarshal([]byte(out), &items); err != nil {
+		t.Fatalf("output is not a JSON array: %v\n%s", err, out)
+	}
+	if err := json.Unmarshal([]byte(want), &wantItems); err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range items[:min(len(items), len(wantItems))] {
+		for key := range item {
+			if _, ok := wantItems[i][key]; !ok {
+				delete(item, key)
+			}
+		}
+	}
+	return fmt.Sprint(items), fmt.Sprint(wantItems)
+}
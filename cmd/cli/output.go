@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"go-boilerplate/pkg/client"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputYAML}
+
+// validateOutput checks that the output format is known
+func validateOutput(format string) error {
+	for _, f := range outputFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// render writes v in the output format; table calls writeTable with a
+// tabwriter that is flushed afterwards
+func render(w io.Writer, format string, v any, writeTable func(tw *tabwriter.Writer)) error {
+	switch format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputYAML:
+		return writeYAML(w, v)
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		writeTable(tw)
+		return tw.Flush()
+	}
+}
+
+// writeYAML writes v as YAML with the same field names and order as its JSON
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	// JSON is valid YAML, so decoding it into a node keeps the field order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle switches a node decoded from JSON to block style
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle | yaml.DoubleQuotedStyle
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// printTodos writes todos in the output format
+func printTodos(w io.Writer, format string, todos []*client.Todo) error {
+	return render(w, format, todos, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tTITLE\tSTATUS\tPRIORITY\tDUE\tOWNER")
+		for _, todo := range todos {
+			due := "-"
+			if todo.DueDate != nil {
+				due = todo.DueDate.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\n", todo.ID, todo.Title, todo.Status, todo.Priority, due, todo.OwnerID)
+		}
+	})
+}
+
+// printUsers writes users in the output format
+func printUsers(w io.Writer, format string, users []*client.User) error {
+	return render(w, format, users, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tUSERNAME\tNAME\tEMAIL")
+		for _, user := range users {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", user.ID, user.Username, user.Name, user.Email)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go-boilerplate/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+// newTodoCommand creates the todo command group
+func newTodoCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "todo",
+		Short: "Manage todos",
+	}
+	cmd.AddCommand(newTodoAddCommand(a), newTodoListCommand(a), newTodoDoneCommand(a), newTodoRmCommand(a))
+	return cmd
+}
+
+// newTodoAddCommand creates the todo add command
+func newTodoAddCommand(a *app) *cobra.Command {
+	var (
+		ownerID     int
+		parentID    int
+		description string
+		priority    string
+		due         string
+		timezone    string
+	)
+	cmd := &cobra.Command{
+		Use:   "add TITLE",
+		Short: "Create a todo",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := a.client()
+			if err != nil {
+				return err
+			}
+			req := &client.CreateTodoRequest{
+				OwnerID:     ownerID,
+				Title:       args[0],
+				Description: description,
+				Priority:    client.Priority(priority),
+				Timezone:    timezone,
+			}
+			if req.OwnerID == 0 {
+				req.OwnerID = a.userID
+			}
+			if parentID > 0 {
+				req.ParentID = &parentID
+			}
+			if due != "" {
+				dueDate, err := time.Parse(time.RFC3339, due)
+				if err != nil {
+					return fmt.Errorf("invalid --due %q: use RFC3339, e.g. 2025-01-31T18:00:00+09:00", due)
+				}
+				req.DueDate = &dueDate
+			}
+
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+			todo, err := b.CreateTodo(ctx, req)
+			if err != nil {
+				return err
+			}
+			return printTodos(cmd.OutOrStdout(), a.output, []*client.Todo{todo})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&ownerID, "owner", 0, "owner of the todo (default: --user); servers make the acting user the owner")
+	flags.IntVar(&parentID, "parent", 0, "parent todo, making this a subtask")
+	flags.StringVarP(&description, "description", "d", "", "description")
+	flags.StringVarP(&priority, "priority", "p", "", "priority: low, medium, high or urgent")
+	flags.StringVar(&due, "due", "", "due date in RFC3339")
+	flags.StringVar(&timezone, "timezone", "", "IANA time zone of the due date")
+	_ = cmd.RegisterFlagCompletionFunc("priority", fixedCompletions(priorities()...))
+	return cmd
+}
+
+// newTodoListCommand creates the todo list command
+func newTodoListCommand(a *app) *cobra.Command {
+	var (
+		ownerID  int
+		status   string
+		priority string
+		tags     []string
+		match    string
+		overdue  bool
+	)
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List todos",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filter := client.TodoFilter{
+				Status:   client.TodoStatus(status),
+				Priority: client.Priority(priority),
+				Tags:     tags,
+				TagMatch: client.TagMatch(match),
+			}
+			if ownerID > 0 {
+				filter.OwnerID = &ownerID
+			}
+			if cmd.Flags().Changed("overdue") {
+				filter.Overdue = &overdue
+			}
+
+			b, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+			todos, err := b.ListTodos(ctx, filter)
+			if err != nil {
+				return err
+			}
+			return printTodos(cmd.OutOrStdout(), a.output, todos)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&ownerID, "owner", 0, "only todos of this owner")
+	flags.StringVar(&status, "status", "", "only todos in this status")
+	flags.StringVarP(&priority, "priority", "p", "", "only todos of this priority")
+	flags.StringSliceVarP(&tags, "tag", "t", nil, "only todos with this tag name; repeatable")
+	flags.StringVar(&match, "match", "", "how to combine tags: all or any")
+	flags.BoolVar(&overdue, "overdue", false, "only open todos past their due date")
+	_ = cmd.RegisterFlagCompletionFunc("status", fixedCompletions(statuses()...))
+	_ = cmd.RegisterFlagCompletionFunc("priority", fixedCompletions(priorities()...))
+	_ = cmd.RegisterFlagCompletionFunc("match", fixedCompletions(string(client.TagMatchAll), string(client.TagMatchAny)))
+	return cmd
+}
+
+// newTodoDoneCommand creates the todo done command
+func newTodoDoneCommand(a *app) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "done ID...",
+		Short: "Mark todos as done",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			b, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			todos := make([]*client.Todo, 0, len(ids))
+			for _, id := range ids {
+				todo, err := b.TransitionTodo(ctx, id, &client.TransitionTodoRequest{To: client.TodoStatusDone, Reason: reason})
+				if err != nil {
+					return fmt.Errorf("todo %d: %w", id, err)
+				}
+				todos = append(todos, todo)
+			}
+			return printTodos(cmd.OutOrStdout(), a.output, todos)
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "reason recorded with the transition")
+	return cmd
+}
+
+// newTodoRmCommand creates the todo rm command
+func newTodoRmCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm ID...",
+		Short: "Move todos to the trash",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := parseIDs(args)
+			if err != nil {
+				return err
+			}
+			b, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+
+			for _, id := range ids {
+				if err := b.DeleteTodo(ctx, id); err != nil {
+					return fmt.Errorf("todo %d: %w", id, err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Moved todo %d to the trash\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+// parseIDs parses positional todo IDs
+func parseIDs(args []string) ([]int, error) {
+	ids := make([]int, len(args))
+	for i, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", arg)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// priorities lists the priority levels for completion
+func priorities() []string {
+	return []string{
+		string(client.PriorityLow),
+		string(client.PriorityMedium),
+		string(client.PriorityHigh),
+		string(client.PriorityUrgent),
+	}
+}
+
+// statuses lists the todo statuses for completion
+func statuses() []string {
+	return []string{
+		string(client.TodoStatusOpen),
+		string(client.TodoStatusInProgress),
+		string(client.TodoStatusBlocked),
+		string(client.TodoStatusDone),
+		string(client.TodoStatusCancelled),
+	}
+}
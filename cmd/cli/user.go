@@ -0,0 +1,74 @@
+package main
+
+import (
+	"go-boilerplate/pkg/client"
+
+	"github.com/spf13/cobra"
+)
+
+// newUserCommand creates the user command group
+func newUserCommand(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+	}
+	cmd.AddCommand(newUserCreateCommand(a), newUserListCommand(a))
+	return cmd
+}
+
+// newUserCreateCommand creates the user create command
+func newUserCreateCommand(a *app) *cobra.Command {
+	var email, name string
+	cmd := &cobra.Command{
+		Use:   "create USERNAME",
+		Short: "Create a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+			user, err := b.CreateUser(ctx, &client.CreateUserRequest{
+				Username: args[0],
+				Email:    email,
+				Name:     name,
+			})
+			if err != nil {
+				return err
+			}
+			return printUsers(cmd.OutOrStdout(), a.output, []*client.User{user})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&email, "email", "", "email address")
+	flags.StringVar(&name, "name", "", "display name")
+	_ = cmd.MarkFlagRequired("email")
+	_ = cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+// newUserListCommand creates the user list command
+func newUserListCommand(a *app) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List users",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			b, err := a.client()
+			if err != nil {
+				return err
+			}
+			ctx, cancel := a.context(cmd)
+			defer cancel()
+			users, err := b.ListUsers(ctx)
+			if err != nil {
+				return err
+			}
+			return printUsers(cmd.OutOrStdout(), a.output, users)
+		},
+	}
+}
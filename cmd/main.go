@@ -1,15 +1,28 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 
 	_ "go-boilerplate/docs"
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/adapter/inbound/graphql"
+	"go-boilerplate/internal/adapter/inbound/grpc"
 	"go-boilerplate/internal/adapter/inbound/http"
+	"go-boilerplate/internal/adapter/inbound/websocket"
+	"go-boilerplate/internal/adapter/outbound/audit"
+	"go-boilerplate/internal/adapter/outbound/eventbus"
 	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/adapter/outbound/readmodel"
+	"go-boilerplate/internal/adapter/outbound/storage"
+	"go-boilerplate/internal/adapter/outbound/webhook"
 	"go-boilerplate/internal/config"
+	"go-boilerplate/internal/domain/port"
 	"go-boilerplate/internal/domain/service"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +35,10 @@ import (
 // @description This is a sample go boilerplate API server.
 // @host localhost:8080
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer token issued with "todoctl token", e.g. "Bearer 1.1767225600.c2ln..."
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
@@ -30,19 +47,142 @@ func main() {
 	}
 
 	// Initialize repositories
-	todoRepo := persistence.NewTodoRepository()
+	todoRepo, todoHistory, err := initializeTodoRepository(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize todo repository: %v", err)
+	}
 	userRepo := persistence.NewUserRepository()
+	tagRepo := persistence.NewTagRepository()
+	listRepo := persistence.NewTodoListRepository()
+	shareRepo := persistence.NewListShareRepository()
+	commentRepo := persistence.NewCommentRepository()
+	attachmentRepo := persistence.NewAttachmentRepository()
+	outboxRepo := persistence.NewOutboxRepository()
+	processedRepo := persistence.NewProcessedEventRepository()
+	webhookRepo := persistence.NewWebhookRepository()
+	// Transactions span every repository, so multi-step operations and their
+	// outbox entries commit or roll back together
+	txManager := persistence.NewTxManager(todoRepo, userRepo, tagRepo, listRepo, shareRepo, commentRepo, attachmentRepo, outboxRepo)
+
+	// Initialize blob storage
+	blobStorage, err := initializeBlobStorage(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob storage: %v", err)
+	}
+
+	// Initialize audit log
+	auditLog, err := initializeAuditLog(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize audit log: %v", err)
+	}
+
+	// Initialize the event bus. Services record their events in the outbox,
+	// which the outbox relay delivers to the bus.
+	eventBus := eventbus.New(cfg.Events.AsyncWorkers, cfg.Events.BufferSize,
+		eventbus.WithRetryBackoff(cfg.Events.RetryBackoff, cfg.Events.MaxRetryBackoff),
+	)
+	outboxPublisher := service.NewOutboxPublisher(outboxRepo)
 
 	// Initialize services
-	todoService := service.NewTodoService(todoRepo)
-	userService := service.NewUserService(userRepo)
+	attachmentService := service.NewAttachmentService(attachmentRepo, todoRepo, blobStorage,
+		service.WithMaxAttachmentSize(cfg.Attachment.MaxSize),
+		service.WithAllowedAttachmentTypes(cfg.Attachment.AllowedTypes),
+		service.WithAttachmentListRoles(listRepo, shareRepo),
+	)
+	todoService := service.NewTodoService(todoRepo, tagRepo, txManager,
+		service.WithSubtaskRule(cfg.Todo.SubtaskRule),
+		service.WithMaxSubtaskDepth(cfg.Todo.MaxSubtaskDepth),
+		service.WithAttachmentCleanup(attachmentService),
+		service.WithTodoAuditLog(auditLog),
+		service.WithTodoEventPublisher(outboxPublisher),
+		service.WithTodoHistory(todoHistory),
+		service.WithListRoles(listRepo, shareRepo),
+	)
+	userService := service.NewUserService(userRepo, txManager,
+		service.WithUsernameGracePeriod(cfg.User.UsernameGracePeriod),
+		service.WithUserAuditLog(auditLog),
+		service.WithUserEventPublisher(outboxPublisher),
+	)
+
+	// Tag and list changes to todos go through the todo service's repository
+	// so they are audited and announced like direct ones, and members of
+	// deleted lists are removed through the share service's so their
+	// removal is announced too
+	tagService := service.NewTagService(tagRepo, todoService.TodoRepository(), txManager)
+	shareService := service.NewListShareService(shareRepo, listRepo, userRepo, txManager,
+		service.WithListShareEventPublisher(outboxPublisher),
+	)
+	listService := service.NewTodoListService(listRepo, shareService.ShareRepository(), todoService.TodoRepository(), todoService, txManager)
+	commentService := service.NewCommentService(commentRepo, todoRepo, userRepo, txManager,
+		service.WithCommentListRoles(listRepo, shareRepo),
+	)
+	auditService := service.NewAuditService(auditLog, cfg.Admin.UserIDs)
+	statsService := service.NewTodoStatsService(readmodel.NewMemoryTodoStats(), todoRepo, cfg.Admin.UserIDs)
+	webhookService := service.NewWebhookService(webhookRepo, service.WithInternalWebhookURLs(cfg.Webhook.AllowInternalTargets))
+	streamService := service.NewTodoStreamService(listRepo, shareRepo,
+		service.WithStreamReplayBuffer(cfg.Stream.ReplayBuffer),
+		service.WithStreamClientBuffer(cfg.Stream.ClientBuffer),
+	)
+
+	// Project todo events onto the dashboard read model, tracking the ones still queued
+	eventBus.SubscribeSync(statsService.TrackEvent, service.TodoStatsEvents...)
+	eventBus.SubscribeAsync(statsService.HandleEvent, service.TodoStatsEvents...)
+	// Queue webhook deliveries, once per event even if the relay redelivers it
+	eventBus.SubscribeAsync(service.Deduplicate("webhooks", processedRepo, webhookService.HandleEvent))
+	// Push todo changes to live streams
+	eventBus.SubscribeAsync(service.Deduplicate("todo_stream", processedRepo, streamService.HandleEvent), service.TodoStreamEvents...)
+	// Fan todo changes out to the collaboration rooms of their lists
+	collabHub := websocket.NewHub()
+	eventBus.SubscribeAsync(service.Deduplicate("collaboration", processedRepo, collabHub.HandleEvent))
 
 	// Initialize handlers
-	todoHandler := http.NewTodoHandler(todoService)
-	userHandler := http.NewUserHandler(userService)
+	handlers := http.Handlers{
+		Todo:       http.NewTodoHandler(todoService),
+		User:       http.NewUserHandler(userService),
+		Tag:        http.NewTagHandler(tagService),
+		List:       http.NewTodoListHandler(listService),
+		Share:      http.NewListShareHandler(shareService),
+		Comment:    http.NewCommentHandler(commentService),
+		Attachment: http.NewAttachmentHandler(attachmentService, cfg.Attachment.MaxSize),
+		Audit:      http.NewAuditHandler(auditService),
+		Stats:      http.NewStatsHandler(statsService),
+		Webhook:    http.NewWebhookHandler(webhookService),
+		Stream:     http.NewTodoStreamHandler(streamService, cfg.Stream.HeartbeatInterval),
+	}
+	collabHandler := websocket.NewHandler(collabHub, todoService, listService, shareService,
+		websocket.WithClientBuffer(cfg.WebSocket.ClientBuffer),
+		websocket.WithPingInterval(cfg.WebSocket.PingInterval),
+		websocket.WithMaxMessageSize(cfg.WebSocket.MaxMessageSize),
+	)
+	graphqlHandler := graphql.NewHandler(todoService, userService, streamService,
+		graphql.WithMaxDepth(cfg.GraphQL.MaxDepth),
+		graphql.WithMaxComplexity(cfg.GraphQL.MaxComplexity),
+		graphql.WithIntrospection(cfg.GraphQL.Introspection),
+		graphql.WithKeepAlive(cfg.WebSocket.PingInterval),
+	)
 
 	// Initialize router
-	r := initializeRouter(todoHandler, userHandler)
+	authenticator := initializeAuthenticator(cfg)
+	r := initializeRouter(cfg, authenticator, handlers, collabHandler, graphqlHandler)
+
+	// Start background workers
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trashPurger := service.NewTrashPurger(todoService, userService, cfg.Trash.Retention)
+	go trashPurger.Run(ctx, cfg.Trash.PurgeInterval)
+	outboxRelay := service.NewOutboxRelay(outboxRepo, eventBus,
+		service.WithOutboxBatchSize(cfg.Outbox.BatchSize),
+		service.WithOutboxMaxBackoff(cfg.Outbox.MaxBackoff),
+		service.WithOutboxRetention(cfg.Outbox.Retention),
+		service.WithProcessedEventCleanup(processedRepo),
+	)
+	go outboxRelay.Run(ctx, cfg.Outbox.RelayInterval)
+	webhookDispatcher := service.NewWebhookDispatcher(webhookRepo, webhook.NewHTTPSender(cfg.Webhook.Timeout, webhook.WithInternalTargets(cfg.Webhook.AllowInternalTargets)),
+		service.WithWebhookMaxAttempts(cfg.Webhook.MaxAttempts),
+		service.WithWebhookBackoff(cfg.Webhook.InitialBackoff, cfg.Webhook.MaxBackoff),
+		service.WithWebhookDisableAfter(cfg.Webhook.DisableAfterFailures),
+	)
+	go webhookDispatcher.Run(ctx, cfg.Webhook.DispatchInterval)
 
 	// Start server
 	go func() {
@@ -52,41 +192,127 @@ func main() {
 		}
 	}()
 
+	// Start gRPC server
+	grpcServer := grpc.NewServer(todoService, userService, authenticator)
+	if cfg.GRPC.Address != "" {
+		listener, err := net.Listen("tcp", cfg.GRPC.Address)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		go func() {
+			log.Printf("gRPC server starting on %s", cfg.GRPC.Address)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("Failed to start gRPC server: %v", err)
+			}
+		}()
+	}
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down server...")
+	grpcServer.GracefulStop()
+	cancel()
+	eventBus.Close()
 	log.Println("Server exited properly")
 }
 
+// todoStore is a todo repository that takes part in in-memory transactions
+type todoStore interface {
+	port.TodoRepositoryPort
+	persistence.Transactional
+}
+
+// initializeTodoRepository creates the todo repository selected in the
+// configuration, and its history if the repository keeps one
+func initializeTodoRepository(cfg *config.Config) (todoStore, port.TodoHistoryPort, error) {
+	switch cfg.Todo.Store {
+	case "event_sourced":
+		repo := persistence.NewEventSourcedTodoRepository(cfg.Todo.SnapshotEvery)
+		return repo, repo, nil
+	case "memory":
+		return persistence.NewTodoRepository(), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown todo store %q", cfg.Todo.Store)
+	}
+}
+
+// initializeBlobStorage creates the blob storage adapter selected in the configuration
+func initializeBlobStorage(cfg *config.Config) (port.BlobStoragePort, error) {
+	switch cfg.Storage.Backend {
+	case "s3":
+		return storage.NewS3BlobStorage(context.Background(), storage.S3Config{
+			Endpoint:  cfg.Storage.S3.Endpoint,
+			Region:    cfg.Storage.S3.Region,
+			Bucket:    cfg.Storage.S3.Bucket,
+			AccessKey: cfg.Storage.S3.AccessKey,
+			SecretKey: cfg.Storage.S3.SecretKey,
+			UseSSL:    cfg.Storage.S3.UseSSL,
+		})
+	case "local":
+		return storage.NewLocalBlobStorage(cfg.Storage.LocalDir)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+}
+
+// initializeAuditLog creates the audit log adapter selected in the configuration
+func initializeAuditLog(cfg *config.Config) (port.AuditPort, error) {
+	switch cfg.Audit.Backend {
+	case "file":
+		return audit.NewFileAuditLog(cfg.Audit.FilePath)
+	case "memory":
+		return audit.NewMemoryAuditLog(), nil
+	default:
+		return nil, fmt.Errorf("unknown audit backend %q", cfg.Audit.Backend)
+	}
+}
+
+// initializeAuthenticator creates the authenticator identifying the users
+// requests act on behalf of
+func initializeAuthenticator(cfg *config.Config) *auth.Authenticator {
+	var opts []auth.Option
+	if cfg.Auth.TokenSecret != "" {
+		opts = append(opts, auth.WithTokens(auth.NewTokenSigner([]byte(cfg.Auth.TokenSecret), cfg.Auth.TokenTTL)))
+	} else {
+		log.Println("AUTH_TOKEN_SECRET is not set, bearer tokens are rejected")
+	}
+	if cfg.Auth.AllowActorHeader {
+		log.Println("WARNING: the X-User-ID header is trusted, anyone can act as any user; never enable this outside development")
+		opts = append(opts, auth.WithActorHeader(true))
+	}
+	if cfg.Admin.APIKey != "" {
+		if len(cfg.Admin.UserIDs) == 0 {
+			log.Println("ADMIN_API_KEY is set but ADMIN_USER_IDS is not, the admin key is rejected")
+		}
+		opts = append(opts, auth.WithAdminKey(cfg.Admin.APIKey, cfg.Admin.UserIDs))
+	}
+	return auth.NewAuthenticator(opts...)
+}
+
 // initializeRouter sets up all routes and middleware
-func initializeRouter(todoHandler *http.TodoHandler, userHandler *http.UserHandler) *gin.Engine {
-	r := gin.Default()
+func initializeRouter(cfg *config.Config, authenticator *auth.Authenticator, handlers http.Handlers, collabHandler *websocket.Handler, graphqlHandler *graphql.Handler) *gin.Engine {
+	r := gin.New()
+	// Tokens in the query are taken out before the request is logged
+	r.Use(http.AccessTokenQueryMiddleware(), gin.Logger(), gin.Recovery())
+	r.Use(http.RequestIDMiddleware(), http.ActorMiddleware(authenticator))
 
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// Todo routes
-	todos := r.Group("/todos")
-	{
-		todos.POST("", todoHandler.CreateTodo)
-		todos.GET("", todoHandler.ListTodos)
-		todos.GET("/:id", todoHandler.GetTodo)
-		todos.PUT("/:id", todoHandler.UpdateTodo)
-		todos.DELETE("/:id", todoHandler.DeleteTodo)
-	}
+	// Collaboration over WebSocket
+	r.GET("/ws", http.StreamAuthMiddleware(authenticator), collabHandler.Serve)
 
-	// User routes
-	users := r.Group("/users")
-	{
-		users.POST("", userHandler.CreateUser)
-		users.GET("", userHandler.ListUsers)
-		users.GET("/:id", userHandler.GetUser)
-		users.PUT("/:id", userHandler.UpdateUser)
-		users.DELETE("/:id", userHandler.DeleteUser)
-	}
+	// GraphQL
+	r.GET("/graphql", graphqlHandler.Serve)
+	r.POST("/graphql", graphqlHandler.Serve)
+
+	// REST routes are served under /v1, and without a version prefix as a
+	// deprecated alias of v1 until the sunset
+	http.RegisterRoutes(r.Group("/v1"), handlers, authenticator)
+	http.RegisterRoutes(r.Group("", http.DeprecationMiddleware(cfg.API.LegacyDeprecatedAt, cfg.API.LegacySunset, "/v1")), handlers, authenticator)
 
 	return r
 }
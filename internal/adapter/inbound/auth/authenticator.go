@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AdminKeyScheme is the Authorization scheme of the admin key
+const AdminKeyScheme = "AdminKey"
+
+// Authentication errors
+var (
+	ErrActorNotAccepted = errors.New("actor IDs are not accepted, authenticate with a bearer token")
+	ErrInvalidActor     = errors.New("invalid actor ID")
+	ErrInvalidAdminKey  = errors.New("invalid admin key")
+	ErrNotAdmin         = errors.New("the admin key only acts as an administrator")
+)
+
+// Authenticator identifies the user a request acts on behalf of. Users prove
+// who they are with a bearer token. A plain actor ID, as sent in the
+// X-User-ID header, proves nothing since any client can send any ID; it is
+// only accepted when explicitly allowed for local development. Operators may
+// instead present the admin key, which acts as an administrator only.
+type Authenticator struct {
+	tokens           *TokenSigner
+	allowActorHeader bool
+	adminKey         []byte
+	adminIDs         []int
+}
+
+// Option configures optional Authenticator behavior
+type Option func(*Authenticator)
+
+// WithTokens verifies bearer tokens with the signer. Without it every token
+// is rejected.
+func WithTokens(tokens *TokenSigner) Option {
+	return func(a *Authenticator) {
+		a.tokens = tokens
+	}
+}
+
+// WithActorHeader trusts plain actor IDs. It must only be enabled in
+// development: it lets anyone act as any user.
+func WithActorHeader(allow bool) Option {
+	return func(a *Authenticator) {
+		a.allowActorHeader = allow
+	}
+}
+
+// WithAdminKey accepts the key with the AdminKey scheme as one of the
+// administrators: the one named by the actor ID, or the first. It is a
+// credential of its own, so operators need neither a user's token nor the
+// secret tokens are signed with. Without administrators the key is rejected.
+func WithAdminKey(key string, adminIDs []int) Option {
+	return func(a *Authenticator) {
+		a.adminKey = []byte(key)
+		a.adminIDs = adminIDs
+	}
+}
+
+// NewAuthenticator creates an Authenticator
+func NewAuthenticator(opts ...Option) *Authenticator {
+	a := &Authenticator{}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Authenticate returns the user identified by an Authorization header value
+// or, failing that, a plain actor ID. ok is false when the request carries
+// neither and continues anonymously.
+func (a *Authenticator) Authenticate(authorization, actorID string) (userID int, ok bool, err error) {
+	if authorization != "" {
+		scheme, token, _ := strings.Cut(authorization, " ")
+		if strings.EqualFold(scheme, AdminKeyScheme) {
+			return a.authenticateAdmin(strings.TrimSpace(token), actorID)
+		}
+		if !strings.EqualFold(scheme, "Bearer") {
+			return 0, false, ErrInvalidToken
+		}
+		userID, err := a.VerifyToken(strings.TrimSpace(token))
+		if err != nil {
+			return 0, false, err
+		}
+		return userID, true, nil
+	}
+
+	if actorID == "" {
+		return 0, false, nil
+	}
+	if !a.allowActorHeader {
+		return 0, false, ErrActorNotAccepted
+	}
+	userID, err = strconv.Atoi(actorID)
+	if err != nil || userID <= 0 {
+		return 0, false, ErrInvalidActor
+	}
+	return userID, true, nil
+}
+
+// VerifyToken returns the user a bearer token identifies
+func (a *Authenticator) VerifyToken(token string) (int, error) {
+	if a.tokens == nil {
+		return 0, ErrInvalidToken
+	}
+	return a.tokens.Verify(token)
+}
+
+// authenticateAdmin returns the administrator an admin key request acts as
+func (a *Authenticator) authenticateAdmin(key, actorID string) (int, bool, error) {
+	if len(a.adminKey) == 0 || len(a.adminIDs) == 0 || subtle.ConstantTimeCompare([]byte(key), a.adminKey) != 1 {
+		return 0, false, ErrInvalidAdminKey
+	}
+	if actorID == "" {
+		return a.adminIDs[0], true, nil
+	}
+	userID, err := strconv.Atoi(actorID)
+	if err != nil || userID <= 0 {
+		return 0, false, ErrInvalidActor
+	}
+	if !slices.Contains(a.adminIDs, userID) {
+		return 0, false, ErrNotAdmin
+	}
+	return userID, true, nil
+}
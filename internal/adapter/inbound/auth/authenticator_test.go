@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenSigner(t *testing.T) {
+	issuedAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := NewTokenSigner([]byte("secret"), time.Hour)
+	signer.now = func() time.Time { return issuedAt }
+	token := signer.Issue(7)
+
+	tests := []struct {
+		name       string
+		token      string
+		secret     string
+		at         time.Time
+		wantUserID int
+		wantErr    error
+	}{
+		{name: "valid", token: token, secret: "secret", at: issuedAt.Add(time.Minute), wantUserID: 7},
+		{name: "expired", token: token, secret: "secret", at: issuedAt.Add(time.Hour), wantErr: ErrTokenExpired},
+		{name: "other secret", token: token, secret: "other", at: issuedAt, wantErr: ErrInvalidToken},
+		{name: "user changed", token: "8" + token[1:], secret: "secret", at: issuedAt, wantErr: ErrInvalidToken},
+		{name: "no signature", token: "7.1893499200", secret: "secret", at: issuedAt, wantErr: ErrInvalidToken},
+		{name: "empty", token: "", secret: "secret", at: issuedAt, wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewTokenSigner([]byte(tt.secret), time.Hour)
+			verifier.now = func() time.Time { return tt.at }
+			userID, err := verifier.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if userID != tt.wantUserID {
+				t.Errorf("Verify() = %d, want %d", userID, tt.wantUserID)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	signer := NewTokenSigner([]byte("secret"), time.Hour)
+	token := signer.Issue(7)
+
+	tests := []struct {
+		name          string
+		opts          []Option
+		authorization string
+		actorID       string
+		wantUserID    int
+		wantOK        bool
+		wantErr       error
+	}{
+		{name: "anonymous", opts: []Option{WithTokens(signer)}},
+		{name: "bearer token", opts: []Option{WithTokens(signer)}, authorization: "Bearer " + token, wantUserID: 7, wantOK: true},
+		{name: "scheme is case-insensitive", opts: []Option{WithTokens(signer)}, authorization: "bearer " + token, wantUserID: 7, wantOK: true},
+		{name: "token wins over actor header", opts: []Option{WithTokens(signer), WithActorHeader(true)}, authorization: "Bearer " + token, actorID: "9", wantUserID: 7, wantOK: true},
+		{name: "forged token", opts: []Option{WithTokens(signer)}, authorization: "Bearer 7.9999999999.forged", wantErr: ErrInvalidToken},
+		{name: "basic auth", opts: []Option{WithTokens(signer)}, authorization: "Basic Ym9iOnB3", wantErr: ErrInvalidToken},
+		{name: "tokens disabled", authorization: "Bearer " + token, wantErr: ErrInvalidToken},
+		{name: "actor header rejected by default", opts: []Option{WithTokens(signer)}, actorID: "9", wantErr: ErrActorNotAccepted},
+		{name: "actor header in development", opts: []Option{WithActorHeader(true)}, actorID: "9", wantUserID: 9, wantOK: true},
+		{name: "invalid actor header", opts: []Option{WithActorHeader(true)}, actorID: "bob", wantErr: ErrInvalidActor},
+		{name: "admin key acts as the first administrator", opts: []Option{WithAdminKey("key", []int{3, 4})}, authorization: "AdminKey key", wantUserID: 3, wantOK: true},
+		{name: "admin key acts as the named administrator", opts: []Option{WithAdminKey("key", []int{3, 4})}, authorization: "AdminKey key", actorID: "4", wantUserID: 4, wantOK: true},
+		{name: "admin key refuses other users", opts: []Option{WithAdminKey("key", []int{3, 4})}, authorization: "AdminKey key", actorID: "1", wantErr: ErrNotAdmin},
+		{name: "wrong admin key", opts: []Option{WithAdminKey("key", []int{3})}, authorization: "AdminKey other", wantErr: ErrInvalidAdminKey},
+		{name: "admin key disabled", opts: []Option{WithTokens(signer)}, authorization: "AdminKey ", wantErr: ErrInvalidAdminKey},
+		{name: "admin key without administrators", opts: []Option{WithAdminKey("key", nil)}, authorization: "AdminKey key", wantErr: ErrInvalidAdminKey},
+		{name: "token is no admin key", opts: []Option{WithTokens(signer), WithAdminKey("key", []int{3})}, authorization: "AdminKey " + token, wantErr: ErrInvalidAdminKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID, ok, err := NewAuthenticator(tt.opts...).Authenticate(tt.authorization, tt.actorID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if userID != tt.wantUserID || ok != tt.wantOK {
+				t.Errorf("Authenticate() = %d, %v, want %d, %v", userID, ok, tt.wantUserID, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"go-boilerplate/internal/domain"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// errorCodes maps domain errors to the codes reported in an error's extensions
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{domain.ErrNotFound, "NOT_FOUND"},
+	{domain.ErrDuplicate, "ALREADY_EXISTS"},
+	{domain.ErrUsernameDuplicate, "ALREADY_EXISTS"},
+	{domain.ErrUnauthenticated, "UNAUTHENTICATED"},
+	{domain.ErrForbidden, "FORBIDDEN"},
+	{domain.ErrHistoryUnavailable, "UNIMPLEMENTED"},
+
+	{domain.ErrInvalidTodoTitle, "BAD_USER_INPUT"},
+	{domain.ErrInvalidPriority, "BAD_USER_INPUT"},
+	{domain.ErrInvalidTimezone, "BAD_USER_INPUT"},
+	{domain.ErrDueDateInPast, "BAD_USER_INPUT"},
+	{domain.ErrInvalidTodoStatus, "BAD_USER_INPUT"},
+	{domain.ErrTagOwnerMismatch, "BAD_USER_INPUT"},
+	{domain.ErrInvalidTagMatch, "BAD_USER_INPUT"},
+	{domain.ErrInvalidParentTodo, "BAD_USER_INPUT"},
+	{domain.ErrInvalidChecklistItem, "BAD_USER_INPUT"},
+	{domain.ErrInvalidRecurrence, "BAD_USER_INPUT"},
+	{domain.ErrRecurrenceRequiresDueDate, "BAD_USER_INPUT"},
+	{domain.ErrInvalidUsername, "BAD_USER_INPUT"},
+	{domain.ErrInvalidEmail, "BAD_USER_INPUT"},
+	{domain.ErrUsernameUnchanged, "BAD_USER_INPUT"},
+
+	{domain.ErrTodoAlreadyCompleted, "FAILED_PRECONDITION"},
+	{domain.ErrIllegalTransition, "FAILED_PRECONDITION"},
+	{domain.ErrTodoCycle, "FAILED_PRECONDITION"},
+	{domain.ErrMaxDepthExceeded, "FAILED_PRECONDITION"},
+	{domain.ErrOpenSubtasks, "FAILED_PRECONDITION"},
+	{domain.ErrClosedParent, "FAILED_PRECONDITION"},
+	{domain.ErrNotRecurring, "FAILED_PRECONDITION"},
+	{domain.ErrUsernameReserved, "FAILED_PRECONDITION"},
+	{domain.ErrUsernameInTrash, "FAILED_PRECONDITION"},
+}
+
+// presentError reports a domain error with its code. Errors raised by the
+// GraphQL layer itself, such as invalid arguments, are kept as they are;
+// anything else is reported as internal without its details.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			gqlErr.Message = mapping.err.Error()
+			errcode.Set(gqlErr, mapping.code)
+			return gqlErr
+		}
+	}
+
+	var existing *gqlerror.Error
+	if errors.As(err, &existing) {
+		return gqlErr
+	}
+	log.Printf("graphql: %v", err)
+	gqlErr.Message = "internal server error"
+	errcode.Set(gqlErr, "INTERNAL_SERVER_ERROR")
+	return gqlErr
+}
+
+// recoverPanic turns a panicking resolver into an internal error
+func recoverPanic(_ context.Context, p any) error {
+	log.Printf("graphql: resolver panic: %v", p)
+	return gqlerror.Errorf("internal server error")
+}
@@ -0,0 +1,36 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go-boilerplate/internal/domain"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestPresentError(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    any
+		wantMessage string
+	}{
+		{name: "not found", err: domain.ErrNotFound, wantCode: "NOT_FOUND", wantMessage: domain.ErrNotFound.Error()},
+		{name: "wrapped", err: fmt.Errorf("creating todo: %w", domain.ErrInvalidTodoTitle), wantCode: "BAD_USER_INPUT", wantMessage: domain.ErrInvalidTodoTitle.Error()},
+		{name: "precondition", err: domain.ErrIllegalTransition, wantCode: "FAILED_PRECONDITION", wantMessage: domain.ErrIllegalTransition.Error()},
+		{name: "graphql errors are kept", err: gqlerror.Errorf("bad argument"), wantCode: nil, wantMessage: "bad argument"},
+		{name: "unknown errors hide their details", err: errors.New("disk on fire"), wantCode: "INTERNAL_SERVER_ERROR", wantMessage: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := presentError(context.Background(), tt.err)
+			if code := got.Extensions["code"]; code != tt.wantCode || got.Message != tt.wantMessage {
+				t.Errorf("presentError(%v) = %v %q, want %v %q", tt.err, code, got.Message, tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
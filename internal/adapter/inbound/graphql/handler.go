@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Handler serves the GraphQL API. Queries and mutations are accepted over GET
+// and POST; subscriptions over WebSocket (graphql-ws and graphql-transport-ws)
+// or Server-Sent Events. Requests act as the user the router authenticated.
+type Handler struct {
+	server        *handler.Server
+	maxDepth      int
+	maxComplexity int
+	introspection bool
+	keepAlive     time.Duration
+	batchWait     time.Duration
+	maxBatch      int
+}
+
+// HandlerOption configures optional Handler behavior
+type HandlerOption func(*Handler)
+
+// WithMaxDepth sets how deeply an operation may nest fields
+func WithMaxDepth(n int) HandlerOption {
+	return func(h *Handler) {
+		h.maxDepth = n
+	}
+}
+
+// WithMaxComplexity sets the highest cost an operation may have. Each field
+// costs one, and list fields cost ten times their selections.
+func WithMaxComplexity(n int) HandlerOption {
+	return func(h *Handler) {
+		h.maxComplexity = n
+	}
+}
+
+// WithIntrospection enables or disables schema introspection
+func WithIntrospection(enabled bool) HandlerOption {
+	return func(h *Handler) {
+		h.introspection = enabled
+	}
+}
+
+// WithKeepAlive sets how often idle WebSocket subscriptions are sent a
+// keep-alive message
+func WithKeepAlive(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.keepAlive = d
+	}
+}
+
+// WithUserBatching sets how long user lookups wait to be batched together and
+// how many are fetched at most in one batch
+func WithUserBatching(wait time.Duration, maxBatch int) HandlerOption {
+	return func(h *Handler) {
+		h.batchWait = wait
+		h.maxBatch = maxBatch
+	}
+}
+
+// NewHandler creates a new Handler
+func NewHandler(todoService port.TodoServicePort, userService port.UserServicePort, streamService port.TodoStreamServicePort, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		maxDepth:      10,
+		maxComplexity: 1000,
+		introspection: true,
+		keepAlive:     15 * time.Second,
+		batchWait:     time.Millisecond,
+		maxBatch:      100,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers: &Resolver{
+			todoService:   todoService,
+			userService:   userService,
+			streamService: streamService,
+		},
+		Complexity: complexity(),
+	}))
+	srv.AddTransport(transport.Websocket{KeepAlivePingInterval: h.keepAlive})
+	srv.AddTransport(transport.SSE{})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	if h.introspection {
+		srv.Use(extension.Introspection{})
+	}
+	srv.Use(depthLimit{limit: h.maxDepth})
+	srv.Use(extension.FixedComplexityLimit(h.maxComplexity))
+	srv.SetErrorPresenter(presentError)
+	srv.SetRecoverFunc(recoverPanic)
+	srv.AroundOperations(func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		return next(contextWithUsers(ctx, newUserLoader(userService, h.batchWait, h.maxBatch)))
+	})
+
+	h.server = srv
+	return h
+}
+
+// Serve handles GET and POST /graphql
+// @Summary GraphQL endpoint
+// @Description Execute GraphQL queries, mutations and subscriptions against the todo and user schema. Queries may be sent with GET or POST; subscriptions use a WebSocket upgrade (graphql-ws or graphql-transport-ws) or a POST accepting text/event-stream. Operations nesting deeper or costing more than the configured limits are rejected before they run.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{} "Unprocessable Entity"
+// @Router /graphql [post]
+func (h *Handler) Serve(c *gin.Context) {
+	h.server.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,441 @@
+package graphql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	httpadapter "go-boilerplate/internal/adapter/inbound/http"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+	"go-boilerplate/internal/domain/service"
+	"go-boilerplate/internal/domain/service/servicetest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamPublisher hands events straight to a todo stream
+type streamPublisher struct {
+	stream *service.TodoStreamService
+}
+
+// Publish sends the events to the stream
+func (p *streamPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	for _, event := range events {
+		if err := p.stream.HandleEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countingUsers counts how often users are fetched in batches
+type countingUsers struct {
+	port.UserServicePort
+
+	mu      sync.Mutex
+	batches [][]int
+}
+
+// GetUsers records the batch and fetches it
+func (u *countingUsers) GetUsers(ctx context.Context, ids []int) ([]*model.User, error) {
+	u.mu.Lock()
+	u.batches = append(u.batches, append([]int(nil), ids...))
+	u.mu.Unlock()
+	return u.UserServicePort.GetUsers(ctx, ids)
+}
+
+// graphqlFixture serves /graphql for users alice (1), bob (2) and carol (3),
+// with alice owning the todo "dishes" (1) and bob the todo "laundry" (2)
+type graphqlFixture struct {
+	server *httptest.Server
+	signer *auth.TokenSigner
+	todos  *service.TodoService
+	users  *countingUsers
+}
+
+// newGraphQLFixture serves the GraphQL handler behind the same middleware as the server
+func newGraphQLFixture(t *testing.T, opts ...HandlerOption) *graphqlFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	// The stream reads the services' lists, and the services publish to it
+	publisher := &streamPublisher{}
+	services := servicetest.New(publisher)
+	stream := service.NewTodoStreamService(services.ListRepo, services.ShareRepo)
+	publisher.stream = stream
+	services.CreateUsers(t, "alice", "bob", "carol")
+	services.CreateTodo(t, 1, "dishes")
+	services.CreateTodo(t, 2, "laundry")
+	users := &countingUsers{UserServicePort: services.Users}
+
+	signer := auth.NewTokenSigner([]byte("secret"), time.Hour)
+	authenticator := auth.NewAuthenticator(auth.WithTokens(signer))
+	handler := NewHandler(services.Todos, users, stream, opts...)
+	r := gin.New()
+	r.Use(httpadapter.ActorMiddleware(authenticator))
+	r.POST("/graphql", handler.Serve)
+	r.GET("/graphql", handler.Serve)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return &graphqlFixture{server: server, signer: signer, todos: services.Todos, users: users}
+}
+
+// gqlResponse is a GraphQL response with the parts the tests look at
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// code returns the first error's code, or "" when there is none
+func (r gqlResponse) code() string {
+	if len(r.Errors) == 0 {
+		return ""
+	}
+	code, _ := r.Errors[0].Extensions["code"].(string)
+	return code
+}
+
+// newRequest builds a POST of the query acting as the user, or anonymously when userID is 0
+func (f *graphqlFixture) newRequest(t *testing.T, userID int, query string) *http.Request {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, f.server.URL+"/graphql", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if userID != 0 {
+		req.Header.Set("Authorization", "Bearer "+f.signer.Issue(userID))
+	}
+	return req
+}
+
+// do runs the query acting as the user and decodes the response
+func (f *graphqlFixture) do(t *testing.T, userID int, query string) gqlResponse {
+	t.Helper()
+	resp, err := http.DefaultClient.Do(f.newRequest(t, userID, query))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var out gqlResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	return out
+}
+
+// compactJSON strips insignificant whitespace so data can be compared as text
+func compactJSON(t *testing.T, data []byte) string {
+	t.Helper()
+	if len(data) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		t.Fatalf("compacting %s: %v", data, err)
+	}
+	return buf.String()
+}
+
+func TestOperations(t *testing.T) {
+	tests := []struct {
+		name  string
+		as    int
+		setup func(t *testing.T, f *graphqlFixture)
+		query string
+
+		wantData    string
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:     "user with their todos",
+			as:       1,
+			query:    `{ user(id: 1) { username todos { title status } } }`,
+			wantData: `{"user":{"username":"alice","todos":[{"title":"dishes","status":"OPEN"}]}}`,
+		},
+		{
+			name:     "todos with their owners",
+			as:       1,
+			query:    `{ todos { title owner { username } } }`,
+			wantData: `{"todos":[{"title":"dishes","owner":{"username":"alice"}},{"title":"laundry","owner":{"username":"bob"}}]}`,
+		},
+		{
+			name:     "missing user",
+			as:       1,
+			query:    `{ user(id: 9) { username } }`,
+			wantData: `null`,
+			wantCode: "NOT_FOUND",
+		},
+		{
+			name:     "create todo",
+			as:       1,
+			query:    `mutation { createTodo(input: {ownerId: 1, title: "groceries", priority: HIGH}) { title priority owner { username } } }`,
+			wantData: `{"createTodo":{"title":"groceries","priority":"HIGH","owner":{"username":"alice"}}}`,
+		},
+		{
+			name:     "blank title",
+			as:       1,
+			query:    `mutation { createTodo(input: {ownerId: 1, title: "  "}) { id } }`,
+			wantData: `null`,
+			wantCode: "BAD_USER_INPUT",
+		},
+		{
+			name:     "duplicate username",
+			as:       1,
+			query:    `mutation { createUser(input: {username: "bob", email: "b@example.com", name: "Bob"}) { id } }`,
+			wantData: `null`,
+			wantCode: "ALREADY_EXISTS",
+		},
+		{
+			name:     "transition",
+			as:       1,
+			query:    `mutation { transitionTodo(id: 1, to: DONE) { status completed } }`,
+			wantData: `{"transitionTodo":{"status":"DONE","completed":true}}`,
+		},
+		{
+			name: "illegal transition",
+			as:   1,
+			setup: func(t *testing.T, f *graphqlFixture) {
+				ctx := domain.ContextWithActor(context.Background(), 1)
+				if _, err := f.todos.TransitionTodo(ctx, 1, &model.TransitionTodoRequest{To: model.TodoStatusCancelled}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			query:    `mutation { transitionTodo(id: 1, to: DONE) { status } }`,
+			wantData: `null`,
+			wantCode: "FAILED_PRECONDITION",
+		},
+		{
+			name:     "trash and restore",
+			as:       1,
+			setup:    func(t *testing.T, f *graphqlFixture) { f.do(t, 1, `mutation { deleteTodo(id: 1) }`) },
+			query:    `{ todos(filter: {deleted: true}) { title } }`,
+			wantData: `{"todos":[{"title":"dishes"}]}`,
+		},
+		{
+			name:     "owner in the trash",
+			as:       1,
+			setup:    func(t *testing.T, f *graphqlFixture) { f.do(t, 2, `mutation { deleteUser(id: 2) }`) },
+			query:    `{ todo(id: 2) { title owner { username } } }`,
+			wantData: `{"todo":{"title":"laundry","owner":null}}`,
+		},
+		{
+			name:        "invalid argument",
+			as:          1,
+			query:       `{ todo(id: 1, asOf: "yesterday") { title } }`,
+			wantData:    `null`,
+			wantMessage: `cannot parse "yesterday"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGraphQLFixture(t)
+			if tt.setup != nil {
+				tt.setup(t, f)
+			}
+
+			resp := f.do(t, tt.as, tt.query)
+			if got := resp.code(); got != tt.wantCode {
+				t.Errorf("error code = %q, want %q (errors: %+v)", got, tt.wantCode, resp.Errors)
+			}
+			if tt.wantMessage != "" && (len(resp.Errors) == 0 || !strings.Contains(resp.Errors[0].Message, tt.wantMessage)) {
+				t.Errorf("errors = %+v, want a message containing %q", resp.Errors, tt.wantMessage)
+			}
+			if got := compactJSON(t, resp.Data); got != tt.wantData {
+				t.Errorf("data = %s, want %s", got, tt.wantData)
+			}
+		})
+	}
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []HandlerOption
+		query       string
+		wantCode    string
+		wantMessage string
+	}{
+		{
+			name:  "within the depth limit",
+			opts:  []HandlerOption{WithMaxDepth(3)},
+			query: `{ todos { owner { username } } }`,
+		},
+		{
+			name:     "too deep",
+			opts:     []HandlerOption{WithMaxDepth(3)},
+			query:    `{ todos { owner { todos { title } } } }`,
+			wantCode: errDepthLimit,
+		},
+		{
+			name:     "too deep through a fragment",
+			opts:     []HandlerOption{WithMaxDepth(3)},
+			query:    `{ todos { ...withOwner } } fragment withOwner on Todo { owner { todos { title } } }`,
+			wantCode: errDepthLimit,
+		},
+		{
+			name:  "introspection fields are not counted",
+			opts:  []HandlerOption{WithMaxDepth(1)},
+			query: `{ __schema { queryType { name } } }`,
+		},
+		{
+			name:  "within the complexity limit",
+			opts:  []HandlerOption{WithMaxComplexity(21)},
+			query: `{ todos { id title } }`,
+		},
+		{
+			name:     "nested lists are too complex",
+			opts:     []HandlerOption{WithMaxComplexity(200)},
+			query:    `{ users { todos { subtasks { id } } } }`,
+			wantCode: "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:        "introspection disabled",
+			opts:        []HandlerOption{WithIntrospection(false)},
+			query:       `{ __schema { queryType { name } } }`,
+			wantMessage: "introspection disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGraphQLFixture(t, tt.opts...)
+			resp := f.do(t, 1, tt.query)
+			if got := resp.code(); got != tt.wantCode {
+				t.Errorf("error code = %q, want %q (errors: %+v)", got, tt.wantCode, resp.Errors)
+			}
+			if tt.wantMessage != "" && (len(resp.Errors) == 0 || resp.Errors[0].Message != tt.wantMessage) {
+				t.Errorf("errors = %+v, want %q", resp.Errors, tt.wantMessage)
+			}
+			if tt.wantCode != "" && string(resp.Data) != "" && string(resp.Data) != "null" {
+				t.Errorf("rejected operation returned data %s", resp.Data)
+			}
+		})
+	}
+}
+
+func TestOwnersAreBatched(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		// wantBatches are the user IDs fetched in each GetUsers call
+		wantBatches string
+	}{
+		{
+			name:        "owners of sibling todos are fetched together",
+			query:       `{ todos { owner { username } } }`,
+			wantBatches: "[[1 2]]",
+		},
+		{
+			name:        "repeated owners are fetched once",
+			query:       `{ a: todo(id: 1) { owner { username } } b: todo(id: 1) { owner { username } } }`,
+			wantBatches: "[[1]]",
+		},
+		{
+			name:        "no owners requested",
+			query:       `{ todos { title } }`,
+			wantBatches: "[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGraphQLFixture(t, WithUserBatching(20*time.Millisecond, 100))
+			if resp := f.do(t, 1, tt.query); len(resp.Errors) > 0 {
+				t.Fatalf("errors: %+v", resp.Errors)
+			}
+			// Lookups join a batch in any order
+			for _, batch := range f.users.batches {
+				slices.Sort(batch)
+			}
+			if got := fmt.Sprint(f.users.batches); got != tt.wantBatches {
+				t.Errorf("batches = %s, want %s", got, tt.wantBatches)
+			}
+		})
+	}
+}
+
+func TestTodoChangedSubscription(t *testing.T) {
+	tests := []struct {
+		name string
+		as   int
+		// wantNext is the first change the user is sent
+		wantNext string
+	}{
+		{
+			name:     "owner sees their change",
+			as:       1,
+			wantNext: `{"todoChanged":{"type":"UPDATED","todoId":"1","todo":{"title":"wash dishes"}}}`,
+		},
+		{
+			name:     "other users see only their own todos",
+			as:       2,
+			wantNext: `{"todoChanged":{"type":"UPDATED","todoId":"2","todo":{"title":"fold laundry"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGraphQLFixture(t)
+			// Both seeded todos were created as changes 1 and 2; resuming after
+			// them replays the updates below
+			for id, title := range map[int]string{1: "wash dishes", 2: "fold laundry"} {
+				ctx := domain.ContextWithActor(context.Background(), id)
+				if _, err := f.todos.UpdateTodo(ctx, id, &model.UpdateTodoRequest{Title: title}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			req := f.newRequest(t, tt.as, `subscription { todoChanged(lastEventId: 2) { type todoId todo { title } } }`).WithContext(ctx)
+			req.Header.Set("Accept", "text/event-stream")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+				t.Fatalf("content type = %q", ct)
+			}
+
+			scanner := bufio.NewScanner(resp.Body)
+			for scanner.Scan() {
+				data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+				if !ok {
+					continue
+				}
+				var next gqlResponse
+				if err := json.Unmarshal([]byte(data), &next); err != nil {
+					t.Fatal(err)
+				}
+				if got := compactJSON(t, next.Data); got != tt.wantNext {
+					t.Errorf("next = %s, want %s", got, tt.wantNext)
+				}
+				return
+			}
+			t.Fatalf("stream ended without a change: %v", scanner.Err())
+		})
+	}
+}
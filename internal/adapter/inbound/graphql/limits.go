@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+
+// listComplexityFactor is how many items a list field is assumed to return
+// when costing a query, since lists are not paginated
+const listComplexityFactor = 10
+
+// depthLimit rejects operations whose fields nest deeper than the limit,
+// before any resolver runs. Introspection fields are not counted.
+type depthLimit struct {
+	limit int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = depthLimit{}
+
+// ExtensionName names the extension
+func (d depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+// Validate accepts any schema
+func (d depthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext measures the operation's depth
+func (d depthLimit) MutateOperationContext(_ context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+	if depth := selectionDepth(op.SelectionSet); depth > d.limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns how deeply fields nest within a selection set,
+// looking through fragments
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range set {
+		var d int
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				d = selectionDepth(sel.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
+
+// complexity costs list fields at listComplexityFactor times their selections
+func complexity() ComplexityRoot {
+	var c ComplexityRoot
+	list := func(childComplexity int) int {
+		return 1 + listComplexityFactor*childComplexity
+	}
+	filteredList := func(childComplexity int, _ *TodoFilter) int {
+		return list(childComplexity)
+	}
+	c.Query.Users = list
+	c.Query.Todos = filteredList
+	c.User.Todos = filteredList
+	c.Todo.Subtasks = list
+	return c
+}
@@ -0,0 +1,104 @@
+package graphql
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// userLoader batches the user lookups of one operation. Resolvers of sibling
+// fields run concurrently, so lookups arriving within the wait window are
+// fetched together with a single GetUsers call instead of one call per todo.
+type userLoader struct {
+	userService port.UserServicePort
+	wait        time.Duration
+	maxBatch    int
+
+	mu    sync.Mutex
+	batch *userBatch
+}
+
+// userBatch is a set of user IDs fetched together
+type userBatch struct {
+	ids   []int
+	users map[int]*model.User
+	err   error
+	done  chan struct{}
+}
+
+// newUserLoader creates a userLoader
+func newUserLoader(userService port.UserServicePort, wait time.Duration, maxBatch int) *userLoader {
+	return &userLoader{
+		userService: userService,
+		wait:        wait,
+		maxBatch:    maxBatch,
+	}
+}
+
+// Load returns the live user with the ID, or ErrNotFound
+func (l *userLoader) Load(ctx context.Context, id int) (*model.User, error) {
+	l.mu.Lock()
+	batch := l.batch
+	if batch == nil {
+		batch = &userBatch{done: make(chan struct{})}
+		l.batch = batch
+		time.AfterFunc(l.wait, func() { l.dispatch(ctx, batch) })
+	}
+	if !slices.Contains(batch.ids, id) {
+		batch.ids = append(batch.ids, id)
+	}
+	if len(batch.ids) >= l.maxBatch {
+		go l.dispatch(ctx, batch)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	user, ok := batch.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return user, nil
+}
+
+// dispatch fetches a batch unless it was already fetched
+func (l *userLoader) dispatch(ctx context.Context, batch *userBatch) {
+	l.mu.Lock()
+	if l.batch != batch {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	users, err := l.userService.GetUsers(ctx, batch.ids)
+	batch.users = make(map[int]*model.User, len(users))
+	for _, user := range users {
+		batch.users[user.ID] = user
+	}
+	batch.err = err
+	close(batch.done)
+}
+
+type loadersKey struct{}
+
+// contextWithUsers returns a copy of ctx carrying the operation's user loader
+func contextWithUsers(ctx context.Context, loader *userLoader) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loader)
+}
+
+// usersFromContext returns the operation's user loader
+func usersFromContext(ctx context.Context) *userLoader {
+	return ctx.Value(loadersKey{}).(*userLoader)
+}
@@ -0,0 +1,32 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphql
+
+import (
+	"go-boilerplate/internal/domain/model"
+	"time"
+)
+
+type Mutation struct {
+}
+
+type Query struct {
+}
+
+type Subscription struct {
+}
+
+type TodoFilter struct {
+	OwnerID   *int              `json:"ownerId,omitempty"`
+	ParentID  *int              `json:"parentId,omitempty"`
+	Completed *bool             `json:"completed,omitempty"`
+	Status    *model.TodoStatus `json:"status,omitempty"`
+	Overdue   *bool             `json:"overdue,omitempty"`
+	DueBefore *time.Time        `json:"dueBefore,omitempty"`
+	DueAfter  *time.Time        `json:"dueAfter,omitempty"`
+	Priority  *model.Priority   `json:"priority,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	TagMatch  *model.TagMatch   `json:"tagMatch,omitempty"`
+	// Selects todos in the trash instead of live todos
+	Deleted *bool `json:"deleted,omitempty"`
+}
@@ -0,0 +1,51 @@
+package graphql
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// Resolver resolves the GraphQL schema through the domain services
+type Resolver struct {
+	todoService   port.TodoServicePort
+	userService   port.UserServicePort
+	streamService port.TodoStreamServicePort
+}
+
+// listTodos lists the live or trashed todos matching the filter. A non-nil
+// ownerID overrides the filter's owner, for todos listed under a user.
+func (r *Resolver) listTodos(ctx context.Context, filter *TodoFilter, ownerID *int) ([]*model.Todo, error) {
+	var f model.TodoFilter
+	deleted := false
+	if filter != nil {
+		f = model.TodoFilter{
+			OwnerID:   filter.OwnerID,
+			ParentID:  filter.ParentID,
+			Completed: filter.Completed,
+			Overdue:   filter.Overdue,
+			DueBefore: filter.DueBefore,
+			DueAfter:  filter.DueAfter,
+			Tags:      filter.Tags,
+		}
+		if filter.Status != nil {
+			f.Status = *filter.Status
+		}
+		if filter.Priority != nil {
+			f.Priority = *filter.Priority
+		}
+		if filter.TagMatch != nil {
+			f.TagMatch = *filter.TagMatch
+		}
+		deleted = filter.Deleted != nil && *filter.Deleted
+	}
+	if ownerID != nil {
+		f.OwnerID = ownerID
+	}
+
+	if deleted {
+		return r.todoService.ListDeletedTodos(ctx, f)
+	}
+	return r.todoService.ListTodos(ctx, f)
+}
@@ -0,0 +1,182 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"context"
+	"errors"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"time"
+)
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input model.CreateUserRequest) (*model.User, error) {
+	return r.userService.CreateUser(ctx, &input)
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, id int, input model.UpdateUserRequest) (*model.User, error) {
+	return r.userService.UpdateUser(ctx, id, &input)
+}
+
+// RenameUser is the resolver for the renameUser field.
+func (r *mutationResolver) RenameUser(ctx context.Context, id int, username string) (*model.User, error) {
+	return r.userService.RenameUser(ctx, id, &model.RenameUserRequest{Username: username})
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id int) (bool, error) {
+	if err := r.userService.DeleteUser(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RestoreUser is the resolver for the restoreUser field.
+func (r *mutationResolver) RestoreUser(ctx context.Context, id int) (*model.User, error) {
+	return r.userService.RestoreUser(ctx, id)
+}
+
+// CreateTodo is the resolver for the createTodo field.
+func (r *mutationResolver) CreateTodo(ctx context.Context, input model.CreateTodoRequest) (*model.Todo, error) {
+	return r.todoService.CreateTodo(ctx, &input)
+}
+
+// UpdateTodo is the resolver for the updateTodo field.
+func (r *mutationResolver) UpdateTodo(ctx context.Context, id int, input model.UpdateTodoRequest) (*model.Todo, error) {
+	return r.todoService.UpdateTodo(ctx, id, &input)
+}
+
+// DeleteTodo is the resolver for the deleteTodo field.
+func (r *mutationResolver) DeleteTodo(ctx context.Context, id int) (bool, error) {
+	if err := r.todoService.DeleteTodo(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RestoreTodo is the resolver for the restoreTodo field.
+func (r *mutationResolver) RestoreTodo(ctx context.Context, id int) (*model.Todo, error) {
+	return r.todoService.RestoreTodo(ctx, id)
+}
+
+// TransitionTodo is the resolver for the transitionTodo field.
+func (r *mutationResolver) TransitionTodo(ctx context.Context, id int, to model.TodoStatus, reason *string) (*model.Todo, error) {
+	req := &model.TransitionTodoRequest{To: to}
+	if reason != nil {
+		req.Reason = *reason
+	}
+	return r.todoService.TransitionTodo(ctx, id, req)
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id int) (*model.User, error) {
+	return r.userService.GetUser(ctx, id)
+}
+
+// UserByUsername is the resolver for the userByUsername field.
+func (r *queryResolver) UserByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.userService.GetUserByUsername(ctx, username)
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
+	return r.userService.ListUsers(ctx)
+}
+
+// Todo is the resolver for the todo field.
+func (r *queryResolver) Todo(ctx context.Context, id int, asOf *time.Time) (*model.Todo, error) {
+	if asOf != nil {
+		return r.todoService.GetTodoAsOf(ctx, id, *asOf)
+	}
+	return r.todoService.GetTodo(ctx, id)
+}
+
+// Todos is the resolver for the todos field.
+func (r *queryResolver) Todos(ctx context.Context, filter *TodoFilter) ([]*model.Todo, error) {
+	return r.listTodos(ctx, filter, nil)
+}
+
+// TodoChanged is the resolver for the todoChanged field.
+func (r *subscriptionResolver) TodoChanged(ctx context.Context, lastEventID *int) (<-chan *model.TodoStreamEvent, error) {
+	sub, err := r.streamService.Subscribe(ctx, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan *model.TodoStreamEvent)
+	go func() {
+		defer close(changes)
+		defer sub.Close()
+
+		send := func(event model.TodoStreamEvent) bool {
+			select {
+			case changes <- &event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, event := range sub.Replay() {
+			if !send(event) {
+				return
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-sub.Changes():
+				// A closed channel means the subscriber fell too far behind;
+				// ending the subscription lets the client resume
+				if !ok || !send(event) {
+					return
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// Owner is the resolver for the owner field.
+func (r *todoResolver) Owner(ctx context.Context, obj *model.Todo) (*model.User, error) {
+	user, err := usersFromContext(ctx).Load(ctx, obj.OwnerID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// Subtasks is the resolver for the subtasks field.
+func (r *todoResolver) Subtasks(ctx context.Context, obj *model.Todo) ([]*model.Todo, error) {
+	return r.todoService.ListSubtasks(ctx, obj.ID)
+}
+
+// Todos is the resolver for the todos field.
+func (r *userResolver) Todos(ctx context.Context, obj *model.User, filter *TodoFilter) ([]*model.Todo, error) {
+	return r.listTodos(ctx, filter, &obj.ID)
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// Todo returns TodoResolver implementation.
+func (r *Resolver) Todo() TodoResolver { return &todoResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type todoResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/grpc/pb"
+	"go-boilerplate/internal/domain/model"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toTimestamp converts an optional time to a protobuf timestamp
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// fromTimestamp converts an optional protobuf timestamp to a time
+func fromTimestamp(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// toInt64Ptr converts an optional ID to its protobuf form
+func toInt64Ptr(id *int) *int64 {
+	if id == nil {
+		return nil
+	}
+	v := int64(*id)
+	return &v
+}
+
+// fromInt64Ptr converts an optional protobuf ID to an int
+func fromInt64Ptr(id *int64) *int {
+	if id == nil {
+		return nil
+	}
+	v := int(*id)
+	return &v
+}
+
+// toPBTodo converts a todo to its protobuf message
+func toPBTodo(todo *model.Todo) *pb.Todo {
+	msg := &pb.Todo{
+		Id:          int64(todo.ID),
+		OwnerId:     int64(todo.OwnerID),
+		ParentId:    toInt64Ptr(todo.ParentID),
+		ListId:      toInt64Ptr(todo.ListID),
+		Title:       todo.Title,
+		Description: todo.Description,
+		Completed:   todo.Completed,
+		Status:      string(todo.Status),
+		Priority:    string(todo.Priority),
+		DueDate:     toTimestamp(todo.DueDate),
+		Timezone:    todo.Timezone,
+		TagIds:      make([]int64, len(todo.TagIDs)),
+		Checklist:   make([]*pb.ChecklistItem, len(todo.Checklist)),
+		Progress:    todo.Progress,
+		CreatedAt:   timestamppb.New(todo.CreatedAt),
+		UpdatedAt:   timestamppb.New(todo.UpdatedAt),
+		CompletedAt: toTimestamp(todo.CompletedAt),
+		DeletedAt:   toTimestamp(todo.DeletedAt),
+	}
+	for i, tagID := range todo.TagIDs {
+		msg.TagIds[i] = int64(tagID)
+	}
+	for i, item := range todo.Checklist {
+		msg.Checklist[i] = &pb.ChecklistItem{Id: int64(item.ID), Text: item.Text, Checked: item.Checked}
+	}
+	return msg
+}
+
+// toPBUser converts a user to its protobuf message
+func toPBUser(user *model.User) *pb.User {
+	return &pb.User{
+		Id:        int64(user.ID),
+		Username:  user.Username,
+		Email:     user.Email,
+		Name:      user.Name,
+		DeletedAt: toTimestamp(user.DeletedAt),
+	}
+}
+
+// fromPBTodoFilter builds a TodoFilter from a list request
+func fromPBTodoFilter(req *pb.ListTodosRequest) model.TodoFilter {
+	return model.TodoFilter{
+		OwnerID:   fromInt64Ptr(req.OwnerId),
+		ParentID:  fromInt64Ptr(req.ParentId),
+		Completed: req.Completed,
+		Overdue:   req.Overdue,
+		DueBefore: fromTimestamp(req.DueBefore),
+		DueAfter:  fromTimestamp(req.DueAfter),
+		Priority:  model.Priority(req.Priority),
+		Status:    model.TodoStatus(req.Status),
+		Tags:      req.Tags,
+		TagMatch:  model.TagMatch(req.TagMatch),
+	}
+}
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"errors"
+
+	"go-boilerplate/internal/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorCodes maps domain errors to the gRPC status codes they are reported with
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	{domain.ErrNotFound, codes.NotFound},
+	{domain.ErrDuplicate, codes.AlreadyExists},
+	{domain.ErrUsernameDuplicate, codes.AlreadyExists},
+	{domain.ErrUnauthenticated, codes.Unauthenticated},
+	{domain.ErrForbidden, codes.PermissionDenied},
+	{domain.ErrHistoryUnavailable, codes.Unimplemented},
+
+	{domain.ErrInvalidTodoTitle, codes.InvalidArgument},
+	{domain.ErrInvalidPriority, codes.InvalidArgument},
+	{domain.ErrInvalidTimezone, codes.InvalidArgument},
+	{domain.ErrDueDateInPast, codes.InvalidArgument},
+	{domain.ErrInvalidTodoStatus, codes.InvalidArgument},
+	{domain.ErrTagOwnerMismatch, codes.InvalidArgument},
+	{domain.ErrInvalidTagMatch, codes.InvalidArgument},
+	{domain.ErrInvalidParentTodo, codes.InvalidArgument},
+	{domain.ErrInvalidChecklistItem, codes.InvalidArgument},
+	{domain.ErrInvalidRecurrence, codes.InvalidArgument},
+	{domain.ErrRecurrenceRequiresDueDate, codes.InvalidArgument},
+	{domain.ErrInvalidUsername, codes.InvalidArgument},
+	{domain.ErrInvalidEmail, codes.InvalidArgument},
+	{domain.ErrUsernameUnchanged, codes.InvalidArgument},
+
+	{domain.ErrTodoAlreadyCompleted, codes.FailedPrecondition},
+	{domain.ErrIllegalTransition, codes.FailedPrecondition},
+	{domain.ErrTodoCycle, codes.FailedPrecondition},
+	{domain.ErrMaxDepthExceeded, codes.FailedPrecondition},
+	{domain.ErrOpenSubtasks, codes.FailedPrecondition},
+	{domain.ErrClosedParent, codes.FailedPrecondition},
+	{domain.ErrNotRecurring, codes.FailedPrecondition},
+	{domain.ErrUsernameReserved, codes.FailedPrecondition},
+	{domain.ErrUsernameInTrash, codes.FailedPrecondition},
+}
+
+// toStatus converts a domain error into a gRPC status error. Errors the
+// domain does not define are reported as internal without their details.
+func toStatus(err error) error {
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return status.Error(mapping.code, mapping.err.Error())
+		}
+	}
+	return status.Error(codes.Internal, "internal server error")
+}
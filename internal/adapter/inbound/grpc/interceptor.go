@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/domain"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ActorMetadataKey carries the ID of the user a call acts on behalf of. Like
+// the X-User-ID header it is not authentication and is only honored when the
+// server allows it for development.
+const ActorMetadataKey = "x-user-id"
+
+// AuthorizationMetadataKey carries the bearer token authenticating a call
+const AuthorizationMetadataKey = "authorization"
+
+// RequestIDMetadataKey carries the ID correlating a call across logs and the audit trail
+const RequestIDMetadataKey = "x-request-id"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// callContext puts the authenticated user and a request ID from the call
+// metadata into the context, like the HTTP middleware does for headers. Calls
+// without credentials continue anonymously and are rejected by services that
+// need a user.
+func (i *interceptor) callContext(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	userID, ok, err := i.authenticator.Authenticate(firstValue(md, AuthorizationMetadataKey), firstValue(md, ActorMetadataKey))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidActor) {
+			return nil, status.Error(codes.InvalidArgument, "invalid "+ActorMetadataKey+" metadata")
+		}
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if ok {
+		ctx = domain.ContextWithActor(ctx, userID)
+	}
+
+	var requestID string
+	if values := md.Get(RequestIDMetadataKey); len(values) > 0 && len(values[0]) <= maxRequestIDLength {
+		requestID = values[0]
+	}
+	if requestID == "" {
+		buf := make([]byte, 16)
+		_, _ = rand.Read(buf)
+		requestID = hex.EncodeToString(buf)
+	}
+	return domain.ContextWithRequestID(ctx, requestID), nil
+}
+
+// firstValue returns the first value of a metadata key, or "" if it is unset
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// interceptor puts the call context in place for every call
+type interceptor struct {
+	authenticator *auth.Authenticator
+}
+
+// unaryCallContext applies callContext to unary calls and echoes the request ID
+func (i *interceptor) unaryCallContext(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := i.callContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	requestID := domain.RequestIDFromContext(ctx)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDMetadataKey, requestID))
+	return handler(ctx, req)
+}
+
+// contextStream overrides the context of a server stream
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the overridden context
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// streamCallContext applies callContext to streaming calls and echoes the request ID
+func (i *interceptor) streamCallContext(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := i.callContext(ss.Context())
+	if err != nil {
+		return err
+	}
+	requestID := domain.RequestIDFromContext(ctx)
+	_ = ss.SetHeader(metadata.Pairs(RequestIDMetadataKey, requestID))
+	return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,25 @@
+package grpc
+
+import (
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/adapter/inbound/grpc/pb"
+	"go-boilerplate/internal/domain/port"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer creates a gRPC server exposing the todo and user services, with
+// server reflection enabled for tools such as grpcurl. Calls authenticate
+// with the authenticator.
+func NewServer(todoService port.TodoServicePort, userService port.UserServicePort, authenticator *auth.Authenticator) *grpc.Server {
+	i := &interceptor{authenticator: authenticator}
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(i.unaryCallContext),
+		grpc.ChainStreamInterceptor(i.streamCallContext),
+	)
+	pb.RegisterTodoServiceServer(srv, NewTodoServer(todoService))
+	pb.RegisterUserServiceServer(srv, NewUserServer(userService))
+	reflection.Register(srv)
+	return srv
+}
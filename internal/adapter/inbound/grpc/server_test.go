@@ -0,0 +1,409 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/adapter/inbound/grpc/pb"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/service/servicetest"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcFixture serves the gRPC API over an in-memory connection. User 1 owns
+// todo 1 on a list shared with user 2 as a viewer, and todo 2 outside any
+// list. User 3 is not on the list.
+type grpcFixture struct {
+	todos  pb.TodoServiceClient
+	users  pb.UserServiceClient
+	signer *auth.TokenSigner
+}
+
+// newGRPCFixture starts a server on top of in-memory stores
+func newGRPCFixture(t *testing.T) *grpcFixture {
+	t.Helper()
+	services := servicetest.New(nil)
+	services.CreateUsers(t, "alice", "bob")
+	list := services.CreateSharedList(t, 1, "chores", map[int]model.ListRole{2: model.ListRoleViewer})
+	shared := services.CreateTodo(t, 1, "shared")
+	services.CreateTodo(t, 1, "private")
+	if _, err := services.Lists.AddTodo(domain.ContextWithActor(context.Background(), 1), list.ID, shared.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	signer := auth.NewTokenSigner([]byte("secret"), time.Hour)
+	srv := NewServer(services.Todos, services.Users, auth.NewAuthenticator(auth.WithTokens(signer)))
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return &grpcFixture{
+		todos:  pb.NewTodoServiceClient(conn),
+		users:  pb.NewUserServiceClient(conn),
+		signer: signer,
+	}
+}
+
+// as returns a context authenticated as the user
+func (f *grpcFixture) as(userID int) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadataKey, "Bearer "+f.signer.Issue(userID))
+}
+
+// listTodoIDs collects the IDs a ListTodos stream sends
+func listTodoIDs(stream grpc.ServerStreamingClient[pb.Todo]) ([]int64, error) {
+	var ids []int64
+	for {
+		todo, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return ids, nil
+		}
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, todo.Id)
+	}
+}
+
+func TestTodoServer(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(f *grpcFixture) (string, error)
+		want string
+		// wantCode is the status code the call fails with, or OK
+		wantCode codes.Code
+	}{
+		{
+			name: "create todo",
+			call: func(f *grpcFixture) (string, error) {
+				todo, err := f.todos.CreateTodo(f.as(1), &pb.CreateTodoRequest{OwnerId: 1, Title: "write docs", Priority: "high"})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d %s %s %s", todo.Id, todo.Title, todo.Status, todo.Priority), nil
+			},
+			want: "3 write docs open high",
+		},
+		{
+			name: "blank title",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.todos.CreateTodo(f.as(1), &pb.CreateTodoRequest{OwnerId: 1, Title: " "})
+				return "", err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "get missing todo",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.todos.GetTodo(f.as(1), &pb.GetTodoRequest{Id: 99})
+				return "", err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "viewer reads a shared todo",
+			call: func(f *grpcFixture) (string, error) {
+				todo, err := f.todos.GetTodo(f.as(2), &pb.GetTodoRequest{Id: 1})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s list %d", todo.Title, todo.GetListId()), nil
+			},
+			want: "shared list 1",
+		},
+		{
+			name: "anonymous read of a shared todo",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.todos.GetTodo(context.Background(), &pb.GetTodoRequest{Id: 1})
+				return "", err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "forged token",
+			call: func(f *grpcFixture) (string, error) {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), AuthorizationMetadataKey, "Bearer 1.9999999999.forged")
+				_, err := f.todos.GetTodo(ctx, &pb.GetTodoRequest{Id: 2})
+				return "", err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "actor metadata is not authentication",
+			call: func(f *grpcFixture) (string, error) {
+				ctx := metadata.AppendToOutgoingContext(context.Background(), ActorMetadataKey, "1")
+				_, err := f.todos.GetTodo(ctx, &pb.GetTodoRequest{Id: 2})
+				return "", err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "viewer cannot edit a shared todo",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.todos.UpdateTodo(f.as(2), &pb.UpdateTodoRequest{Id: 1, Title: "mine now"})
+				return "", err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name: "owner completes a todo",
+			call: func(f *grpcFixture) (string, error) {
+				todo, err := f.todos.TransitionTodo(f.as(1), &pb.TransitionTodoRequest{Id: 1, To: "done"})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s %v %v", todo.Status, todo.Completed, todo.CompletedAt != nil), nil
+			},
+			want: "done true true",
+		},
+		{
+			name: "illegal transition",
+			call: func(f *grpcFixture) (string, error) {
+				if _, err := f.todos.TransitionTodo(f.as(1), &pb.TransitionTodoRequest{Id: 1, To: "cancelled"}); err != nil {
+					return "", err
+				}
+				_, err := f.todos.TransitionTodo(f.as(1), &pb.TransitionTodoRequest{Id: 1, To: "done"})
+				return "", err
+			},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "viewer lists the shared todo",
+			call: func(f *grpcFixture) (string, error) {
+				stream, err := f.todos.ListTodos(f.as(2), &pb.ListTodosRequest{})
+				if err != nil {
+					return "", err
+				}
+				ids, err := listTodoIDs(stream)
+				return fmt.Sprint(ids), err
+			},
+			want: "[1 2]",
+		},
+		{
+			name: "list hides todos on lists the caller is not on",
+			call: func(f *grpcFixture) (string, error) {
+				stream, err := f.todos.ListTodos(f.as(3), &pb.ListTodosRequest{})
+				if err != nil {
+					return "", err
+				}
+				ids, err := listTodoIDs(stream)
+				return fmt.Sprint(ids), err
+			},
+			want: "[2]",
+		},
+		{
+			name: "delete and list the trash",
+			call: func(f *grpcFixture) (string, error) {
+				if _, err := f.todos.DeleteTodo(f.as(1), &pb.DeleteTodoRequest{Id: 2}); err != nil {
+					return "", err
+				}
+				stream, err := f.todos.ListTodos(f.as(1), &pb.ListTodosRequest{Deleted: true})
+				if err != nil {
+					return "", err
+				}
+				ids, err := listTodoIDs(stream)
+				return fmt.Sprint(ids), err
+			},
+			want: "[2]",
+		},
+		{
+			name: "restore",
+			call: func(f *grpcFixture) (string, error) {
+				if _, err := f.todos.DeleteTodo(f.as(1), &pb.DeleteTodoRequest{Id: 2}); err != nil {
+					return "", err
+				}
+				todo, err := f.todos.RestoreTodo(f.as(1), &pb.RestoreTodoRequest{Id: 2})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d %v", todo.Id, todo.DeletedAt == nil), nil
+			},
+			want: "2 true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGRPCFixture(t)
+
+			got, err := tt.call(f)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s (%v), want %s", code, err, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserServer(t *testing.T) {
+	tests := []struct {
+		name     string
+		call     func(f *grpcFixture) (string, error)
+		want     string
+		wantCode codes.Code
+	}{
+		{
+			name: "create and get by username",
+			call: func(f *grpcFixture) (string, error) {
+				if _, err := f.users.CreateUser(f.as(1), &pb.CreateUserRequest{Username: "carol", Email: "carol@example.com", Name: "Carol"}); err != nil {
+					return "", err
+				}
+				user, err := f.users.GetUserByUsername(f.as(1), &pb.GetUserByUsernameRequest{Username: "carol"})
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d %s %s", user.Id, user.Username, user.Email), nil
+			},
+			want: "3 carol carol@example.com",
+		},
+		{
+			name: "duplicate username",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.users.CreateUser(f.as(1), &pb.CreateUserRequest{Username: "alice", Email: "other@example.com", Name: "Alice"})
+				return "", err
+			},
+			wantCode: codes.AlreadyExists,
+		},
+		{
+			name: "invalid username",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.users.CreateUser(f.as(1), &pb.CreateUserRequest{Username: "da/ve", Email: "dave@example.com", Name: "Dave"})
+				return "", err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "rename",
+			call: func(f *grpcFixture) (string, error) {
+				user, err := f.users.RenameUser(f.as(1), &pb.RenameUserRequest{Id: 1, Username: "alicia"})
+				if err != nil {
+					return "", err
+				}
+				return user.Username, nil
+			},
+			want: "alicia",
+		},
+		{
+			name: "list users",
+			call: func(f *grpcFixture) (string, error) {
+				stream, err := f.users.ListUsers(f.as(1), &pb.ListUsersRequest{})
+				if err != nil {
+					return "", err
+				}
+				var names []string
+				for {
+					user, err := stream.Recv()
+					if errors.Is(err, io.EOF) {
+						return fmt.Sprint(names), nil
+					}
+					if err != nil {
+						return "", err
+					}
+					names = append(names, user.Username)
+				}
+			},
+			want: "[alice bob]",
+		},
+		{
+			name: "get missing user",
+			call: func(f *grpcFixture) (string, error) {
+				_, err := f.users.GetUser(f.as(1), &pb.GetUserRequest{Id: 99})
+				return "", err
+			},
+			wantCode: codes.NotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newGRPCFixture(t)
+
+			got, err := tt.call(f)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("code = %s (%v), want %s", code, err, tt.wantCode)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestIDMetadata(t *testing.T) {
+	f := newGRPCFixture(t)
+	tests := []struct {
+		name      string
+		requestID string
+		wantEcho  bool
+	}{
+		{name: "client request ID is echoed", requestID: "req-42", wantEcho: true},
+		{name: "missing request ID is generated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := f.as(1)
+			if tt.requestID != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, RequestIDMetadataKey, tt.requestID)
+			}
+			var header metadata.MD
+			if _, err := f.todos.GetTodo(ctx, &pb.GetTodoRequest{Id: 2}, grpc.Header(&header)); err != nil {
+				t.Fatal(err)
+			}
+			got := firstValue(header, RequestIDMetadataKey)
+			if tt.wantEcho && got != tt.requestID {
+				t.Errorf("request ID = %q, want %q", got, tt.requestID)
+			}
+			if !tt.wantEcho && len(got) != 32 {
+				t.Errorf("generated request ID = %q, want 32 hex digits", got)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{name: "not found", err: domain.ErrNotFound, wantCode: codes.NotFound, wantMessage: domain.ErrNotFound.Error()},
+		{name: "wrapped", err: fmt.Errorf("creating todo: %w", domain.ErrInvalidTodoTitle), wantCode: codes.InvalidArgument, wantMessage: domain.ErrInvalidTodoTitle.Error()},
+		{name: "forbidden", err: domain.ErrForbidden, wantCode: codes.PermissionDenied, wantMessage: domain.ErrForbidden.Error()},
+		{name: "precondition", err: domain.ErrIllegalTransition, wantCode: codes.FailedPrecondition, wantMessage: domain.ErrIllegalTransition.Error()},
+		{name: "unknown errors hide their details", err: errors.New("disk on fire"), wantCode: codes.Internal, wantMessage: "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(toStatus(tt.err))
+			if st.Code() != tt.wantCode || st.Message() != tt.wantMessage {
+				t.Errorf("toStatus(%v) = %s %q, want %s %q", tt.err, st.Code(), st.Message(), tt.wantCode, tt.wantMessage)
+			}
+		})
+	}
+}
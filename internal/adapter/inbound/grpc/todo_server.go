@@ -0,0 +1,135 @@
+package grpc
+
+import (
+	"context"
+
+	"go-boilerplate/internal/adapter/inbound/grpc/pb"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TodoServer implements the TodoService gRPC service on top of the todo service port
+type TodoServer struct {
+	pb.UnimplementedTodoServiceServer
+	todoService port.TodoServicePort
+}
+
+// NewTodoServer creates a new TodoServer
+func NewTodoServer(todoService port.TodoServicePort) *TodoServer {
+	return &TodoServer{
+		todoService: todoService,
+	}
+}
+
+// CreateTodo creates a new todo
+func (s *TodoServer) CreateTodo(ctx context.Context, req *pb.CreateTodoRequest) (*pb.Todo, error) {
+	todo, err := s.todoService.CreateTodo(ctx, &model.CreateTodoRequest{
+		OwnerID:          int(req.OwnerId),
+		ParentID:         fromInt64Ptr(req.ParentId),
+		Title:            req.Title,
+		Description:      req.Description,
+		Priority:         model.Priority(req.Priority),
+		DueDate:          fromTimestamp(req.DueDate),
+		Timezone:         req.Timezone,
+		AllowPastDueDate: req.AllowPastDueDate,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTodo(todo), nil
+}
+
+// GetTodo retrieves a todo, or its state at as_of when set
+func (s *TodoServer) GetTodo(ctx context.Context, req *pb.GetTodoRequest) (*pb.Todo, error) {
+	var (
+		todo *model.Todo
+		err  error
+	)
+	if asOf := fromTimestamp(req.AsOf); asOf != nil {
+		todo, err = s.todoService.GetTodoAsOf(ctx, int(req.Id), *asOf)
+	} else {
+		todo, err = s.todoService.GetTodo(ctx, int(req.Id))
+	}
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTodo(todo), nil
+}
+
+// ListTodos streams the todos matching the filter
+func (s *TodoServer) ListTodos(req *pb.ListTodosRequest, stream grpc.ServerStreamingServer[pb.Todo]) error {
+	ctx := stream.Context()
+	filter := fromPBTodoFilter(req)
+
+	var (
+		todos []*model.Todo
+		err   error
+	)
+	if req.Deleted {
+		todos, err = s.todoService.ListDeletedTodos(ctx, filter)
+	} else {
+		todos, err = s.todoService.ListTodos(ctx, filter)
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for _, todo := range todos {
+		if err := stream.Send(toPBTodo(todo)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateTodo updates an existing todo
+func (s *TodoServer) UpdateTodo(ctx context.Context, req *pb.UpdateTodoRequest) (*pb.Todo, error) {
+	todo, err := s.todoService.UpdateTodo(ctx, int(req.Id), &model.UpdateTodoRequest{
+		Title:            req.Title,
+		Description:      req.Description,
+		Completed:        req.Completed,
+		Priority:         model.Priority(req.Priority),
+		DueDate:          fromTimestamp(req.DueDate),
+		Timezone:         req.Timezone,
+		ClearDueDate:     req.ClearDueDate,
+		ParentID:         fromInt64Ptr(req.ParentId),
+		ClearParent:      req.ClearParent,
+		AllowPastDueDate: req.AllowPastDueDate,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTodo(todo), nil
+}
+
+// DeleteTodo moves a todo to the trash
+func (s *TodoServer) DeleteTodo(ctx context.Context, req *pb.DeleteTodoRequest) (*emptypb.Empty, error) {
+	if err := s.todoService.DeleteTodo(ctx, int(req.Id)); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreTodo moves a todo out of the trash
+func (s *TodoServer) RestoreTodo(ctx context.Context, req *pb.RestoreTodoRequest) (*pb.Todo, error) {
+	todo, err := s.todoService.RestoreTodo(ctx, int(req.Id))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTodo(todo), nil
+}
+
+// TransitionTodo moves a todo to another status
+func (s *TodoServer) TransitionTodo(ctx context.Context, req *pb.TransitionTodoRequest) (*pb.Todo, error) {
+	todo, err := s.todoService.TransitionTodo(ctx, int(req.Id), &model.TransitionTodoRequest{
+		To:     model.TodoStatus(req.To),
+		Reason: req.Reason,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBTodo(todo), nil
+}
@@ -0,0 +1,121 @@
+package grpc
+
+import (
+	"context"
+
+	"go-boilerplate/internal/adapter/inbound/grpc/pb"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// UserServer implements the UserService gRPC service on top of the user service port
+type UserServer struct {
+	pb.UnimplementedUserServiceServer
+	userService port.UserServicePort
+}
+
+// NewUserServer creates a new UserServer
+func NewUserServer(userService port.UserServicePort) *UserServer {
+	return &UserServer{
+		userService: userService,
+	}
+}
+
+// CreateUser creates a new user
+func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.User, error) {
+	user, err := s.userService.CreateUser(ctx, &model.CreateUserRequest{
+		Username: req.Username,
+		Email:    req.Email,
+		Name:     req.Name,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+// GetUser retrieves a user by ID
+func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	user, err := s.userService.GetUser(ctx, int(req.Id))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+// GetUserByUsername retrieves a user by current or recently released username
+func (s *UserServer) GetUserByUsername(ctx context.Context, req *pb.GetUserByUsernameRequest) (*pb.User, error) {
+	user, err := s.userService.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+// ListUsers streams the live users, or the trashed ones when deleted is set
+func (s *UserServer) ListUsers(req *pb.ListUsersRequest, stream grpc.ServerStreamingServer[pb.User]) error {
+	ctx := stream.Context()
+
+	var (
+		users []*model.User
+		err   error
+	)
+	if req.Deleted {
+		users, err = s.userService.ListDeletedUsers(ctx)
+	} else {
+		users, err = s.userService.ListUsers(ctx)
+	}
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for _, user := range users {
+		if err := stream.Send(toPBUser(user)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateUser updates a user's email and name
+func (s *UserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.User, error) {
+	user, err := s.userService.UpdateUser(ctx, int(req.Id), &model.UpdateUserRequest{
+		Email: req.Email,
+		Name:  req.Name,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+// RenameUser changes a user's username
+func (s *UserServer) RenameUser(ctx context.Context, req *pb.RenameUserRequest) (*pb.User, error) {
+	user, err := s.userService.RenameUser(ctx, int(req.Id), &model.RenameUserRequest{
+		Username: req.Username,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+// DeleteUser moves a user to the trash
+func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.userService.DeleteUser(ctx, int(req.Id)); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RestoreUser moves a user out of the trash
+func (s *UserServer) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.User, error) {
+	user, err := s.userService.RestoreUser(ctx, int(req.Id))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
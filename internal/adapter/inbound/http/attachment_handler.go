@@ -0,0 +1,240 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uploadOverhead is the room left in an upload request for the multipart
+// framing around the file
+const uploadOverhead = 64 << 10
+
+// AttachmentHandler handles HTTP requests for todo attachments
+type AttachmentHandler struct {
+	attachmentService port.AttachmentServicePort
+	maxSize           int64
+}
+
+// NewAttachmentHandler creates a new AttachmentHandler that stops reading an
+// upload once its file could no longer fit in maxSize bytes
+func NewAttachmentHandler(attachmentService port.AttachmentServicePort, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		maxSize:           maxSize,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *AttachmentHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
+	case errors.Is(err, domain.ErrInvalidAttachmentName):
+		return http.StatusBadRequest, "Attachment file name cannot be empty"
+	case errors.Is(err, domain.ErrAttachmentTooLarge):
+		return http.StatusRequestEntityTooLarge, "Attachment is too large"
+	case errors.Is(err, domain.ErrAttachmentTypeNotAllowed):
+		return http.StatusUnsupportedMediaType, "Attachment type is not allowed"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// parseTodoAndAttachmentIDs reads the :id and :attachment_id path parameters
+func parseTodoAndAttachmentIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	attachmentID, err := strconv.Atoi(c.Param("attachment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attachment id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	return id, attachmentID, true
+}
+
+// UploadAttachment handles POST /todos/:id/attachments
+// @Summary Upload an attachment
+// @Description Attach a file to a todo. The content type is detected from the file content and checked against the allowed types.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param file formData file true "File to attach"
+// @Success 201 {object} v1.Attachment
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 413 {object} map[string]string "Request Entity Too Large"
+// @Failure 415 {object} map[string]string "Unsupported Media Type"
+// @Router /v1/todos/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxSize+uploadOverhead)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			statusCode, message := h.mapDomainErrorToHTTP(domain.ErrAttachmentTooLarge)
+			c.JSON(statusCode, gin.H{"error": message, "code": errorCode(domain.ErrAttachmentTooLarge)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required", "code": codeInvalidRequest})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), id, &model.UploadAttachmentRequest{
+		Filename: header.Filename,
+		Size:     header.Size,
+		Content:  file,
+	})
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewAttachment(attachment))
+}
+
+// ListAttachments handles GET /todos/:id/attachments
+// @Summary List attachments
+// @Description Get the files attached to a todo
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {array} v1.Attachment
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/attachments [get]
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	attachments, err := h.attachmentService.ListAttachments(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewAttachments(attachments))
+}
+
+// GetAttachment handles GET /todos/:id/attachments/:attachment_id
+// @Summary Get an attachment
+// @Description Get the metadata of a file attached to a todo
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} v1.Attachment
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) GetAttachment(c *gin.Context) {
+	id, attachmentID, ok := parseTodoAndAttachmentIDs(c)
+	if !ok {
+		return
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewAttachment(attachment))
+}
+
+// DownloadAttachment handles GET /todos/:id/attachments/:attachment_id/content
+// @Summary Download an attachment
+// @Description Stream the content of a file attached to a todo. Supports Range requests.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Todo ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Param Range header string false "Byte range, e.g. bytes=0-1023"
+// @Success 200 {file} file
+// @Success 206 {file} file "Partial Content"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 416 {string} string "Range Not Satisfiable"
+// @Router /v1/todos/{id}/attachments/{attachment_id}/content [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	id, attachmentID, ok := parseTodoAndAttachmentIDs(c)
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), id, attachmentID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+	defer content.Close()
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	c.Header("ETag", strconv.Quote(attachment.SHA256))
+	// ServeContent answers Range and conditional requests from the seekable content
+	http.ServeContent(c.Writer, c.Request, attachment.Filename, attachment.CreatedAt, content)
+}
+
+// DeleteAttachment handles DELETE /todos/:id/attachments/:attachment_id
+// @Summary Delete an attachment
+// @Description Remove a file from a todo
+// @Tags attachments
+// @Param id path int true "Todo ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	id, attachmentID, ok := parseTodoAndAttachmentIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), id, attachmentID); err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
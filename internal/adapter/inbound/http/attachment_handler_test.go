@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/adapter/outbound/storage"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// endlessFile is a multipart form whose file part never ends
+func endlessFile() (io.Reader, string) {
+	const boundary = "upload-boundary"
+	head := "--" + boundary + "\r\n" +
+		`Content-Disposition: form-data; name="file"; filename="notes.txt"` + "\r\n" +
+		"Content-Type: text/plain\r\n\r\n"
+	return io.MultiReader(strings.NewReader(head), neverEnding('a')), "multipart/form-data; boundary=" + boundary
+}
+
+// neverEnding yields the same byte forever
+type neverEnding byte
+
+func (b neverEnding) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(b)
+	}
+	return len(p), nil
+}
+
+func TestUploadAttachmentSizeLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const maxSize = 1 << 10
+
+	tests := []struct {
+		name       string
+		body       func(t *testing.T) (io.Reader, string)
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "within the limit",
+			body: func(t *testing.T) (io.Reader, string) {
+				body, contentType := uploadBody(t, "notes.txt", strings.Repeat("a", maxSize))
+				return strings.NewReader(body), contentType
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "file over the limit",
+			body: func(t *testing.T) (io.Reader, string) {
+				body, contentType := uploadBody(t, "notes.txt", strings.Repeat("a", maxSize+1))
+				return strings.NewReader(body), contentType
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "attachment_too_large",
+		},
+		{
+			name:       "body that never ends",
+			body:       func(t *testing.T) (io.Reader, string) { return endlessFile() },
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "attachment_too_large",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blobs, err := storage.NewLocalBlobStorage(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+			todoRepo := persistence.NewTodoRepository()
+			if err := todoRepo.Create(context.Background(), &model.Todo{OwnerID: 1, Title: "t"}); err != nil {
+				t.Fatal(err)
+			}
+			attachments := service.NewAttachmentService(persistence.NewAttachmentRepository(), todoRepo, blobs, service.WithMaxAttachmentSize(maxSize))
+			r := gin.New()
+			r.POST("/todos/:id/attachments", NewAttachmentHandler(attachments, maxSize).UploadAttachment)
+
+			body, contentType := tt.body(t)
+			counted := &countingReader{r: body}
+			req := httptest.NewRequest(http.MethodPost, "/todos/1/attachments", counted)
+			req.Header.Set("Content-Type", contentType)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" {
+				var resp struct {
+					Code string `json:"code"`
+				}
+				if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Code != tt.wantCode {
+					t.Errorf("body = %s, want code %q", rec.Body, tt.wantCode)
+				}
+			}
+			// Reading stops shortly after the limit, whatever the client sends
+			if limit := int64(maxSize + uploadOverhead + 64<<10); counted.n > limit {
+				t.Errorf("read %d bytes of the request, want at most %d", counted.n, limit)
+			}
+		})
+	}
+}
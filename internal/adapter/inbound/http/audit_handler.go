@@ -0,0 +1,158 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAuditLimit and maxAuditLimit bound how many audit entries one request returns
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditHandler handles HTTP requests for the audit log
+type AuditHandler struct {
+	auditService port.AuditServicePort
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(auditService port.AuditServicePort) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *AuditHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// parseAuditFilter reads the audit query parameters
+func parseAuditFilter(c *gin.Context) (model.AuditFilter, error) {
+	filter := model.AuditFilter{
+		EntityType: c.Query("entity_type"),
+		Action:     model.AuditAction(c.Query("action")),
+		Limit:      defaultAuditLimit,
+	}
+
+	parseInt := func(key string) (*int, error) {
+		raw, ok := c.GetQuery(key)
+		if !ok {
+			return nil, nil
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, errors.New("invalid " + key + " parameter")
+		}
+		return &v, nil
+	}
+	parseTime := func(key string) (*time.Time, error) {
+		raw, ok := c.GetQuery(key)
+		if !ok {
+			return nil, nil
+		}
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errors.New("invalid " + key + " parameter, expected RFC3339")
+		}
+		return &v, nil
+	}
+
+	var err error
+	if filter.ActorID, err = parseInt("actor_id"); err != nil {
+		return filter, err
+	}
+	if filter.EntityID, err = parseInt("entity_id"); err != nil {
+		return filter, err
+	}
+	if filter.Since, err = parseTime("since"); err != nil {
+		return filter, err
+	}
+	if filter.Until, err = parseTime("until"); err != nil {
+		return filter, err
+	}
+	limit, err := parseInt("limit")
+	if err != nil {
+		return filter, err
+	}
+	if limit != nil {
+		if *limit < 1 || *limit > maxAuditLimit {
+			return filter, errors.New("limit must be between 1 and 1000")
+		}
+		filter.Limit = *limit
+	}
+	return filter, nil
+}
+
+// ListAuditEntries handles GET /audit
+// @Summary Query the audit log
+// @Description Get audit entries, newest first. Only administrators may read the audit log.
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query int false "User who made the change"
+// @Param entity_type query string false "Entity type" Enums(todo, user)
+// @Param entity_id query int false "Entity ID"
+// @Param action query string false "Action" Enums(create, update, delete, restore, purge)
+// @Param since query string false "Only entries at or after this RFC3339 time"
+// @Param until query string false "Only entries before this RFC3339 time"
+// @Param limit query int false "Maximum number of entries (1-1000, default 100)"
+// @Success 200 {array} v1.AuditEntry
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /v1/audit [get]
+func (h *AuditHandler) ListAuditEntries(c *gin.Context) {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	entries, err := h.auditService.ListAuditEntries(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewAuditEntries(entries))
+}
+
+// VerifyAuditLog handles GET /audit/verify
+// @Summary Verify the audit log
+// @Description Recompute the audit log's hash chain and report the first tampered entry, if any
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} v1.AuditVerification
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /v1/audit/verify [get]
+func (h *AuditHandler) VerifyAuditLog(c *gin.Context) {
+	verification, err := h.auditService.VerifyAuditLog(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewAuditVerification(verification))
+}
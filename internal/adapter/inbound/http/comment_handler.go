@@ -0,0 +1,240 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CommentHandler handles HTTP requests for todo comments and activity
+type CommentHandler struct {
+	commentService port.CommentServicePort
+}
+
+// NewCommentHandler creates a new CommentHandler
+func NewCommentHandler(commentService port.CommentServicePort) *CommentHandler {
+	return &CommentHandler{
+		commentService: commentService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *CommentHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
+	case errors.Is(err, domain.ErrInvalidCommentBody):
+		return http.StatusBadRequest, "Comment body cannot be empty"
+	case errors.Is(err, domain.ErrInvalidParentComment):
+		return http.StatusBadRequest, "Parent comment must belong to the same todo"
+	case errors.Is(err, domain.ErrCommentDeleted):
+		return http.StatusConflict, "Comment was deleted"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// parseTodoAndCommentIDs reads the :id and :comment_id path parameters
+func parseTodoAndCommentIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	commentID, err := strconv.Atoi(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid comment id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	return id, commentID, true
+}
+
+// AddComment handles POST /todos/:id/comments
+// @Summary Comment on a todo
+// @Description Comment on a todo as the acting user. Set parent_id to reply to a comment; @username mentions are resolved to user IDs.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Todo ID"
+// @Param comment body v1.CreateCommentRequest true "Comment object"
+// @Success 201 {object} v1.Comment
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/comments [post]
+func (h *CommentHandler) AddComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	comment, err := h.commentService.AddComment(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewComment(comment))
+}
+
+// ListComments handles GET /todos/:id/comments
+// @Summary List comments
+// @Description Get the discussion of a todo as threads of top-level comments with nested replies
+// @Tags comments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {array} v1.Comment
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	comments, err := h.commentService.ListComments(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewComments(comments))
+}
+
+// UpdateComment handles PUT /todos/:id/comments/:comment_id
+// @Summary Edit a comment
+// @Description Edit the acting user's comment. The previous body is kept in the comment history.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Todo ID"
+// @Param comment_id path int true "Comment ID"
+// @Param comment body v1.UpdateCommentRequest true "Comment update object"
+// @Success 200 {object} v1.Comment
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Comment was deleted"
+// @Router /v1/todos/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	id, commentID, ok := parseTodoAndCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	var req v1.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(c.Request.Context(), id, commentID, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewComment(comment))
+}
+
+// DeleteComment handles DELETE /todos/:id/comments/:comment_id
+// @Summary Delete a comment
+// @Description Delete the acting user's comment. It stays in its thread without a body.
+// @Tags comments
+// @Security BearerAuth
+// @Param id path int true "Todo ID"
+// @Param comment_id path int true "Comment ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Comment was deleted"
+// @Router /v1/todos/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	id, commentID, ok := parseTodoAndCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.DeleteComment(c.Request.Context(), id, commentID); err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListCommentRevisions handles GET /todos/:id/comments/:comment_id/history
+// @Summary Get comment history
+// @Description Get the previous bodies of a comment, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param comment_id path int true "Comment ID"
+// @Success 200 {array} v1.CommentRevision
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/comments/{comment_id}/history [get]
+func (h *CommentHandler) ListCommentRevisions(c *gin.Context) {
+	id, commentID, ok := parseTodoAndCommentIDs(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := h.commentService.ListCommentRevisions(c.Request.Context(), id, commentID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewCommentRevisions(revisions))
+}
+
+// GetActivity handles GET /todos/:id/activity
+// @Summary Get todo activity
+// @Description Get the activity feed of a todo combining field changes, status changes and comments, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {array} v1.Activity
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/activity [get]
+func (h *CommentHandler) GetActivity(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	activities, err := h.commentService.GetActivity(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewActivities(activities))
+}
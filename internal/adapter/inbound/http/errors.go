@@ -0,0 +1,89 @@
+package http
+
+import (
+	"errors"
+
+	"go-boilerplate/internal/domain"
+)
+
+// Codes of error responses that do not come from a domain error
+const (
+	codeInvalidRequest  = "invalid_request"
+	codeUnauthenticated = "unauthenticated"
+	codeVersionRetired  = "version_retired"
+	codeInternal        = "internal"
+)
+
+// errorCodes maps domain errors to the code field of error responses. Clients
+// should switch on the code rather than the message: codes are part of the API
+// and never change, while messages may be reworded.
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{domain.ErrNotFound, "not_found"},
+	{domain.ErrDuplicate, "duplicate"},
+	{domain.ErrUnauthenticated, "unauthenticated"},
+	{domain.ErrForbidden, "forbidden"},
+	{domain.ErrInvalidTodoTitle, "invalid_todo_title"},
+	{domain.ErrTodoAlreadyCompleted, "todo_already_completed"},
+	{domain.ErrInvalidPriority, "invalid_priority"},
+	{domain.ErrInvalidTimezone, "invalid_timezone"},
+	{domain.ErrDueDateInPast, "due_date_in_past"},
+	{domain.ErrInvalidTodoStatus, "invalid_todo_status"},
+	{domain.ErrIllegalTransition, "illegal_transition"},
+	{domain.ErrInvalidParentTodo, "invalid_parent_todo"},
+	{domain.ErrTodoCycle, "todo_cycle"},
+	{domain.ErrMaxDepthExceeded, "max_depth_exceeded"},
+	{domain.ErrOpenSubtasks, "open_subtasks"},
+	{domain.ErrClosedParent, "closed_parent"},
+	{domain.ErrInvalidChecklistItem, "invalid_checklist_item"},
+	{domain.ErrInvalidRecurrence, "invalid_recurrence"},
+	{domain.ErrRecurrenceRequiresDueDate, "recurrence_requires_due_date"},
+	{domain.ErrNotRecurring, "not_recurring"},
+	{domain.ErrHistoryUnavailable, "history_unavailable"},
+	{domain.ErrInvalidTagName, "invalid_tag_name"},
+	{domain.ErrInvalidTagColor, "invalid_tag_color"},
+	{domain.ErrTagNameDuplicate, "tag_name_duplicate"},
+	{domain.ErrTagOwnerMismatch, "tag_owner_mismatch"},
+	{domain.ErrTagMergeIntoSelf, "tag_merge_into_self"},
+	{domain.ErrInvalidTagMatch, "invalid_tag_match"},
+	{domain.ErrInvalidListName, "invalid_list_name"},
+	{domain.ErrListArchived, "list_archived"},
+	{domain.ErrListNotEmpty, "list_not_empty"},
+	{domain.ErrListOwnerMismatch, "list_owner_mismatch"},
+	{domain.ErrTodoNotInList, "todo_not_in_list"},
+	{domain.ErrInvalidListDeleteMode, "invalid_list_delete_mode"},
+	{domain.ErrInvalidPosition, "invalid_position"},
+	{domain.ErrInvalidListRole, "invalid_list_role"},
+	{domain.ErrAlreadyListMember, "already_list_member"},
+	{domain.ErrInvitationPending, "invitation_pending"},
+	{domain.ErrInvitationNotPending, "invitation_not_pending"},
+	{domain.ErrInvalidCommentBody, "invalid_comment_body"},
+	{domain.ErrInvalidParentComment, "invalid_parent_comment"},
+	{domain.ErrCommentDeleted, "comment_deleted"},
+	{domain.ErrInvalidAttachmentName, "invalid_attachment_name"},
+	{domain.ErrAttachmentTooLarge, "attachment_too_large"},
+	{domain.ErrAttachmentTypeNotAllowed, "attachment_type_not_allowed"},
+	{domain.ErrInvalidWebhookURL, "invalid_webhook_url"},
+	{domain.ErrInternalWebhookURL, "internal_webhook_url"},
+	{domain.ErrInvalidEventType, "invalid_event_type"},
+	{domain.ErrInvalidDeliveryStatus, "invalid_delivery_status"},
+	{domain.ErrInvalidUsername, "invalid_username"},
+	{domain.ErrUsernameDuplicate, "username_duplicate"},
+	{domain.ErrInvalidEmail, "invalid_email"},
+	{domain.ErrUsernameUnchanged, "username_unchanged"},
+	{domain.ErrUsernameReserved, "username_reserved"},
+	{domain.ErrUsernameInTrash, "username_in_trash"},
+}
+
+// errorCode returns the code reported for an error, or internal when it is
+// not a domain error
+func errorCode(err error) string {
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return mapping.code
+		}
+	}
+	return codeInternal
+}
@@ -0,0 +1,316 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListShareHandler handles HTTP requests for list sharing
+type ListShareHandler struct {
+	shareService port.ListShareServicePort
+}
+
+// NewListShareHandler creates a new ListShareHandler
+func NewListShareHandler(shareService port.ListShareServicePort) *ListShareHandler {
+	return &ListShareHandler{
+		shareService: shareService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *ListShareHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
+	case errors.Is(err, domain.ErrInvalidListRole):
+		return http.StatusBadRequest, "Role must be viewer or editor"
+	case errors.Is(err, domain.ErrListArchived):
+		return http.StatusConflict, "List is archived"
+	case errors.Is(err, domain.ErrAlreadyListMember):
+		return http.StatusConflict, "User already has access to the list"
+	case errors.Is(err, domain.ErrInvitationPending):
+		return http.StatusConflict, "User already has a pending invitation to the list"
+	case errors.Is(err, domain.ErrInvitationNotPending):
+		return http.StatusConflict, "Invitation was already answered"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// parseListAndUserIDs reads the :id and :user_id path parameters
+func parseListAndUserIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	return id, userID, true
+}
+
+// ListMembers handles GET /lists/:id/members
+// @Summary List members
+// @Description Get everyone with access to a list, starting with its owner
+// @Tags sharing
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {array} v1.ListMember
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/members [get]
+func (h *ListShareHandler) ListMembers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	members, err := h.shareService.ListMembers(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewListMembers(members))
+}
+
+// UpdateMember handles PUT /lists/:id/members/:user_id
+// @Summary Change a member's role
+// @Description Change a collaborator's role on a list. Only the owner may do this.
+// @Tags sharing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param user_id path int true "User ID"
+// @Param member body v1.UpdateListMemberRequest true "Member update object"
+// @Success 200 {object} v1.ListMember
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/members/{user_id} [put]
+func (h *ListShareHandler) UpdateMember(c *gin.Context) {
+	id, userID, ok := parseListAndUserIDs(c)
+	if !ok {
+		return
+	}
+
+	var req v1.UpdateListMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	member, err := h.shareService.UpdateMember(c.Request.Context(), id, userID, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewListMember(member))
+}
+
+// RemoveMember handles DELETE /lists/:id/members/:user_id
+// @Summary Remove a member
+// @Description Revoke a collaborator's access. Collaborators may remove themselves to leave the list.
+// @Tags sharing
+// @Security BearerAuth
+// @Param id path int true "List ID"
+// @Param user_id path int true "User ID"
+// @Success 204 "No Content"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/members/{user_id} [delete]
+func (h *ListShareHandler) RemoveMember(c *gin.Context) {
+	id, userID, ok := parseListAndUserIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.shareService.RemoveMember(c.Request.Context(), id, userID); err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Invite handles POST /lists/:id/invitations
+// @Summary Invite a user to a list
+// @Description Invite a user, by username, to collaborate on a list as viewer or editor
+// @Tags sharing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param invitation body v1.InviteToListRequest true "Invitation object"
+// @Success 201 {object} v1.ListInvitation
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Already a member or invited"
+// @Router /v1/lists/{id}/invitations [post]
+func (h *ListShareHandler) Invite(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.InviteToListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	invitation, err := h.shareService.Invite(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewListInvitation(invitation))
+}
+
+// ListListInvitations handles GET /lists/:id/invitations
+// @Summary List a list's invitations
+// @Description Get every invitation sent for a list. Only the owner may do this.
+// @Tags sharing
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {array} v1.ListInvitation
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/invitations [get]
+func (h *ListShareHandler) ListListInvitations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	invitations, err := h.shareService.ListListInvitations(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewListInvitations(invitations))
+}
+
+// ListMyInvitations handles GET /invitations
+// @Summary List my invitations
+// @Description Get the acting user's pending list invitations
+// @Tags sharing
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} v1.ListInvitation
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /v1/invitations [get]
+func (h *ListShareHandler) ListMyInvitations(c *gin.Context) {
+	invitations, err := h.shareService.ListMyInvitations(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewListInvitations(invitations))
+}
+
+// AcceptInvitation handles POST /invitations/:id/accept
+// @Summary Accept an invitation
+// @Description Accept a pending invitation and become a collaborator on the list
+// @Tags sharing
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} v1.ListMember
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Invitation already answered"
+// @Router /v1/invitations/{id}/accept [post]
+func (h *ListShareHandler) AcceptInvitation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation id", "code": codeInvalidRequest})
+		return
+	}
+
+	member, err := h.shareService.AcceptInvitation(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewListMember(member))
+}
+
+// DeclineInvitation handles POST /invitations/:id/decline
+// @Summary Decline an invitation
+// @Description Decline a pending list invitation
+// @Tags sharing
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} v1.ListInvitation
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Invitation already answered"
+// @Router /v1/invitations/{id}/decline [post]
+func (h *ListShareHandler) DeclineInvitation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid invitation id", "code": codeInvalidRequest})
+		return
+	}
+
+	invitation, err := h.shareService.DeclineInvitation(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewListInvitation(invitation))
+}
+
+// ListSharedWithMe handles GET /lists/shared
+// @Summary List lists shared with me
+// @Description Get the active lists other users have shared with the acting user
+// @Tags sharing
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} v1.TodoList
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /v1/lists/shared [get]
+func (h *ListShareHandler) ListSharedWithMe(c *gin.Context) {
+	lists, err := h.shareService.ListSharedWithMe(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoLists(lists))
+}
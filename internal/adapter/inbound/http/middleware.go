@@ -0,0 +1,151 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader carries the ID of the user a request acts on behalf of. It is
+// not authentication and is only honored when the server allows it for
+// development.
+const ActorHeader = "X-User-ID"
+
+// RequestIDHeader carries the ID correlating a request across logs and the audit trail
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs
+const maxRequestIDLength = 128
+
+// AccessTokenParam names the query parameter and the cookie that carry a
+// bearer token on streaming routes, for browser clients such as EventSource
+// and WebSocket that cannot set the Authorization header
+const AccessTokenParam = "access_token"
+
+// accessTokenKey is the gin context key a token taken from the query is kept under
+const accessTokenKey = "accessToken"
+
+// ActorMiddleware authenticates the acting user from the bearer token in the
+// Authorization header, or from the X-User-ID header where the authenticator
+// allows it, into the request context. Requests without credentials continue
+// anonymously and are rejected by services that need an actor.
+func ActorMiddleware(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, ok, err := authenticator.Authenticate(c.GetHeader("Authorization"), c.GetHeader(ActorHeader))
+		if err != nil {
+			status, code := http.StatusUnauthorized, codeUnauthenticated
+			if errors.Is(err, auth.ErrInvalidActor) {
+				status, code = http.StatusBadRequest, codeInvalidRequest
+			}
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(status, gin.H{"error": err.Error(), "code": code})
+			return
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		ctx := domain.ContextWithActor(c.Request.Context(), userID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// AccessTokenQueryMiddleware moves an access_token query parameter out of the
+// request URL into the gin context, so tokens are never written to access
+// logs. It must run before the logger.
+func AccessTokenQueryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		if !query.Has(AccessTokenParam) {
+			c.Next()
+			return
+		}
+
+		c.Set(accessTokenKey, query.Get(AccessTokenParam))
+		query.Del(AccessTokenParam)
+		c.Request.URL.RawQuery = query.Encode()
+		c.Next()
+	}
+}
+
+// StreamAuthMiddleware authenticates the acting user of a streaming route from
+// the access_token query parameter or cookie when the Authorization header
+// did not already. A token that does not verify is rejected; requests
+// without one continue anonymously.
+func StreamAuthMiddleware(authenticator *auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := domain.ActorFromContext(c.Request.Context()); ok {
+			c.Next()
+			return
+		}
+
+		token := c.GetString(accessTokenKey)
+		if token == "" {
+			token, _ = c.Cookie(AccessTokenParam)
+		}
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		userID, err := authenticator.VerifyToken(token)
+		if err != nil {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error(), "code": codeUnauthenticated})
+			return
+		}
+		ctx := domain.ContextWithActor(c.Request.Context(), userID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequestIDMiddleware puts a request ID into the request context and echoes it
+// in the response. A client-supplied X-Request-ID is kept; otherwise a random
+// one is generated.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			buf := make([]byte, 16)
+			_, _ = rand.Read(buf)
+			requestID = hex.EncodeToString(buf)
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		ctx := domain.ContextWithRequestID(c.Request.Context(), requestID)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// DeprecationMiddleware marks the routes of a superseded API version. Responses
+// carry the Deprecation and Sunset headers (RFC 9745, RFC 8594) and link to
+// the same route under successorPrefix; from the sunset on, the routes answer
+// 410 Gone.
+func DeprecationMiddleware(deprecatedAt, sunset time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	return func(c *gin.Context) {
+		successor := successorPrefix + c.Request.URL.Path
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", "<"+successor+">; rel=\"successor-version\"")
+
+		if !time.Now().Before(sunset) {
+			c.AbortWithStatusJSON(http.StatusGone, gin.H{"error": "this API version is no longer served, use " + successor, "code": codeVersionRetired})
+			return
+		}
+		c.Next()
+	}
+}
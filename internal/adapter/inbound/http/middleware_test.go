@@ -0,0 +1,124 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestActorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := auth.NewTokenSigner([]byte("secret"), time.Hour)
+
+	tests := []struct {
+		name       string
+		opts       []auth.Option
+		header     map[string]string
+		wantStatus int
+		wantActor  string
+	}{
+		{name: "anonymous", opts: []auth.Option{auth.WithTokens(signer)}, wantStatus: http.StatusOK, wantActor: "none"},
+		{name: "bearer token", opts: []auth.Option{auth.WithTokens(signer)}, header: map[string]string{"Authorization": "Bearer " + signer.Issue(3)}, wantStatus: http.StatusOK, wantActor: "3"},
+		{name: "forged token", opts: []auth.Option{auth.WithTokens(signer)}, header: map[string]string{"Authorization": "Bearer 3.9999999999.forged"}, wantStatus: http.StatusUnauthorized},
+		{name: "actor header rejected", opts: []auth.Option{auth.WithTokens(signer)}, header: map[string]string{ActorHeader: "3"}, wantStatus: http.StatusUnauthorized},
+		{name: "actor header in development", opts: []auth.Option{auth.WithActorHeader(true)}, header: map[string]string{ActorHeader: "3"}, wantStatus: http.StatusOK, wantActor: "3"},
+		{name: "invalid actor header", opts: []auth.Option{auth.WithActorHeader(true)}, header: map[string]string{ActorHeader: "x"}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ActorMiddleware(auth.NewAuthenticator(tt.opts...)))
+			r.GET("/", func(c *gin.Context) {
+				actor := "none"
+				if userID, ok := domain.ActorFromContext(c.Request.Context()); ok {
+					actor = strconv.Itoa(userID)
+				}
+				c.String(http.StatusOK, actor)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantActor != "" && rec.Body.String() != tt.wantActor {
+				t.Errorf("actor = %s, want %s", rec.Body.String(), tt.wantActor)
+			}
+		})
+	}
+}
+
+func TestStreamAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	signer := auth.NewTokenSigner([]byte("secret"), time.Hour)
+	authenticator := auth.NewAuthenticator(auth.WithTokens(signer))
+
+	tests := []struct {
+		name       string
+		target     string
+		header     map[string]string
+		cookie     string
+		wantStatus int
+		wantActor  string
+		wantQuery  string
+	}{
+		{name: "anonymous", target: "/stream", wantStatus: http.StatusOK, wantActor: "none"},
+		{name: "query token", target: "/stream?access_token=" + signer.Issue(3) + "&last_event_id=7", wantStatus: http.StatusOK, wantActor: "3", wantQuery: "last_event_id=7"},
+		{name: "cookie token", target: "/stream", cookie: signer.Issue(4), wantStatus: http.StatusOK, wantActor: "4"},
+		{name: "query token wins over cookie", target: "/stream?access_token=" + signer.Issue(3), cookie: signer.Issue(4), wantStatus: http.StatusOK, wantActor: "3"},
+		{name: "authorization header wins", target: "/stream?access_token=forged", header: map[string]string{"Authorization": "Bearer " + signer.Issue(5)}, wantStatus: http.StatusOK, wantActor: "5"},
+		{name: "forged query token", target: "/stream?access_token=3.9999999999.forged", wantStatus: http.StatusUnauthorized},
+		{name: "forged cookie", target: "/stream", cookie: "3.9999999999.forged", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			var loggedQuery string
+			r.Use(AccessTokenQueryMiddleware(), func(c *gin.Context) {
+				// Stands in for the logger, which sees the URL as it is here
+				loggedQuery = c.Request.URL.RawQuery
+			}, ActorMiddleware(authenticator))
+			r.GET("/stream", StreamAuthMiddleware(authenticator), func(c *gin.Context) {
+				actor := "none"
+				if userID, ok := domain.ActorFromContext(c.Request.Context()); ok {
+					actor = strconv.Itoa(userID)
+				}
+				c.String(http.StatusOK, actor)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: AccessTokenParam, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantActor != "" && rec.Body.String() != tt.wantActor {
+				t.Errorf("actor = %s, want %s", rec.Body.String(), tt.wantActor)
+			}
+			if loggedQuery != tt.wantQuery {
+				t.Errorf("logged query = %q, want %q", loggedQuery, tt.wantQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"go-boilerplate/internal/adapter/inbound/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers are the handlers of the REST API
+type Handlers struct {
+	Todo       *TodoHandler
+	User       *UserHandler
+	Tag        *TagHandler
+	List       *TodoListHandler
+	Share      *ListShareHandler
+	Comment    *CommentHandler
+	Attachment *AttachmentHandler
+	Audit      *AuditHandler
+	Stats      *StatsHandler
+	Webhook    *WebhookHandler
+	Stream     *TodoStreamHandler
+}
+
+// RegisterRoutes registers the REST API's routes on a router group, which
+// serves one version of the API. The todo stream authenticates with the
+// authenticator as well, accepting tokens browsers can send.
+func RegisterRoutes(api *gin.RouterGroup, h Handlers, authenticator *auth.Authenticator) {
+	// Todo routes
+	todos := api.Group("/todos")
+	{
+		todos.POST("", h.Todo.CreateTodo)
+		todos.GET("", h.Todo.ListTodos)
+		todos.GET("/trash", h.Todo.ListDeletedTodos)
+		todos.GET("/stream", StreamAuthMiddleware(authenticator), h.Stream.StreamTodos)
+		todos.GET("/:id", h.Todo.GetTodo)
+		todos.PUT("/:id", h.Todo.UpdateTodo)
+		todos.DELETE("/:id", h.Todo.DeleteTodo)
+		todos.POST("/:id/restore", h.Todo.RestoreTodo)
+		todos.POST("/:id/transitions", h.Todo.TransitionTodo)
+		todos.GET("/:id/transitions", h.Todo.ListTodoTransitions)
+		todos.GET("/:id/history", h.Todo.GetTodoHistory)
+		todos.PUT("/:id/tags/:tag_id", h.Todo.AttachTag)
+		todos.DELETE("/:id/tags/:tag_id", h.Todo.DetachTag)
+		todos.GET("/:id/subtasks", h.Todo.ListSubtasks)
+		todos.POST("/:id/checklist", h.Todo.AddChecklistItem)
+		todos.PUT("/:id/checklist/:item_id", h.Todo.UpdateChecklistItem)
+		todos.DELETE("/:id/checklist/:item_id", h.Todo.DeleteChecklistItem)
+		todos.PUT("/:id/recurrence", h.Todo.SetRecurrence)
+		todos.DELETE("/:id/recurrence", h.Todo.ClearRecurrence)
+		todos.GET("/:id/occurrences", h.Todo.PreviewOccurrences)
+		todos.POST("/:id/comments", h.Comment.AddComment)
+		todos.GET("/:id/comments", h.Comment.ListComments)
+		todos.PUT("/:id/comments/:comment_id", h.Comment.UpdateComment)
+		todos.DELETE("/:id/comments/:comment_id", h.Comment.DeleteComment)
+		todos.GET("/:id/comments/:comment_id/history", h.Comment.ListCommentRevisions)
+		todos.GET("/:id/activity", h.Comment.GetActivity)
+		todos.POST("/:id/attachments", h.Attachment.UploadAttachment)
+		todos.GET("/:id/attachments", h.Attachment.ListAttachments)
+		todos.GET("/:id/attachments/:attachment_id", h.Attachment.GetAttachment)
+		todos.GET("/:id/attachments/:attachment_id/content", h.Attachment.DownloadAttachment)
+		todos.DELETE("/:id/attachments/:attachment_id", h.Attachment.DeleteAttachment)
+	}
+
+	// Tag routes
+	tags := api.Group("/tags")
+	{
+		tags.POST("", h.Tag.CreateTag)
+		tags.GET("", h.Tag.ListTags)
+		tags.GET("/:id", h.Tag.GetTag)
+		tags.PUT("/:id", h.Tag.UpdateTag)
+		tags.DELETE("/:id", h.Tag.DeleteTag)
+		tags.POST("/:id/merge", h.Tag.MergeTags)
+	}
+
+	// List routes
+	lists := api.Group("/lists")
+	{
+		lists.POST("", h.List.CreateList)
+		lists.GET("", h.List.ListLists)
+		lists.GET("/shared", h.Share.ListSharedWithMe)
+		lists.GET("/:id", h.List.GetList)
+		lists.PUT("/:id", h.List.UpdateList)
+		lists.DELETE("/:id", h.List.DeleteList)
+		lists.POST("/:id/archive", h.List.ArchiveList)
+		lists.POST("/:id/unarchive", h.List.UnarchiveList)
+		lists.GET("/:id/todos", h.List.ListMemberTodos)
+		lists.PUT("/:id/todos/:todo_id", h.List.AddTodo)
+		lists.DELETE("/:id/todos/:todo_id", h.List.RemoveTodo)
+		lists.POST("/:id/todos/:todo_id/move", h.List.MoveTodo)
+		lists.GET("/:id/members", h.Share.ListMembers)
+		lists.PUT("/:id/members/:user_id", h.Share.UpdateMember)
+		lists.DELETE("/:id/members/:user_id", h.Share.RemoveMember)
+		lists.POST("/:id/invitations", h.Share.Invite)
+		lists.GET("/:id/invitations", h.Share.ListListInvitations)
+	}
+
+	// Audit routes
+	audits := api.Group("/audit")
+	{
+		audits.GET("", h.Audit.ListAuditEntries)
+		audits.GET("/verify", h.Audit.VerifyAuditLog)
+	}
+
+	// Stats routes
+	stats := api.Group("/stats")
+	{
+		stats.GET("/todos", h.Stats.GetTodoStats)
+		stats.GET("/todos/status", h.Stats.GetProjectionStatus)
+		stats.POST("/todos/rebuild", h.Stats.RebuildTodoStats)
+	}
+
+	// Webhook routes
+	webhooks := api.Group("/webhooks")
+	{
+		webhooks.POST("", h.Webhook.CreateWebhook)
+		webhooks.GET("", h.Webhook.ListWebhooks)
+		webhooks.GET("/:id", h.Webhook.GetWebhook)
+		webhooks.PUT("/:id", h.Webhook.UpdateWebhook)
+		webhooks.DELETE("/:id", h.Webhook.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.Webhook.ListDeliveries)
+		webhooks.GET("/:id/deliveries/:delivery_id", h.Webhook.GetDelivery)
+		webhooks.POST("/:id/deliveries/:delivery_id/replay", h.Webhook.ReplayDelivery)
+	}
+
+	// Invitation routes
+	invitations := api.Group("/invitations")
+	{
+		invitations.GET("", h.Share.ListMyInvitations)
+		invitations.POST("/:id/accept", h.Share.AcceptInvitation)
+		invitations.POST("/:id/decline", h.Share.DeclineInvitation)
+	}
+
+	// User routes
+	users := api.Group("/users")
+	{
+		users.POST("", h.User.CreateUser)
+		users.GET("", h.User.ListUsers)
+		users.GET("/trash", h.User.ListDeletedUsers)
+		users.GET("/by-username/:name", h.User.GetUserByUsername)
+		users.GET("/:id", h.User.GetUser)
+		users.PUT("/:id", h.User.UpdateUser)
+		users.PUT("/:id/username", h.User.RenameUser)
+		users.DELETE("/:id", h.User.DeleteUser)
+		users.POST("/:id/restore", h.User.RestoreUser)
+	}
+}
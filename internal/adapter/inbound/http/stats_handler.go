@@ -0,0 +1,110 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler handles HTTP requests for dashboard statistics
+type StatsHandler struct {
+	statsService port.TodoStatsServicePort
+}
+
+// NewStatsHandler creates a new StatsHandler
+func NewStatsHandler(statsService port.TodoStatsServicePort) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *StatsHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// GetTodoStats handles GET /stats/todos
+// @Summary Get todo dashboard statistics
+// @Description Get counts of live todos per status, owner and tag, and how many are overdue. Counts come from a read model that may trail the latest changes by the reported lag. Users count their own todos; only administrators may count everyone's or another owner's.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Param owner_id query int false "Only count this owner's todos"
+// @Success 200 {object} v1.TodoStats
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /v1/stats/todos [get]
+func (h *StatsHandler) GetTodoStats(c *gin.Context) {
+	var filter model.TodoStatsFilter
+	if raw, ok := c.GetQuery("owner_id"); ok {
+		ownerID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner_id parameter", "code": codeInvalidRequest})
+			return
+		}
+		filter.OwnerID = &ownerID
+	}
+
+	stats, err := h.statsService.GetTodoStats(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoStats(stats))
+}
+
+// GetProjectionStatus handles GET /stats/todos/status
+// @Summary Get the todo statistics projection status
+// @Description Report how many events the statistics read model has applied, how many are still pending and how far it lags behind them
+// @Tags stats
+// @Produce json
+// @Success 200 {object} v1.ProjectionStatus
+// @Router /v1/stats/todos/status [get]
+func (h *StatsHandler) GetProjectionStatus(c *gin.Context) {
+	status, err := h.statsService.GetProjectionStatus(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewProjectionStatus(status))
+}
+
+// RebuildTodoStats handles POST /stats/todos/rebuild
+// @Summary Rebuild the todo statistics
+// @Description Recompute the statistics read model from scratch. Only administrators may rebuild it.
+// @Tags stats
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} v1.ProjectionStatus
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Router /v1/stats/todos/rebuild [post]
+func (h *StatsHandler) RebuildTodoStats(c *gin.Context) {
+	status, err := h.statsService.RebuildTodoStats(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewProjectionStatus(status))
+}
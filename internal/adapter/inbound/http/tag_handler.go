@@ -0,0 +1,225 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler handles HTTP requests for tags
+type TagHandler struct {
+	tagService port.TagServicePort
+}
+
+// NewTagHandler creates a new TagHandler
+func NewTagHandler(tagService port.TagServicePort) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *TagHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "Tag not found"
+	case errors.Is(err, domain.ErrInvalidTagName):
+		return http.StatusBadRequest, "Invalid tag name"
+	case errors.Is(err, domain.ErrInvalidTagColor):
+		return http.StatusBadRequest, "Tag color must be a #RRGGBB hex value"
+	case errors.Is(err, domain.ErrTagNameDuplicate):
+		return http.StatusConflict, "Tag name already exists"
+	case errors.Is(err, domain.ErrTagOwnerMismatch):
+		return http.StatusBadRequest, "Tags belong to different owners"
+	case errors.Is(err, domain.ErrTagMergeIntoSelf):
+		return http.StatusBadRequest, "Cannot merge a tag into itself"
+	case errors.Is(err, domain.ErrDuplicate):
+		return http.StatusConflict, "Resource already exists"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// CreateTag handles POST /tags
+// @Summary Create a new tag
+// @Description Create a new tag in the owner's namespace
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param tag body v1.CreateTagRequest true "Tag object"
+// @Success 201 {object} v1.Tag
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 409 {object} map[string]string "Conflict - Tag name already exists"
+// @Router /v1/tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req v1.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c.Request.Context(), req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewTag(tag))
+}
+
+// GetTag handles GET /tags/:id
+// @Summary Get a tag
+// @Description Get a tag by ID
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} v1.Tag
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/tags/{id} [get]
+func (h *TagHandler) GetTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id", "code": codeInvalidRequest})
+		return
+	}
+
+	tag, err := h.tagService.GetTag(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTag(tag))
+}
+
+// ListTags handles GET /tags
+// @Summary List tags
+// @Description Get all tags, optionally restricted to one owner's namespace
+// @Tags tags
+// @Produce json
+// @Param owner_id query int false "Owner ID"
+// @Success 200 {array} v1.Tag
+// @Router /v1/tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	var ownerID *int
+	if raw, ok := c.GetQuery("owner_id"); ok {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner_id parameter", "code": codeInvalidRequest})
+			return
+		}
+		ownerID = &id
+	}
+
+	tags, err := h.tagService.ListTags(c.Request.Context(), ownerID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTags(tags))
+}
+
+// UpdateTag handles PUT /tags/:id
+// @Summary Update a tag
+// @Description Rename or recolor a tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body v1.UpdateTagRequest true "Tag update object"
+// @Success 200 {object} v1.Tag
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Tag name already exists"
+// @Router /v1/tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.UpdateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTag(tag))
+}
+
+// MergeTags handles POST /tags/:id/merge
+// @Summary Merge a tag into another
+// @Description Move all todos from a tag to the target tag and delete the source tag
+// @Tags tags
+// @Accept json
+// @Produce json
+// @Param id path int true "Source tag ID"
+// @Param merge body v1.MergeTagsRequest true "Target tag"
+// @Success 200 {object} v1.Tag
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/tags/{id}/merge [post]
+func (h *TagHandler) MergeTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	tag, err := h.tagService.MergeTags(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTag(tag))
+}
+
+// DeleteTag handles DELETE /tags/:id
+// @Summary Delete a tag
+// @Description Delete a tag and detach it from all todos
+// @Tags tags
+// @Param id path int true "Tag ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id", "code": codeInvalidRequest})
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), id); err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
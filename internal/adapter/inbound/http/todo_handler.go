@@ -4,7 +4,10 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"go-boilerplate/internal/adapter/inbound/http/v1"
 	"go-boilerplate/internal/domain"
 	"go-boilerplate/internal/domain/model"
 	"go-boilerplate/internal/domain/port"
@@ -29,10 +32,48 @@ func (h *TodoHandler) mapDomainErrorToHTTP(err error) (int, string) {
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
 		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
 	case errors.Is(err, domain.ErrInvalidTodoTitle):
 		return http.StatusBadRequest, "Invalid todo title"
 	case errors.Is(err, domain.ErrTodoAlreadyCompleted):
 		return http.StatusConflict, "Todo is already completed"
+	case errors.Is(err, domain.ErrInvalidPriority):
+		return http.StatusBadRequest, "Invalid todo priority"
+	case errors.Is(err, domain.ErrInvalidTimezone):
+		return http.StatusBadRequest, "Invalid timezone"
+	case errors.Is(err, domain.ErrDueDateInPast):
+		return http.StatusBadRequest, "Due date cannot be before the todo was created"
+	case errors.Is(err, domain.ErrInvalidTodoStatus):
+		return http.StatusBadRequest, "Invalid todo status"
+	case errors.Is(err, domain.ErrIllegalTransition):
+		return http.StatusConflict, "Illegal todo status transition"
+	case errors.Is(err, domain.ErrTagOwnerMismatch):
+		return http.StatusBadRequest, "Tag belongs to a different owner"
+	case errors.Is(err, domain.ErrInvalidTagMatch):
+		return http.StatusBadRequest, "Tag match must be all or any"
+	case errors.Is(err, domain.ErrInvalidParentTodo):
+		return http.StatusBadRequest, "Invalid parent todo"
+	case errors.Is(err, domain.ErrTodoCycle):
+		return http.StatusConflict, "Todo cannot be nested under its own subtask"
+	case errors.Is(err, domain.ErrMaxDepthExceeded):
+		return http.StatusConflict, "Maximum subtask depth exceeded"
+	case errors.Is(err, domain.ErrOpenSubtasks):
+		return http.StatusConflict, "Todo has open subtasks"
+	case errors.Is(err, domain.ErrClosedParent):
+		return http.StatusConflict, "Open subtask cannot be placed under a closed todo"
+	case errors.Is(err, domain.ErrInvalidChecklistItem):
+		return http.StatusBadRequest, "Checklist item text cannot be empty"
+	case errors.Is(err, domain.ErrInvalidRecurrence):
+		return http.StatusBadRequest, "Invalid recurrence rule"
+	case errors.Is(err, domain.ErrRecurrenceRequiresDueDate):
+		return http.StatusBadRequest, "Recurring todos need a due date"
+	case errors.Is(err, domain.ErrNotRecurring):
+		return http.StatusConflict, "Todo is not recurring"
+	case errors.Is(err, domain.ErrHistoryUnavailable):
+		return http.StatusNotImplemented, "Todo history is not recorded by this store"
 	case errors.Is(err, domain.ErrDuplicate):
 		return http.StatusConflict, "Resource already exists"
 	default:
@@ -46,67 +87,167 @@ func (h *TodoHandler) mapDomainErrorToHTTP(err error) (int, string) {
 // @Tags todos
 // @Accept json
 // @Produce json
-// @Param todo body model.CreateTodoRequest true "Todo object"
-// @Success 201 {object} model.Todo
-// @Router /todos [post]
+// @Param todo body v1.CreateTodoRequest true "Todo object"
+// @Success 201 {object} v1.Todo
+// @Router /v1/todos [post]
 func (h *TodoHandler) CreateTodo(c *gin.Context) {
-	var req model.CreateTodoRequest
+	var req v1.CreateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
 		return
 	}
 
-	todo, err := h.todoService.CreateTodo(c.Request.Context(), &req)
+	todo, err := h.todoService.CreateTodo(c.Request.Context(), req.ToModel())
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusCreated, todo)
+	c.JSON(http.StatusCreated, v1.NewTodo(todo))
 }
 
 // GetTodo handles GET /todos/:id
 // @Summary Get a todo
-// @Description Get a todo by ID
+// @Description Get a todo by ID. With as_of, get the todo as it was at that time; progress is then not computed.
 // @Tags todos
 // @Produce json
 // @Param id path int true "Todo ID"
-// @Success 200 {object} model.Todo
-// @Router /todos/{id} [get]
+// @Param as_of query string false "Point in time to read the todo at (RFC3339)"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 501 {object} map[string]string "History not recorded"
+// @Router /v1/todos/{id} [get]
 func (h *TodoHandler) GetTodo(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
 		return
 	}
 
-	todo, err := h.todoService.GetTodo(c.Request.Context(), id)
+	var todo *model.Todo
+	if raw, ok := c.GetQuery("as_of"); ok {
+		asOf, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid as_of parameter, expected RFC3339", "code": codeInvalidRequest})
+			return
+		}
+		todo, err = h.todoService.GetTodoAsOf(c.Request.Context(), id, asOf)
+	} else {
+		todo, err = h.todoService.GetTodo(c.Request.Context(), id)
+	}
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, todo)
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// parseTodoFilter builds a TodoFilter from the list query parameters
+func parseTodoFilter(c *gin.Context) (model.TodoFilter, error) {
+	var filter model.TodoFilter
+
+	parseBool := func(key string) (*bool, error) {
+		raw, ok := c.GetQuery(key)
+		if !ok {
+			return nil, nil
+		}
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, errors.New("invalid " + key + " parameter")
+		}
+		return &v, nil
+	}
+	parseTime := func(key string) (*time.Time, error) {
+		raw, ok := c.GetQuery(key)
+		if !ok {
+			return nil, nil
+		}
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, errors.New("invalid " + key + " parameter, expected RFC3339")
+		}
+		return &v, nil
+	}
+
+	if raw, ok := c.GetQuery("owner_id"); ok {
+		ownerID, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, errors.New("invalid owner_id parameter")
+		}
+		filter.OwnerID = &ownerID
+	}
+	if raw, ok := c.GetQuery("parent_id"); ok {
+		parentID, err := strconv.Atoi(raw)
+		if err != nil {
+			return filter, errors.New("invalid parent_id parameter")
+		}
+		filter.ParentID = &parentID
+	}
+
+	var err error
+	if filter.Completed, err = parseBool("completed"); err != nil {
+		return filter, err
+	}
+	if filter.Overdue, err = parseBool("overdue"); err != nil {
+		return filter, err
+	}
+	if filter.DueBefore, err = parseTime("due_before"); err != nil {
+		return filter, err
+	}
+	if filter.DueAfter, err = parseTime("due_after"); err != nil {
+		return filter, err
+	}
+	filter.Priority = model.Priority(c.Query("priority"))
+	filter.Status = model.TodoStatus(c.Query("status"))
+
+	if raw := c.Query("tags"); raw != "" {
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				filter.Tags = append(filter.Tags, name)
+			}
+		}
+	}
+	filter.TagMatch = model.TagMatch(c.Query("match"))
+
+	return filter, nil
 }
 
 // ListTodos handles GET /todos
 // @Summary List todos
-// @Description Get all todos
+// @Description Get all todos, optionally filtered
 // @Tags todos
 // @Produce json
-// @Success 200 {array} model.Todo
-// @Router /todos [get]
+// @Param completed query bool false "Filter by completion"
+// @Param overdue query bool false "Filter open todos past their due date"
+// @Param due_before query string false "Due strictly before (RFC3339)"
+// @Param due_after query string false "Due strictly after (RFC3339)"
+// @Param priority query string false "Filter by priority (low, medium, high, urgent)"
+// @Param status query string false "Filter by status (open, in_progress, blocked, done, cancelled)"
+// @Param owner_id query int false "Filter by owner"
+// @Param parent_id query int false "Filter by parent todo"
+// @Param tags query string false "Comma-separated tag names"
+// @Param match query string false "How to combine tags: all (default) or any"
+// @Success 200 {array} v1.Todo
+// @Router /v1/todos [get]
 func (h *TodoHandler) ListTodos(c *gin.Context) {
-	todos, err := h.todoService.ListTodos(c.Request.Context())
+	filter, err := parseTodoFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	todos, err := h.todoService.ListTodos(c.Request.Context(), filter)
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, todos)
+	c.JSON(http.StatusOK, v1.NewTodos(todos))
 }
 
 // UpdateTodo handles PUT /todos/:id
@@ -116,51 +257,491 @@ func (h *TodoHandler) ListTodos(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "Todo ID"
-// @Param todo body model.UpdateTodoRequest true "Todo object"
-// @Success 200 {object} model.Todo
-// @Router /todos/{id} [put]
+// @Param todo body v1.UpdateTodoRequest true "Todo object"
+// @Success 200 {object} v1.Todo
+// @Router /v1/todos/{id} [put]
 func (h *TodoHandler) UpdateTodo(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
 		return
 	}
 
-	var req model.UpdateTodoRequest
+	var req v1.UpdateTodoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
 		return
 	}
 
-	todo, err := h.todoService.UpdateTodo(c.Request.Context(), id, &req)
+	todo, err := h.todoService.UpdateTodo(c.Request.Context(), id, req.ToModel())
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, todo)
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
 }
 
 // DeleteTodo handles DELETE /todos/:id
 // @Summary Delete a todo
-// @Description Delete a todo by ID
+// @Description Move a todo to the trash. It can be restored until the trash retention period ends.
 // @Tags todos
 // @Param id path int true "Todo ID"
 // @Success 204 "No Content"
-// @Router /todos/{id} [delete]
+// @Router /v1/todos/{id} [delete]
 func (h *TodoHandler) DeleteTodo(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
 		return
 	}
 
 	if err := h.todoService.DeleteTodo(c.Request.Context(), id); err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// ListDeletedTodos handles GET /todos/trash
+// @Summary List deleted todos
+// @Description Get the todos in the trash, optionally for one owner
+// @Tags todos
+// @Produce json
+// @Param owner_id query int false "Owner ID"
+// @Success 200 {array} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Router /v1/todos/trash [get]
+func (h *TodoHandler) ListDeletedTodos(c *gin.Context) {
+	var filter model.TodoFilter
+	if raw, ok := c.GetQuery("owner_id"); ok {
+		ownerID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid owner_id parameter", "code": codeInvalidRequest})
+			return
+		}
+		filter.OwnerID = &ownerID
+	}
+
+	todos, err := h.todoService.ListDeletedTodos(c.Request.Context(), filter)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodos(todos))
+}
+
+// RestoreTodo handles POST /todos/:id/restore
+// @Summary Restore a todo
+// @Description Move a todo out of the trash
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} v1.Todo
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/restore [post]
+func (h *TodoHandler) RestoreTodo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.RestoreTodo(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// TransitionTodo handles POST /todos/:id/transitions
+// @Summary Change a todo's status
+// @Description Move a todo to another lifecycle state if the workflow allows it
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param transition body v1.TransitionTodoRequest true "Target status"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Illegal transition"
+// @Router /v1/todos/{id}/transitions [post]
+func (h *TodoHandler) TransitionTodo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.TransitionTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.TransitionTodo(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// ListTodoTransitions handles GET /todos/:id/transitions
+// @Summary List a todo's status history
+// @Description Get the lifecycle transitions of a todo, oldest first
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {array} v1.TodoTransition
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/transitions [get]
+func (h *TodoHandler) ListTodoTransitions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	transitions, err := h.todoService.ListTodoTransitions(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoTransitions(transitions))
+}
+
+// GetTodoHistory handles GET /todos/:id/history
+// @Summary List a todo's stored events
+// @Description Get the events that built a todo, oldest first. Only available when todos are event-sourced.
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {array} v1.TodoEvent
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 501 {object} map[string]string "History not recorded"
+// @Router /v1/todos/{id}/history [get]
+func (h *TodoHandler) GetTodoHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	events, err := h.todoService.GetTodoHistory(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoEvents(events))
+}
+
+// AttachTag handles PUT /todos/:id/tags/:tag_id
+// @Summary Attach a tag to a todo
+// @Description Attach a tag from the todo owner's namespace to a todo
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/tags/{tag_id} [put]
+func (h *TodoHandler) AttachTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id", "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.AttachTag(c.Request.Context(), id, tagID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// DetachTag handles DELETE /todos/:id/tags/:tag_id
+// @Summary Detach a tag from a todo
+// @Description Remove a tag from a todo
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {object} v1.Todo
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/tags/{tag_id} [delete]
+func (h *TodoHandler) DetachTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tag id", "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.DetachTag(c.Request.Context(), id, tagID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// ListSubtasks handles GET /todos/:id/subtasks
+// @Summary List subtasks
+// @Description Get the direct subtasks of a todo
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {array} v1.Todo
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/subtasks [get]
+func (h *TodoHandler) ListSubtasks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	todos, err := h.todoService.ListSubtasks(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodos(todos))
+}
+
+// AddChecklistItem handles POST /todos/:id/checklist
+// @Summary Add a checklist item
+// @Description Append a checklist item to a todo
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param item body v1.CreateChecklistItemRequest true "Checklist item"
+// @Success 201 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/checklist [post]
+func (h *TodoHandler) AddChecklistItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.AddChecklistItem(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewTodo(todo))
+}
+
+// UpdateChecklistItem handles PUT /todos/:id/checklist/:item_id
+// @Summary Update a checklist item
+// @Description Edit the text of a checklist item or check it off
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param item_id path int true "Checklist item ID"
+// @Param item body v1.UpdateChecklistItemRequest true "Checklist item update"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/checklist/{item_id} [put]
+func (h *TodoHandler) UpdateChecklistItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist item id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.UpdateChecklistItem(c.Request.Context(), id, itemID, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// DeleteChecklistItem handles DELETE /todos/:id/checklist/:item_id
+// @Summary Delete a checklist item
+// @Description Remove a checklist item from a todo
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param item_id path int true "Checklist item ID"
+// @Success 200 {object} v1.Todo
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/checklist/{item_id} [delete]
+func (h *TodoHandler) DeleteChecklistItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid checklist item id", "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.DeleteChecklistItem(c.Request.Context(), id, itemID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// SetRecurrence handles PUT /todos/:id/recurrence
+// @Summary Make a todo recurring
+// @Description Set an RFC 5545 RRULE on a todo. The series starts at the todo's due date and completing an occurrence generates the next one.
+// @Tags todos
+// @Accept json
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param recurrence body v1.RecurrenceRequest true "Recurrence rule"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/todos/{id}/recurrence [put]
+func (h *TodoHandler) SetRecurrence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.RecurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.SetRecurrence(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// ClearRecurrence handles DELETE /todos/:id/recurrence
+// @Summary Stop a todo from recurring
+// @Description Remove the recurrence rule from a todo
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Success 200 {object} v1.Todo
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Todo is not recurring"
+// @Router /v1/todos/{id}/recurrence [delete]
+func (h *TodoHandler) ClearRecurrence(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.todoService.ClearRecurrence(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// PreviewOccurrences handles GET /todos/:id/occurrences
+// @Summary Preview upcoming occurrences
+// @Description Get the next occurrences of a recurring todo, starting with its current due date
+// @Tags todos
+// @Produce json
+// @Param id path int true "Todo ID"
+// @Param count query int false "Number of occurrences (1-100, default 10)"
+// @Success 200 {array} string
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Todo is not recurring"
+// @Router /v1/todos/{id}/occurrences [get]
+func (h *TodoHandler) PreviewOccurrences(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	count, err := strconv.Atoi(c.DefaultQuery("count", "10"))
+	if err != nil || count < 1 || count > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "count must be between 1 and 100", "code": codeInvalidRequest})
+		return
+	}
+
+	occurrences, err := h.todoService.PreviewOccurrences(c.Request.Context(), id, count)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrences)
+}
@@ -0,0 +1,400 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TodoListHandler handles HTTP requests for todo lists
+type TodoListHandler struct {
+	listService port.TodoListServicePort
+}
+
+// NewTodoListHandler creates a new TodoListHandler
+func NewTodoListHandler(listService port.TodoListServicePort) *TodoListHandler {
+	return &TodoListHandler{
+		listService: listService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *TodoListHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "Permission denied"
+	case errors.Is(err, domain.ErrInvalidListName):
+		return http.StatusBadRequest, "Invalid list name"
+	case errors.Is(err, domain.ErrInvalidListDeleteMode):
+		return http.StatusBadRequest, "Delete mode must be detach, cascade or restrict"
+	case errors.Is(err, domain.ErrListArchived):
+		return http.StatusConflict, "List is archived"
+	case errors.Is(err, domain.ErrListNotEmpty):
+		return http.StatusConflict, "List still has todos"
+	case errors.Is(err, domain.ErrListOwnerMismatch):
+		return http.StatusBadRequest, "Todo belongs to a different owner than the list"
+	case errors.Is(err, domain.ErrTodoNotInList):
+		return http.StatusBadRequest, "Todo is not in the list"
+	case errors.Is(err, domain.ErrInvalidPosition):
+		return http.StatusBadRequest, "Invalid list position"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// parseListAndTodoIDs reads the :id and :todo_id path parameters
+func parseListAndTodoIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	todoID, err := strconv.Atoi(c.Param("todo_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid todo id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	return id, todoID, true
+}
+
+// CreateList handles POST /lists
+// @Summary Create a new list
+// @Description Create a new todo list owned by the acting user
+// @Tags lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param list body v1.CreateTodoListRequest true "List object"
+// @Success 201 {object} v1.TodoList
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Router /v1/lists [post]
+func (h *TodoListHandler) CreateList(c *gin.Context) {
+	var req v1.CreateTodoListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	list, err := h.listService.CreateList(c.Request.Context(), req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewTodoList(list))
+}
+
+// GetList handles GET /lists/:id
+// @Summary Get a list
+// @Description Get a todo list by ID
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} v1.TodoList
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id} [get]
+func (h *TodoListHandler) GetList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	list, err := h.listService.GetList(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoList(list))
+}
+
+// ListLists handles GET /lists
+// @Summary List todo lists
+// @Description Get the todo lists owned by the acting user. Archived lists are hidden unless requested.
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param include_archived query bool false "Include archived lists"
+// @Success 200 {array} v1.TodoList
+// @Router /v1/lists [get]
+func (h *TodoListHandler) ListLists(c *gin.Context) {
+	includeArchived, err := strconv.ParseBool(c.DefaultQuery("include_archived", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid include_archived parameter", "code": codeInvalidRequest})
+		return
+	}
+
+	lists, err := h.listService.ListLists(c.Request.Context(), includeArchived)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoLists(lists))
+}
+
+// UpdateList handles PUT /lists/:id
+// @Summary Update a list
+// @Description Rename a todo list or change its description
+// @Tags lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param list body v1.UpdateTodoListRequest true "List update object"
+// @Success 200 {object} v1.TodoList
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id} [put]
+func (h *TodoListHandler) UpdateList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.UpdateTodoListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	list, err := h.listService.UpdateList(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoList(list))
+}
+
+// ArchiveList handles POST /lists/:id/archive
+// @Summary Archive a list
+// @Description Hide a list from default listings and freeze its membership
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} v1.TodoList
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/archive [post]
+func (h *TodoListHandler) ArchiveList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	list, err := h.listService.ArchiveList(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoList(list))
+}
+
+// UnarchiveList handles POST /lists/:id/unarchive
+// @Summary Unarchive a list
+// @Description Restore an archived list
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {object} v1.TodoList
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/unarchive [post]
+func (h *TodoListHandler) UnarchiveList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	list, err := h.listService.UnarchiveList(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodoList(list))
+}
+
+// DeleteList handles DELETE /lists/:id
+// @Summary Delete a list
+// @Description Delete a todo list. Member todos are detached (default), deleted (cascade) or block the deletion (restrict).
+// @Tags lists
+// @Security BearerAuth
+// @Param id path int true "List ID"
+// @Param mode query string false "detach, cascade or restrict"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - List still has todos"
+// @Router /v1/lists/{id} [delete]
+func (h *TodoListHandler) DeleteList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	mode := model.ListDeleteMode(c.Query("mode"))
+	if err := h.listService.DeleteList(c.Request.Context(), id, mode); err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListMemberTodos handles GET /lists/:id/todos
+// @Summary List the todos of a list
+// @Description Get the todos of a list in list order
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Success 200 {array} v1.Todo
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/lists/{id}/todos [get]
+func (h *TodoListHandler) ListMemberTodos(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid list id", "code": codeInvalidRequest})
+		return
+	}
+
+	todos, err := h.listService.ListMemberTodos(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodos(todos))
+}
+
+// AddTodo handles PUT /lists/:id/todos/:todo_id
+// @Summary Add a todo to a list
+// @Description Append a todo to the end of a list, moving it out of any other list
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param todo_id path int true "Todo ID"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - List is archived"
+// @Router /v1/lists/{id}/todos/{todo_id} [put]
+func (h *TodoListHandler) AddTodo(c *gin.Context) {
+	id, todoID, ok := parseListAndTodoIDs(c)
+	if !ok {
+		return
+	}
+
+	todo, err := h.listService.AddTodo(c.Request.Context(), id, todoID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// RemoveTodo handles DELETE /lists/:id/todos/:todo_id
+// @Summary Remove a todo from a list
+// @Description Take a todo out of a list without deleting it
+// @Tags lists
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "List ID"
+// @Param todo_id path int true "Todo ID"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - List is archived"
+// @Router /v1/lists/{id}/todos/{todo_id} [delete]
+func (h *TodoListHandler) RemoveTodo(c *gin.Context) {
+	id, todoID, ok := parseListAndTodoIDs(c)
+	if !ok {
+		return
+	}
+
+	todo, err := h.listService.RemoveTodo(c.Request.Context(), id, todoID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
+
+// MoveTodo handles POST /lists/:id/todos/:todo_id/move
+// @Summary Reorder a todo within a list
+// @Description Place a todo after after_id and/or before before_id. Only the moved todo gets a new position.
+// @Tags lists
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param todo_id path int true "Todo ID"
+// @Param move body v1.MoveTodoRequest true "New neighbours"
+// @Success 200 {object} v1.Todo
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - List is archived"
+// @Router /v1/lists/{id}/todos/{todo_id}/move [post]
+func (h *TodoListHandler) MoveTodo(c *gin.Context) {
+	id, todoID, ok := parseListAndTodoIDs(c)
+	if !ok {
+		return
+	}
+
+	var req v1.MoveTodoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	todo, err := h.listService.MoveTodo(c.Request.Context(), id, todoID, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewTodo(todo))
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LastEventIDHeader carries the ID of the last server-sent event a reconnecting client received
+const LastEventIDHeader = "Last-Event-ID"
+
+// streamWriteTimeout bounds a single write to a stream, so a client that
+// stopped reading cannot hold its connection open
+const streamWriteTimeout = 10 * time.Second
+
+// streamRetry is the reconnect delay, in milliseconds, suggested to clients
+const streamRetry = 3000
+
+// TodoStreamHandler handles Server-Sent Events streams of todo changes
+type TodoStreamHandler struct {
+	streamService port.TodoStreamServicePort
+	heartbeat     time.Duration
+}
+
+// NewTodoStreamHandler creates a new TodoStreamHandler that sends a heartbeat
+// comment whenever a stream was idle for the given interval
+func NewTodoStreamHandler(streamService port.TodoStreamServicePort, heartbeat time.Duration) *TodoStreamHandler {
+	return &TodoStreamHandler{
+		streamService: streamService,
+		heartbeat:     heartbeat,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *TodoStreamHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// writeStreamEvent writes a change as a server-sent event
+func writeStreamEvent(w gin.ResponseWriter, event model.TodoStreamEvent) error {
+	data, err := json.Marshal(v1.NewTodoStreamEvent(event))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+// StreamTodos handles GET /todos/stream
+// @Summary Stream todo changes
+// @Description Push created, updated and deleted events for the todos the acting user can see, as Server-Sent Events. Send Last-Event-ID (or last_event_id) to resume after a disconnect; if the missed changes are no longer buffered a reset event asks the client to refetch. Idle streams get heartbeat comments, and clients that fall too far behind are disconnected and should resume. Browsers, whose EventSource cannot set headers, may pass the bearer token in the access_token query parameter or cookie. Joining or leaving a list sends a reset, and todos moved out of a list are deleted for those who saw them only through it.
+// @Tags todos
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param Last-Event-ID header int false "ID of the last event received"
+// @Param last_event_id query int false "ID of the last event received, for clients that cannot set headers"
+// @Param access_token query string false "Bearer token, for clients that cannot set headers"
+// @Success 200 {object} v1.TodoStreamEvent
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /v1/todos/stream [get]
+func (h *TodoStreamHandler) StreamTodos(c *gin.Context) {
+	var lastEventID *int
+	raw := c.GetHeader(LastEventIDHeader)
+	if raw == "" {
+		raw = c.Query("last_event_id")
+	}
+	if raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid last event id", "code": codeInvalidRequest})
+			return
+		}
+		lastEventID = &id
+	}
+
+	sub, err := h.streamService.Subscribe(c.Request.Context(), lastEventID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	rc := http.NewResponseController(w)
+	flush := func() bool {
+		return rc.Flush() == nil
+	}
+
+	_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetry); err != nil {
+		return
+	}
+	for _, event := range sub.Replay() {
+		if err := writeStreamEvent(w, event); err != nil {
+			return
+		}
+	}
+	if !flush() {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Changes():
+			if !ok {
+				// The client fell too far behind; it reconnects and resumes
+				return
+			}
+			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := writeStreamEvent(w, event); err != nil || !flush() {
+				return
+			}
+			heartbeat.Reset(h.heartbeat)
+		case <-heartbeat.C:
+			_ = rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil || !flush() {
+				return
+			}
+		}
+	}
+}
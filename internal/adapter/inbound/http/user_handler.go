@@ -3,10 +3,12 @@ package http
 import (
 	"errors"
 	"net/http"
+	"net/url"
+	"path"
 	"strconv"
 
+	"go-boilerplate/internal/adapter/inbound/http/v1"
 	"go-boilerplate/internal/domain"
-	"go-boilerplate/internal/domain/model"
 	"go-boilerplate/internal/domain/port"
 
 	"github.com/gin-gonic/gin"
@@ -35,6 +37,12 @@ func (h *UserHandler) mapDomainErrorToHTTP(err error) (int, string) {
 		return http.StatusConflict, "Username already exists"
 	case errors.Is(err, domain.ErrInvalidEmail):
 		return http.StatusBadRequest, "Invalid email format"
+	case errors.Is(err, domain.ErrUsernameUnchanged):
+		return http.StatusBadRequest, "New username is the same as the current one"
+	case errors.Is(err, domain.ErrUsernameReserved):
+		return http.StatusConflict, "Username is reserved by a recent rename"
+	case errors.Is(err, domain.ErrUsernameInTrash):
+		return http.StatusConflict, "Username belongs to a deleted user; it is released when the user is purged"
 	case errors.Is(err, domain.ErrDuplicate):
 		return http.StatusConflict, "Resource already exists"
 	default:
@@ -48,27 +56,27 @@ func (h *UserHandler) mapDomainErrorToHTTP(err error) (int, string) {
 // @Tags users
 // @Accept json
 // @Produce json
-// @Param user body model.CreateUserRequest true "User object"
-// @Success 201 {object} model.User
+// @Param user body v1.CreateUserRequest true "User object"
+// @Success 201 {object} v1.User
 // @Failure 400 {object} map[string]string "Bad Request"
-// @Failure 409 {object} map[string]string "Conflict - Username already exists"
+// @Failure 409 {object} map[string]string "Conflict - Username already exists or belongs to a deleted user"
 // @Failure 500 {object} map[string]string "Internal Server Error"
-// @Router /users [post]
+// @Router /v1/users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	var req model.CreateUserRequest
+	var req v1.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
 		return
 	}
 
-	user, err := h.userService.CreateUser(c.Request.Context(), &req)
+	user, err := h.userService.CreateUser(c.Request.Context(), req.ToModel())
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	c.JSON(http.StatusCreated, v1.NewUser(user))
 }
 
 // GetUser handles GET /users/:id
@@ -77,26 +85,55 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 // @Tags users
 // @Produce json
 // @Param id path int true "User ID"
-// @Success 200 {object} model.User
+// @Success 200 {object} v1.User
 // @Failure 400 {object} map[string]string "Bad Request - Invalid ID"
 // @Failure 404 {object} map[string]string "Not Found"
 // @Failure 500 {object} map[string]string "Internal Server Error"
-// @Router /users/{id} [get]
+// @Router /v1/users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id", "code": codeInvalidRequest})
 		return
 	}
 
 	user, err := h.userService.GetUser(c.Request.Context(), id)
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, v1.NewUser(user))
+}
+
+// GetUserByUsername handles GET /users/by-username/:name
+// @Summary Get a user by username
+// @Description Get user information by username. A username released by a rename within the grace period redirects to the user's current username.
+// @Tags users
+// @Produce json
+// @Param name path string true "Username"
+// @Success 200 {object} v1.User
+// @Success 302 "Found - Username was renamed"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/users/by-username/{name} [get]
+func (h *UserHandler) GetUserByUsername(c *gin.Context) {
+	name := c.Param("name")
+
+	user, err := h.userService.GetUserByUsername(c.Request.Context(), name)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	if user.Username != name {
+		c.Redirect(http.StatusFound, path.Join(path.Dir(c.Request.URL.Path), url.PathEscape(user.Username)))
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewUser(user))
 }
 
 // ListUsers handles GET /users
@@ -104,18 +141,18 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Description Get a list of all users
 // @Tags users
 // @Produce json
-// @Success 200 {array} model.User
+// @Success 200 {array} v1.User
 // @Failure 500 {object} map[string]string "Internal Server Error"
-// @Router /users [get]
+// @Router /v1/users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	users, err := h.userService.ListUsers(c.Request.Context())
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	c.JSON(http.StatusOK, v1.NewUsers(users))
 }
 
 // UpdateUser handles PUT /users/:id
@@ -125,57 +162,141 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID"
-// @Param user body model.UpdateUserRequest true "User update object"
-// @Success 200 {object} model.User
+// @Param user body v1.UpdateUserRequest true "User update object"
+// @Success 200 {object} v1.User
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 404 {object} map[string]string "Not Found"
 // @Failure 500 {object} map[string]string "Internal Server Error"
-// @Router /users/{id} [put]
+// @Router /v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewUser(user))
+}
+
+// RenameUser handles PUT /users/:id/username
+// @Summary Change a user's username
+// @Description Change the username of a user. The previous username redirects to the user and stays reserved for a grace period.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param user body v1.RenameUserRequest true "New username"
+// @Success 200 {object} v1.User
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict - Username already exists, is reserved or belongs to a deleted user"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/users/{id}/username [put]
+func (h *UserHandler) RenameUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id", "code": codeInvalidRequest})
 		return
 	}
 
-	var req model.UpdateUserRequest
+	var req v1.RenameUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
 		return
 	}
 
-	user, err := h.userService.UpdateUser(c.Request.Context(), id, &req)
+	user, err := h.userService.RenameUser(c.Request.Context(), id, req.ToModel())
 	if err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, v1.NewUser(user))
 }
 
 // DeleteUser handles DELETE /users/:id
 // @Summary Delete a user
-// @Description Delete a user by user ID
+// @Description Move a user to the trash. The user can be restored until the trash retention period ends.
 // @Tags users
 // @Param id path int true "User ID"
 // @Success 204 "No Content"
 // @Failure 400 {object} map[string]string "Bad Request - Invalid ID"
 // @Failure 404 {object} map[string]string "Not Found"
 // @Failure 500 {object} map[string]string "Internal Server Error"
-// @Router /users/{id} [delete]
+// @Router /v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id", "code": codeInvalidRequest})
 		return
 	}
 
 	if err := h.userService.DeleteUser(c.Request.Context(), id); err != nil {
 		statusCode, message := h.mapDomainErrorToHTTP(err)
-		c.JSON(statusCode, gin.H{"error": message})
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
+
+// ListDeletedUsers handles GET /users/trash
+// @Summary List deleted users
+// @Description Get the users in the trash
+// @Tags users
+// @Produce json
+// @Success 200 {array} v1.User
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/users/trash [get]
+func (h *UserHandler) ListDeletedUsers(c *gin.Context) {
+	users, err := h.userService.ListDeletedUsers(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewUsers(users))
+}
+
+// RestoreUser handles POST /users/:id/restore
+// @Summary Restore a user
+// @Description Move a user out of the trash
+// @Tags users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} v1.User
+// @Failure 400 {object} map[string]string "Bad Request - Invalid ID"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /v1/users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id", "code": codeInvalidRequest})
+		return
+	}
+
+	user, err := h.userService.RestoreUser(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewUser(user))
+}
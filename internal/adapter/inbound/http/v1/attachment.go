@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// Attachment is a file attached to a todo as returned by the v1 API
+type Attachment struct {
+	ID          int       `json:"id" example:"1"`
+	TodoID      int       `json:"todo_id" example:"1"`
+	UploaderID  *int      `json:"uploader_id,omitempty" example:"1"`
+	Filename    string    `json:"filename" example:"screenshot.png"`
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"48213"`
+	SHA256      string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// NewAttachment converts an attachment to its v1 form
+func NewAttachment(attachment *model.Attachment) *Attachment {
+	return &Attachment{
+		ID:          attachment.ID,
+		TodoID:      attachment.TodoID,
+		UploaderID:  attachment.UploaderID,
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.SHA256,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
+
+// NewAttachments converts attachments to their v1 form
+func NewAttachments(attachments []*model.Attachment) []*Attachment {
+	resp := make([]*Attachment, len(attachments))
+	for i, attachment := range attachments {
+		resp[i] = NewAttachment(attachment)
+	}
+	return resp
+}
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// AuditEntry is an entry of the audit log as returned by the v1 API
+type AuditEntry struct {
+	Sequence   int64                `json:"sequence" example:"1"`
+	Timestamp  time.Time            `json:"timestamp"`
+	ActorID    *int                 `json:"actor_id,omitempty" example:"1"`
+	RequestID  string               `json:"request_id,omitempty" example:"4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"`
+	Action     string               `json:"action" example:"update"`
+	EntityType string               `json:"entity_type" example:"todo"`
+	EntityID   int                  `json:"entity_id" example:"1"`
+	Changes    map[string]FieldDiff `json:"changes"`
+	PrevHash   string               `json:"prev_hash"`
+	Hash       string               `json:"hash"`
+}
+
+// FieldDiff is the value of a field before and after an audited change
+type FieldDiff struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditVerification is the result of checking the audit log's hash chain
+type AuditVerification struct {
+	Valid   bool `json:"valid" example:"true"`
+	Entries int  `json:"entries" example:"42"`
+	// BrokenAt is the sequence of the first entry whose hash does not match
+	BrokenAt *int64 `json:"broken_at,omitempty" example:"17"`
+}
+
+// NewAuditEntries converts audit log entries to their v1 form
+func NewAuditEntries(entries []*model.AuditEntry) []*AuditEntry {
+	resp := make([]*AuditEntry, len(entries))
+	for i, e := range entries {
+		resp[i] = &AuditEntry{
+			Sequence:   e.Sequence,
+			Timestamp:  e.Timestamp,
+			ActorID:    e.ActorID,
+			RequestID:  e.RequestID,
+			Action:     string(e.Action),
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			PrevHash:   e.PrevHash,
+			Hash:       e.Hash,
+		}
+		if e.Changes != nil {
+			resp[i].Changes = make(map[string]FieldDiff, len(e.Changes))
+			for field, diff := range e.Changes {
+				resp[i].Changes[field] = FieldDiff{Before: diff.Before, After: diff.After}
+			}
+		}
+	}
+	return resp
+}
+
+// NewAuditVerification converts an audit log check to its v1 form
+func NewAuditVerification(v *model.AuditVerification) *AuditVerification {
+	return &AuditVerification{Valid: v.Valid, Entries: v.Entries, BrokenAt: v.BrokenAt}
+}
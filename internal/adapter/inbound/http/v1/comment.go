@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// Comment is a comment on a todo as returned by the v1 API
+type Comment struct {
+	ID        int            `json:"id" example:"1"`
+	TodoID    int            `json:"todo_id" example:"1"`
+	ParentID  *int           `json:"parent_id,omitempty" example:"1"`
+	AuthorID  int            `json:"author_id" example:"1"`
+	Author    *CommentAuthor `json:"author,omitempty"`
+	Body      string         `json:"body" example:"Can @janedoe take a look?"`
+	Mentions  []int          `json:"mentions"`
+	Edited    bool           `json:"edited"`
+	Deleted   bool           `json:"deleted"`
+	Replies   []*Comment     `json:"replies,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// CommentAuthor is the user who wrote a comment
+type CommentAuthor struct {
+	ID       int    `json:"id" example:"1"`
+	Username string `json:"username" example:"johndoe"`
+	Name     string `json:"name" example:"John Doe"`
+}
+
+// CommentRevision is an earlier body of a comment
+type CommentRevision struct {
+	CommentID int       `json:"comment_id" example:"1"`
+	Body      string    `json:"body" example:"Can @jane take a look?"`
+	Deleted   bool      `json:"deleted"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// Activity is an entry of a todo's activity feed
+type Activity struct {
+	Type      string    `json:"type" example:"field_changed"`
+	TodoID    int       `json:"todo_id" example:"1"`
+	ActorID   *int      `json:"actor_id,omitempty" example:"1"`
+	CommentID *int      `json:"comment_id,omitempty" example:"1"`
+	Field     string    `json:"field,omitempty" example:"priority"`
+	From      string    `json:"from,omitempty" example:"medium"`
+	To        string    `json:"to,omitempty" example:"high"`
+	At        time.Time `json:"at"`
+}
+
+// NewComment converts a comment and its replies to their v1 form
+func NewComment(comment *model.Comment) *Comment {
+	resp := &Comment{
+		ID:        comment.ID,
+		TodoID:    comment.TodoID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Body:      comment.Body,
+		Mentions:  comment.Mentions,
+		Edited:    comment.Edited,
+		Deleted:   comment.Deleted,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+		DeletedAt: comment.DeletedAt,
+	}
+	if a := comment.Author; a != nil {
+		resp.Author = &CommentAuthor{ID: a.ID, Username: a.Username, Name: a.Name}
+	}
+	if comment.Replies != nil {
+		resp.Replies = NewComments(comment.Replies)
+	}
+	return resp
+}
+
+// NewComments converts comments to their v1 form
+func NewComments(comments []*model.Comment) []*Comment {
+	resp := make([]*Comment, len(comments))
+	for i, comment := range comments {
+		resp[i] = NewComment(comment)
+	}
+	return resp
+}
+
+// NewCommentRevisions converts comment revisions to their v1 form
+func NewCommentRevisions(revisions []*model.CommentRevision) []*CommentRevision {
+	resp := make([]*CommentRevision, len(revisions))
+	for i, r := range revisions {
+		resp[i] = &CommentRevision{
+			CommentID: r.CommentID,
+			Body:      r.Body,
+			Deleted:   r.Deleted,
+			ChangedAt: r.ChangedAt,
+		}
+	}
+	return resp
+}
+
+// NewActivities converts activity feed entries to their v1 form
+func NewActivities(activities []*model.Activity) []*Activity {
+	resp := make([]*Activity, len(activities))
+	for i, a := range activities {
+		resp[i] = &Activity{
+			Type:      string(a.Type),
+			TodoID:    a.TodoID,
+			ActorID:   a.ActorID,
+			CommentID: a.CommentID,
+			Field:     a.Field,
+			From:      a.From,
+			To:        a.To,
+			At:        a.At,
+		}
+	}
+	return resp
+}
+
+// CreateCommentRequest is the body of a request to comment on a todo
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required" example:"Can @janedoe take a look?"`
+	ParentID *int   `json:"parent_id,omitempty" example:"1"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateCommentRequest) ToModel() *model.CreateCommentRequest {
+	return &model.CreateCommentRequest{Body: r.Body, ParentID: r.ParentID}
+}
+
+// UpdateCommentRequest is the body of a request to edit a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required" example:"Can @janedoe take a look today?"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateCommentRequest) ToModel() *model.UpdateCommentRequest {
+	return &model.UpdateCommentRequest{Body: r.Body}
+}
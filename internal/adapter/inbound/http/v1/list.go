@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoList is a todo list as returned by the v1 API
+type TodoList struct {
+	ID          int        `json:"id" example:"1"`
+	OwnerID     int        `json:"owner_id" example:"1"`
+	Name        string     `json:"name" example:"Home renovation"`
+	Description string     `json:"description" example:"Everything for the new kitchen"`
+	Archived    bool       `json:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewTodoList converts a list to its v1 form
+func NewTodoList(list *model.TodoList) *TodoList {
+	return &TodoList{
+		ID:          list.ID,
+		OwnerID:     list.OwnerID,
+		Name:        list.Name,
+		Description: list.Description,
+		Archived:    list.Archived,
+		ArchivedAt:  list.ArchivedAt,
+		CreatedAt:   list.CreatedAt,
+		UpdatedAt:   list.UpdatedAt,
+	}
+}
+
+// NewTodoLists converts lists to their v1 form
+func NewTodoLists(lists []*model.TodoList) []*TodoList {
+	resp := make([]*TodoList, len(lists))
+	for i, list := range lists {
+		resp[i] = NewTodoList(list)
+	}
+	return resp
+}
+
+// CreateTodoListRequest is the body of a request to create a list
+type CreateTodoListRequest struct {
+	Name        string `json:"name" binding:"required" example:"Home renovation"`
+	Description string `json:"description" example:"Everything for the new kitchen"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateTodoListRequest) ToModel() *model.CreateTodoListRequest {
+	return &model.CreateTodoListRequest{Name: r.Name, Description: r.Description}
+}
+
+// UpdateTodoListRequest is the body of a request to update a list
+type UpdateTodoListRequest struct {
+	Name        string `json:"name" example:"Kitchen renovation"`
+	Description string `json:"description" example:"New cabinets and appliances"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateTodoListRequest) ToModel() *model.UpdateTodoListRequest {
+	return &model.UpdateTodoListRequest{Name: r.Name, Description: r.Description}
+}
+
+// MoveTodoRequest is the body of a request to reorder a todo within its list
+type MoveTodoRequest struct {
+	AfterID  *int `json:"after_id" example:"3"`
+	BeforeID *int `json:"before_id" example:"4"`
+}
+
+// ToModel converts the request to its domain form
+func (r *MoveTodoRequest) ToModel() *model.MoveTodoRequest {
+	return &model.MoveTodoRequest{AfterID: r.AfterID, BeforeID: r.BeforeID}
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// ListMember is a collaborator on a list as returned by the v1 API
+type ListMember struct {
+	ListID  int       `json:"list_id" example:"1"`
+	UserID  int       `json:"user_id" example:"2"`
+	Role    string    `json:"role" example:"editor"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// NewListMember converts a list member to its v1 form
+func NewListMember(member *model.ListMember) *ListMember {
+	return &ListMember{
+		ListID:  member.ListID,
+		UserID:  member.UserID,
+		Role:    string(member.Role),
+		AddedAt: member.AddedAt,
+	}
+}
+
+// NewListMembers converts list members to their v1 form
+func NewListMembers(members []*model.ListMember) []*ListMember {
+	resp := make([]*ListMember, len(members))
+	for i, member := range members {
+		resp[i] = NewListMember(member)
+	}
+	return resp
+}
+
+// ListInvitation is an invitation to a list as returned by the v1 API
+type ListInvitation struct {
+	ID              int        `json:"id" example:"1"`
+	ListID          int        `json:"list_id" example:"1"`
+	InviterID       int        `json:"inviter_id" example:"1"`
+	InviteeID       int        `json:"invitee_id" example:"2"`
+	InviteeUsername string     `json:"invitee_username" example:"janedoe"`
+	Role            string     `json:"role" example:"editor"`
+	Status          string     `json:"status" example:"pending"`
+	CreatedAt       time.Time  `json:"created_at"`
+	RespondedAt     *time.Time `json:"responded_at,omitempty"`
+}
+
+// NewListInvitation converts an invitation to its v1 form
+func NewListInvitation(invitation *model.ListInvitation) *ListInvitation {
+	return &ListInvitation{
+		ID:              invitation.ID,
+		ListID:          invitation.ListID,
+		InviterID:       invitation.InviterID,
+		InviteeID:       invitation.InviteeID,
+		InviteeUsername: invitation.InviteeUsername,
+		Role:            string(invitation.Role),
+		Status:          string(invitation.Status),
+		CreatedAt:       invitation.CreatedAt,
+		RespondedAt:     invitation.RespondedAt,
+	}
+}
+
+// NewListInvitations converts invitations to their v1 form
+func NewListInvitations(invitations []*model.ListInvitation) []*ListInvitation {
+	resp := make([]*ListInvitation, len(invitations))
+	for i, invitation := range invitations {
+		resp[i] = NewListInvitation(invitation)
+	}
+	return resp
+}
+
+// UpdateListMemberRequest is the body of a request to change a member's role
+type UpdateListMemberRequest struct {
+	Role string `json:"role" binding:"required" example:"viewer"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateListMemberRequest) ToModel() *model.UpdateListMemberRequest {
+	return &model.UpdateListMemberRequest{Role: model.ListRole(r.Role)}
+}
+
+// InviteToListRequest is the body of a request to invite a user to a list
+type InviteToListRequest struct {
+	Username string `json:"username" binding:"required" example:"janedoe"`
+	Role     string `json:"role" binding:"required" example:"editor"`
+}
+
+// ToModel converts the request to its domain form
+func (r *InviteToListRequest) ToModel() *model.InviteToListRequest {
+	return &model.InviteToListRequest{Username: r.Username, Role: model.ListRole(r.Role)}
+}
@@ -0,0 +1,65 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoStats is a summary of todos as returned by the v1 API
+type TodoStats struct {
+	Total    int            `json:"total" example:"12"`
+	ByStatus map[string]int `json:"by_status"`
+	ByOwner  map[int]int    `json:"by_owner"`
+	ByTag    map[int]int    `json:"by_tag"`
+	// Overdue counts open todos whose due date has passed
+	Overdue    int              `json:"overdue" example:"2"`
+	Projection ProjectionStatus `json:"projection"`
+}
+
+// ProjectionStatus describes how far a read model has caught up with the events
+type ProjectionStatus struct {
+	Name          string     `json:"name" example:"todo_stats"`
+	EventsApplied int64      `json:"events_applied" example:"42"`
+	LastEventID   string     `json:"last_event_id,omitempty"`
+	LastEventAt   *time.Time `json:"last_event_at,omitempty"`
+	LastAppliedAt *time.Time `json:"last_applied_at,omitempty"`
+	RebuiltAt     *time.Time `json:"rebuilt_at,omitempty"`
+	// EventsPending counts the events published but not yet applied
+	EventsPending int `json:"events_pending" example:"0"`
+	// LagSeconds is how long the oldest pending event has waited, or how long
+	// the last applied event took to reach the read model when none is pending
+	LagSeconds float64 `json:"lag_seconds" example:"0.4"`
+}
+
+// NewTodoStats converts todo statistics to their v1 form
+func NewTodoStats(stats *model.TodoStats) *TodoStats {
+	resp := &TodoStats{
+		Total:      stats.Total,
+		ByOwner:    stats.ByOwner,
+		ByTag:      stats.ByTag,
+		Overdue:    stats.Overdue,
+		Projection: *NewProjectionStatus(&stats.Projection),
+	}
+	if stats.ByStatus != nil {
+		resp.ByStatus = make(map[string]int, len(stats.ByStatus))
+		for status, n := range stats.ByStatus {
+			resp.ByStatus[string(status)] = n
+		}
+	}
+	return resp
+}
+
+// NewProjectionStatus converts a projection status to its v1 form
+func NewProjectionStatus(status *model.ProjectionStatus) *ProjectionStatus {
+	return &ProjectionStatus{
+		Name:          status.Name,
+		EventsApplied: status.EventsApplied,
+		LastEventID:   status.LastEventID,
+		LastEventAt:   status.LastEventAt,
+		LastAppliedAt: status.LastAppliedAt,
+		RebuiltAt:     status.RebuiltAt,
+		EventsPending: status.EventsPending,
+		LagSeconds:    status.LagSeconds,
+	}
+}
@@ -0,0 +1,30 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoStreamEvent is a change pushed on the todo stream
+type TodoStreamEvent struct {
+	ID         int       `json:"id" example:"42"`
+	Type       string    `json:"type" example:"updated"`
+	TodoID     int       `json:"todo_id,omitempty" example:"1"`
+	Todo       *Todo     `json:"todo,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewTodoStreamEvent converts a stream event to its v1 form
+func NewTodoStreamEvent(event model.TodoStreamEvent) *TodoStreamEvent {
+	resp := &TodoStreamEvent{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		TodoID:     event.TodoID,
+		OccurredAt: event.OccurredAt,
+	}
+	if event.Todo != nil {
+		resp.Todo = NewTodo(event.Todo)
+	}
+	return resp
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// Tag is a tag as returned by the v1 API
+type Tag struct {
+	ID        int       `json:"id" example:"1"`
+	OwnerID   int       `json:"owner_id" example:"1"`
+	Name      string    `json:"name" example:"work"`
+	Color     string    `json:"color,omitempty" example:"#ff8800"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewTag converts a tag to its v1 form
+func NewTag(tag *model.Tag) *Tag {
+	return &Tag{
+		ID:        tag.ID,
+		OwnerID:   tag.OwnerID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		CreatedAt: tag.CreatedAt,
+	}
+}
+
+// NewTags converts tags to their v1 form
+func NewTags(tags []*model.Tag) []*Tag {
+	resp := make([]*Tag, len(tags))
+	for i, tag := range tags {
+		resp[i] = NewTag(tag)
+	}
+	return resp
+}
+
+// CreateTagRequest is the body of a request to create a tag
+type CreateTagRequest struct {
+	OwnerID int    `json:"owner_id" example:"1"`
+	Name    string `json:"name" binding:"required" example:"work"`
+	Color   string `json:"color" example:"#ff8800"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateTagRequest) ToModel() *model.CreateTagRequest {
+	return &model.CreateTagRequest{OwnerID: r.OwnerID, Name: r.Name, Color: r.Color}
+}
+
+// UpdateTagRequest is the body of a request to rename or recolor a tag
+type UpdateTagRequest struct {
+	Name  string `json:"name" example:"office"`
+	Color string `json:"color" example:"#0088ff"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateTagRequest) ToModel() *model.UpdateTagRequest {
+	return &model.UpdateTagRequest{Name: r.Name, Color: r.Color}
+}
+
+// MergeTagsRequest is the body of a request to merge a tag into another
+type MergeTagsRequest struct {
+	TargetID int `json:"target_id" binding:"required" example:"2"`
+}
+
+// ToModel converts the request to its domain form
+func (r *MergeTagsRequest) ToModel() *model.MergeTagsRequest {
+	return &model.MergeTagsRequest{TargetID: r.TargetID}
+}
@@ -0,0 +1,254 @@
+// Package v1 holds the request and response bodies of version 1 of the HTTP
+// API. They are kept apart from the domain models so that the models can
+// change without changing what v1 clients send and receive.
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// Todo is a todo as returned by the v1 API
+type Todo struct {
+	ID          int             `json:"id"`
+	OwnerID     int             `json:"owner_id" example:"1"`
+	ParentID    *int            `json:"parent_id,omitempty" example:"1"`
+	ListID      *int            `json:"list_id,omitempty" example:"1"`
+	Position    string          `json:"position,omitempty" example:"V"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Completed   bool            `json:"completed"`
+	Status      string          `json:"status" example:"open"`
+	Priority    string          `json:"priority" example:"medium"`
+	DueDate     *time.Time      `json:"due_date,omitempty"`
+	Timezone    string          `json:"timezone,omitempty" example:"Asia/Seoul"`
+	TagIDs      []int           `json:"tag_ids"`
+	Checklist   []ChecklistItem `json:"checklist"`
+	Recurrence  *Recurrence     `json:"recurrence,omitempty"`
+	Progress    float64         `json:"progress" example:"0.5"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	DeletedAt   *time.Time      `json:"deleted_at,omitempty"`
+}
+
+// ChecklistItem is an item of a todo's checklist
+type ChecklistItem struct {
+	ID      int    `json:"id" example:"1"`
+	Text    string `json:"text" example:"Write the summary"`
+	Checked bool   `json:"checked"`
+}
+
+// Recurrence is the rule a recurring todo repeats by
+type Recurrence struct {
+	RRule          string      `json:"rrule" example:"FREQ=WEEKLY;BYDAY=SA"`
+	Start          time.Time   `json:"start"`
+	ExceptionDates []time.Time `json:"exception_dates"`
+	NextTodoID     *int        `json:"next_todo_id,omitempty" example:"2"`
+}
+
+// TodoTransition is a recorded status change of a todo
+type TodoTransition struct {
+	TodoID int       `json:"todo_id" example:"1"`
+	From   string    `json:"from" example:"open"`
+	To     string    `json:"to" example:"in_progress"`
+	Reason string    `json:"reason,omitempty" example:"Started working on it"`
+	At     time.Time `json:"at"`
+}
+
+// TodoEvent is a stored change to an event-sourced todo
+type TodoEvent struct {
+	TodoID  int    `json:"todo_id" example:"1"`
+	Version int    `json:"version" example:"3"`
+	Type    string `json:"type" example:"updated"`
+	// Changes holds the fields the event set, keyed by their JSON name;
+	// a null value clears the field
+	Changes    map[string]json.RawMessage `json:"changes" swaggertype:"object"`
+	ActorID    *int                       `json:"actor_id,omitempty" example:"1"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// NewTodo converts a todo to its v1 form
+func NewTodo(todo *model.Todo) *Todo {
+	resp := &Todo{
+		ID:          todo.ID,
+		OwnerID:     todo.OwnerID,
+		ParentID:    todo.ParentID,
+		ListID:      todo.ListID,
+		Position:    todo.Position,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Completed:   todo.Completed,
+		Status:      string(todo.Status),
+		Priority:    string(todo.Priority),
+		DueDate:     todo.DueDate,
+		Timezone:    todo.Timezone,
+		TagIDs:      todo.TagIDs,
+		Progress:    todo.Progress,
+		CreatedAt:   todo.CreatedAt,
+		UpdatedAt:   todo.UpdatedAt,
+		CompletedAt: todo.CompletedAt,
+		DeletedAt:   todo.DeletedAt,
+	}
+	if todo.Checklist != nil {
+		resp.Checklist = make([]ChecklistItem, len(todo.Checklist))
+		for i, item := range todo.Checklist {
+			resp.Checklist[i] = ChecklistItem{ID: item.ID, Text: item.Text, Checked: item.Checked}
+		}
+	}
+	if r := todo.Recurrence; r != nil {
+		resp.Recurrence = &Recurrence{
+			RRule:          r.RRule,
+			Start:          r.Start,
+			ExceptionDates: r.ExceptionDates,
+			NextTodoID:     r.NextTodoID,
+		}
+	}
+	return resp
+}
+
+// NewTodos converts todos to their v1 form
+func NewTodos(todos []*model.Todo) []*Todo {
+	resp := make([]*Todo, len(todos))
+	for i, todo := range todos {
+		resp[i] = NewTodo(todo)
+	}
+	return resp
+}
+
+// NewTodoTransitions converts status changes to their v1 form
+func NewTodoTransitions(transitions []*model.TodoTransition) []*TodoTransition {
+	resp := make([]*TodoTransition, len(transitions))
+	for i, t := range transitions {
+		resp[i] = &TodoTransition{
+			TodoID: t.TodoID,
+			From:   string(t.From),
+			To:     string(t.To),
+			Reason: t.Reason,
+			At:     t.At,
+		}
+	}
+	return resp
+}
+
+// NewTodoEvents converts stored todo events to their v1 form
+func NewTodoEvents(events []*model.TodoEvent) []*TodoEvent {
+	resp := make([]*TodoEvent, len(events))
+	for i, e := range events {
+		resp[i] = &TodoEvent{
+			TodoID:     e.TodoID,
+			Version:    e.Version,
+			Type:       string(e.Type),
+			Changes:    e.Changes,
+			ActorID:    e.ActorID,
+			OccurredAt: e.OccurredAt,
+		}
+	}
+	return resp
+}
+
+// CreateTodoRequest is the body of a request to create a todo
+type CreateTodoRequest struct {
+	OwnerID          int                `json:"owner_id" example:"1"`
+	ParentID         *int               `json:"parent_id" example:"1"`
+	Title            string             `json:"title" binding:"required"`
+	Description      string             `json:"description"`
+	Priority         string             `json:"priority" example:"medium"`
+	DueDate          *time.Time         `json:"due_date"`
+	Timezone         string             `json:"timezone" example:"Asia/Seoul"`
+	AllowPastDueDate bool               `json:"allow_past_due_date"`
+	Recurrence       *RecurrenceRequest `json:"recurrence"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateTodoRequest) ToModel() *model.CreateTodoRequest {
+	return &model.CreateTodoRequest{
+		OwnerID:          r.OwnerID,
+		ParentID:         r.ParentID,
+		Title:            r.Title,
+		Description:      r.Description,
+		Priority:         model.Priority(r.Priority),
+		DueDate:          r.DueDate,
+		Timezone:         r.Timezone,
+		AllowPastDueDate: r.AllowPastDueDate,
+		Recurrence:       r.Recurrence.ToModel(),
+	}
+}
+
+// UpdateTodoRequest is the body of a request to update a todo
+type UpdateTodoRequest struct {
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Completed        *bool      `json:"completed"`
+	Priority         string     `json:"priority" example:"high"`
+	DueDate          *time.Time `json:"due_date"`
+	Timezone         string     `json:"timezone" example:"Asia/Seoul"`
+	ClearDueDate     bool       `json:"clear_due_date"`
+	ParentID         *int       `json:"parent_id" example:"1"`
+	ClearParent      bool       `json:"clear_parent"`
+	AllowPastDueDate bool       `json:"allow_past_due_date"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateTodoRequest) ToModel() *model.UpdateTodoRequest {
+	return &model.UpdateTodoRequest{
+		Title:            r.Title,
+		Description:      r.Description,
+		Completed:        r.Completed,
+		Priority:         model.Priority(r.Priority),
+		DueDate:          r.DueDate,
+		Timezone:         r.Timezone,
+		ClearDueDate:     r.ClearDueDate,
+		ParentID:         r.ParentID,
+		ClearParent:      r.ClearParent,
+		AllowPastDueDate: r.AllowPastDueDate,
+	}
+}
+
+// TransitionTodoRequest is the body of a request to move a todo to another status
+type TransitionTodoRequest struct {
+	To     string `json:"to" binding:"required" example:"in_progress"`
+	Reason string `json:"reason" example:"Started working on it"`
+}
+
+// ToModel converts the request to its domain form
+func (r *TransitionTodoRequest) ToModel() *model.TransitionTodoRequest {
+	return &model.TransitionTodoRequest{To: model.TodoStatus(r.To), Reason: r.Reason}
+}
+
+// CreateChecklistItemRequest is the body of a request to add a checklist item
+type CreateChecklistItemRequest struct {
+	Text string `json:"text" binding:"required" example:"Write the summary"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateChecklistItemRequest) ToModel() *model.CreateChecklistItemRequest {
+	return &model.CreateChecklistItemRequest{Text: r.Text}
+}
+
+// UpdateChecklistItemRequest is the body of a request to edit or check a checklist item
+type UpdateChecklistItemRequest struct {
+	Text    string `json:"text" example:"Write the executive summary"`
+	Checked *bool  `json:"checked"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateChecklistItemRequest) ToModel() *model.UpdateChecklistItemRequest {
+	return &model.UpdateChecklistItemRequest{Text: r.Text, Checked: r.Checked}
+}
+
+// RecurrenceRequest is the body of a request to make a todo repeat
+type RecurrenceRequest struct {
+	RRule          string      `json:"rrule" binding:"required" example:"FREQ=WEEKLY;BYDAY=SA"`
+	ExceptionDates []time.Time `json:"exception_dates"`
+}
+
+// ToModel converts the request to its domain form; a nil request stays nil
+func (r *RecurrenceRequest) ToModel() *model.RecurrenceRequest {
+	if r == nil {
+		return nil
+	}
+	return &model.RecurrenceRequest{RRule: r.RRule, ExceptionDates: r.ExceptionDates}
+}
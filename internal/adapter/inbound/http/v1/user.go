@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// User is a user as returned by the v1 API
+type User struct {
+	ID        int        `json:"id" example:"1"`
+	Username  string     `json:"username" example:"johndoe"`
+	Email     string     `json:"email" example:"john@example.com"`
+	Name      string     `json:"name" example:"John Doe"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// NewUser converts a user to its v1 form
+func NewUser(user *model.User) *User {
+	return &User{
+		ID:        user.ID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Name:      user.Name,
+		DeletedAt: user.DeletedAt,
+	}
+}
+
+// NewUsers converts users to their v1 form
+func NewUsers(users []*model.User) []*User {
+	resp := make([]*User, len(users))
+	for i, user := range users {
+		resp[i] = NewUser(user)
+	}
+	return resp
+}
+
+// CreateUserRequest is the body of a request to create a user
+type CreateUserRequest struct {
+	Username string `json:"username" binding:"required" example:"johndoe"`
+	Email    string `json:"email" binding:"required" example:"john@example.com"`
+	Name     string `json:"name" binding:"required" example:"John Doe"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateUserRequest) ToModel() *model.CreateUserRequest {
+	return &model.CreateUserRequest{Username: r.Username, Email: r.Email, Name: r.Name}
+}
+
+// UpdateUserRequest is the body of a request to update a user
+type UpdateUserRequest struct {
+	Email string `json:"email" example:"john.new@example.com"`
+	Name  string `json:"name" example:"John Smith"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateUserRequest) ToModel() *model.UpdateUserRequest {
+	return &model.UpdateUserRequest{Email: r.Email, Name: r.Name}
+}
+
+// RenameUserRequest is the body of a request to change a user's username
+type RenameUserRequest struct {
+	Username string `json:"username" binding:"required" example:"john.doe"`
+}
+
+// ToModel converts the request to its domain form
+func (r *RenameUserRequest) ToModel() *model.RenameUserRequest {
+	return &model.RenameUserRequest{Username: r.Username}
+}
@@ -0,0 +1,165 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// Webhook is a webhook subscription as returned by the v1 API
+type Webhook struct {
+	ID      int    `json:"id" example:"1"`
+	OwnerID int    `json:"owner_id" example:"1"`
+	URL     string `json:"url" example:"https://example.com/hooks/todos"`
+	// EventTypes lists the subscribed events; empty subscribes to all of them
+	EventTypes []string `json:"event_types"`
+	// Secret signs deliveries. It is only returned when the webhook is created.
+	Secret string `json:"secret,omitempty" example:"s3cr3t"`
+	Active bool   `json:"active" example:"true"`
+	// ConsecutiveFailures counts failed attempts since the last successful
+	// delivery; the webhook is disabled when it reaches the configured limit
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// WebhookDelivery is a delivery of an event to a webhook
+type WebhookDelivery struct {
+	ID        int             `json:"id" example:"1"`
+	WebhookID int             `json:"webhook_id" example:"1"`
+	EventID   string          `json:"event_id"`
+	EventType string          `json:"event_type" example:"todo.completed"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Status    string          `json:"status" example:"pending"`
+	// ReplayOf is the delivery this one was replayed from
+	ReplayOf      *int              `json:"replay_of,omitempty" example:"1"`
+	Attempts      []DeliveryAttempt `json:"attempts"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DeliveredAt   *time.Time        `json:"delivered_at,omitempty"`
+}
+
+// DeliveryAttempt is one try at delivering an event
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty" example:"500"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms" example:"120"`
+}
+
+// NewWebhook converts a webhook to its v1 form
+func NewWebhook(webhook *model.Webhook) *Webhook {
+	resp := &Webhook{
+		ID:                  webhook.ID,
+		OwnerID:             webhook.OwnerID,
+		URL:                 webhook.URL,
+		Secret:              webhook.Secret,
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		DisabledReason:      webhook.DisabledReason,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+	if webhook.EventTypes != nil {
+		resp.EventTypes = make([]string, len(webhook.EventTypes))
+		for i, t := range webhook.EventTypes {
+			resp.EventTypes[i] = string(t)
+		}
+	}
+	return resp
+}
+
+// NewWebhooks converts webhooks to their v1 form
+func NewWebhooks(webhooks []*model.Webhook) []*Webhook {
+	resp := make([]*Webhook, len(webhooks))
+	for i, webhook := range webhooks {
+		resp[i] = NewWebhook(webhook)
+	}
+	return resp
+}
+
+// NewWebhookDelivery converts a delivery to its v1 form
+func NewWebhookDelivery(delivery *model.WebhookDelivery) *WebhookDelivery {
+	resp := &WebhookDelivery{
+		ID:            delivery.ID,
+		WebhookID:     delivery.WebhookID,
+		EventID:       delivery.EventID,
+		EventType:     string(delivery.EventType),
+		Payload:       delivery.Payload,
+		Status:        string(delivery.Status),
+		ReplayOf:      delivery.ReplayOf,
+		NextAttemptAt: delivery.NextAttemptAt,
+		CreatedAt:     delivery.CreatedAt,
+		DeliveredAt:   delivery.DeliveredAt,
+	}
+	if delivery.Attempts != nil {
+		resp.Attempts = make([]DeliveryAttempt, len(delivery.Attempts))
+		for i, a := range delivery.Attempts {
+			resp.Attempts[i] = DeliveryAttempt{
+				At:         a.At,
+				StatusCode: a.StatusCode,
+				Error:      a.Error,
+				DurationMs: a.DurationMs,
+			}
+		}
+	}
+	return resp
+}
+
+// NewWebhookDeliveries converts deliveries to their v1 form
+func NewWebhookDeliveries(deliveries []*model.WebhookDelivery) []*WebhookDelivery {
+	resp := make([]*WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		resp[i] = NewWebhookDelivery(delivery)
+	}
+	return resp
+}
+
+// CreateWebhookRequest is the body of a request to subscribe a webhook
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required" example:"https://example.com/hooks/todos"`
+	EventTypes []string `json:"event_types" example:"todo.created,todo.completed"`
+	// Secret signs deliveries; one is generated when empty
+	Secret string `json:"secret" example:"s3cr3t"`
+}
+
+// ToModel converts the request to its domain form
+func (r *CreateWebhookRequest) ToModel() *model.CreateWebhookRequest {
+	return &model.CreateWebhookRequest{URL: r.URL, EventTypes: eventTypes(r.EventTypes), Secret: r.Secret}
+}
+
+// UpdateWebhookRequest is the body of a request to update a webhook
+type UpdateWebhookRequest struct {
+	URL string `json:"url" example:"https://example.com/hooks/todos"`
+	// EventTypes replaces the subscribed events when present; an empty list subscribes to all
+	EventTypes []string `json:"event_types" example:"todo.completed"`
+	Secret     string   `json:"secret" example:"n3w-s3cr3t"`
+	Active     *bool    `json:"active" example:"true"`
+}
+
+// ToModel converts the request to its domain form
+func (r *UpdateWebhookRequest) ToModel() *model.UpdateWebhookRequest {
+	return &model.UpdateWebhookRequest{
+		URL:        r.URL,
+		EventTypes: eventTypes(r.EventTypes),
+		Secret:     r.Secret,
+		Active:     r.Active,
+	}
+}
+
+// eventTypes converts event type names, keeping nil apart from an empty list
+// since updates use it to tell "unchanged" from "all events"
+func eventTypes(names []string) []model.EventType {
+	if names == nil {
+		return nil
+	}
+	types := make([]model.EventType, len(names))
+	for i, name := range names {
+		types[i] = model.EventType(name)
+	}
+	return types
+}
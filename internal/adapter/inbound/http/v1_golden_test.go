@@ -0,0 +1,280 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	"go-boilerplate/internal/adapter/outbound/audit"
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/adapter/outbound/readmodel"
+	"go-boilerplate/internal/adapter/outbound/storage"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+	"go-boilerplate/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/v1")
+
+// syncPublisher hands events straight to its handlers, standing in for the
+// outbox relay and the event bus
+type syncPublisher []port.EventHandler
+
+func (p syncPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	for _, event := range events {
+		for _, handle := range p {
+			if err := handle(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newV1Router wires every /v1 handler to in-memory adapters. User 1 is an
+// administrator.
+func newV1Router(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	todoRepo := persistence.NewEventSourcedTodoRepository(10)
+	userRepo := persistence.NewUserRepository()
+	tagRepo := persistence.NewTagRepository()
+	listRepo := persistence.NewTodoListRepository()
+	shareRepo := persistence.NewListShareRepository()
+	commentRepo := persistence.NewCommentRepository()
+	attachmentRepo := persistence.NewAttachmentRepository()
+	webhookRepo := persistence.NewWebhookRepository()
+	tx := persistence.NewTxManager(todoRepo, userRepo, tagRepo, listRepo, shareRepo, commentRepo, attachmentRepo)
+
+	blobs, err := storage.NewLocalBlobStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditLog := audit.NewMemoryAuditLog()
+	adminIDs := []int{1}
+
+	statsService := service.NewTodoStatsService(readmodel.NewMemoryTodoStats(), todoRepo, adminIDs)
+	webhookService := service.NewWebhookService(webhookRepo)
+	publisher := syncPublisher{statsService.HandleEvent, webhookService.HandleEvent}
+
+	attachmentService := service.NewAttachmentService(attachmentRepo, todoRepo, blobs)
+	todoService := service.NewTodoService(todoRepo, tagRepo, tx,
+		service.WithAttachmentCleanup(attachmentService),
+		service.WithTodoAuditLog(auditLog),
+		service.WithTodoEventPublisher(publisher),
+		service.WithTodoHistory(todoRepo),
+		service.WithListRoles(listRepo, shareRepo),
+	)
+	userService := service.NewUserService(userRepo, tx, service.WithUserAuditLog(auditLog))
+	shareService := service.NewListShareService(shareRepo, listRepo, userRepo, tx)
+	listService := service.NewTodoListService(listRepo, shareService.ShareRepository(), todoService.TodoRepository(), todoService, tx)
+	streamService := service.NewTodoStreamService(listRepo, shareRepo)
+
+	handlers := Handlers{
+		Todo:       NewTodoHandler(todoService),
+		User:       NewUserHandler(userService),
+		Tag:        NewTagHandler(service.NewTagService(tagRepo, todoService.TodoRepository(), tx)),
+		List:       NewTodoListHandler(listService),
+		Share:      NewListShareHandler(shareService),
+		Comment:    NewCommentHandler(service.NewCommentService(commentRepo, todoRepo, userRepo, tx)),
+		Attachment: NewAttachmentHandler(attachmentService, 10<<20),
+		Audit:      NewAuditHandler(service.NewAuditService(auditLog, adminIDs)),
+		Stats:      NewStatsHandler(statsService),
+		Webhook:    NewWebhookHandler(webhookService),
+		Stream:     NewTodoStreamHandler(streamService, time.Minute),
+	}
+
+	authenticator := auth.NewAuthenticator(auth.WithActorHeader(true))
+	r := gin.New()
+	r.Use(ActorMiddleware(authenticator))
+	RegisterRoutes(r.Group("/v1"), handlers, authenticator)
+	return r
+}
+
+// volatile matches the parts of a response that change from run to run:
+// timestamps, generated IDs and hashes, and projection lag
+var volatile = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`"\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d(\.\d+)?(Z|[+-]\d\d:\d\d)"`), `"<time>"`},
+	{regexp.MustCompile(`"(id|event_id|last_event_id|hash|prev_hash)":"[0-9a-f]{32,}"`), `"$1":"<hex>"`},
+	{regexp.MustCompile(`"(lag_seconds|duration_ms)":[0-9.e+-]+`), `"$1":0`},
+}
+
+func normalize(body []byte) []byte {
+	for _, v := range volatile {
+		body = v.re.ReplaceAll(body, []byte(v.repl))
+	}
+	return body
+}
+
+// checkGolden compares a response with testdata/v1/<name>.golden, or
+// rewrites the file when the tests run with -update
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "v1", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run the tests with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response changed\n got: %s\nwant: %s", got, want)
+	}
+}
+
+// uploadBody is a multipart form holding a file to attach
+func uploadBody(t *testing.T, filename, content string) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String(), w.FormDataContentType()
+}
+
+// TestV1Responses pins the bytes of every /v1 resource. The steps share one
+// server and run in order, each one's response compared with its golden
+// file; a failure means a change would break existing v1 clients.
+func TestV1Responses(t *testing.T) {
+	r := newV1Router(t)
+	png, pngType := uploadBody(t, "screenshot.png", "\x89PNG\r\n\x1a\nnot really an image")
+
+	steps := []struct {
+		name        string
+		actor       int
+		method      string
+		path        string
+		body        string
+		contentType string
+		wantStatus  int
+	}{
+		{name: "create_user", method: http.MethodPost, path: "/v1/users", body: `{"username":"alice","email":"alice@example.com","name":"Alice"}`, wantStatus: http.StatusCreated},
+		{name: "create_second_user", method: http.MethodPost, path: "/v1/users", body: `{"username":"bob","email":"bob@example.com","name":"Bob"}`, wantStatus: http.StatusCreated},
+		{name: "create_webhook", actor: 1, method: http.MethodPost, path: "/v1/webhooks", body: `{"url":"https://example.com/hooks/todos","event_types":["todo.created"],"secret":"s3cr3t"}`, wantStatus: http.StatusCreated},
+		{name: "update_webhook", actor: 1, method: http.MethodPut, path: "/v1/webhooks/1", body: `{"event_types":["todo.created","todo.completed"]}`, wantStatus: http.StatusOK},
+		{name: "create_tag", actor: 1, method: http.MethodPost, path: "/v1/tags", body: `{"owner_id":1,"name":"work","color":"#ff8800"}`, wantStatus: http.StatusCreated},
+		{name: "create_second_tag", actor: 1, method: http.MethodPost, path: "/v1/tags", body: `{"owner_id":1,"name":"office"}`, wantStatus: http.StatusCreated},
+		{name: "merge_tags", actor: 1, method: http.MethodPost, path: "/v1/tags/2/merge", body: `{"target_id":1}`, wantStatus: http.StatusOK},
+		{name: "list_tags", actor: 1, method: http.MethodGet, path: "/v1/tags?owner_id=1", wantStatus: http.StatusOK},
+		{name: "get_missing_tag", actor: 1, method: http.MethodGet, path: "/v1/tags/99", wantStatus: http.StatusNotFound},
+		{name: "create_todo", actor: 1, method: http.MethodPost, path: "/v1/todos", body: `{"owner_id":1,"title":"Write report","priority":"high","due_date":"2099-01-01T09:00:00Z"}`, wantStatus: http.StatusCreated},
+		{name: "attach_tag", actor: 1, method: http.MethodPut, path: "/v1/todos/1/tags/1", wantStatus: http.StatusOK},
+		{name: "add_checklist_item", actor: 1, method: http.MethodPost, path: "/v1/todos/1/checklist", body: `{"text":"Write the summary"}`, wantStatus: http.StatusCreated},
+		{name: "transition_todo", actor: 1, method: http.MethodPost, path: "/v1/todos/1/transitions", body: `{"to":"in_progress","reason":"Started working on it"}`, wantStatus: http.StatusOK},
+		{name: "list_transitions", actor: 1, method: http.MethodGet, path: "/v1/todos/1/transitions", wantStatus: http.StatusOK},
+		{name: "todo_history", actor: 1, method: http.MethodGet, path: "/v1/todos/1/history", wantStatus: http.StatusOK},
+		{name: "create_list", actor: 1, method: http.MethodPost, path: "/v1/lists", body: `{"name":"Home renovation","description":"Everything for the new kitchen"}`, wantStatus: http.StatusCreated},
+		{name: "add_todo_to_list", actor: 1, method: http.MethodPut, path: "/v1/lists/1/todos/1", wantStatus: http.StatusOK},
+		{name: "invite", actor: 1, method: http.MethodPost, path: "/v1/lists/1/invitations", body: `{"username":"bob","role":"editor"}`, wantStatus: http.StatusCreated},
+		{name: "my_invitations", actor: 2, method: http.MethodGet, path: "/v1/invitations", wantStatus: http.StatusOK},
+		{name: "accept_invitation", actor: 2, method: http.MethodPost, path: "/v1/invitations/1/accept", wantStatus: http.StatusOK},
+		{name: "list_invitations", actor: 1, method: http.MethodGet, path: "/v1/lists/1/invitations", wantStatus: http.StatusOK},
+		{name: "update_member", actor: 1, method: http.MethodPut, path: "/v1/lists/1/members/2", body: `{"role":"viewer"}`, wantStatus: http.StatusOK},
+		{name: "list_members", actor: 1, method: http.MethodGet, path: "/v1/lists/1/members", wantStatus: http.StatusOK},
+		{name: "shared_lists", actor: 2, method: http.MethodGet, path: "/v1/lists/shared", wantStatus: http.StatusOK},
+		{name: "archive_list", actor: 1, method: http.MethodPost, path: "/v1/lists/1/archive", wantStatus: http.StatusOK},
+		{name: "list_lists", actor: 1, method: http.MethodGet, path: "/v1/lists?include_archived=true", wantStatus: http.StatusOK},
+		{name: "add_comment", actor: 1, method: http.MethodPost, path: "/v1/todos/1/comments", body: `{"body":"Can @bob take a look?"}`, wantStatus: http.StatusCreated},
+		{name: "reply_comment", actor: 2, method: http.MethodPost, path: "/v1/todos/1/comments", body: `{"body":"On it","parent_id":1}`, wantStatus: http.StatusCreated},
+		{name: "edit_comment", actor: 1, method: http.MethodPut, path: "/v1/todos/1/comments/1", body: `{"body":"Can @bob take a look today?"}`, wantStatus: http.StatusOK},
+		{name: "list_comments", actor: 1, method: http.MethodGet, path: "/v1/todos/1/comments", wantStatus: http.StatusOK},
+		{name: "comment_history", actor: 1, method: http.MethodGet, path: "/v1/todos/1/comments/1/history", wantStatus: http.StatusOK},
+		{name: "activity", actor: 1, method: http.MethodGet, path: "/v1/todos/1/activity", wantStatus: http.StatusOK},
+		{name: "upload_attachment", actor: 1, method: http.MethodPost, path: "/v1/todos/1/attachments", body: png, contentType: pngType, wantStatus: http.StatusCreated},
+		{name: "list_attachments", actor: 1, method: http.MethodGet, path: "/v1/todos/1/attachments", wantStatus: http.StatusOK},
+		{name: "list_deliveries", actor: 1, method: http.MethodGet, path: "/v1/webhooks/1/deliveries", wantStatus: http.StatusOK},
+		{name: "list_webhooks", actor: 1, method: http.MethodGet, path: "/v1/webhooks", wantStatus: http.StatusOK},
+		{name: "todo_stats", actor: 1, method: http.MethodGet, path: "/v1/stats/todos", wantStatus: http.StatusOK},
+		{name: "projection_status", actor: 1, method: http.MethodGet, path: "/v1/stats/todos/status", wantStatus: http.StatusOK},
+		{name: "list_audit_entries", actor: 1, method: http.MethodGet, path: "/v1/audit?entity_type=user", wantStatus: http.StatusOK},
+		{name: "verify_audit_log", actor: 1, method: http.MethodGet, path: "/v1/audit/verify", wantStatus: http.StatusOK},
+		{name: "get_todo", actor: 1, method: http.MethodGet, path: "/v1/todos/1", wantStatus: http.StatusOK},
+	}
+
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			req := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+			contentType := step.contentType
+			if contentType == "" {
+				contentType = "application/json"
+			}
+			req.Header.Set("Content-Type", contentType)
+			if step.actor != 0 {
+				req.Header.Set(ActorHeader, strconv.Itoa(step.actor))
+			}
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != step.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, step.wantStatus, rec.Body)
+			}
+			checkGolden(t, step.name, normalize(rec.Body.Bytes()))
+		})
+	}
+}
+
+func TestV1StreamEvent(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	todo := &model.Todo{
+		ID:        1,
+		OwnerID:   1,
+		Title:     "Write report",
+		Status:    model.TodoStatusOpen,
+		Priority:  model.PriorityHigh,
+		TagIDs:    []int{},
+		Checklist: []model.ChecklistItem{},
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+
+	tests := []struct {
+		name  string
+		event model.TodoStreamEvent
+	}{
+		{name: "stream_updated", event: model.TodoStreamEvent{ID: 42, Type: model.TodoStreamUpdated, TodoID: 1, Todo: todo, OccurredAt: at}},
+		{name: "stream_deleted", event: model.TodoStreamEvent{ID: 43, Type: model.TodoStreamDeleted, TodoID: 1, OccurredAt: at}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			if err := writeStreamEvent(c.Writer, tt.event); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, tt.name, rec.Body.Bytes())
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-boilerplate/internal/adapter/inbound/http/v1"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles HTTP requests for webhooks and their deliveries
+type WebhookHandler struct {
+	webhookService port.WebhookServicePort
+}
+
+// NewWebhookHandler creates a new WebhookHandler
+func NewWebhookHandler(webhookService port.WebhookServicePort) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// mapDomainErrorToHTTP maps domain errors to appropriate HTTP status codes
+func (h *WebhookHandler) mapDomainErrorToHTTP(err error) (int, string) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return http.StatusUnauthorized, "Authentication required"
+	case errors.Is(err, domain.ErrInvalidWebhookURL):
+		return http.StatusBadRequest, "Webhook URL must be an absolute http or https URL"
+	case errors.Is(err, domain.ErrInternalWebhookURL):
+		return http.StatusBadRequest, "Webhook URL must not point to a loopback, private or link-local address"
+	case errors.Is(err, domain.ErrInvalidEventType):
+		return http.StatusBadRequest, "Unknown event type"
+	case errors.Is(err, domain.ErrInvalidDeliveryStatus):
+		return http.StatusBadRequest, "Delivery status must be pending, succeeded or dead"
+	default:
+		return http.StatusInternalServerError, "Internal server error"
+	}
+}
+
+// parseWebhookAndDeliveryIDs reads the :id and :delivery_id path parameters
+func parseWebhookAndDeliveryIDs(c *gin.Context) (int, int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	deliveryID, err := strconv.Atoi(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery id", "code": codeInvalidRequest})
+		return 0, 0, false
+	}
+	return id, deliveryID, true
+}
+
+// CreateWebhook handles POST /webhooks
+// @Summary Create a webhook
+// @Description Subscribe a URL to the acting user's todo and account events. Deliveries are signed with X-Webhook-Signature, the HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" keyed with the secret, which is only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body v1.CreateWebhookRequest true "Webhook object"
+// @Success 201 {object} v1.Webhook
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req v1.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, v1.NewWebhook(webhook))
+}
+
+// ListWebhooks handles GET /webhooks
+// @Summary List webhooks
+// @Description Get the acting user's webhooks
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} v1.Webhook
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(c.Request.Context())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewWebhooks(webhooks))
+}
+
+// GetWebhook handles GET /webhooks/:id
+// @Summary Get a webhook
+// @Description Get one of the acting user's webhooks, including whether it was disabled and why
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} v1.Webhook
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewWebhook(webhook))
+}
+
+// UpdateWebhook handles PUT /webhooks/:id
+// @Summary Update a webhook
+// @Description Update one of the acting user's webhooks. Set active to true to re-enable a disabled webhook; its pending deliveries resume.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param webhook body v1.UpdateWebhookRequest true "Webhook update object"
+// @Success 200 {object} v1.Webhook
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	var req v1.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.Request.Context(), id, req.ToModel())
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewWebhook(webhook))
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id
+// @Summary Delete a webhook
+// @Description Delete one of the acting user's webhooks together with its deliveries
+// @Tags webhooks
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /webhooks/:id/deliveries
+// @Summary List webhook deliveries
+// @Description Get a webhook's deliveries with their attempt logs, newest first. Use status=dead for the dead-letter queue.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Filter by status (pending, succeeded, dead)"
+// @Success 200 {array} v1.WebhookDelivery
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id", "code": codeInvalidRequest})
+		return
+	}
+
+	status := model.DeliveryStatus(c.Query("status"))
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), id, status)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewWebhookDeliveries(deliveries))
+}
+
+// GetDelivery handles GET /webhooks/:id/deliveries/:delivery_id
+// @Summary Get a webhook delivery
+// @Description Get a delivery with its payload and the log of every attempt
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 200 {object} v1.WebhookDelivery
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, deliveryID, ok := parseWebhookAndDeliveryIDs(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, v1.NewWebhookDelivery(delivery))
+}
+
+// ReplayDelivery handles POST /webhooks/:id/deliveries/:delivery_id/replay
+// @Summary Replay a webhook delivery
+// @Description Queue a new delivery of the same event, for example to retry one from the dead-letter queue
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} v1.WebhookDelivery
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Router /v1/webhooks/{id}/deliveries/{delivery_id}/replay [post]
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	id, deliveryID, ok := parseWebhookAndDeliveryIDs(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		statusCode, message := h.mapDomainErrorToHTTP(err)
+		c.JSON(statusCode, gin.H{"error": message, "code": errorCode(err)})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, v1.NewWebhookDelivery(delivery))
+}
@@ -0,0 +1,86 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// writeTimeout bounds a single write to a client
+const writeTimeout = 10 * time.Second
+
+// client is one WebSocket connection of an authenticated user
+type client struct {
+	conn   *websocket.Conn
+	userID int
+	// ctx carries the user and request ID into service calls
+	ctx context.Context
+	out chan []byte
+	// done is closed to make the writer close the connection
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+}
+
+// newClient wraps an upgraded connection
+func newClient(ctx context.Context, conn *websocket.Conn, userID int, buffer int) *client {
+	return &client{
+		conn:   conn,
+		userID: userID,
+		ctx:    ctx,
+		out:    make(chan []byte, buffer),
+		done:   make(chan struct{}),
+	}
+}
+
+// close makes the writer send a close frame with the code and reason and end the connection
+func (c *client) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.done)
+	})
+}
+
+// enqueue queues an encoded message without waiting. A client whose queue is
+// full is too slow to keep up and is disconnected.
+func (c *client) enqueue(data []byte) {
+	select {
+	case <-c.done:
+	case c.out <- data:
+	default:
+		c.close(websocket.CloseTryAgainLater, "too slow to keep up")
+	}
+}
+
+// writeLoop sends queued messages and pings until the client is closed
+func (c *client) writeLoop(pingInterval time.Duration) {
+	ping := time.NewTicker(pingInterval)
+	defer ping.Stop()
+	defer c.conn.Close()
+
+	for {
+		select {
+		case data := <-c.out:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-c.done:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				msg := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+				_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeTimeout))
+			}
+			return
+		}
+	}
+}
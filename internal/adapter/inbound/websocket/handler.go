@@ -0,0 +1,235 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// Protocol errors reported to clients
+var (
+	errUnknownMessage = errors.New("unknown message type")
+	errNotSubscribed  = errors.New("not subscribed to the list")
+	errMissingTodo    = errors.New("todo changes are required")
+)
+
+// Handler upgrades HTTP requests to WebSocket connections for collaborating
+// on shared lists. Clients subscribe to the rooms of lists they can see, get
+// the changes to the list's todos and who is viewing it, send typing
+// indicators and edit todos through the todo service. Handshakes keep the
+// upgrader's default same-origin check, which matters since browsers send
+// the access token cookie along with cross-site handshakes.
+type Handler struct {
+	hub            *Hub
+	todoService    port.TodoServicePort
+	listService    port.TodoListServicePort
+	shareService   port.ListShareServicePort
+	upgrader       websocket.Upgrader
+	clientBuffer   int
+	pingInterval   time.Duration
+	maxMessageSize int64
+}
+
+// HandlerOption configures optional Handler behavior
+type HandlerOption func(*Handler)
+
+// WithClientBuffer sets how many messages may queue for one client before it
+// is disconnected as too slow
+func WithClientBuffer(n int) HandlerOption {
+	return func(h *Handler) {
+		h.clientBuffer = n
+	}
+}
+
+// WithPingInterval sets how often clients are pinged; a client that does not
+// answer within two intervals is disconnected
+func WithPingInterval(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.pingInterval = d
+	}
+}
+
+// WithMaxMessageSize sets the largest message accepted from a client, in bytes
+func WithMaxMessageSize(n int64) HandlerOption {
+	return func(h *Handler) {
+		h.maxMessageSize = n
+	}
+}
+
+// NewHandler creates a new Handler
+func NewHandler(hub *Hub, todoService port.TodoServicePort, listService port.TodoListServicePort, shareService port.ListShareServicePort, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		hub:            hub,
+		todoService:    todoService,
+		listService:    listService,
+		shareService:   shareService,
+		clientBuffer:   64,
+		pingInterval:   30 * time.Second,
+		maxMessageSize: 64 << 10,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// errorMessage turns an error into the text reported to the client
+func errorMessage(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return "Resource not found"
+	case errors.Is(err, domain.ErrUnauthenticated):
+		return "Authentication required"
+	case errors.Is(err, domain.ErrForbidden):
+		return "Permission denied"
+	default:
+		return err.Error()
+	}
+}
+
+// Serve handles GET /ws
+// @Summary Collaborate on lists over WebSocket
+// @Description Upgrade to a WebSocket carrying JSON messages. Send subscribe or unsubscribe with a list_id to join or leave a list's room; the room receives todo_changed for its todos, todo_removed when one is moved off the list, and presence with the viewing users. A client removed from a list gets revoked and leaves its room. Send typing with a todo_id to show others who is editing, and update_todo with a todo object to edit a todo as a list editor. Requests may carry a ref that is echoed in the ack or error reply. Clients that fall too far behind are disconnected with close code 1013. Browsers, which cannot set headers on the handshake, may pass the bearer token in the access_token query parameter or cookie.
+// @Tags collaboration
+// @Security BearerAuth
+// @Param access_token query string false "Bearer token, for clients that cannot set headers"
+// @Success 101 "Switching Protocols"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /ws [get]
+func (h *Handler) Serve(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader already responded with the error
+		return
+	}
+
+	cl := newClient(ctx, conn, userID, h.clientBuffer)
+	go cl.writeLoop(h.pingInterval)
+	h.readLoop(cl)
+	h.hub.disconnect(cl)
+	cl.close(websocket.CloseNormalClosure, "")
+}
+
+// readLoop handles the client's messages until the connection ends
+func (h *Handler) readLoop(cl *client) {
+	pongWait := 2 * h.pingInterval
+	cl.conn.SetReadLimit(h.maxMessageSize)
+	_ = cl.conn.SetReadDeadline(time.Now().Add(pongWait))
+	cl.conn.SetPongHandler(func(string) error {
+		return cl.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var msg inboundMessage
+		if err := cl.conn.ReadJSON(&msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				h.reply(cl, outboundMessage{Type: MessageError, Error: "invalid message: " + err.Error()})
+				continue
+			}
+			return
+		}
+		h.handle(cl, msg)
+	}
+}
+
+// reply queues a message for the client
+func (h *Handler) reply(cl *client, msg outboundMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to encode %s message: %v", msg.Type, err)
+		return
+	}
+	cl.enqueue(data)
+}
+
+// handle carries out a client message and replies to it
+func (h *Handler) handle(cl *client, msg inboundMessage) {
+	fail := func(err error) {
+		h.reply(cl, outboundMessage{Type: MessageError, Ref: msg.Ref, ListID: msg.ListID, Error: errorMessage(err)})
+	}
+
+	switch msg.Type {
+	case MessageSubscribe:
+		if _, err := h.listService.GetList(cl.ctx, msg.ListID); err != nil {
+			fail(err)
+			return
+		}
+		viewers := h.hub.join(msg.ListID, cl)
+		// Access revoked between the check and the join is announced before
+		// the client was in the room to be evicted, so check again
+		if _, err := h.listService.GetList(cl.ctx, msg.ListID); err != nil {
+			h.hub.unsubscribe(msg.ListID, cl)
+			fail(err)
+			return
+		}
+		h.reply(cl, outboundMessage{Type: MessageSubscribed, Ref: msg.Ref, ListID: msg.ListID, Viewers: viewers})
+	case MessageUnsubscribe:
+		h.hub.unsubscribe(msg.ListID, cl)
+		h.reply(cl, outboundMessage{Type: MessageAck, Ref: msg.Ref, ListID: msg.ListID})
+	case MessageTyping:
+		if !h.hub.inRoom(msg.ListID, cl) {
+			fail(errNotSubscribed)
+			return
+		}
+		h.hub.typing(msg.ListID, msg.TodoID, cl, msg.Typing)
+	case MessageUpdateTodo:
+		todo, err := h.updateTodo(cl.ctx, msg)
+		if err != nil {
+			fail(err)
+			return
+		}
+		h.reply(cl, outboundMessage{Type: MessageAck, Ref: msg.Ref, ListID: msg.ListID, TodoID: todo.ID, Todo: todo})
+	default:
+		fail(errUnknownMessage)
+	}
+}
+
+// updateTodo edits a todo on a list the acting user holds the editor role on
+func (h *Handler) updateTodo(ctx context.Context, msg inboundMessage) (*model.Todo, error) {
+	if msg.Todo == nil {
+		return nil, errMissingTodo
+	}
+
+	members, err := h.shareService.ListMembers(ctx, msg.ListID)
+	if err != nil {
+		return nil, err
+	}
+	userID, _ := domain.ActorFromContext(ctx)
+	var role model.ListRole
+	for _, member := range members {
+		if member.UserID == userID {
+			role = member.Role
+		}
+	}
+	if !role.Includes(model.ListRoleEditor) {
+		return nil, domain.ErrForbidden
+	}
+
+	todo, err := h.todoService.GetTodo(ctx, msg.TodoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.ListID == nil || *todo.ListID != msg.ListID {
+		return nil, domain.ErrNotFound
+	}
+	return h.todoService.UpdateTodo(ctx, msg.TodoID, msg.Todo)
+}
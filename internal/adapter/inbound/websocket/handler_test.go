@@ -0,0 +1,198 @@
+package websocket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	httpadapter "go-boilerplate/internal/adapter/inbound/http"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/service"
+	"go-boilerplate/internal/domain/service/servicetest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// hubPublisher hands events straight to a hub
+type hubPublisher struct {
+	hub *Hub
+}
+
+// Publish sends the events to the hub
+func (p hubPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	for _, event := range events {
+		if err := p.hub.HandleEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collabFixture serves the collaboration endpoint for a list owned by user 1
+// and shared with user 2
+type collabFixture struct {
+	server *httptest.Server
+	signer *auth.TokenSigner
+	lists  *service.TodoListService
+	shares *service.ListShareService
+	listID int
+}
+
+// newCollabFixture serves /ws behind the same middleware as the server
+func newCollabFixture(t *testing.T) *collabFixture {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	hub := NewHub()
+	services := servicetest.New(hubPublisher{hub: hub})
+	list := services.CreateSharedList(t, 1, "chores", map[int]model.ListRole{2: model.ListRoleEditor})
+
+	signer := auth.NewTokenSigner([]byte("secret"), time.Hour)
+	authenticator := auth.NewAuthenticator(auth.WithTokens(signer))
+	handler := NewHandler(hub, services.Todos, services.Lists, services.Shares, WithPingInterval(time.Minute))
+	r := gin.New()
+	r.Use(httpadapter.AccessTokenQueryMiddleware(), httpadapter.ActorMiddleware(authenticator))
+	r.GET("/ws", httpadapter.StreamAuthMiddleware(authenticator), handler.Serve)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	return &collabFixture{server: server, signer: signer, lists: services.Lists, shares: services.Shares, listID: list.ID}
+}
+
+// dial opens a connection with the given query and headers
+func (f *collabFixture) dial(query string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	url := "ws" + strings.TrimPrefix(f.server.URL, "http") + "/ws" + query
+	return websocket.DefaultDialer.Dial(url, header)
+}
+
+// request sends a message and returns the next reply
+func request(t *testing.T, conn *websocket.Conn, msg inboundMessage) outboundMessage {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatal(err)
+	}
+	return receive(t, conn)
+}
+
+// receive reads the next message
+func receive(t *testing.T, conn *websocket.Conn) outboundMessage {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var reply outboundMessage
+	if err := conn.ReadJSON(&reply); err != nil {
+		t.Fatal(err)
+	}
+	return reply
+}
+
+func TestServeAuthenticates(t *testing.T) {
+	f := newCollabFixture(t)
+	tests := []struct {
+		name       string
+		query      string
+		header     http.Header
+		wantStatus int
+	}{
+		{name: "no credentials", wantStatus: http.StatusUnauthorized},
+		{name: "bearer header", header: http.Header{"Authorization": {"Bearer " + f.signer.Issue(2)}}, wantStatus: http.StatusSwitchingProtocols},
+		{name: "query token", query: "?access_token=" + f.signer.Issue(2), wantStatus: http.StatusSwitchingProtocols},
+		{name: "cookie", header: http.Header{"Cookie": {httpadapter.AccessTokenParam + "=" + f.signer.Issue(2)}}, wantStatus: http.StatusSwitchingProtocols},
+		{name: "forged query token", query: "?access_token=2.9999999999.forged", wantStatus: http.StatusUnauthorized},
+		{name: "cross-site handshake", header: http.Header{"Cookie": {httpadapter.AccessTokenParam + "=" + f.signer.Issue(2)}, "Origin": {"https://evil.example"}}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, resp, err := f.dial(tt.query, tt.header)
+			if resp == nil {
+				t.Fatalf("dial failed without a response: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if conn == nil {
+				return
+			}
+			defer conn.Close()
+
+			reply := request(t, conn, inboundMessage{Type: MessageSubscribe, ListID: f.listID})
+			if reply.Type != MessageSubscribed {
+				t.Errorf("subscribe reply = %+v", reply)
+			}
+		})
+	}
+}
+
+func TestServeEvictsRevokedMembers(t *testing.T) {
+	tests := []struct {
+		name   string
+		revoke func(f *collabFixture) error
+	}{
+		{
+			name: "owner removes the member",
+			revoke: func(f *collabFixture) error {
+				return f.shares.RemoveMember(domain.ContextWithActor(context.Background(), 1), f.listID, 2)
+			},
+		},
+		{
+			name: "member leaves elsewhere",
+			revoke: func(f *collabFixture) error {
+				return f.shares.RemoveMember(domain.ContextWithActor(context.Background(), 2), f.listID, 2)
+			},
+		},
+		{
+			name: "list is deleted",
+			revoke: func(f *collabFixture) error {
+				return f.lists.DeleteList(domain.ContextWithActor(context.Background(), 1), f.listID, model.ListDeleteDetach)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCollabFixture(t)
+			owner, _, err := f.dial("?access_token="+f.signer.Issue(1), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer owner.Close()
+			member, _, err := f.dial("?access_token="+f.signer.Issue(2), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer member.Close()
+
+			if reply := request(t, owner, inboundMessage{Type: MessageSubscribe, ListID: f.listID}); reply.Type != MessageSubscribed {
+				t.Fatalf("owner subscribe reply = %+v", reply)
+			}
+			if reply := request(t, member, inboundMessage{Type: MessageSubscribe, ListID: f.listID}); reply.Type != MessageSubscribed {
+				t.Fatalf("member subscribe reply = %+v", reply)
+			}
+			if presence := receive(t, owner); presence.Type != MessagePresence {
+				t.Fatalf("owner got %+v, want presence", presence)
+			}
+
+			if err := tt.revoke(f); err != nil {
+				t.Fatalf("revoking failed: %v", err)
+			}
+
+			if revoked := receive(t, member); revoked.Type != MessageRevoked || revoked.ListID != f.listID {
+				t.Fatalf("member got %+v, want revoked", revoked)
+			}
+			if presence := receive(t, owner); presence.Type != MessagePresence || len(presence.Viewers) != 1 || presence.Viewers[0] != 1 {
+				t.Errorf("owner got %+v, want presence of the owner alone", presence)
+			}
+			if reply := request(t, member, inboundMessage{Type: MessageTyping, ListID: f.listID, TodoID: 1, Typing: true}); reply.Type != MessageError {
+				t.Errorf("typing after eviction got %+v, want error", reply)
+			}
+			if reply := request(t, member, inboundMessage{Type: MessageSubscribe, ListID: f.listID}); reply.Type != MessageError {
+				t.Errorf("subscribing again got %+v, want error", reply)
+			}
+		})
+	}
+}
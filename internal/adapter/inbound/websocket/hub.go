@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"sync"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// Hub tracks which clients are in which list's room and fans messages out to
+// them. A client that cannot keep up with its room is disconnected instead
+// of slowing the room down.
+type Hub struct {
+	mu    sync.Mutex
+	rooms map[int]map[*client]struct{}
+}
+
+// NewHub creates a new Hub
+func NewHub() *Hub {
+	return &Hub{
+		rooms: make(map[int]map[*client]struct{}),
+	}
+}
+
+// viewers returns the users in a room, each once and in ascending order. The caller holds the lock.
+func (h *Hub) viewers(listID int) []int {
+	users := make([]int, 0, len(h.rooms[listID]))
+	for c := range h.rooms[listID] {
+		if !slices.Contains(users, c.userID) {
+			users = append(users, c.userID)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
+
+// broadcast sends a message to everyone in a room except one client. The caller holds the lock.
+func (h *Hub) broadcast(listID int, msg outboundMessage, except *client) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to encode %s message: %v", msg.Type, err)
+		return
+	}
+	for c := range h.rooms[listID] {
+		if c != except {
+			c.enqueue(data)
+		}
+	}
+}
+
+// join adds a client to a list's room, tells the others and returns who is viewing
+func (h *Hub) join(listID int, c *client) []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	room, exists := h.rooms[listID]
+	if !exists {
+		room = make(map[*client]struct{})
+		h.rooms[listID] = room
+	}
+	room[c] = struct{}{}
+
+	viewers := h.viewers(listID)
+	h.broadcast(listID, outboundMessage{Type: MessagePresence, ListID: listID, Viewers: viewers}, c)
+	return viewers
+}
+
+// leave removes a client from a list's room and tells the others. The caller holds the lock.
+func (h *Hub) leave(listID int, c *client) {
+	room, exists := h.rooms[listID]
+	if !exists {
+		return
+	}
+	if _, member := room[c]; !member {
+		return
+	}
+	delete(room, c)
+	if len(room) == 0 {
+		delete(h.rooms, listID)
+		return
+	}
+	h.broadcast(listID, outboundMessage{Type: MessagePresence, ListID: listID, Viewers: h.viewers(listID)}, nil)
+}
+
+// unsubscribe removes a client from a list's room
+func (h *Hub) unsubscribe(listID int, c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.leave(listID, c)
+}
+
+// disconnect removes a client from every room
+func (h *Hub) disconnect(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for listID := range h.rooms {
+		h.leave(listID, c)
+	}
+}
+
+// inRoom reports whether a client is in a list's room
+func (h *Hub) inRoom(listID int, c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, member := h.rooms[listID][c]
+	return member
+}
+
+// typing tells the rest of a room that a user started or stopped editing a todo
+func (h *Hub) typing(listID, todoID int, c *client, typing bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.broadcast(listID, outboundMessage{
+		Type:   MessageTyping,
+		ListID: listID,
+		TodoID: todoID,
+		UserID: c.userID,
+		Typing: &typing,
+	}, c)
+}
+
+// evict removes a user's clients from a list's room and tells them they lost access
+func (h *Hub) evict(listID, userID int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	data, err := json.Marshal(outboundMessage{Type: MessageRevoked, ListID: listID})
+	if err != nil {
+		log.Printf("Failed to encode %s message: %v", MessageRevoked, err)
+		return
+	}
+
+	room := h.rooms[listID]
+	evicted := false
+	for c := range room {
+		if c.userID == userID {
+			delete(room, c)
+			c.enqueue(data)
+			evicted = true
+		}
+	}
+	if !evicted {
+		return
+	}
+	if len(room) == 0 {
+		delete(h.rooms, listID)
+		return
+	}
+	h.broadcast(listID, outboundMessage{Type: MessagePresence, ListID: listID, Viewers: h.viewers(listID)}, nil)
+}
+
+// HandleEvent sends a todo event to the room of the todo's list, and tells the
+// room of the list an update moved it out of that it left, without its new
+// state. A member removed from a list is evicted from the list's room.
+func (h *Hub) HandleEvent(ctx context.Context, event model.Event) error {
+	if event.Type == model.EventListMemberRemoved && event.Member != nil {
+		h.evict(event.Member.ListID, event.Member.UserID)
+		return nil
+	}
+	if event.Todo == nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if event.PreviousListID != nil {
+		h.broadcast(*event.PreviousListID, outboundMessage{
+			Type:   MessageTodoRemoved,
+			ListID: *event.PreviousListID,
+			TodoID: event.AggregateID,
+		}, nil)
+	}
+	if event.Todo.ListID == nil {
+		return nil
+	}
+
+	listID := *event.Todo.ListID
+	h.broadcast(listID, outboundMessage{
+		Type:   MessageTodoChanged,
+		ListID: listID,
+		TodoID: event.AggregateID,
+		Event:  event.Type,
+		Todo:   event.Todo,
+	}, nil)
+	return nil
+}
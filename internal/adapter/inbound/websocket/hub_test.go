@@ -0,0 +1,126 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// drain returns the messages queued for a client, as type:list plus the todo when there is one
+func drain(t *testing.T, c *client) []string {
+	t.Helper()
+	var got []string
+	for len(c.out) > 0 {
+		var msg outboundMessage
+		if err := json.Unmarshal(<-c.out, &msg); err != nil {
+			t.Fatal(err)
+		}
+		s := fmt.Sprintf("%s:%d", msg.Type, msg.ListID)
+		if msg.Type == MessagePresence {
+			s += fmt.Sprint(msg.Viewers)
+		}
+		if msg.Todo != nil {
+			s += "+todo"
+		}
+		got = append(got, s)
+	}
+	return got
+}
+
+func TestHubHandleEvent(t *testing.T) {
+	todoOn := func(listID *int, previousListID *int) model.Event {
+		return model.Event{
+			Type:           model.EventTodoUpdated,
+			AggregateID:    7,
+			Todo:           &model.Todo{ID: 7, ListID: listID},
+			PreviousListID: previousListID,
+		}
+	}
+	removed := func(listID, userID int) model.Event {
+		return model.Event{
+			Type:   model.EventListMemberRemoved,
+			Member: &model.ListMember{ListID: listID, UserID: userID},
+		}
+	}
+	list1, list2 := 1, 2
+
+	tests := []struct {
+		name  string
+		event model.Event
+		// want lists the messages each client receives: the owner and a
+		// member in list 1's room, the member's second connection in both
+		// rooms, and another user in list 2's room
+		want [4][]string
+		// wantRoom1 is who is left in list 1's room
+		wantRoom1 []int
+	}{
+		{
+			name:      "todo change reaches its list's room",
+			event:     todoOn(&list1, nil),
+			want:      [4][]string{{"todo_changed:1+todo"}, {"todo_changed:1+todo"}, {"todo_changed:1+todo"}, nil},
+			wantRoom1: []int{1, 2},
+		},
+		{
+			name:      "todo moved between lists leaves the old room without its state",
+			event:     todoOn(&list2, &list1),
+			want:      [4][]string{{"todo_removed:1"}, {"todo_removed:1"}, {"todo_removed:1", "todo_changed:2+todo"}, {"todo_changed:2+todo"}},
+			wantRoom1: []int{1, 2},
+		},
+		{
+			name:      "todo taken off a list",
+			event:     todoOn(nil, &list1),
+			want:      [4][]string{{"todo_removed:1"}, {"todo_removed:1"}, {"todo_removed:1"}, nil},
+			wantRoom1: []int{1, 2},
+		},
+		{
+			name:      "removed member is evicted from every connection",
+			event:     removed(1, 2),
+			want:      [4][]string{{"presence:1[1]"}, {"revoked:1"}, {"revoked:1"}, nil},
+			wantRoom1: []int{1},
+		},
+		{
+			name:      "removal from another list keeps the member",
+			event:     removed(3, 2),
+			wantRoom1: []int{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := NewHub()
+			owner := newClient(context.Background(), nil, 1, 8)
+			member := newClient(context.Background(), nil, 2, 8)
+			memberAgain := newClient(context.Background(), nil, 2, 8)
+			other := newClient(context.Background(), nil, 3, 8)
+			hub.join(1, owner)
+			hub.join(1, member)
+			hub.join(1, memberAgain)
+			hub.join(2, memberAgain)
+			hub.join(2, other)
+			clients := []*client{owner, member, memberAgain, other}
+			for _, c := range clients {
+				drain(t, c)
+			}
+
+			if err := hub.HandleEvent(context.Background(), tt.event); err != nil {
+				t.Fatal(err)
+			}
+
+			for i, c := range clients {
+				if got := drain(t, c); strings.Join(got, " ") != strings.Join(tt.want[i], " ") {
+					t.Errorf("client %d received %v, want %v", i, got, tt.want[i])
+				}
+			}
+			hub.mu.Lock()
+			room := fmt.Sprint(hub.viewers(1))
+			hub.mu.Unlock()
+			if room != fmt.Sprint(tt.wantRoom1) {
+				t.Errorf("list 1 viewers = %s, want %v", room, tt.wantRoom1)
+			}
+		})
+	}
+}
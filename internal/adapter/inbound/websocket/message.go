@@ -0,0 +1,61 @@
+package websocket
+
+import (
+	"go-boilerplate/internal/domain/model"
+)
+
+// Message types sent by clients
+const (
+	// MessageSubscribe joins the room of a list the user can see
+	MessageSubscribe = "subscribe"
+	// MessageUnsubscribe leaves a list's room
+	MessageUnsubscribe = "unsubscribe"
+	// MessageTyping tells the room the user started or stopped editing a todo
+	MessageTyping = "typing"
+	// MessageUpdateTodo edits a todo on a list the user can edit
+	MessageUpdateTodo = "update_todo"
+)
+
+// Message types sent by the server
+const (
+	// MessageSubscribed confirms a subscription and lists who is viewing
+	MessageSubscribed = "subscribed"
+	// MessagePresence tells a room who is viewing after someone joined or left
+	MessagePresence = "presence"
+	// MessageTodoChanged carries a change to a todo on the list
+	MessageTodoChanged = "todo_changed"
+	// MessageTodoRemoved tells a room a todo was moved off the list
+	MessageTodoRemoved = "todo_removed"
+	// MessageRevoked tells a client it lost access to a list and left its room
+	MessageRevoked = "revoked"
+	// MessageAck confirms that a client request succeeded
+	MessageAck = "ack"
+	// MessageError reports that a client request failed
+	MessageError = "error"
+)
+
+// inboundMessage is a message from a client. Ref is echoed in the reply so
+// clients can match replies to requests.
+type inboundMessage struct {
+	Type   string                   `json:"type"`
+	Ref    string                   `json:"ref,omitempty"`
+	ListID int                      `json:"list_id"`
+	TodoID int                      `json:"todo_id,omitempty"`
+	Typing bool                     `json:"typing,omitempty"`
+	Todo   *model.UpdateTodoRequest `json:"todo,omitempty"`
+}
+
+// outboundMessage is a message to a client
+type outboundMessage struct {
+	Type   string `json:"type"`
+	Ref    string `json:"ref,omitempty"`
+	ListID int    `json:"list_id,omitempty"`
+	TodoID int    `json:"todo_id,omitempty"`
+	UserID int    `json:"user_id,omitempty"`
+	// Typing is only set on typing messages, where false is meaningful
+	Typing  *bool           `json:"typing,omitempty"`
+	Viewers []int           `json:"viewers,omitempty"`
+	Event   model.EventType `json:"event,omitempty"`
+	Todo    *model.Todo     `json:"todo,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
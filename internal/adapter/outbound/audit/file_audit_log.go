@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// FileAuditLog implements the AuditPort interface as an append-only file with
+// one JSON entry per line. Entries are also kept in memory for queries; the
+// file is read back when the log is opened so the hash chain continues across
+// restarts.
+type FileAuditLog struct {
+	file    *os.File
+	entries []*model.AuditEntry
+	// size is the length of the file up to the end of the last complete entry
+	size int64
+	mu   sync.RWMutex
+}
+
+// NewFileAuditLog opens or creates the audit log file at path. A last line
+// cut short by a crash is discarded: its append never completed, so it was
+// never acknowledged.
+func NewFileAuditLog(path string) (*FileAuditLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating audit log directory: %w", err)
+	}
+
+	entries, size, err := readAuditFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error opening audit log: %w", err)
+	}
+	if info.Size() > size {
+		log.Printf("Discarding %d bytes of an incomplete entry at the end of audit log %s", info.Size()-size, path)
+		if err := file.Truncate(size); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("error truncating audit log: %w", err)
+		}
+	}
+	return &FileAuditLog{file: file, entries: entries, size: size}, nil
+}
+
+// readAuditFile reads every complete entry of an existing audit log file and
+// returns the length of the file up to the end of the last one. Only the last
+// line may be incomplete; a line that does not parse anywhere else is an error.
+func readAuditFile(path string) ([]*model.AuditEntry, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("error reading audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []*model.AuditEntry
+	var size int64
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			// Entries are written with their newline, so a line without one is incomplete
+			return entries, size, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading audit log: %w", err)
+		}
+
+		var entry model.AuditEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, 0, fmt.Errorf("error parsing audit log line %d: %w", line, err)
+		}
+		entries = append(entries, &entry)
+		size += int64(len(data))
+	}
+}
+
+// Append chains the entry after the newest one and writes it to the file
+// before making it visible to queries
+func (l *FileAuditLog) Append(ctx context.Context, entry *model.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var last *model.AuditEntry
+	if len(l.entries) > 0 {
+		last = l.entries[len(l.entries)-1]
+	}
+	entry.Chain(last)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line := append(data, '\n')
+	if _, err := l.file.Write(line); err != nil {
+		// Cut off a partial write so the next entry starts on a line of its own
+		_ = l.file.Truncate(l.size)
+		return fmt.Errorf("error writing audit log: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		_ = l.file.Truncate(l.size)
+		return fmt.Errorf("error syncing audit log: %w", err)
+	}
+	l.size += int64(len(line))
+
+	// Keep the decoded form so verification sees what a restart would read back
+	var stored model.AuditEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+	l.entries = append(l.entries, &stored)
+	return nil
+}
+
+// List retrieves the entries matching the filter, newest first
+func (l *FileAuditLog) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return filterEntries(l.entries, filter), nil
+}
+
+// All retrieves every entry in chain order
+func (l *FileAuditLog) All(ctx context.Context) ([]*model.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]*model.AuditEntry, len(l.entries))
+	for i, entry := range l.entries {
+		found := *entry
+		entries[i] = &found
+	}
+	return entries, nil
+}
+
+// Close closes the audit log file
+func (l *FileAuditLog) Close() error {
+	return l.file.Close()
+}
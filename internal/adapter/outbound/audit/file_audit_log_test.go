@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/service"
+)
+
+var auditStart = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func openAuditLog(t *testing.T, path string) *FileAuditLog {
+	t.Helper()
+	l, err := NewFileAuditLog(path)
+	if err != nil {
+		t.Fatalf("NewFileAuditLog() error = %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+// mustAppend appends an update of the todo with the given ID
+func mustAppend(t *testing.T, l *FileAuditLog, todoID int) *model.AuditEntry {
+	t.Helper()
+	actorID := 1
+	entry := &model.AuditEntry{
+		Timestamp:  auditStart.Add(time.Duration(todoID) * time.Minute),
+		ActorID:    &actorID,
+		Action:     model.AuditActionUpdate,
+		EntityType: model.AuditEntityTodo,
+		EntityID:   todoID,
+		Changes:    map[string]model.FieldDiff{"title": {Before: "old", After: "new"}},
+	}
+	if err := l.Append(context.Background(), entry); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	return entry
+}
+
+// verify checks the log's hash chain the way administrators do
+func verify(t *testing.T, l *FileAuditLog) *model.AuditVerification {
+	t.Helper()
+	ctx := domain.ContextWithActor(context.Background(), 1)
+	verification, err := service.NewAuditService(l, []int{1}).VerifyAuditLog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return verification
+}
+
+func assertSequences(t *testing.T, l *FileAuditLog, want int) {
+	t.Helper()
+	entries, err := l.All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != want {
+		t.Fatalf("entries = %d, want %d", len(entries), want)
+	}
+	for i, entry := range entries {
+		if entry.Sequence != int64(i+1) {
+			t.Errorf("entry %d has sequence %d", i, entry.Sequence)
+		}
+	}
+	if v := verify(t, l); !v.Valid || v.Entries != want {
+		t.Errorf("verification = %+v, want %d valid entries", v, want)
+	}
+}
+
+func TestFileAuditLogAppend(t *testing.T) {
+	l := openAuditLog(t, filepath.Join(t.TempDir(), "audit", "audit.log"))
+	first := mustAppend(t, l, 1)
+	second := mustAppend(t, l, 2)
+	mustAppend(t, l, 3)
+
+	if first.PrevHash != "" || second.PrevHash != first.Hash {
+		t.Errorf("chain = %q <- %q, want the second entry to point at %q", first.PrevHash, second.PrevHash, first.Hash)
+	}
+	assertSequences(t, l, 3)
+
+	entityID := 2
+	entries, err := l.List(context.Background(), model.AuditFilter{EntityID: &entityID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Sequence != 2 {
+		t.Errorf("List() = %+v, want the entry of todo 2", entries)
+	}
+}
+
+func TestFileAuditLogReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := openAuditLog(t, path)
+	mustAppend(t, l, 1)
+	last := mustAppend(t, l, 2)
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The chain continues after the entries read back from the file
+	reopened := openAuditLog(t, path)
+	next := mustAppend(t, reopened, 3)
+	if next.Sequence != 3 || next.PrevHash != last.Hash {
+		t.Errorf("next entry = sequence %d after %q, want sequence 3 after %q", next.Sequence, next.PrevHash, last.Hash)
+	}
+	assertSequences(t, reopened, 3)
+	assertSequences(t, openAuditLog(t, path), 3)
+}
+
+func TestFileAuditLogIncompleteLastLine(t *testing.T) {
+	tests := []struct {
+		name string
+		tail string
+	}{
+		{name: "cut inside the entry", tail: `{"sequence":3,"timest`},
+		{name: "cut before the newline", tail: `{"sequence":3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			l := openAuditLog(t, path)
+			mustAppend(t, l, 1)
+			mustAppend(t, l, 2)
+			l.Close()
+			appendToFile(t, path, tt.tail)
+
+			reopened := openAuditLog(t, path)
+			assertSequences(t, reopened, 2)
+			mustAppend(t, reopened, 3)
+			assertSequences(t, reopened, 3)
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Count(data, []byte("\n")) != 3 || !bytes.HasSuffix(data, []byte("}\n")) {
+				t.Errorf("file = %s, want three complete entries", data)
+			}
+			assertSequences(t, openAuditLog(t, path), 3)
+		})
+	}
+}
+
+func TestFileAuditLogDamage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	l := openAuditLog(t, path)
+	mustAppend(t, l, 1)
+	mustAppend(t, l, 2)
+	l.Close()
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A complete line that does not parse is not a crash but damage
+	garbled := append([]byte("not json\n"), original...)
+	if err := os.WriteFile(path, garbled, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileAuditLog(path); err == nil {
+		t.Error("NewFileAuditLog() of a garbled file error = nil, want a parse error")
+	}
+
+	// An edited entry reads back but breaks the chain
+	edited := bytes.Replace(original, []byte(`"entity_id":1`), []byte(`"entity_id":7`), 1)
+	if err := os.WriteFile(path, edited, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v := verify(t, openAuditLog(t, path))
+	if v.Valid || v.BrokenAt == nil || *v.BrokenAt != 1 {
+		t.Errorf("verification = %+v, want broken at 1", v)
+	}
+}
+
+func appendToFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"context"
+	"sync"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// MemoryAuditLog implements the AuditPort interface in memory
+type MemoryAuditLog struct {
+	entries []*model.AuditEntry
+	mu      sync.RWMutex
+}
+
+// NewMemoryAuditLog creates a new MemoryAuditLog
+func NewMemoryAuditLog() *MemoryAuditLog {
+	return &MemoryAuditLog{}
+}
+
+// last returns the newest entry, or nil for an empty log. The caller holds the lock.
+func (l *MemoryAuditLog) last() *model.AuditEntry {
+	if len(l.entries) == 0 {
+		return nil
+	}
+	return l.entries[len(l.entries)-1]
+}
+
+// Append chains the entry after the newest one and stores it
+func (l *MemoryAuditLog) Append(ctx context.Context, entry *model.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Chain(l.last())
+	stored := *entry
+	l.entries = append(l.entries, &stored)
+	return nil
+}
+
+// List retrieves the entries matching the filter, newest first
+func (l *MemoryAuditLog) List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return filterEntries(l.entries, filter), nil
+}
+
+// All retrieves every entry in chain order
+func (l *MemoryAuditLog) All(ctx context.Context) ([]*model.AuditEntry, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	entries := make([]*model.AuditEntry, len(l.entries))
+	for i, entry := range l.entries {
+		found := *entry
+		entries[i] = &found
+	}
+	return entries, nil
+}
+
+// filterEntries returns copies of the matching entries, newest first, up to the filter's limit
+func filterEntries(entries []*model.AuditEntry, filter model.AuditFilter) []*model.AuditEntry {
+	matched := make([]*model.AuditEntry, 0)
+	for i := len(entries) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(matched) == filter.Limit {
+			break
+		}
+		if filter.Matches(entries[i]) {
+			found := *entries[i]
+			matched = append(matched, &found)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,228 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// ErrBusClosed is returned when publishing to a bus that was closed
+var ErrBusClosed = errors.New("event bus is closed")
+
+// envelope carries an event to an async subscriber together with the
+// context values of the publisher
+type envelope struct {
+	ctx   context.Context
+	event model.Event
+}
+
+// subscription is a handler and the event types it listens to
+type subscription struct {
+	handler port.EventHandler
+	types   map[model.EventType]bool
+	// shards are the queues of an async subscription; events of one
+	// aggregate always land on the same shard and are handled in order
+	shards []chan envelope
+}
+
+// wants reports whether the subscription listens to the event type
+func (s *subscription) wants(eventType model.EventType) bool {
+	return len(s.types) == 0 || s.types[eventType]
+}
+
+// Bus implements the EventPublisherPort and EventSubscriberPort interfaces in
+// process. Sync subscribers run before Publish returns. Each async subscriber
+// gets its own workers, so a slow subscriber never delays the others; when its
+// queues are full Publish waits. A failing or panicking handler never affects
+// the publisher or other subscribers. A failing sync handler is logged; a
+// failing async handler is retried with backoff until it succeeds, holding
+// back the later events of its shard so their order is kept.
+type Bus struct {
+	syncSubs     []*subscription
+	asyncSubs    []*subscription
+	workers      int
+	bufferSize   int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	closed       bool
+	// closing is closed by Close to stop retrying failed events
+	closing chan struct{}
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+}
+
+// Option configures optional Bus behavior
+type Option func(*Bus)
+
+// WithRetryBackoff sets the wait before retrying a failed async handler, which
+// doubles with every further failure up to maxBackoff
+func WithRetryBackoff(initial, maxBackoff time.Duration) Option {
+	return func(b *Bus) {
+		b.retryBackoff = initial
+		b.maxBackoff = maxBackoff
+	}
+}
+
+// New creates a Bus that runs each async subscriber on the given number of
+// workers, each with a queue of bufferSize events
+func New(workers, bufferSize int, opts ...Option) *Bus {
+	b := &Bus{
+		workers:      max(workers, 1),
+		bufferSize:   max(bufferSize, 0),
+		retryBackoff: 100 * time.Millisecond,
+		maxBackoff:   time.Minute,
+		closing:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// newSubscription builds a subscription for the handler and event types
+func newSubscription(handler port.EventHandler, types []model.EventType) *subscription {
+	sub := &subscription{handler: handler, types: make(map[model.EventType]bool, len(types))}
+	for _, eventType := range types {
+		sub.types[eventType] = true
+	}
+	return sub
+}
+
+// SubscribeSync runs the handler before Publish returns
+func (b *Bus) SubscribeSync(handler port.EventHandler, types ...model.EventType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.syncSubs = append(b.syncSubs, newSubscription(handler, types))
+}
+
+// SubscribeAsync runs the handler on background workers, in publish order per aggregate
+func (b *Bus) SubscribeAsync(handler port.EventHandler, types ...model.EventType) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := newSubscription(handler, types)
+	sub.shards = make([]chan envelope, b.workers)
+	for i := range sub.shards {
+		sub.shards[i] = make(chan envelope, b.bufferSize)
+		b.wg.Add(1)
+		go b.work(sub.handler, sub.shards[i])
+	}
+	b.asyncSubs = append(b.asyncSubs, sub)
+}
+
+// work handles the events of one shard until it is closed
+func (b *Bus) work(handler port.EventHandler, shard <-chan envelope) {
+	defer b.wg.Done()
+
+	for env := range shard {
+		b.deliver(env.ctx, handler, env.event)
+	}
+}
+
+// deliver runs an async handler until it succeeds, waiting longer after each
+// failure. Once the bus is closing a failed event is given up, so Close
+// cannot hang on a handler that keeps failing.
+func (b *Bus) deliver(ctx context.Context, handler port.EventHandler, event model.Event) {
+	backoff := b.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := dispatch(ctx, handler, event)
+		if err == nil {
+			return
+		}
+
+		select {
+		case <-b.closing:
+			log.Printf("Event handler gave up on %s %s after %d attempts, the bus is closing: %v", event.Type, event.ID, attempt, err)
+			return
+		default:
+		}
+		log.Printf("Event handler failed on %s %s (attempt %d), retrying in %s: %v", event.Type, event.ID, attempt, backoff, err)
+		select {
+		case <-b.closing:
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, b.maxBackoff)
+	}
+}
+
+// Publish delivers the events to every interested subscriber, in order
+func (b *Bus) Publish(ctx context.Context, events ...model.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+
+	for _, event := range events {
+		for _, sub := range b.syncSubs {
+			if sub.wants(event.Type) {
+				if err := dispatch(ctx, sub.handler, event); err != nil {
+					log.Printf("Event handler failed on %s %s: %v", event.Type, event.ID, err)
+				}
+			}
+		}
+
+		// Async handlers outlive the request, so they keep its values but not its cancellation
+		asyncCtx := context.WithoutCancel(ctx)
+		shard := shardOf(event, b.workers)
+		for _, sub := range b.asyncSubs {
+			if sub.wants(event.Type) {
+				sub.shards[shard] <- envelope{ctx: asyncCtx, event: event}
+			}
+		}
+	}
+	return nil
+}
+
+// Close stops accepting events and waits until the async subscribers have
+// handled everything already published. Events whose handler fails are no
+// longer retried.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.closing)
+	for _, sub := range b.asyncSubs {
+		for _, shard := range sub.shards {
+			close(shard)
+		}
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// shardOf picks the worker that handles the event's aggregate
+func shardOf(event model.Event, workers int) int {
+	h := fnv.New32a()
+	h.Write([]byte(event.AggregateType))
+	h.Write([]byte(strconv.Itoa(event.AggregateID)))
+	return int(h.Sum32() % uint32(workers))
+}
+
+// dispatch runs a handler, turning a panic into an error so it cannot take the
+// publisher or a worker down
+func dispatch(ctx context.Context, handler port.EventHandler, event model.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event handler panicked on %s %s: %v\n%s", event.Type, event.ID, r, debug.Stack())
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, event)
+}
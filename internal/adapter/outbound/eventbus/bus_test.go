@@ -0,0 +1,279 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// recorder is a handler that records the events it receives
+type recorder struct {
+	mu     sync.Mutex
+	events []model.Event
+	// delay slows every call down, so queues fill up
+	delay time.Duration
+}
+
+func (r *recorder) handle(ctx context.Context, event model.Event) error {
+	time.Sleep(r.delay)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// ids returns the IDs of the recorded events, optionally of one aggregate only
+func (r *recorder) ids(aggregateID int) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ids []string
+	for _, event := range r.events {
+		if aggregateID == 0 || event.AggregateID == aggregateID {
+			ids = append(ids, event.ID)
+		}
+	}
+	return ids
+}
+
+// todoEvent creates the n-th event of a todo
+func todoEvent(todoID, n int) model.Event {
+	return model.Event{
+		ID:            fmt.Sprintf("%d-%d", todoID, n),
+		Type:          model.EventTodoUpdated,
+		AggregateType: model.AggregateTodo,
+		AggregateID:   todoID,
+	}
+}
+
+func TestBusOrderPerAggregate(t *testing.T) {
+	const todos, perTodo = 16, 50
+	bus := New(4, 8)
+	rec := &recorder{}
+	bus.SubscribeAsync(rec.handle)
+
+	// Events of different todos interleave, and spread over every shard
+	shards := make(map[int]bool)
+	for n := range perTodo {
+		for todoID := 1; todoID <= todos; todoID++ {
+			event := todoEvent(todoID, n)
+			shards[shardOf(event, 4)] = true
+			if err := bus.Publish(context.Background(), event); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	bus.Close()
+	if len(shards) != 4 {
+		t.Fatalf("events landed on %d shards, want 4", len(shards))
+	}
+
+	for todoID := 1; todoID <= todos; todoID++ {
+		var want []string
+		for n := range perTodo {
+			want = append(want, todoEvent(todoID, n).ID)
+		}
+		if got := rec.ids(todoID); !slices.Equal(got, want) {
+			t.Errorf("events of todo %d = %v, want %v", todoID, got, want)
+		}
+	}
+}
+
+func TestBusIsolatesFailingHandlers(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func() error
+	}{
+		{name: "panic", fail: func() error { panic("handler bug") }},
+		{name: "error", fail: func() error { return errors.New("handler failed") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := New(2, 4)
+			failing := &recorder{}
+			healthy := &recorder{}
+			syncHealthy := &recorder{}
+			handler := func(ctx context.Context, event model.Event) error {
+				if event.ID == "1-0" {
+					return tt.fail()
+				}
+				return failing.handle(ctx, event)
+			}
+			bus.SubscribeSync(func(ctx context.Context, event model.Event) error {
+				if event.ID == "1-0" {
+					return tt.fail()
+				}
+				return syncHealthy.handle(ctx, event)
+			})
+			bus.SubscribeAsync(handler)
+			bus.SubscribeAsync(healthy.handle)
+
+			// The publisher never sees the failure
+			for _, event := range []model.Event{todoEvent(1, 0), todoEvent(1, 1), todoEvent(2, 0)} {
+				if err := bus.Publish(context.Background(), event); err != nil {
+					t.Fatalf("Publish(%s) error = %v", event.ID, err)
+				}
+			}
+			bus.Close()
+
+			if got := healthy.ids(0); len(got) != 3 {
+				t.Errorf("healthy subscriber got %v, want all three events", got)
+			}
+			if got := syncHealthy.ids(0); !slices.Equal(got, []string{"1-1", "2-0"}) {
+				t.Errorf("failing sync subscriber got %v, want the later events", got)
+			}
+			// The worker survives and goes on with the later events
+			if got := failing.ids(0); !slices.Contains(got, "1-1") || !slices.Contains(got, "2-0") {
+				t.Errorf("failing async subscriber got %v, want the later events", got)
+			}
+		})
+	}
+}
+
+func TestBusCloseDrainsQueues(t *testing.T) {
+	bus := New(2, 64)
+	slow := &recorder{delay: time.Millisecond}
+	bus.SubscribeAsync(slow.handle)
+
+	var want []string
+	for n := range 40 {
+		event := todoEvent(n%5+1, n)
+		want = append(want, event.ID)
+		if err := bus.Publish(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Most events are still queued when Close is called; it waits for them all
+	bus.Close()
+	got := slow.ids(0)
+	slices.Sort(got)
+	slices.Sort(want)
+	if !slices.Equal(got, want) {
+		t.Errorf("handled %d events, want %d", len(got), len(want))
+	}
+
+	if err := bus.Publish(context.Background(), todoEvent(1, 99)); !errors.Is(err, ErrBusClosed) {
+		t.Errorf("Publish() after Close error = %v, want %v", err, ErrBusClosed)
+	}
+	bus.Close()
+}
+
+func TestBusEventTypes(t *testing.T) {
+	bus := New(1, 4)
+	completed := &recorder{}
+	all := &recorder{}
+	bus.SubscribeAsync(completed.handle, model.EventTodoCompleted)
+	bus.SubscribeAsync(all.handle)
+
+	done := todoEvent(1, 1)
+	done.Type = model.EventTodoCompleted
+	for _, event := range []model.Event{todoEvent(1, 0), done} {
+		if err := bus.Publish(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bus.Close()
+
+	if got := completed.ids(0); !slices.Equal(got, []string{"1-1"}) {
+		t.Errorf("completed subscriber got %v, want [1-1]", got)
+	}
+	if got := all.ids(0); !slices.Equal(got, []string{"1-0", "1-1"}) {
+		t.Errorf("subscriber to every type got %v, want [1-0 1-1]", got)
+	}
+}
+
+func TestBusAsyncContext(t *testing.T) {
+	type key struct{}
+	bus := New(1, 1)
+	values := make(chan any, 1)
+	bus.SubscribeAsync(func(ctx context.Context, event model.Event) error {
+		if ctx.Err() != nil {
+			values <- ctx.Err()
+			return nil
+		}
+		values <- ctx.Value(key{})
+		return nil
+	})
+
+	// The handler keeps the request's values, but outlives its cancellation
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request"))
+	if err := bus.Publish(ctx, todoEvent(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	bus.Close()
+	if got := <-values; got != "request" {
+		t.Errorf("handler saw %v, want the request's value", got)
+	}
+}
+
+func TestBusRetriesFailingHandlers(t *testing.T) {
+	bus := New(1, 4, WithRetryBackoff(time.Millisecond, 2*time.Millisecond))
+	rec := &recorder{}
+	var mu sync.Mutex
+	attempts := 0
+	bus.SubscribeAsync(func(ctx context.Context, event model.Event) error {
+		if event.ID == "1-0" {
+			mu.Lock()
+			attempts++
+			n := attempts
+			mu.Unlock()
+			if n < 3 {
+				return errors.New("consumer unavailable")
+			}
+		}
+		return rec.handle(ctx, event)
+	})
+
+	for _, event := range []model.Event{todoEvent(1, 0), todoEvent(1, 1)} {
+		if err := bus.Publish(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(rec.ids(0)) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	bus.Close()
+
+	// The later event waits for the failing one, keeping their order
+	if got := rec.ids(0); !slices.Equal(got, []string{"1-0", "1-1"}) {
+		t.Errorf("handled %v, want [1-0 1-1]", got)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestBusCloseStopsRetrying(t *testing.T) {
+	bus := New(1, 4, WithRetryBackoff(time.Hour, time.Hour))
+	failed := make(chan struct{}, 1)
+	bus.SubscribeAsync(func(ctx context.Context, event model.Event) error {
+		select {
+		case failed <- struct{}{}:
+		default:
+		}
+		return errors.New("consumer unavailable")
+	})
+	if err := bus.Publish(context.Background(), todoEvent(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	<-failed
+
+	closed := make(chan struct{})
+	go func() {
+		bus.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close() waited for a handler that keeps failing")
+	}
+}
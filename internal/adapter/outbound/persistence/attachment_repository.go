@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// AttachmentRepository implements the AttachmentRepositoryPort interface
+type AttachmentRepository struct {
+	attachments map[int]*model.Attachment
+	mu          txMutex
+	nextID      int
+}
+
+// NewAttachmentRepository creates a new AttachmentRepository
+func NewAttachmentRepository() *AttachmentRepository {
+	return &AttachmentRepository{
+		attachments: make(map[int]*model.Attachment),
+		nextID:      1,
+	}
+}
+
+// Create creates a new attachment
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *model.Attachment) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	attachment.ID = r.nextID
+	r.nextID++
+	stored := *attachment
+	saveEntry(ctx, &r.mu, r.attachments, attachment.ID, nil)
+	r.attachments[attachment.ID] = &stored
+	return nil
+}
+
+// GetByID retrieves an attachment by ID
+func (r *AttachmentRepository) GetByID(ctx context.Context, id int) (*model.Attachment, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	attachment, exists := r.attachments[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *attachment
+	return &found, nil
+}
+
+// ListByTodo retrieves the attachments of a todo, ordered by ID
+func (r *AttachmentRepository) ListByTodo(ctx context.Context, todoID int) ([]*model.Attachment, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	attachments := make([]*model.Attachment, 0)
+	for _, attachment := range r.attachments {
+		if attachment.TodoID == todoID {
+			found := *attachment
+			attachments = append(attachments, &found)
+		}
+	}
+	sort.Slice(attachments, func(i, j int) bool { return attachments[i].ID < attachments[j].ID })
+	return attachments, nil
+}
+
+// Delete deletes an attachment
+func (r *AttachmentRepository) Delete(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.attachments[id]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.attachments, id, nil)
+	delete(r.attachments, id)
+	return nil
+}
+
+// CountByHash counts the attachments sharing the content with the given hash
+func (r *AttachmentRepository) CountByHash(ctx context.Context, sha256 string) (int, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	count := 0
+	for _, attachment := range r.attachments {
+		if attachment.SHA256 == sha256 {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// txLock returns the repository's lock
+func (r *AttachmentRepository) txLock() *txMutex {
+	return &r.mu
+}
@@ -0,0 +1,129 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// CommentRepository implements the CommentRepositoryPort interface
+type CommentRepository struct {
+	comments  map[int]*model.Comment
+	revisions map[int][]*model.CommentRevision
+	mu        txMutex
+	nextID    int
+}
+
+// NewCommentRepository creates a new CommentRepository
+func NewCommentRepository() *CommentRepository {
+	return &CommentRepository{
+		comments:  make(map[int]*model.Comment),
+		revisions: make(map[int][]*model.CommentRevision),
+		nextID:    1,
+	}
+}
+
+// Create creates a new comment
+func (r *CommentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	comment.ID = r.nextID
+	r.nextID++
+	saveEntry(ctx, &r.mu, r.comments, comment.ID, nil)
+	r.comments[comment.ID] = comment.Clone()
+	return nil
+}
+
+// GetByID retrieves a comment by ID
+func (r *CommentRepository) GetByID(ctx context.Context, id int) (*model.Comment, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	comment, exists := r.comments[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return comment.Clone(), nil
+}
+
+// ListByTodo retrieves the comments of a todo, ordered by ID
+func (r *CommentRepository) ListByTodo(ctx context.Context, todoID int) ([]*model.Comment, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	comments := make([]*model.Comment, 0)
+	for _, comment := range r.comments {
+		if comment.TodoID == todoID {
+			comments = append(comments, comment.Clone())
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	return comments, nil
+}
+
+// Update updates an existing comment
+func (r *CommentRepository) Update(ctx context.Context, comment *model.Comment) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.comments[comment.ID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.comments, comment.ID, nil)
+	r.comments[comment.ID] = comment.Clone()
+	return nil
+}
+
+// AppendRevision records a previous body of a comment
+func (r *CommentRepository) AppendRevision(ctx context.Context, revision *model.CommentRevision) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.comments[revision.CommentID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	stored := *revision
+	saveEntry(ctx, &r.mu, r.revisions, revision.CommentID, nil)
+	r.revisions[revision.CommentID] = append(r.revisions[revision.CommentID], &stored)
+	return nil
+}
+
+// ListRevisions retrieves the previous bodies of a comment, oldest first
+func (r *CommentRepository) ListRevisions(ctx context.Context, commentID int) ([]*model.CommentRevision, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	if _, exists := r.comments[commentID]; !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	revisions := make([]*model.CommentRevision, len(r.revisions[commentID]))
+	for i, revision := range r.revisions[commentID] {
+		found := *revision
+		revisions[i] = &found
+	}
+	return revisions, nil
+}
+
+// txLock returns the repository's lock
+func (r *CommentRepository) txLock() *txMutex {
+	return &r.mu
+}
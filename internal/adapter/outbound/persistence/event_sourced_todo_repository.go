@@ -0,0 +1,474 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"maps"
+	"sort"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// nullField is the stored value of a field an event cleared
+var nullField = json.RawMessage("null")
+
+// EventSourcedTodoRepository implements the TodoRepositoryPort and
+// TodoHistoryPort interfaces by keeping every todo as an append-only stream of
+// events. A todo's state is rebuilt by replaying its stream on top of the
+// latest snapshot, which is taken every snapshotEvery events.
+type EventSourcedTodoRepository struct {
+	streams       map[int][]*model.TodoEvent
+	snapshots     map[int][]*model.TodoSnapshot
+	transitions   map[int][]*model.TodoTransition
+	changes       map[int][]*model.TodoChange
+	snapshotEvery int
+	mu            txMutex
+	nextID        int
+	now           func() time.Time
+}
+
+// NewEventSourcedTodoRepository creates a new EventSourcedTodoRepository that
+// snapshots a todo every snapshotEvery events
+func NewEventSourcedTodoRepository(snapshotEvery int) *EventSourcedTodoRepository {
+	return &EventSourcedTodoRepository{
+		streams:       make(map[int][]*model.TodoEvent),
+		snapshots:     make(map[int][]*model.TodoSnapshot),
+		transitions:   make(map[int][]*model.TodoTransition),
+		changes:       make(map[int][]*model.TodoChange),
+		snapshotEvery: max(snapshotEvery, 1),
+		nextID:        1,
+		now:           time.Now,
+	}
+}
+
+// todoFields returns the stored fields of a todo keyed by their JSON name.
+// Progress is computed on read and never stored.
+func todoFields(todo *model.Todo) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(todo)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "progress")
+	return fields, nil
+}
+
+// diffFields returns the fields that differ between two states, with null for removed fields
+func diffFields(before, after map[string]json.RawMessage) map[string]json.RawMessage {
+	diff := make(map[string]json.RawMessage)
+	for name, value := range after {
+		if !bytes.Equal(before[name], value) {
+			diff[name] = value
+		}
+	}
+	for name := range before {
+		if _, exists := after[name]; !exists {
+			diff[name] = nullField
+		}
+	}
+	return diff
+}
+
+// rebuild replays a todo's stream up to the given time, or to its latest
+// event if at is nil. It returns ErrNotFound if the todo did not exist then.
+// The caller holds the lock.
+func (r *EventSourcedTodoRepository) rebuild(id int, at *time.Time) (*model.Todo, error) {
+	stream := r.streams[id]
+	if len(stream) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	reached := func(t time.Time) bool { return at == nil || !t.After(*at) }
+
+	fields := make(map[string]json.RawMessage)
+	version := 0
+	snapshots := r.snapshots[id]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if reached(snapshots[i].OccurredAt) {
+			snapshotFields, err := todoFields(snapshots[i].Todo)
+			if err != nil {
+				return nil, err
+			}
+			fields, version = snapshotFields, snapshots[i].Version
+			break
+		}
+	}
+
+	for _, event := range stream[version:] {
+		if !reached(event.OccurredAt) {
+			break
+		}
+		maps.Copy(fields, event.Changes)
+		version = event.Version
+	}
+	if version == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	todo := &model.Todo{}
+	if err := json.Unmarshal(data, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// current rebuilds the latest state of a live todo. The caller holds the lock.
+func (r *EventSourcedTodoRepository) current(id int) (*model.Todo, error) {
+	todo, err := r.rebuild(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if todo.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return todo, nil
+}
+
+// appendEvent adds an event to a todo's stream, taking a snapshot when one is
+// due. Events without changes are dropped. The caller holds the lock.
+func (r *EventSourcedTodoRepository) appendEvent(ctx context.Context, id int, eventType model.TodoEventType, changes map[string]json.RawMessage) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	event := &model.TodoEvent{
+		TodoID:     id,
+		Version:    len(r.streams[id]) + 1,
+		Type:       eventType,
+		Changes:    changes,
+		OccurredAt: r.now(),
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		event.ActorID = &actorID
+	}
+	saveEntry(ctx, &r.mu, r.streams, id, nil)
+	r.streams[id] = append(r.streams[id], event)
+
+	if event.Version%r.snapshotEvery == 0 {
+		todo, err := r.rebuild(id, nil)
+		if err != nil {
+			return err
+		}
+		saveEntry(ctx, &r.mu, r.snapshots, id, nil)
+		r.snapshots[id] = append(r.snapshots[id], &model.TodoSnapshot{
+			Version:    event.Version,
+			Todo:       todo,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+	return nil
+}
+
+// recordState appends an event holding the fields that changed between two states of a todo.
+// The caller holds the lock.
+func (r *EventSourcedTodoRepository) recordState(ctx context.Context, eventType model.TodoEventType, before, after *model.Todo) error {
+	beforeFields := map[string]json.RawMessage{}
+	if before != nil {
+		fields, err := todoFields(before)
+		if err != nil {
+			return err
+		}
+		beforeFields = fields
+	}
+	afterFields, err := todoFields(after)
+	if err != nil {
+		return err
+	}
+	return r.appendEvent(ctx, after.ID, eventType, diffFields(beforeFields, afterFields))
+}
+
+// Create starts the stream of a new todo
+func (r *EventSourcedTodoRepository) Create(ctx context.Context, todo *model.Todo) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	todo.ID = r.nextID
+	r.nextID++
+	return r.recordState(ctx, model.TodoEventCreated, nil, todo)
+}
+
+// GetByID rebuilds a live todo
+func (r *EventSourcedTodoRepository) GetByID(ctx context.Context, id int) (*model.Todo, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	return r.current(id)
+}
+
+// List rebuilds all todos matching the filter, ordered by ID
+func (r *EventSourcedTodoRepository) List(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	todos := make([]*model.Todo, 0, len(r.streams))
+	for id := range r.streams {
+		todo, err := r.rebuild(id, nil)
+		if err != nil {
+			return nil, err
+		}
+		if filter.Matches(todo) {
+			todos = append(todos, todo)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+	return todos, nil
+}
+
+// Update appends the fields that changed on a live todo
+func (r *EventSourcedTodoRepository) Update(ctx context.Context, todo *model.Todo) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	before, err := r.current(todo.ID)
+	if err != nil {
+		return err
+	}
+	return r.recordState(ctx, model.TodoEventUpdated, before, todo)
+}
+
+// Delete permanently deletes a todo together with its stream and history
+func (r *EventSourcedTodoRepository) Delete(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.streams[id]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.streams, id, nil)
+	saveEntry(ctx, &r.mu, r.snapshots, id, nil)
+	saveEntry(ctx, &r.mu, r.transitions, id, nil)
+	saveEntry(ctx, &r.mu, r.changes, id, nil)
+	delete(r.streams, id)
+	delete(r.snapshots, id)
+	delete(r.transitions, id)
+	delete(r.changes, id)
+	return nil
+}
+
+// SoftDelete moves a live todo to the trash
+func (r *EventSourcedTodoRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	before, err := r.current(id)
+	if err != nil {
+		return err
+	}
+	after := before.Clone()
+	after.DeletedAt = &deletedAt
+	return r.recordState(ctx, model.TodoEventDeleted, before, after)
+}
+
+// Restore moves a trashed todo out of the trash
+func (r *EventSourcedTodoRepository) Restore(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	before, err := r.rebuild(id, nil)
+	if err != nil {
+		return err
+	}
+	if before.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+	after := before.Clone()
+	after.DeletedAt = nil
+	return r.recordState(ctx, model.TodoEventRestored, before, after)
+}
+
+// AppendTransition records a status transition of a todo
+func (r *EventSourcedTodoRepository) AppendTransition(ctx context.Context, transition *model.TodoTransition) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, err := r.current(transition.TodoID); err != nil {
+		return err
+	}
+
+	saveEntry(ctx, &r.mu, r.transitions, transition.TodoID, nil)
+	r.transitions[transition.TodoID] = append(r.transitions[transition.TodoID], transition)
+	return nil
+}
+
+// ListTransitions retrieves the status transitions of a todo, oldest first
+func (r *EventSourcedTodoRepository) ListTransitions(ctx context.Context, todoID int) ([]*model.TodoTransition, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	if _, err := r.current(todoID); err != nil {
+		return nil, err
+	}
+
+	transitions := make([]*model.TodoTransition, len(r.transitions[todoID]))
+	copy(transitions, r.transitions[todoID])
+	return transitions, nil
+}
+
+// AppendChanges records field changes of todos
+func (r *EventSourcedTodoRepository) AppendChanges(ctx context.Context, changes []*model.TodoChange) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	for _, change := range changes {
+		if _, err := r.current(change.TodoID); err != nil {
+			return err
+		}
+	}
+	for _, change := range changes {
+		stored := *change
+		saveEntry(ctx, &r.mu, r.changes, change.TodoID, nil)
+		r.changes[change.TodoID] = append(r.changes[change.TodoID], &stored)
+	}
+	return nil
+}
+
+// ListChanges retrieves the field changes of a todo, oldest first
+func (r *EventSourcedTodoRepository) ListChanges(ctx context.Context, todoID int) ([]*model.TodoChange, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	if _, err := r.current(todoID); err != nil {
+		return nil, err
+	}
+
+	changes := make([]*model.TodoChange, len(r.changes[todoID]))
+	for i, change := range r.changes[todoID] {
+		found := *change
+		changes[i] = &found
+	}
+	return changes, nil
+}
+
+// updateTags appends a tag change to every todo whose tags the function alters.
+// The caller holds the lock.
+func (r *EventSourcedTodoRepository) updateTags(ctx context.Context, tagID int, update func(tagIDs []int) []int) error {
+	for id := range r.streams {
+		before, err := r.rebuild(id, nil)
+		if err != nil {
+			return err
+		}
+		if !before.HasTag(tagID) {
+			continue
+		}
+		after := before.Clone()
+		after.TagIDs = update(after.TagIDs)
+		if err := r.recordState(ctx, model.TodoEventUpdated, before, after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceTag swaps a tag for another one on every todo carrying it
+func (r *EventSourcedTodoRepository) ReplaceTag(ctx context.Context, oldTagID, newTagID int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	return r.updateTags(ctx, oldTagID, func(tagIDs []int) []int {
+		replaced := make([]int, 0, len(tagIDs))
+		for _, id := range tagIDs {
+			if id != oldTagID && id != newTagID {
+				replaced = append(replaced, id)
+			}
+		}
+		replaced = append(replaced, newTagID)
+		sort.Ints(replaced)
+		return replaced
+	})
+}
+
+// RemoveTag detaches a tag from every todo carrying it
+func (r *EventSourcedTodoRepository) RemoveTag(ctx context.Context, tagID int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	return r.updateTags(ctx, tagID, func(tagIDs []int) []int {
+		remaining := make([]int, 0, len(tagIDs))
+		for _, id := range tagIDs {
+			if id != tagID {
+				remaining = append(remaining, id)
+			}
+		}
+		return remaining
+	})
+}
+
+// ListEvents retrieves the stored events of a live or trashed todo, oldest first
+func (r *EventSourcedTodoRepository) ListEvents(ctx context.Context, todoID int) ([]*model.TodoEvent, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	stream, exists := r.streams[todoID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	events := make([]*model.TodoEvent, len(stream))
+	for i, event := range stream {
+		found := *event
+		found.Changes = maps.Clone(event.Changes)
+		events[i] = &found
+	}
+	return events, nil
+}
+
+// GetAsOf rebuilds a todo as it was at the given time
+func (r *EventSourcedTodoRepository) GetAsOf(ctx context.Context, id int, at time.Time) (*model.Todo, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	todo, err := r.rebuild(id, &at)
+	if err != nil {
+		return nil, err
+	}
+	if todo.DeletedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return todo, nil
+}
+
+// txLock returns the repository's lock
+func (r *EventSourcedTodoRepository) txLock() *txMutex {
+	return &r.mu
+}
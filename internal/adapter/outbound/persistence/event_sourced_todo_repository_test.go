@@ -0,0 +1,308 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// todoStep is an operation on a todo repository
+type todoStep func(ctx context.Context, repo port.TodoRepositoryPort) error
+
+// createTodo creates a todo with the title
+func createTodo(title string) todoStep {
+	return func(ctx context.Context, repo port.TodoRepositoryPort) error {
+		return repo.Create(ctx, &model.Todo{OwnerID: 1, Title: title, Status: model.TodoStatusOpen, CreatedAt: esStart})
+	}
+}
+
+// renameTodo changes the title of a todo
+func renameTodo(id int, title string) todoStep {
+	return func(ctx context.Context, repo port.TodoRepositoryPort) error {
+		todo, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		todo.Title = title
+		return repo.Update(ctx, todo)
+	}
+}
+
+// tagTodo attaches tags to a todo
+func tagTodo(id int, tagIDs ...int) todoStep {
+	return func(ctx context.Context, repo port.TodoRepositoryPort) error {
+		todo, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		todo.TagIDs = tagIDs
+		return repo.Update(ctx, todo)
+	}
+}
+
+var esStart = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestEventSourcedTodoRepositoryMatchesMemory(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []todoStep
+	}{
+		{
+			name:  "created",
+			steps: []todoStep{createTodo("a"), createTodo("b")},
+		},
+		{
+			name:  "updated past several snapshots",
+			steps: []todoStep{createTodo("a"), renameTodo(1, "b"), renameTodo(1, "c"), renameTodo(1, "d"), renameTodo(1, "e")},
+		},
+		{
+			name: "field cleared",
+			steps: []todoStep{createTodo("a"), tagTodo(1, 1, 2), func(ctx context.Context, repo port.TodoRepositoryPort) error {
+				todo, err := repo.GetByID(ctx, 1)
+				if err != nil {
+					return err
+				}
+				todo.TagIDs = nil
+				return repo.Update(ctx, todo)
+			}},
+		},
+		{
+			name: "trashed and restored",
+			steps: []todoStep{createTodo("a"), createTodo("b"),
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.SoftDelete(ctx, 1, esStart) },
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.SoftDelete(ctx, 2, esStart) },
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.Restore(ctx, 2) },
+			},
+		},
+		{
+			name: "purged",
+			steps: []todoStep{createTodo("a"), createTodo("b"),
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.Delete(ctx, 1) },
+			},
+		},
+		{
+			name: "tags replaced and removed",
+			steps: []todoStep{createTodo("a"), createTodo("b"), createTodo("c"), tagTodo(1, 1, 3), tagTodo(2, 2), tagTodo(3, 1),
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.ReplaceTag(ctx, 1, 2) },
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.RemoveTag(ctx, 3) },
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, snapshotEvery := range []int{1, 2, 20} {
+			t.Run(fmt.Sprintf("%s/snapshot every %d", tt.name, snapshotEvery), func(t *testing.T) {
+				ctx := context.Background()
+				memory := NewTodoRepository()
+				eventSourced := NewEventSourcedTodoRepository(snapshotEvery)
+				for i, step := range tt.steps {
+					if err := step(ctx, memory); err != nil {
+						t.Fatalf("step %d on memory: %v", i, err)
+					}
+					if err := step(ctx, eventSourced); err != nil {
+						t.Fatalf("step %d on event-sourced: %v", i, err)
+					}
+				}
+
+				for _, deleted := range []bool{false, true} {
+					want := listJSON(t, memory, deleted)
+					if got := listJSON(t, eventSourced, deleted); got != want {
+						t.Errorf("deleted=%v:\n got %s\nwant %s", deleted, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// listJSON returns the live or trashed todos of a repository as JSON
+func listJSON(t *testing.T, repo port.TodoRepositoryPort, deleted bool) string {
+	t.Helper()
+	todos, err := repo.List(context.Background(), model.TodoFilter{Deleted: deleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(todos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestEventSourcedGetAsOf(t *testing.T) {
+	// The todo is created at 0h, renamed at 1h and 2h, trashed at 3h and
+	// restored at 4h; a snapshot is taken every two events
+	tests := []struct {
+		name      string
+		at        time.Duration
+		wantTitle string
+		wantErr   error
+	}{
+		{name: "before creation", at: -time.Hour, wantErr: domain.ErrNotFound},
+		{name: "at creation", at: 0, wantTitle: "first"},
+		{name: "between renames", at: 90 * time.Minute, wantTitle: "second"},
+		{name: "at snapshot", at: 2 * time.Hour, wantTitle: "third"},
+		{name: "in the trash", at: 3 * time.Hour, wantErr: domain.ErrNotFound},
+		{name: "after restore", at: 5 * time.Hour, wantTitle: "third"},
+	}
+
+	ctx := context.Background()
+	repo := NewEventSourcedTodoRepository(2)
+	clock := esStart
+	repo.now = func() time.Time { return clock }
+	steps := []todoStep{
+		createTodo("first"),
+		renameTodo(1, "second"),
+		renameTodo(1, "third"),
+		func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.SoftDelete(ctx, 1, clock) },
+		func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.Restore(ctx, 1) },
+	}
+	for i, step := range steps {
+		clock = esStart.Add(time.Duration(i) * time.Hour)
+		if err := step(ctx, repo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo, err := repo.GetAsOf(ctx, 1, esStart.Add(tt.at))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAsOf() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && todo.Title != tt.wantTitle {
+				t.Errorf("GetAsOf() title = %q, want %q", todo.Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestEventSourcedEvents(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       []todoStep
+		wantTypes   []model.TodoEventType
+		wantChanged []string
+	}{
+		{
+			name:        "created",
+			steps:       []todoStep{createTodo("a")},
+			wantTypes:   []model.TodoEventType{model.TodoEventCreated},
+			wantChanged: []string{"title"},
+		},
+		{
+			name:        "only changed fields stored",
+			steps:       []todoStep{createTodo("a"), renameTodo(1, "b")},
+			wantTypes:   []model.TodoEventType{model.TodoEventCreated, model.TodoEventUpdated},
+			wantChanged: []string{"title"},
+		},
+		{
+			name:      "unchanged update dropped",
+			steps:     []todoStep{createTodo("a"), renameTodo(1, "a")},
+			wantTypes: []model.TodoEventType{model.TodoEventCreated},
+		},
+		{
+			name: "trashed",
+			steps: []todoStep{createTodo("a"),
+				func(ctx context.Context, repo port.TodoRepositoryPort) error { return repo.SoftDelete(ctx, 1, esStart) },
+			},
+			wantTypes:   []model.TodoEventType{model.TodoEventCreated, model.TodoEventDeleted},
+			wantChanged: []string{"deleted_at"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewEventSourcedTodoRepository(20)
+			for _, step := range tt.steps {
+				if err := step(ctx, repo); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			events, err := repo.ListEvents(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != len(tt.wantTypes) {
+				t.Fatalf("events = %d, want %d", len(events), len(tt.wantTypes))
+			}
+			for i, event := range events {
+				if event.Type != tt.wantTypes[i] || event.Version != i+1 {
+					t.Errorf("event %d = %s v%d, want %s v%d", i, event.Type, event.Version, tt.wantTypes[i], i+1)
+				}
+			}
+			last := events[len(events)-1]
+			for _, field := range tt.wantChanged {
+				if _, changed := last.Changes[field]; !changed {
+					t.Errorf("last event does not change %s: %v", field, last.Changes)
+				}
+			}
+			if len(events) > 1 && len(last.Changes) != len(tt.wantChanged) {
+				t.Errorf("last event changes %d fields, want %d", len(last.Changes), len(tt.wantChanged))
+			}
+		})
+	}
+}
+
+func TestEventSourcedRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		step      todoStep
+		wantTitle string
+	}{
+		{name: "update undone", step: renameTodo(1, "changed"), wantTitle: "kept"},
+		{name: "snapshot undone", step: func(ctx context.Context, repo port.TodoRepositoryPort) error {
+			for _, title := range []string{"b", "c", "d"} {
+				if err := renameTodo(1, title)(ctx, repo); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, wantTitle: "kept"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewEventSourcedTodoRepository(2)
+			tx := NewTxManager(repo)
+			if err := createTodo("kept")(ctx, repo); err != nil {
+				t.Fatal(err)
+			}
+
+			err := tx.WithinTx(ctx, func(ctx context.Context) error {
+				if err := tt.step(ctx, repo); err != nil {
+					t.Fatal(err)
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("WithinTx() error = %v", err)
+			}
+
+			todo, err := repo.GetByID(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			events, err := repo.ListEvents(ctx, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if todo.Title != tt.wantTitle || len(events) != 1 {
+				t.Errorf("after rollback: title %q with %d events, want %q with 1", todo.Title, len(events), tt.wantTitle)
+			}
+			if len(repo.snapshots[1]) != 0 {
+				t.Errorf("snapshots after rollback = %d, want 0", len(repo.snapshots[1]))
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// listMemberKey identifies a user's membership of a list
+type listMemberKey struct {
+	listID int
+	userID int
+}
+
+// ListShareRepository implements the ListShareRepositoryPort interface
+type ListShareRepository struct {
+	members          map[listMemberKey]*model.ListMember
+	invitations      map[int]*model.ListInvitation
+	mu               txMutex
+	nextInvitationID int
+}
+
+// NewListShareRepository creates a new ListShareRepository
+func NewListShareRepository() *ListShareRepository {
+	return &ListShareRepository{
+		members:          make(map[listMemberKey]*model.ListMember),
+		invitations:      make(map[int]*model.ListInvitation),
+		nextInvitationID: 1,
+	}
+}
+
+// SaveMember creates or replaces a list membership
+func (r *ListShareRepository) SaveMember(ctx context.Context, member *model.ListMember) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	key := listMemberKey{listID: member.ListID, userID: member.UserID}
+	stored := *member
+	saveEntry(ctx, &r.mu, r.members, key, nil)
+	r.members[key] = &stored
+	return nil
+}
+
+// GetMember retrieves a user's membership of a list
+func (r *ListShareRepository) GetMember(ctx context.Context, listID, userID int) (*model.ListMember, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	member, exists := r.members[listMemberKey{listID: listID, userID: userID}]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *member
+	return &found, nil
+}
+
+// ListMembers retrieves the members of a list, ordered by user ID
+func (r *ListShareRepository) ListMembers(ctx context.Context, listID int) ([]*model.ListMember, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	members := make([]*model.ListMember, 0)
+	for key, member := range r.members {
+		if key.listID == listID {
+			found := *member
+			members = append(members, &found)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return members, nil
+}
+
+// ListMemberships retrieves every list membership of a user, ordered by list ID
+func (r *ListShareRepository) ListMemberships(ctx context.Context, userID int) ([]*model.ListMember, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	members := make([]*model.ListMember, 0)
+	for key, member := range r.members {
+		if key.userID == userID {
+			found := *member
+			members = append(members, &found)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].ListID < members[j].ListID })
+	return members, nil
+}
+
+// DeleteMember removes a user's membership of a list
+func (r *ListShareRepository) DeleteMember(ctx context.Context, listID, userID int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	key := listMemberKey{listID: listID, userID: userID}
+	if _, exists := r.members[key]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.members, key, nil)
+	delete(r.members, key)
+	return nil
+}
+
+// CreateInvitation creates a new invitation
+func (r *ListShareRepository) CreateInvitation(ctx context.Context, invitation *model.ListInvitation) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextInvitationID)
+	invitation.ID = r.nextInvitationID
+	r.nextInvitationID++
+	stored := *invitation
+	saveEntry(ctx, &r.mu, r.invitations, invitation.ID, nil)
+	r.invitations[invitation.ID] = &stored
+	return nil
+}
+
+// GetInvitation retrieves an invitation by ID
+func (r *ListShareRepository) GetInvitation(ctx context.Context, id int) (*model.ListInvitation, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	invitation, exists := r.invitations[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *invitation
+	return &found, nil
+}
+
+// ListInvitations retrieves the invitations addressed to a user, ordered by ID
+func (r *ListShareRepository) ListInvitations(ctx context.Context, inviteeID int, status model.InvitationStatus) ([]*model.ListInvitation, error) {
+	return r.filterInvitations(ctx, func(invitation *model.ListInvitation) bool {
+		return invitation.InviteeID == inviteeID && (status == "" || invitation.Status == status)
+	})
+}
+
+// ListInvitationsForList retrieves the invitations to a list, ordered by ID
+func (r *ListShareRepository) ListInvitationsForList(ctx context.Context, listID int) ([]*model.ListInvitation, error) {
+	return r.filterInvitations(ctx, func(invitation *model.ListInvitation) bool {
+		return invitation.ListID == listID
+	})
+}
+
+// filterInvitations returns copies of the invitations matching the predicate, ordered by ID
+func (r *ListShareRepository) filterInvitations(ctx context.Context, match func(*model.ListInvitation) bool) ([]*model.ListInvitation, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	invitations := make([]*model.ListInvitation, 0)
+	for _, invitation := range r.invitations {
+		if match(invitation) {
+			found := *invitation
+			invitations = append(invitations, &found)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool { return invitations[i].ID < invitations[j].ID })
+	return invitations, nil
+}
+
+// UpdateInvitation updates an existing invitation
+func (r *ListShareRepository) UpdateInvitation(ctx context.Context, invitation *model.ListInvitation) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.invitations[invitation.ID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	stored := *invitation
+	saveEntry(ctx, &r.mu, r.invitations, invitation.ID, nil)
+	r.invitations[invitation.ID] = &stored
+	return nil
+}
+
+// DeleteByList removes all members and invitations of a list
+func (r *ListShareRepository) DeleteByList(ctx context.Context, listID int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	for key := range r.members {
+		if key.listID == listID {
+			saveEntry(ctx, &r.mu, r.members, key, nil)
+			delete(r.members, key)
+		}
+	}
+	for id, invitation := range r.invitations {
+		if invitation.ListID == listID {
+			saveEntry(ctx, &r.mu, r.invitations, id, nil)
+			delete(r.invitations, id)
+		}
+	}
+	return nil
+}
+
+// txLock returns the repository's lock
+func (r *ListShareRepository) txLock() *txMutex {
+	return &r.mu
+}
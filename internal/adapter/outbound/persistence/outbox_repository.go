@@ -0,0 +1,123 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// OutboxRepository implements the OutboxRepositoryPort interface
+type OutboxRepository struct {
+	entries map[int]*model.OutboxEntry
+	mu      txMutex
+	nextID  int
+}
+
+// NewOutboxRepository creates a new OutboxRepository
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{
+		entries: make(map[int]*model.OutboxEntry),
+		nextID:  1,
+	}
+}
+
+// Append stores the entries in order, assigning their IDs
+func (r *OutboxRepository) Append(ctx context.Context, entries []*model.OutboxEntry) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	for _, entry := range entries {
+		entry.ID = r.nextID
+		r.nextID++
+		stored := *entry
+		saveEntry(ctx, &r.mu, r.entries, entry.ID, nil)
+		r.entries[entry.ID] = &stored
+	}
+	return nil
+}
+
+// ListPending retrieves up to limit unpublished entries, oldest first
+func (r *OutboxRepository) ListPending(ctx context.Context, limit int) ([]*model.OutboxEntry, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	entries := make([]*model.OutboxEntry, 0)
+	for _, entry := range r.entries {
+		if entry.PublishedAt == nil {
+			found := *entry
+			entries = append(entries, &found)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// MarkPublished records that an entry was handed to the publisher
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int, publishedAt time.Time) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	entry, exists := r.entries[id]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	saveEntry(ctx, &r.mu, r.entries, id, copyOf)
+	entry.Attempts++
+	entry.LastError = ""
+	entry.PublishedAt = &publishedAt
+	return nil
+}
+
+// MarkFailed records a failed attempt and when to try again
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int, lastError string, nextAttemptAt time.Time) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	entry, exists := r.entries[id]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	saveEntry(ctx, &r.mu, r.entries, id, copyOf)
+	entry.Attempts++
+	entry.LastError = lastError
+	entry.NextAttemptAt = nextAttemptAt
+	return nil
+}
+
+// DeletePublished removes entries published before the cutoff
+func (r *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int, error) {
+	if err := r.mu.Lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.mu.Unlock(ctx)
+
+	deleted := 0
+	for id, entry := range r.entries {
+		if entry.PublishedAt != nil && entry.PublishedAt.Before(before) {
+			saveEntry(ctx, &r.mu, r.entries, id, nil)
+			delete(r.entries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// txLock returns the repository's lock
+func (r *OutboxRepository) txLock() *txMutex {
+	return &r.mu
+}
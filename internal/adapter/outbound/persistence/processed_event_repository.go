@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+	"time"
+)
+
+// processedEventKey identifies an event handled by a consumer
+type processedEventKey struct {
+	consumer string
+	eventID  string
+}
+
+// ProcessedEventRepository implements the ProcessedEventRepositoryPort interface
+type ProcessedEventRepository struct {
+	// processed maps each handled event to when it was handled
+	processed map[processedEventKey]time.Time
+	mu        txMutex
+}
+
+// NewProcessedEventRepository creates a new ProcessedEventRepository
+func NewProcessedEventRepository() *ProcessedEventRepository {
+	return &ProcessedEventRepository{
+		processed: make(map[processedEventKey]time.Time),
+	}
+}
+
+// IsProcessed reports whether the consumer handled the event
+func (r *ProcessedEventRepository) IsProcessed(ctx context.Context, consumer, eventID string) (bool, error) {
+	if err := r.mu.Lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.mu.Unlock(ctx)
+
+	_, exists := r.processed[processedEventKey{consumer: consumer, eventID: eventID}]
+	return exists, nil
+}
+
+// MarkProcessed records that the consumer handled the event, reporting false if it already had
+func (r *ProcessedEventRepository) MarkProcessed(ctx context.Context, consumer, eventID string, processedAt time.Time) (bool, error) {
+	if err := r.mu.Lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.mu.Unlock(ctx)
+
+	key := processedEventKey{consumer: consumer, eventID: eventID}
+	if _, exists := r.processed[key]; exists {
+		return false, nil
+	}
+	saveEntry(ctx, &r.mu, r.processed, key, nil)
+	r.processed[key] = processedAt
+	return true, nil
+}
+
+// DeleteProcessedBefore forgets the events handled before the cutoff
+func (r *ProcessedEventRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int, error) {
+	if err := r.mu.Lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.mu.Unlock(ctx)
+
+	deleted := 0
+	for key, processedAt := range r.processed {
+		if processedAt.Before(before) {
+			saveEntry(ctx, &r.mu, r.processed, key, nil)
+			delete(r.processed, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// txLock returns the repository's lock
+func (r *ProcessedEventRepository) txLock() *txMutex {
+	return &r.mu
+}
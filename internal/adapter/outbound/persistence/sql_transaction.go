@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// SQLConn runs statements: a *sql.DB, or a *sql.Tx within a transaction
+type SQLConn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqlTxContextKey is the context key under which the current SQL transaction is stored
+type sqlTxContextKey struct{}
+
+// sqlTx is a transaction of the SQLTxManager
+type sqlTx struct {
+	manager  *SQLTxManager
+	tx       *sql.Tx
+	active   atomic.Bool
+	onCommit []func(ctx context.Context) error
+}
+
+// SQLTxManager implements the TransactionManagerPort interface for SQL
+// repositories sharing one database. The transaction's *sql.Tx travels in the
+// context, and repositories run their statements on Conn(ctx) so they take
+// part in it. Unlike the TxManager, fn runs exactly once: a transaction the
+// database aborts, for example on a serialization failure, returns its error.
+// The context given to the transaction's function must not be shared with
+// other goroutines.
+type SQLTxManager struct {
+	db   *sql.DB
+	opts *sql.TxOptions
+}
+
+// NewSQLTxManager creates a SQLTxManager beginning transactions on db with the
+// given options; nil uses the driver's defaults
+func NewSQLTxManager(db *sql.DB, opts *sql.TxOptions) *SQLTxManager {
+	return &SQLTxManager{db: db, opts: opts}
+}
+
+// current returns the active transaction of the manager in the context
+func (m *SQLTxManager) current(ctx context.Context) *sqlTx {
+	tx, ok := ctx.Value(sqlTxContextKey{}).(*sqlTx)
+	if !ok || tx.manager != m || !tx.active.Load() {
+		return nil
+	}
+	return tx
+}
+
+// Conn returns the transaction in the context, or the database outside one
+func (m *SQLTxManager) Conn(ctx context.Context) SQLConn {
+	if tx := m.current(ctx); tx != nil {
+		return tx.tx
+	}
+	return m.db
+}
+
+// WithinTx runs fn in a transaction, committing if it returns nil and rolling
+// back if it returns an error or panics. A call made within a transaction of
+// the same manager joins it.
+func (m *SQLTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.current(ctx) != nil {
+		return fn(ctx)
+	}
+
+	begun, err := m.db.BeginTx(ctx, m.opts)
+	if err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+	tx := &sqlTx{manager: m, tx: begun}
+	tx.active.Store(true)
+	txCtx := context.WithValue(ctx, sqlTxContextKey{}, tx)
+
+	committed := false
+	defer func() {
+		tx.active.Store(false)
+		if committed {
+			return
+		}
+		if err := begun.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("Failed to roll back transaction: %v", err)
+		}
+	}()
+
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+	for _, commit := range tx.onCommit {
+		if err := commit(txCtx); err != nil {
+			return err
+		}
+	}
+	if err := begun.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// BeforeCommit runs fn as the last step of the transaction in the context,
+// after its function succeeded and before it commits; an error from fn rolls
+// the transaction back. Outside a transaction of the manager fn runs right away.
+func (m *SQLTxManager) BeforeCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx := m.current(ctx); tx != nil {
+		tx.onCommit = append(tx.onCommit, fn)
+		return nil
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,211 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// recordingConnector is a database/sql driver that runs no SQL but records
+// the statements it is given and the transactions they ran in
+type recordingConnector struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (c *recordingConnector) record(entry string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.log = append(c.log, entry)
+}
+
+func (c *recordingConnector) entries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.log)
+}
+
+func (c *recordingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &recordingConn{connector: c}, nil
+}
+
+func (c *recordingConnector) Driver() driver.Driver { return nil }
+
+// recordingConn is a connection of the recordingConnector
+type recordingConn struct {
+	connector *recordingConnector
+	inTx      bool
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.inTx = true
+	c.connector.record("begin")
+	return c, nil
+}
+
+func (c *recordingConn) Commit() error {
+	c.inTx = false
+	c.connector.record("commit")
+	return nil
+}
+
+func (c *recordingConn) Rollback() error {
+	c.inTx = false
+	c.connector.record("rollback")
+	return nil
+}
+
+func (c *recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.inTx {
+		query += " in tx"
+	}
+	c.connector.record(query)
+	return driver.RowsAffected(1), nil
+}
+
+func newRecordingTxManager(t *testing.T) (*SQLTxManager, *recordingConnector) {
+	t.Helper()
+	connector := &recordingConnector{}
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return NewSQLTxManager(db, nil), connector
+}
+
+// exec runs a statement on the manager's connection for the context
+func exec(ctx context.Context, m *SQLTxManager, query string) error {
+	_, err := m.Conn(ctx).ExecContext(ctx, query)
+	return err
+}
+
+func TestSQLTxManager(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context, m *SQLTxManager) error
+		wantErr error
+		want    []string
+	}{
+		{
+			name: "commit",
+			fn: func(ctx context.Context, m *SQLTxManager) error {
+				return exec(ctx, m, "insert")
+			},
+			want: []string{"begin", "insert in tx", "commit"},
+		},
+		{
+			name: "rollback on error",
+			fn: func(ctx context.Context, m *SQLTxManager) error {
+				if err := exec(ctx, m, "insert"); err != nil {
+					return err
+				}
+				return errAbort
+			},
+			wantErr: errAbort,
+			want:    []string{"begin", "insert in tx", "rollback"},
+		},
+		{
+			name: "nested call joins",
+			fn: func(ctx context.Context, m *SQLTxManager) error {
+				return m.WithinTx(ctx, func(ctx context.Context) error {
+					return exec(ctx, m, "insert")
+				})
+			},
+			want: []string{"begin", "insert in tx", "commit"},
+		},
+		{
+			name: "before commit runs last",
+			fn: func(ctx context.Context, m *SQLTxManager) error {
+				if err := m.BeforeCommit(ctx, func(ctx context.Context) error { return exec(ctx, m, "outbox") }); err != nil {
+					return err
+				}
+				return exec(ctx, m, "insert")
+			},
+			want: []string{"begin", "insert in tx", "outbox in tx", "commit"},
+		},
+		{
+			name: "before commit failure rolls back",
+			fn: func(ctx context.Context, m *SQLTxManager) error {
+				if err := m.BeforeCommit(ctx, func(ctx context.Context) error { return errAbort }); err != nil {
+					return err
+				}
+				return exec(ctx, m, "insert")
+			},
+			wantErr: errAbort,
+			want:    []string{"begin", "insert in tx", "rollback"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, connector := newRecordingTxManager(t)
+			err := m.WithinTx(context.Background(), func(ctx context.Context) error { return tt.fn(ctx, m) })
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("WithinTx() error = %v, want %v", err, tt.wantErr)
+			}
+			if got := connector.entries(); !slices.Equal(got, tt.want) {
+				t.Errorf("statements = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLTxManagerRollbackOnPanic(t *testing.T) {
+	m, connector := newRecordingTxManager(t)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		_ = m.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := exec(ctx, m, "insert"); err != nil {
+				t.Fatal(err)
+			}
+			panic("boom")
+		})
+	}()
+
+	if got, want := connector.entries(), []string{"begin", "insert in tx", "rollback"}; !slices.Equal(got, want) {
+		t.Errorf("statements = %v, want %v", got, want)
+	}
+}
+
+func TestSQLTxManagerOutsideTransactions(t *testing.T) {
+	m, connector := newRecordingTxManager(t)
+	ctx := context.Background()
+
+	var leaked context.Context
+	if err := m.WithinTx(ctx, func(ctx context.Context) error {
+		leaked = ctx
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A context that outlived its transaction, and one without any, use the database
+	if err := exec(leaked, m, "late insert"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.BeforeCommit(ctx, func(ctx context.Context) error { return exec(ctx, m, "insert") }); err != nil {
+		t.Fatal(err)
+	}
+	// Another manager's transaction is not joined
+	other, _ := newRecordingTxManager(t)
+	if err := other.WithinTx(ctx, func(ctx context.Context) error { return exec(ctx, m, "foreign insert") }); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"begin", "commit", "late insert", "insert", "foreign insert"}
+	if got := connector.entries(); !slices.Equal(got, want) {
+		t.Errorf("statements = %v, want %v", got, want)
+	}
+}
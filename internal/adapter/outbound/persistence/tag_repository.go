@@ -0,0 +1,188 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// tagNameKey identifies a tag name within an owner's namespace
+type tagNameKey struct {
+	ownerID int
+	name    string
+}
+
+// newTagNameKey builds the case-insensitive index key for a tag name
+func newTagNameKey(ownerID int, name string) tagNameKey {
+	return tagNameKey{ownerID: ownerID, name: strings.ToLower(name)}
+}
+
+// TagRepository implements the TagRepositoryPort interface
+type TagRepository struct {
+	tags      map[int]*model.Tag
+	nameIndex map[tagNameKey]int
+	mu        txMutex
+	nextID    int
+}
+
+// NewTagRepository creates a new TagRepository
+func NewTagRepository() *TagRepository {
+	return &TagRepository{
+		tags:      make(map[int]*model.Tag),
+		nameIndex: make(map[tagNameKey]int),
+		nextID:    1,
+	}
+}
+
+// Create creates a new tag
+func (r *TagRepository) Create(ctx context.Context, tag *model.Tag) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	key := newTagNameKey(tag.OwnerID, tag.Name)
+	if _, exists := r.nameIndex[key]; exists {
+		return domain.ErrDuplicate
+	}
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	tag.ID = r.nextID
+	r.nextID++
+	stored := *tag
+	saveEntry(ctx, &r.mu, r.tags, tag.ID, nil)
+	saveEntry(ctx, &r.mu, r.nameIndex, key, nil)
+	r.tags[tag.ID] = &stored
+	r.nameIndex[key] = tag.ID
+	return nil
+}
+
+// GetByID retrieves a tag by ID
+func (r *TagRepository) GetByID(ctx context.Context, id int) (*model.Tag, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	tag, exists := r.tags[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *tag
+	return &found, nil
+}
+
+// GetByName retrieves a tag by name within an owner's namespace
+func (r *TagRepository) GetByName(ctx context.Context, ownerID int, name string) (*model.Tag, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	id, exists := r.nameIndex[newTagNameKey(ownerID, name)]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *r.tags[id]
+	return &found, nil
+}
+
+// ListByNames retrieves the tags with any of the given names, across all owners
+func (r *TagRepository) ListByNames(ctx context.Context, names []string) ([]*model.Tag, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[strings.ToLower(name)] = true
+	}
+
+	tags := make([]*model.Tag, 0)
+	for _, tag := range r.tags {
+		if wanted[strings.ToLower(tag.Name)] {
+			found := *tag
+			tags = append(tags, &found)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags, nil
+}
+
+// List retrieves all tags, optionally restricted to one owner
+func (r *TagRepository) List(ctx context.Context, ownerID *int) ([]*model.Tag, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	tags := make([]*model.Tag, 0, len(r.tags))
+	for _, tag := range r.tags {
+		if ownerID != nil && tag.OwnerID != *ownerID {
+			continue
+		}
+		found := *tag
+		tags = append(tags, &found)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags, nil
+}
+
+// Update updates an existing tag
+func (r *TagRepository) Update(ctx context.Context, tag *model.Tag) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	existing, exists := r.tags[tag.ID]
+	if !exists {
+		return domain.ErrNotFound
+	}
+
+	oldKey := newTagNameKey(existing.OwnerID, existing.Name)
+	newKey := newTagNameKey(tag.OwnerID, tag.Name)
+	if oldKey != newKey {
+		if _, taken := r.nameIndex[newKey]; taken {
+			return domain.ErrDuplicate
+		}
+		saveEntry(ctx, &r.mu, r.nameIndex, oldKey, nil)
+		saveEntry(ctx, &r.mu, r.nameIndex, newKey, nil)
+		delete(r.nameIndex, oldKey)
+		r.nameIndex[newKey] = tag.ID
+	}
+
+	stored := *tag
+	saveEntry(ctx, &r.mu, r.tags, tag.ID, nil)
+	r.tags[tag.ID] = &stored
+	return nil
+}
+
+// Delete deletes a tag
+func (r *TagRepository) Delete(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	tag, exists := r.tags[id]
+	if !exists {
+		return domain.ErrNotFound
+	}
+
+	key := newTagNameKey(tag.OwnerID, tag.Name)
+	saveEntry(ctx, &r.mu, r.tags, id, nil)
+	saveEntry(ctx, &r.mu, r.nameIndex, key, nil)
+	delete(r.tags, id)
+	delete(r.nameIndex, key)
+	return nil
+}
+
+// txLock returns the repository's lock
+func (r *TagRepository) txLock() *txMutex {
+	return &r.mu
+}
@@ -0,0 +1,115 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoListRepository implements the TodoListRepositoryPort interface
+type TodoListRepository struct {
+	lists  map[int]*model.TodoList
+	mu     txMutex
+	nextID int
+}
+
+// NewTodoListRepository creates a new TodoListRepository
+func NewTodoListRepository() *TodoListRepository {
+	return &TodoListRepository{
+		lists:  make(map[int]*model.TodoList),
+		nextID: 1,
+	}
+}
+
+// Create creates a new list
+func (r *TodoListRepository) Create(ctx context.Context, list *model.TodoList) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	list.ID = r.nextID
+	r.nextID++
+	stored := *list
+	saveEntry(ctx, &r.mu, r.lists, list.ID, nil)
+	r.lists[list.ID] = &stored
+	return nil
+}
+
+// GetByID retrieves a list by ID
+func (r *TodoListRepository) GetByID(ctx context.Context, id int) (*model.TodoList, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	list, exists := r.lists[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *list
+	return &found, nil
+}
+
+// List retrieves all lists, optionally restricted to one owner, ordered by ID
+func (r *TodoListRepository) List(ctx context.Context, ownerID *int, includeArchived bool) ([]*model.TodoList, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	lists := make([]*model.TodoList, 0, len(r.lists))
+	for _, list := range r.lists {
+		if ownerID != nil && list.OwnerID != *ownerID {
+			continue
+		}
+		if list.Archived && !includeArchived {
+			continue
+		}
+		found := *list
+		lists = append(lists, &found)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].ID < lists[j].ID })
+	return lists, nil
+}
+
+// Update updates an existing list
+func (r *TodoListRepository) Update(ctx context.Context, list *model.TodoList) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.lists[list.ID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	stored := *list
+	saveEntry(ctx, &r.mu, r.lists, list.ID, nil)
+	r.lists[list.ID] = &stored
+	return nil
+}
+
+// Delete deletes a list
+func (r *TodoListRepository) Delete(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.lists[id]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.lists, id, nil)
+	delete(r.lists, id)
+	return nil
+}
+
+// txLock returns the repository's lock
+func (r *TodoListRepository) txLock() *txMutex {
+	return &r.mu
+}
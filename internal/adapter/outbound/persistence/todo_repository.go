@@ -2,84 +2,274 @@ package persistence
 
 import (
 	"context"
-	"sync"
+	"sort"
+	"time"
 
 	"go-boilerplate/internal/domain"
 	"go-boilerplate/internal/domain/model"
 )
 
-// TodoRepository implements the TodoRepositoryPort interface
+// TodoRepository implements the TodoRepositoryPort interface.
+// Todos are stored as copies so callers cannot modify stored state without Update.
 type TodoRepository struct {
-	todos  map[int]*model.Todo
-	mu     sync.RWMutex
-	nextID int
+	todos       map[int]*model.Todo
+	transitions map[int][]*model.TodoTransition
+	changes     map[int][]*model.TodoChange
+	mu          txMutex
+	nextID      int
 }
 
 // NewTodoRepository creates a new TodoRepository
 func NewTodoRepository() *TodoRepository {
 	return &TodoRepository{
-		todos:  make(map[int]*model.Todo),
-		nextID: 1,
+		todos:       make(map[int]*model.Todo),
+		transitions: make(map[int][]*model.TodoTransition),
+		changes:     make(map[int][]*model.TodoChange),
+		nextID:      1,
 	}
 }
 
+// isLive reports whether a todo is stored and not in the trash.
+// The caller holds the lock.
+func (r *TodoRepository) isLive(id int) bool {
+	todo, exists := r.todos[id]
+	return exists && todo.DeletedAt == nil
+}
+
 // Create creates a new todo
 func (r *TodoRepository) Create(ctx context.Context, todo *model.Todo) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
 
+	saveValue(ctx, &r.mu, &r.nextID)
 	todo.ID = r.nextID
 	r.nextID++
-	r.todos[todo.ID] = todo
+	saveEntry(ctx, &r.mu, r.todos, todo.ID, nil)
+	r.todos[todo.ID] = todo.Clone()
 	return nil
 }
 
 // GetByID retrieves a todo by ID
 func (r *TodoRepository) GetByID(ctx context.Context, id int) (*model.Todo, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
 
-	todo, exists := r.todos[id]
-	if !exists {
+	if !r.isLive(id) {
 		return nil, domain.ErrNotFound
 	}
-	return todo, nil
+	return r.todos[id].Clone(), nil
 }
 
-// List retrieves all todos
-func (r *TodoRepository) List(ctx context.Context) ([]*model.Todo, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// List retrieves all todos matching the filter, ordered by ID
+func (r *TodoRepository) List(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
 
 	todos := make([]*model.Todo, 0, len(r.todos))
 	for _, todo := range r.todos {
-		todos = append(todos, todo)
+		if filter.Matches(todo) {
+			todos = append(todos, todo.Clone())
+		}
 	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
 	return todos, nil
 }
 
 // Update updates an existing todo
 func (r *TodoRepository) Update(ctx context.Context, todo *model.Todo) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
 
-	if _, exists := r.todos[todo.ID]; !exists {
+	if !r.isLive(todo.ID) {
 		return domain.ErrNotFound
 	}
 
-	r.todos[todo.ID] = todo
+	saveEntry(ctx, &r.mu, r.todos, todo.ID, nil)
+	r.todos[todo.ID] = todo.Clone()
 	return nil
 }
 
-// Delete deletes a todo
+// Delete permanently deletes a todo and its history
 func (r *TodoRepository) Delete(ctx context.Context, id int) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
 
 	if _, exists := r.todos[id]; !exists {
 		return domain.ErrNotFound
 	}
 
+	saveEntry(ctx, &r.mu, r.todos, id, nil)
+	saveEntry(ctx, &r.mu, r.transitions, id, nil)
+	saveEntry(ctx, &r.mu, r.changes, id, nil)
 	delete(r.todos, id)
+	delete(r.transitions, id)
+	delete(r.changes, id)
+	return nil
+}
+
+// SoftDelete moves a live todo to the trash
+func (r *TodoRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if !r.isLive(id) {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.todos, id, (*model.Todo).Clone)
+	r.todos[id].DeletedAt = &deletedAt
+	return nil
+}
+
+// Restore moves a trashed todo out of the trash
+func (r *TodoRepository) Restore(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	todo, exists := r.todos[id]
+	if !exists || todo.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.todos, id, (*model.Todo).Clone)
+	todo.DeletedAt = nil
+	return nil
+}
+
+// AppendTransition records a status transition of a todo
+func (r *TodoRepository) AppendTransition(ctx context.Context, transition *model.TodoTransition) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if !r.isLive(transition.TodoID) {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.transitions, transition.TodoID, nil)
+	r.transitions[transition.TodoID] = append(r.transitions[transition.TodoID], transition)
+	return nil
+}
+
+// ListTransitions retrieves the status transitions of a todo, oldest first
+func (r *TodoRepository) ListTransitions(ctx context.Context, todoID int) ([]*model.TodoTransition, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	if !r.isLive(todoID) {
+		return nil, domain.ErrNotFound
+	}
+
+	transitions := make([]*model.TodoTransition, len(r.transitions[todoID]))
+	copy(transitions, r.transitions[todoID])
+	return transitions, nil
+}
+
+// AppendChanges records field changes of todos
+func (r *TodoRepository) AppendChanges(ctx context.Context, changes []*model.TodoChange) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	for _, change := range changes {
+		if !r.isLive(change.TodoID) {
+			return domain.ErrNotFound
+		}
+	}
+	for _, change := range changes {
+		stored := *change
+		saveEntry(ctx, &r.mu, r.changes, change.TodoID, nil)
+		r.changes[change.TodoID] = append(r.changes[change.TodoID], &stored)
+	}
+	return nil
+}
+
+// ListChanges retrieves the field changes of a todo, oldest first
+func (r *TodoRepository) ListChanges(ctx context.Context, todoID int) ([]*model.TodoChange, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	if !r.isLive(todoID) {
+		return nil, domain.ErrNotFound
+	}
+
+	changes := make([]*model.TodoChange, len(r.changes[todoID]))
+	for i, change := range r.changes[todoID] {
+		found := *change
+		changes[i] = &found
+	}
+	return changes, nil
+}
+
+// ReplaceTag swaps a tag for another one on every todo carrying it
+func (r *TodoRepository) ReplaceTag(ctx context.Context, oldTagID, newTagID int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	for _, todo := range r.todos {
+		if !todo.HasTag(oldTagID) {
+			continue
+		}
+		tagIDs := make([]int, 0, len(todo.TagIDs))
+		for _, id := range todo.TagIDs {
+			if id != oldTagID && id != newTagID {
+				tagIDs = append(tagIDs, id)
+			}
+		}
+		tagIDs = append(tagIDs, newTagID)
+		sort.Ints(tagIDs)
+		saveEntry(ctx, &r.mu, r.todos, todo.ID, (*model.Todo).Clone)
+		todo.TagIDs = tagIDs
+	}
 	return nil
 }
+
+// RemoveTag detaches a tag from every todo carrying it
+func (r *TodoRepository) RemoveTag(ctx context.Context, tagID int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	for _, todo := range r.todos {
+		if !todo.HasTag(tagID) {
+			continue
+		}
+		tagIDs := make([]int, 0, len(todo.TagIDs))
+		for _, id := range todo.TagIDs {
+			if id != tagID {
+				tagIDs = append(tagIDs, id)
+			}
+		}
+		saveEntry(ctx, &r.mu, r.todos, todo.ID, (*model.Todo).Clone)
+		todo.TagIDs = tagIDs
+	}
+	return nil
+}
+
+// txLock returns the repository's lock
+func (r *TodoRepository) txLock() *txMutex {
+	return &r.mu
+}
@@ -0,0 +1,260 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// txMutex is the lock of an in-memory repository. A transaction takes the
+// lock the first time it touches the repository and holds it until it ends;
+// calls made with the transaction's context then skip locking, while
+// everyone else waits. Locking fails only within a transaction, with
+// errLockConflict, and the repository returns that error to its caller.
+type txMutex struct {
+	mu sync.RWMutex
+}
+
+// tx returns the active transaction in the context if it spans the lock
+func (m *txMutex) tx(ctx context.Context) *memoryTx {
+	tx, ok := ctx.Value(txContextKey{}).(*memoryTx)
+	if !ok || !tx.active.Load() {
+		return nil
+	}
+	if _, spans := tx.manager.ranks[m]; !spans {
+		return nil
+	}
+	return tx
+}
+
+// Lock locks for writing, or joins the lock to the caller's transaction
+func (m *txMutex) Lock(ctx context.Context) error {
+	if tx := m.tx(ctx); tx != nil {
+		return tx.acquire(m)
+	}
+	m.mu.Lock()
+	return nil
+}
+
+// Unlock undoes Lock. A lock joined to a transaction is released when it ends.
+func (m *txMutex) Unlock(ctx context.Context) {
+	if m.tx(ctx) == nil {
+		m.mu.Unlock()
+	}
+}
+
+// RLock locks for reading, or joins the lock to the caller's transaction.
+// Transactions lock exclusively, so they never need to upgrade a lock.
+func (m *txMutex) RLock(ctx context.Context) error {
+	if tx := m.tx(ctx); tx != nil {
+		return tx.acquire(m)
+	}
+	m.mu.RLock()
+	return nil
+}
+
+// RUnlock undoes RLock
+func (m *txMutex) RUnlock(ctx context.Context) {
+	if m.tx(ctx) == nil {
+		m.mu.RUnlock()
+	}
+}
+
+// onRollback registers a function undoing a change the caller is about to
+// make, run if the transaction in the context rolls back. Outside a
+// transaction it does nothing. The caller holds the lock.
+func (m *txMutex) onRollback(ctx context.Context, undo func()) {
+	if tx := m.tx(ctx); tx != nil {
+		tx.undo = append(tx.undo, undo)
+	}
+}
+
+// saveEntry arranges for m[key] to get back its current value, or to be
+// removed if it has none, when the transaction in the context rolls back.
+// Stored records the caller is about to modify in place are copied with
+// clone; pass nil for values that are only ever replaced. The caller holds
+// the lock.
+func saveEntry[K comparable, V any](ctx context.Context, mu *txMutex, m map[K]V, key K, clone func(V) V) {
+	if mu.tx(ctx) == nil {
+		return
+	}
+	old, existed := m[key]
+	if existed && clone != nil {
+		old = clone(old)
+	}
+	mu.onRollback(ctx, func() {
+		if existed {
+			m[key] = old
+		} else {
+			delete(m, key)
+		}
+	})
+}
+
+// copyOf returns a shallow copy of a stored record
+func copyOf[T any](v *T) *T {
+	c := *v
+	return &c
+}
+
+// saveValue arranges for *p to get back its current value when the
+// transaction in the context rolls back. The caller holds the lock.
+func saveValue[V any](ctx context.Context, mu *txMutex, p *V) {
+	if mu.tx(ctx) == nil {
+		return
+	}
+	old := *p
+	mu.onRollback(ctx, func() { *p = old })
+}
+
+// Transactional is an in-memory repository that can take part in transactions
+type Transactional interface {
+	// txLock returns the repository's lock
+	txLock() *txMutex
+}
+
+// txContextKey is the context key under which the current transaction is stored
+type txContextKey struct{}
+
+// errLockConflict aborts an attempt of a transaction that needs a lock ranked
+// below one it already holds while another transaction holds it. Waiting
+// could deadlock, so the attempt is rolled back and retried with the lock
+// taken up front.
+var errLockConflict = errors.New("transaction lock conflict")
+
+// memoryTx is a transaction of the TxManager
+type memoryTx struct {
+	manager *TxManager
+	active  atomic.Bool
+	// held lists the locks the transaction holds, in the order taken
+	held    []*txMutex
+	maxRank int
+	// undo lists the functions reverting the transaction's changes, oldest first
+	undo     []func()
+	onCommit []func(ctx context.Context) error
+	// conflict is the lock the attempt could not take, voiding it
+	conflict *txMutex
+}
+
+// acquire takes the lock for the transaction unless it already holds it.
+// Locks are taken in rank order; a lock ranked below one already held is
+// only taken if it is free, and otherwise the attempt fails with
+// errLockConflict. Once an attempt failed every further lock fails too.
+func (tx *memoryTx) acquire(mu *txMutex) error {
+	if tx.conflict != nil {
+		return errLockConflict
+	}
+	if slices.Contains(tx.held, mu) {
+		return nil
+	}
+	rank := tx.manager.ranks[mu]
+	if rank > tx.maxRank {
+		mu.mu.Lock()
+	} else if !mu.mu.TryLock() {
+		tx.conflict = mu
+		return errLockConflict
+	}
+	tx.held = append(tx.held, mu)
+	tx.maxRank = max(tx.maxRank, rank)
+	return nil
+}
+
+// TxManager implements the TransactionManagerPort interface for the in-memory
+// repositories. A transaction locks only the repositories it touches, holding
+// their locks until it ends, and records how to undo each change it makes so
+// a rollback costs no more than the changes themselves. The context given to
+// the transaction's function must not be shared with other goroutines.
+type TxManager struct {
+	// ranks orders the locks of the spanned repositories
+	ranks map[*txMutex]int
+}
+
+// NewTxManager creates a TxManager spanning the given repositories
+func NewTxManager(resources ...Transactional) *TxManager {
+	m := &TxManager{ranks: make(map[*txMutex]int, len(resources))}
+	for i, resource := range resources {
+		m.ranks[resource.txLock()] = i
+	}
+	return m
+}
+
+// WithinTx runs fn in a transaction, committing if it returns nil and
+// rolling back if it returns an error or panics. A call made within a
+// transaction of the same manager joins it.
+//
+// fn may run more than once: an attempt that would have to wait for a lock
+// out of rank order is rolled back and retried with the locks it needed
+// taken up front, so fn must not have effects outside the transaction.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*memoryTx); ok && tx.manager == m && tx.active.Load() {
+		return fn(ctx)
+	}
+
+	var locks []*txMutex
+	for {
+		retry, err := m.attempt(ctx, locks, fn)
+		if retry == nil {
+			return err
+		}
+		locks = retry
+	}
+}
+
+// attempt runs fn once in a new transaction that first takes the given locks.
+// If the attempt ran into a lock conflict it is rolled back, and the locks to
+// take on the next attempt are returned.
+func (m *TxManager) attempt(ctx context.Context, locks []*txMutex, fn func(ctx context.Context) error) (retry []*txMutex, err error) {
+	tx := &memoryTx{manager: m, maxRank: -1}
+	slices.SortFunc(locks, func(a, b *txMutex) int { return m.ranks[a] - m.ranks[b] })
+	for _, mu := range locks {
+		// Taken in rank order, so acquiring waits instead of failing
+		_ = tx.acquire(mu)
+	}
+	tx.active.Store(true)
+	txCtx := context.WithValue(ctx, txContextKey{}, tx)
+
+	defer func() {
+		r := recover()
+		if tx.conflict != nil && r == nil {
+			// fn may have swallowed the conflict, but the attempt is void either way
+			retry = append(slices.Clone(tx.held), tx.conflict)
+			err = nil
+		}
+		if err != nil || r != nil || retry != nil {
+			for _, undo := range slices.Backward(tx.undo) {
+				undo()
+			}
+		}
+		tx.active.Store(false)
+		for _, mu := range slices.Backward(tx.held) {
+			mu.mu.Unlock()
+		}
+		if r != nil {
+			panic(r)
+		}
+	}()
+
+	if err = fn(txCtx); err != nil || tx.conflict != nil {
+		return nil, err
+	}
+	for _, commit := range tx.onCommit {
+		if err = commit(txCtx); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// BeforeCommit runs fn as the last step of the transaction in the context,
+// after its function succeeded and while its locks are still held; an error
+// from fn rolls the transaction back. Outside a transaction of the manager fn
+// runs right away.
+func (m *TxManager) BeforeCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*memoryTx); ok && tx.manager == m && tx.active.Load() {
+		tx.onCommit = append(tx.onCommit, fn)
+		return nil
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,531 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+var errAbort = errors.New("abort")
+
+// testRepos are the repositories a test transaction manager spans
+type testRepos struct {
+	todos  *TodoRepository
+	users  *UserRepository
+	tags   *TagRepository
+	outbox *OutboxRepository
+	tx     *TxManager
+}
+
+func newTestRepos() *testRepos {
+	r := &testRepos{
+		todos:  NewTodoRepository(),
+		users:  NewUserRepository(),
+		tags:   NewTagRepository(),
+		outbox: NewOutboxRepository(),
+	}
+	r.tx = NewTxManager(r.todos, r.users, r.tags, r.outbox)
+	return r
+}
+
+// state captures everything the repositories hold
+func (r *testRepos) state(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	todos, err := r.todos.List(ctx, model.TodoFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deleted, err := r.todos.List(ctx, model.TodoFilter{Deleted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, err := r.users.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tags, err := r.tags.List(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := r.outbox.ListPending(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	history, _ := r.users.GetUsernameHistory(ctx, "alice")
+	transitions, _ := r.todos.ListTransitions(ctx, 1)
+	return fmt.Sprintf("%+v|%+v|%+v|%+v|%+v|%+v|%+v|%d|%d|%d",
+		deref(todos), deref(deleted), deref(users), deref(tags), deref(pending), history, deref(transitions),
+		r.todos.nextID, r.users.nextID, r.outbox.nextID)
+}
+
+func deref[T any](items []*T) []T {
+	values := make([]T, len(items))
+	for i, item := range items {
+		values[i] = *item
+	}
+	return values
+}
+
+func seed(t *testing.T, r *testRepos) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	steps := []error{
+		r.users.Create(ctx, &model.User{Username: "alice", Email: "alice@example.com", Name: "Alice"}),
+		r.tags.Create(ctx, &model.Tag{OwnerID: 1, Name: "work"}),
+		r.tags.Create(ctx, &model.Tag{OwnerID: 1, Name: "home"}),
+		r.todos.Create(ctx, &model.Todo{OwnerID: 1, Title: "first", Status: model.TodoStatusOpen, TagIDs: []int{1}, CreatedAt: now}),
+		r.todos.Create(ctx, &model.Todo{OwnerID: 1, Title: "second", Status: model.TodoStatusOpen, TagIDs: []int{1, 2}, CreatedAt: now}),
+		r.outbox.Append(ctx, []*model.OutboxEntry{{Event: model.Event{Type: "todo.created"}}}),
+	}
+	for _, err := range steps {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestTxManagerRollback(t *testing.T) {
+	now := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context, r *testRepos) error
+	}{
+		{
+			name: "create",
+			fn: func(ctx context.Context, r *testRepos) error {
+				return r.todos.Create(ctx, &model.Todo{OwnerID: 1, Title: "third"})
+			},
+		},
+		{
+			name: "update",
+			fn: func(ctx context.Context, r *testRepos) error {
+				return r.todos.Update(ctx, &model.Todo{ID: 1, OwnerID: 1, Title: "changed"})
+			},
+		},
+		{
+			name: "soft delete and restore",
+			fn: func(ctx context.Context, r *testRepos) error {
+				if err := r.todos.SoftDelete(ctx, 1, now); err != nil {
+					return err
+				}
+				if err := r.todos.Restore(ctx, 1); err != nil {
+					return err
+				}
+				return r.todos.SoftDelete(ctx, 2, now)
+			},
+		},
+		{
+			name: "hard delete",
+			fn: func(ctx context.Context, r *testRepos) error {
+				return r.todos.Delete(ctx, 1)
+			},
+		},
+		{
+			name: "transition",
+			fn: func(ctx context.Context, r *testRepos) error {
+				return r.todos.AppendTransition(ctx, &model.TodoTransition{TodoID: 1, From: model.TodoStatusOpen, To: model.TodoStatusDone})
+			},
+		},
+		{
+			name: "merge tags",
+			fn: func(ctx context.Context, r *testRepos) error {
+				if err := r.todos.ReplaceTag(ctx, 1, 2); err != nil {
+					return err
+				}
+				return r.tags.Delete(ctx, 1)
+			},
+		},
+		{
+			name: "rename tag",
+			fn: func(ctx context.Context, r *testRepos) error {
+				return r.tags.Update(ctx, &model.Tag{ID: 1, OwnerID: 1, Name: "office"})
+			},
+		},
+		{
+			name: "rename user",
+			fn: func(ctx context.Context, r *testRepos) error {
+				_, err := r.users.Rename(ctx, 1, "alicia", &model.UsernameHistory{Username: "alice", UserID: 1, ChangedAt: now})
+				return err
+			},
+		},
+		{
+			name: "outbox",
+			fn: func(ctx context.Context, r *testRepos) error {
+				if err := r.outbox.MarkFailed(ctx, 1, "boom", now); err != nil {
+					return err
+				}
+				return r.outbox.Append(ctx, []*model.OutboxEntry{{Event: model.Event{Type: "todo.updated"}}, {Event: model.Event{Type: "todo.deleted"}}})
+			},
+		},
+		{
+			name: "nested",
+			fn: func(ctx context.Context, r *testRepos) error {
+				return r.tx.WithinTx(ctx, func(ctx context.Context) error {
+					return r.users.SoftDelete(ctx, 1, now)
+				})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepos()
+			seed(t, r)
+			before := r.state(t)
+
+			err := r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+				if err := tt.fn(ctx, r); err != nil {
+					t.Fatalf("operation failed: %v", err)
+				}
+				return errAbort
+			})
+			if !errors.Is(err, errAbort) {
+				t.Fatalf("WithinTx() error = %v, want %v", err, errAbort)
+			}
+			if after := r.state(t); after != before {
+				t.Errorf("state after rollback\n got: %s\nwant: %s", after, before)
+			}
+
+			// The same changes stick once committed
+			if err := r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+				return tt.fn(ctx, r)
+			}); err != nil {
+				t.Fatalf("WithinTx() error = %v", err)
+			}
+			if after := r.state(t); after == before {
+				t.Error("state unchanged after commit")
+			}
+		})
+	}
+}
+
+func TestTxManagerRollbackOnPanic(t *testing.T) {
+	r := newTestRepos()
+	seed(t, r)
+	before := r.state(t)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		_ = r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+			if err := r.todos.Delete(ctx, 1); err != nil {
+				t.Fatal(err)
+			}
+			panic("boom")
+		})
+	}()
+
+	if after := r.state(t); after != before {
+		t.Errorf("state after panic\n got: %s\nwant: %s", after, before)
+	}
+	// The locks were released
+	if _, err := r.todos.GetByID(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTxManagerLocksOnlyTouchedRepositories(t *testing.T) {
+	tests := []struct {
+		name    string
+		touch   func(ctx context.Context, r *testRepos) error
+		probe   func(ctx context.Context, r *testRepos) error
+		blocked bool
+	}{
+		{
+			name: "other repository",
+			touch: func(ctx context.Context, r *testRepos) error {
+				_, err := r.todos.GetByID(ctx, 1)
+				return err
+			},
+			probe: func(ctx context.Context, r *testRepos) error {
+				_, err := r.users.GetByID(ctx, 1)
+				return err
+			},
+		},
+		{
+			name: "touched repository",
+			touch: func(ctx context.Context, r *testRepos) error {
+				_, err := r.todos.GetByID(ctx, 1)
+				return err
+			},
+			probe: func(ctx context.Context, r *testRepos) error {
+				_, err := r.todos.GetByID(ctx, 2)
+				return err
+			},
+			blocked: true,
+		},
+		{
+			name: "repository outside the manager",
+			touch: func(ctx context.Context, r *testRepos) error {
+				return nil
+			},
+			probe: func(ctx context.Context, r *testRepos) error {
+				_, err := NewWebhookRepository().ListActive(ctx)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepos()
+			seed(t, r)
+
+			touched := make(chan struct{})
+			release := make(chan struct{})
+			done := make(chan error, 1)
+			go func() {
+				done <- r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+					if err := tt.touch(ctx, r); err != nil {
+						return err
+					}
+					close(touched)
+					<-release
+					return nil
+				})
+			}()
+			<-touched
+
+			probed := make(chan error, 1)
+			go func() { probed <- tt.probe(context.Background(), r) }()
+
+			select {
+			case err := <-probed:
+				if tt.blocked {
+					t.Fatal("probe ran while the transaction held the lock")
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				close(release)
+			case <-time.After(50 * time.Millisecond):
+				if !tt.blocked {
+					t.Fatal("probe blocked on a repository the transaction did not touch")
+				}
+				close(release)
+				if err := <-probed; err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestTxManagerLockOrderConflicts(t *testing.T) {
+	r := newTestRepos()
+	seed(t, r)
+	ctx := context.Background()
+
+	// Half the transactions touch the todo repository first and half the user
+	// repository first; retries keep them from deadlocking.
+	const workers = 20
+	const rounds = 50
+	orders := []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			if err := bumpTodo(ctx, r); err != nil {
+				return err
+			}
+			return bumpUser(ctx, r)
+		},
+		func(ctx context.Context) error {
+			if err := bumpUser(ctx, r); err != nil {
+				return err
+			}
+			return bumpTodo(ctx, r)
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				if err := r.tx.WithinTx(ctx, orders[i%len(orders)]); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	finished := make(chan struct{})
+	go func() { wg.Wait(); close(finished) }()
+	select {
+	case <-finished:
+	case <-time.After(10 * time.Second):
+		t.Fatal("transactions deadlocked")
+	}
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	todo, err := r.todos.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := r.users.GetByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprint(workers * rounds); todo.Description != want || user.Name != want {
+		t.Errorf("counters = %q, %q, want %q", todo.Description, user.Name, want)
+	}
+}
+
+func TestTxManagerLockConflictIsAnError(t *testing.T) {
+	r := newTestRepos()
+	seed(t, r)
+
+	// Another transaction holds the user repository, ranked below the tag repository
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+			if _, err := r.users.GetByID(ctx, 1); err != nil {
+				return err
+			}
+			close(holding)
+			<-release
+			return nil
+		})
+	}()
+	<-holding
+
+	var errs []error
+	err := r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+		if _, err := r.tags.GetByID(ctx, 1); err != nil {
+			return err
+		}
+		// The conflict is swallowed, yet the attempt is still rolled back and retried
+		_, err := r.users.GetByID(ctx, 1)
+		errs = append(errs, err)
+		if len(errs) == 1 {
+			close(release)
+		}
+		return r.tags.Update(ctx, &model.Tag{ID: 1, OwnerID: 1, Name: "renamed"})
+	})
+	if err != nil {
+		t.Fatalf("WithinTx() error = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(errs) != 2 || !errors.Is(errs[0], errLockConflict) || errs[1] != nil {
+		t.Errorf("user lookups = %v, want a lock conflict and then success", errs)
+	}
+	tag, err := r.tags.GetByID(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Name != "renamed" {
+		t.Errorf("tag name = %q, want the retried attempt's change", tag.Name)
+	}
+}
+
+// bumpTodo increments a counter kept in the first todo's description
+func bumpTodo(ctx context.Context, r *testRepos) error {
+	todo, err := r.todos.GetByID(ctx, 1)
+	if err != nil {
+		return err
+	}
+	todo.Description = increment(todo.Description)
+	return r.todos.Update(ctx, todo)
+}
+
+// bumpUser increments a counter kept in the first user's name
+func bumpUser(ctx context.Context, r *testRepos) error {
+	user, err := r.users.GetByID(ctx, 1)
+	if err != nil {
+		return err
+	}
+	user.Name = increment(user.Name)
+	return r.users.Update(ctx, user)
+}
+
+func increment(counter string) string {
+	var n int
+	fmt.Sscan(counter, &n)
+	return fmt.Sprint(n + 1)
+}
+
+func TestTxManagerBeforeCommit(t *testing.T) {
+	tests := []struct {
+		name      string
+		fnErr     error
+		hookErr   error
+		wantRuns  []string
+		wantErr   error
+		committed bool
+	}{
+		{name: "commit", wantRuns: []string{"first", "second"}, committed: true},
+		{name: "rollback", fnErr: errAbort, wantRuns: nil},
+		{name: "hook fails", hookErr: errAbort, wantRuns: []string{"first"}, wantErr: errAbort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRepos()
+			var runs []string
+			err := r.tx.WithinTx(context.Background(), func(ctx context.Context) error {
+				if err := r.todos.Create(ctx, &model.Todo{OwnerID: 1, Title: "todo"}); err != nil {
+					return err
+				}
+				for _, name := range []string{"first", "second"} {
+					if err := r.tx.BeforeCommit(ctx, func(ctx context.Context) error {
+						runs = append(runs, name)
+						return tt.hookErr
+					}); err != nil {
+						return err
+					}
+				}
+				if len(runs) > 0 {
+					t.Error("hook ran before the transaction's function returned")
+				}
+				return tt.fnErr
+			})
+
+			wantErr := tt.wantErr
+			if tt.fnErr != nil {
+				wantErr = tt.fnErr
+			}
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("WithinTx() error = %v, want %v", err, wantErr)
+			}
+			if !reflect.DeepEqual(runs, tt.wantRuns) {
+				t.Errorf("hooks run = %v, want %v", runs, tt.wantRuns)
+			}
+			if _, err := r.todos.GetByID(context.Background(), 1); (err == nil) != tt.committed {
+				t.Errorf("todo stored = %v, want %v", err == nil, tt.committed)
+			}
+		})
+	}
+
+	t.Run("outside a transaction", func(t *testing.T) {
+		ran := false
+		if err := NewTxManager().BeforeCommit(context.Background(), func(ctx context.Context) error {
+			ran = true
+			return nil
+		}); err != nil || !ran {
+			t.Errorf("BeforeCommit() = %v, ran = %v", err, ran)
+		}
+	})
+}
@@ -2,102 +2,301 @@ package persistence
 
 import (
 	"context"
-	"sync"
+	"sort"
+	"time"
 
 	"go-boilerplate/internal/domain"
 	"go-boilerplate/internal/domain/model"
 )
 
-// UserRepository implements the UserRepositoryPort interface
+// UserRepository implements the UserRepositoryPort interface.
+// Users are stored as copies so the username index can be kept in sync
+// even when callers modify the returned values.
 type UserRepository struct {
-	users         map[int]*model.User
-	usernameIndex map[string]*model.User
-	mu            sync.RWMutex
-	nextID        int
+	users           map[int]*model.User
+	usernameIndex   map[string]int
+	usernameHistory map[string]*model.UsernameHistory
+	mu              txMutex
+	nextID          int
 }
 
 // NewUserRepository creates a new UserRepository
 func NewUserRepository() *UserRepository {
 	return &UserRepository{
-		users:         make(map[int]*model.User),
-		usernameIndex: make(map[string]*model.User),
-		nextID:        1,
+		users:           make(map[int]*model.User),
+		usernameIndex:   make(map[string]int),
+		usernameHistory: make(map[string]*model.UsernameHistory),
+		nextID:          1,
 	}
 }
 
+// isLive reports whether a user is stored and not in the trash.
+// The caller holds the lock.
+func (r *UserRepository) isLive(id int) bool {
+	user, exists := r.users[id]
+	return exists && user.DeletedAt == nil
+}
+
+// checkUsernameFree reports why a username cannot be claimed, if it is held
+// by a live or trashed user. The caller holds the lock.
+func (r *UserRepository) checkUsernameFree(username string) error {
+	holder, taken := r.usernameIndex[username]
+	if !taken {
+		return nil
+	}
+	if !r.isLive(holder) {
+		return domain.ErrUsernameInTrash
+	}
+	return domain.ErrUsernameDuplicate
+}
+
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user *model.User) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if err := r.checkUsernameFree(user.Username); err != nil {
+		return err
+	}
 
+	saveValue(ctx, &r.mu, &r.nextID)
 	user.ID = r.nextID
 	r.nextID++
-	r.users[user.ID] = user
-	r.usernameIndex[user.Username] = user
+	stored := *user
+	saveEntry(ctx, &r.mu, r.users, user.ID, nil)
+	saveEntry(ctx, &r.mu, r.usernameIndex, user.Username, nil)
+	r.users[user.ID] = &stored
+	r.usernameIndex[user.Username] = user.ID
 	return nil
 }
 
 // GetByID retrieves a user by ID
 func (r *UserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
 
-	user, exists := r.users[id]
-	if !exists {
+	if !r.isLive(id) {
 		return nil, domain.ErrNotFound
 	}
-	return user, nil
+	found := *r.users[id]
+	return &found, nil
+}
+
+// GetByIDs retrieves the live users among the IDs, ordered like the IDs
+func (r *UserRepository) GetByIDs(ctx context.Context, ids []int) ([]*model.User, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	users := make([]*model.User, 0, len(ids))
+	for _, id := range ids {
+		if r.isLive(id) {
+			found := *r.users[id]
+			users = append(users, &found)
+		}
+	}
+	return users, nil
 }
 
 // GetByUsername retrieves a user by username
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
 
-	user, exists := r.usernameIndex[username]
-	if !exists {
+	id, exists := r.usernameIndex[username]
+	if !exists || !r.isLive(id) {
 		return nil, domain.ErrNotFound
 	}
-	return user, nil
+	found := *r.users[id]
+	return &found, nil
 }
 
-// List retrieves all users
+// List retrieves all live users in ID order
 func (r *UserRepository) List(ctx context.Context) ([]*model.User, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
 
 	users := make([]*model.User, 0, len(r.users))
 	for _, user := range r.users {
-		users = append(users, user)
+		if user.DeletedAt == nil {
+			found := *user
+			users = append(users, &found)
+		}
 	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
 	return users, nil
 }
 
 // Update updates an existing user
 func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
 
-	if _, exists := r.users[user.ID]; !exists {
+	if !r.isLive(user.ID) {
 		return domain.ErrNotFound
 	}
+	existing := r.users[user.ID]
 
-	r.users[user.ID] = user
-	r.usernameIndex[user.Username] = user
+	if existing.Username != user.Username {
+		if err := r.checkUsernameFree(user.Username); err != nil {
+			return err
+		}
+		saveEntry(ctx, &r.mu, r.usernameIndex, existing.Username, nil)
+		saveEntry(ctx, &r.mu, r.usernameIndex, user.Username, nil)
+		delete(r.usernameIndex, existing.Username)
+		r.usernameIndex[user.Username] = user.ID
+	}
+
+	stored := *user
+	saveEntry(ctx, &r.mu, r.users, user.ID, nil)
+	r.users[user.ID] = &stored
 	return nil
 }
 
-// Delete deletes a user
+// Rename changes a user's username and records the previous one. The new
+// username is checked and claimed under the same lock, so concurrent renames
+// cannot both take it.
+func (r *UserRepository) Rename(ctx context.Context, id int, username string, history *model.UsernameHistory) (*model.User, error) {
+	if err := r.mu.Lock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if !r.isLive(id) {
+		return nil, domain.ErrNotFound
+	}
+	user := r.users[id]
+	if user.Username == username {
+		return nil, domain.ErrUsernameUnchanged
+	}
+	if err := r.checkUsernameFree(username); err != nil {
+		return nil, err
+	}
+	// Another user's released name stays reserved until its grace period ends
+	if released, ok := r.usernameHistory[username]; ok && released.UserID != id && history.ChangedAt.Before(released.ExpiresAt) {
+		return nil, domain.ErrUsernameReserved
+	}
+
+	saveEntry(ctx, &r.mu, r.users, id, copyOf)
+	saveEntry(ctx, &r.mu, r.usernameIndex, user.Username, nil)
+	saveEntry(ctx, &r.mu, r.usernameIndex, username, nil)
+	saveEntry(ctx, &r.mu, r.usernameHistory, username, nil)
+	saveEntry(ctx, &r.mu, r.usernameHistory, history.Username, nil)
+	delete(r.usernameIndex, user.Username)
+	r.usernameIndex[username] = id
+	// A user reclaiming one of their own previous names no longer needs the redirect
+	delete(r.usernameHistory, username)
+	stored := *history
+	r.usernameHistory[history.Username] = &stored
+
+	user.Username = username
+	renamed := *user
+	return &renamed, nil
+}
+
+// GetUsernameHistory retrieves the history entry for a previously used username
+func (r *UserRepository) GetUsernameHistory(ctx context.Context, username string) (*model.UsernameHistory, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	history, exists := r.usernameHistory[username]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	found := *history
+	return &found, nil
+}
+
+// Delete permanently deletes a user and releases their usernames
 func (r *UserRepository) Delete(ctx context.Context, id int) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
 
 	user, exists := r.users[id]
 	if !exists {
 		return domain.ErrNotFound
 	}
 
+	saveEntry(ctx, &r.mu, r.users, id, nil)
+	saveEntry(ctx, &r.mu, r.usernameIndex, user.Username, nil)
 	delete(r.users, id)
 	delete(r.usernameIndex, user.Username)
+	for username, history := range r.usernameHistory {
+		if history.UserID == id {
+			saveEntry(ctx, &r.mu, r.usernameHistory, username, nil)
+			delete(r.usernameHistory, username)
+		}
+	}
+	return nil
+}
+
+// SoftDelete moves a live user to the trash
+func (r *UserRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if !r.isLive(id) {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.users, id, copyOf)
+	r.users[id].DeletedAt = &deletedAt
 	return nil
 }
+
+// Restore moves a trashed user out of the trash
+func (r *UserRepository) Restore(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	user, exists := r.users[id]
+	if !exists || user.DeletedAt == nil {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.users, id, copyOf)
+	user.DeletedAt = nil
+	return nil
+}
+
+// ListDeleted retrieves the users in the trash, ordered by ID
+func (r *UserRepository) ListDeleted(ctx context.Context) ([]*model.User, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	users := make([]*model.User, 0)
+	for _, user := range r.users {
+		if user.DeletedAt != nil {
+			found := *user
+			users = append(users, &found)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users, nil
+}
+
+// txLock returns the repository's lock
+func (r *UserRepository) txLock() *txMutex {
+	return &r.mu
+}
@@ -0,0 +1,244 @@
+package persistence
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// WebhookRepository implements the WebhookRepositoryPort interface
+type WebhookRepository struct {
+	webhooks       map[int]*model.Webhook
+	deliveries     map[int]*model.WebhookDelivery
+	mu             txMutex
+	nextID         int
+	nextDeliveryID int
+}
+
+// NewWebhookRepository creates a new WebhookRepository
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:       make(map[int]*model.Webhook),
+		deliveries:     make(map[int]*model.WebhookDelivery),
+		nextID:         1,
+		nextDeliveryID: 1,
+	}
+}
+
+// copyWebhook returns a copy of a webhook that shares no event types with the original
+func copyWebhook(webhook *model.Webhook) *model.Webhook {
+	c := *webhook
+	c.EventTypes = append(make([]model.EventType, 0, len(webhook.EventTypes)), webhook.EventTypes...)
+	return &c
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	saveValue(ctx, &r.mu, &r.nextID)
+	webhook.ID = r.nextID
+	r.nextID++
+	saveEntry(ctx, &r.mu, r.webhooks, webhook.ID, nil)
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id int) (*model.Webhook, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+// filterWebhooks returns copies of the webhooks matching the predicate, ordered by ID
+func (r *WebhookRepository) filterWebhooks(ctx context.Context, match func(*model.Webhook) bool) ([]*model.Webhook, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	webhooks := make([]*model.Webhook, 0)
+	for _, webhook := range r.webhooks {
+		if match(webhook) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+// ListByOwner retrieves a user's webhooks, ordered by ID
+func (r *WebhookRepository) ListByOwner(ctx context.Context, ownerID int) ([]*model.Webhook, error) {
+	return r.filterWebhooks(ctx, func(webhook *model.Webhook) bool {
+		return webhook.OwnerID == ownerID
+	})
+}
+
+// ListActive retrieves the webhooks that receive deliveries, ordered by ID
+func (r *WebhookRepository) ListActive(ctx context.Context) ([]*model.Webhook, error) {
+	return r.filterWebhooks(ctx, func(webhook *model.Webhook) bool {
+		return webhook.Active
+	})
+}
+
+// Update updates an existing webhook
+func (r *WebhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.webhooks[webhook.ID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.webhooks, webhook.ID, nil)
+	r.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// Delete removes a webhook and its deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, id int) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.webhooks[id]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.webhooks, id, nil)
+	delete(r.webhooks, id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.WebhookID == id {
+			saveEntry(ctx, &r.mu, r.deliveries, deliveryID, nil)
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// CreateDelivery creates a new delivery
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.webhooks[delivery.WebhookID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveValue(ctx, &r.mu, &r.nextDeliveryID)
+	delivery.ID = r.nextDeliveryID
+	r.nextDeliveryID++
+	saveEntry(ctx, &r.mu, r.deliveries, delivery.ID, nil)
+	r.deliveries[delivery.ID] = delivery.Clone()
+	return nil
+}
+
+// GetDelivery retrieves a delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id int) (*model.WebhookDelivery, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	delivery, exists := r.deliveries[id]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return delivery.Clone(), nil
+}
+
+// ListDeliveries retrieves a webhook's deliveries, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus) ([]*model.WebhookDelivery, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery.Clone())
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return deliveries, nil
+}
+
+// ListDueDeliveries retrieves up to limit pending deliveries of active
+// webhooks that are due at now, oldest first. A webhook's deliveries are
+// listed only up to its first one that is still waiting for a retry, so
+// backed-off and disabled webhooks do not use up the batch.
+func (r *WebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error) {
+	if err := r.mu.RLock(ctx); err != nil {
+		return nil, err
+	}
+	defer r.mu.RUnlock(ctx)
+
+	pending := make([]*model.WebhookDelivery, 0)
+	for _, delivery := range r.deliveries {
+		if delivery.Status != model.DeliveryPending {
+			continue
+		}
+		if webhook, exists := r.webhooks[delivery.WebhookID]; exists && webhook.Active {
+			pending = append(pending, delivery)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+
+	deliveries := make([]*model.WebhookDelivery, 0)
+	waiting := make(map[int]bool)
+	for _, delivery := range pending {
+		if limit > 0 && len(deliveries) == limit {
+			break
+		}
+		if waiting[delivery.WebhookID] {
+			continue
+		}
+		if now.Before(delivery.NextAttemptAt) {
+			waiting[delivery.WebhookID] = true
+			continue
+		}
+		deliveries = append(deliveries, delivery.Clone())
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery updates an existing delivery
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	if err := r.mu.Lock(ctx); err != nil {
+		return err
+	}
+	defer r.mu.Unlock(ctx)
+
+	if _, exists := r.deliveries[delivery.ID]; !exists {
+		return domain.ErrNotFound
+	}
+
+	saveEntry(ctx, &r.mu, r.deliveries, delivery.ID, nil)
+	r.deliveries[delivery.ID] = delivery.Clone()
+	return nil
+}
+
+// txLock returns the repository's lock
+func (r *WebhookRepository) txLock() *txMutex {
+	return &r.mu
+}
@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+func TestListDueDeliveries(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	type delivery struct {
+		webhook int
+		status  model.DeliveryStatus
+		next    time.Duration
+	}
+	tests := []struct {
+		name       string
+		inactive   []int
+		deliveries []delivery
+		limit      int
+		want       []int
+	}{
+		{
+			name: "pending due deliveries oldest first",
+			deliveries: []delivery{
+				{webhook: 1, status: model.DeliveryPending},
+				{webhook: 2, status: model.DeliveryPending, next: -time.Minute},
+				{webhook: 1, status: model.DeliverySucceeded},
+				{webhook: 1, status: model.DeliveryDead},
+				{webhook: 1, status: model.DeliveryPending},
+			},
+			want: []int{1, 2, 5},
+		},
+		{
+			name: "deliveries stop at a webhook's first one that is not due",
+			deliveries: []delivery{
+				{webhook: 1, status: model.DeliveryPending, next: time.Minute},
+				{webhook: 1, status: model.DeliveryPending},
+				{webhook: 2, status: model.DeliveryPending},
+				{webhook: 2, status: model.DeliveryPending, next: time.Second},
+				{webhook: 2, status: model.DeliveryPending},
+			},
+			want: []int{3},
+		},
+		{
+			name:     "inactive webhooks are skipped",
+			inactive: []int{1},
+			deliveries: []delivery{
+				{webhook: 1, status: model.DeliveryPending},
+				{webhook: 2, status: model.DeliveryPending},
+			},
+			want: []int{2},
+		},
+		{
+			name:     "waiting and inactive deliveries do not use up the limit",
+			inactive: []int{2},
+			deliveries: []delivery{
+				{webhook: 1, status: model.DeliveryPending, next: time.Hour},
+				{webhook: 1, status: model.DeliveryPending},
+				{webhook: 2, status: model.DeliveryPending},
+				{webhook: 3, status: model.DeliveryPending},
+				{webhook: 3, status: model.DeliveryPending},
+				{webhook: 3, status: model.DeliveryPending},
+			},
+			limit: 2,
+			want:  []int{4, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewWebhookRepository()
+			for id := 1; id <= 3; id++ {
+				active := true
+				for _, inactive := range tt.inactive {
+					active = active && inactive != id
+				}
+				if err := repo.Create(ctx, &model.Webhook{OwnerID: 1, URL: "https://example.com", Active: active}); err != nil {
+					t.Fatalf("Create: %v", err)
+				}
+			}
+			for _, d := range tt.deliveries {
+				err := repo.CreateDelivery(ctx, &model.WebhookDelivery{WebhookID: d.webhook, Status: d.status, NextAttemptAt: now.Add(d.next)})
+				if err != nil {
+					t.Fatalf("CreateDelivery: %v", err)
+				}
+			}
+
+			deliveries, err := repo.ListDueDeliveries(ctx, now, tt.limit)
+			if err != nil {
+				t.Fatalf("ListDueDeliveries: %v", err)
+			}
+			got := make([]int, 0, len(deliveries))
+			for _, delivery := range deliveries {
+				got = append(got, delivery.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("due deliveries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package readmodel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// todoStatsName names the projection in its status
+const todoStatsName = "todo_stats"
+
+// todoRow is what the read model keeps of a todo
+type todoRow struct {
+	ownerID int
+	status  model.TodoStatus
+	tagIDs  []int
+	dueDate *time.Time
+	// removed rows are kept as tombstones so late events cannot revive them
+	removed bool
+	version time.Time
+}
+
+// counters are the running totals over a set of rows
+type counters struct {
+	total    int
+	byStatus map[model.TodoStatus]int
+	byTag    map[int]int
+	// due holds the due dates of open todos, for counting overdue ones at read time
+	due map[int]time.Time
+}
+
+// newCounters creates empty counters
+func newCounters() *counters {
+	return &counters{
+		byStatus: make(map[model.TodoStatus]int),
+		byTag:    make(map[int]int),
+		due:      make(map[int]time.Time),
+	}
+}
+
+// add counts a row once, or uncounts it with delta -1
+func (c *counters) add(todoID int, row *todoRow, delta int) {
+	c.total += delta
+	c.byStatus[row.status] += delta
+	if c.byStatus[row.status] == 0 {
+		delete(c.byStatus, row.status)
+	}
+	for _, tagID := range row.tagIDs {
+		c.byTag[tagID] += delta
+		if c.byTag[tagID] == 0 {
+			delete(c.byTag, tagID)
+		}
+	}
+	if row.dueDate != nil && !row.status.IsClosed() {
+		if delta > 0 {
+			c.due[todoID] = *row.dueDate
+		} else {
+			delete(c.due, todoID)
+		}
+	}
+}
+
+// MemoryTodoStats implements the TodoStatsReadModelPort interface in memory.
+// Counts are maintained as todos change, so queries never scan the todos.
+type MemoryTodoStats struct {
+	rows    map[int]*todoRow
+	all     *counters
+	byOwner map[int]*counters
+	status  model.ProjectionStatus
+	// pending maps the IDs of published events not yet applied to when they occurred
+	pending map[string]time.Time
+	mu      sync.RWMutex
+}
+
+// NewMemoryTodoStats creates a new, empty MemoryTodoStats
+func NewMemoryTodoStats() *MemoryTodoStats {
+	return &MemoryTodoStats{
+		rows:    make(map[int]*todoRow),
+		all:     newCounters(),
+		byOwner: make(map[int]*counters),
+		status:  model.ProjectionStatus{Name: todoStatsName},
+		pending: make(map[string]time.Time),
+	}
+}
+
+// count adds or removes a live row from the totals. The caller holds the lock.
+func (m *MemoryTodoStats) count(todoID int, row *todoRow, delta int) {
+	if row.removed {
+		return
+	}
+	m.all.add(todoID, row, delta)
+
+	owner, exists := m.byOwner[row.ownerID]
+	if !exists {
+		owner = newCounters()
+		m.byOwner[row.ownerID] = owner
+	}
+	owner.add(todoID, row, delta)
+	if owner.total == 0 {
+		delete(m.byOwner, row.ownerID)
+	}
+}
+
+// store replaces a todo's row unless a newer one is stored. The caller holds the lock.
+func (m *MemoryTodoStats) store(todoID int, row *todoRow) {
+	if old, exists := m.rows[todoID]; exists {
+		if row.version.Before(old.version) {
+			return
+		}
+		m.count(todoID, old, -1)
+	}
+	m.rows[todoID] = row
+	m.count(todoID, row, 1)
+}
+
+// rowOf builds the row of a todo
+func rowOf(todo *model.Todo, version time.Time) *todoRow {
+	row := &todoRow{
+		ownerID: todo.OwnerID,
+		status:  todo.Status,
+		tagIDs:  append([]int{}, todo.TagIDs...),
+		version: version,
+	}
+	if todo.DueDate != nil {
+		dueDate := *todo.DueDate
+		row.dueDate = &dueDate
+	}
+	return row
+}
+
+// Upsert counts a live todo
+func (m *MemoryTodoStats) Upsert(ctx context.Context, todo *model.Todo, version time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store(todo.ID, rowOf(todo, version))
+	return nil
+}
+
+// Remove stops counting a todo
+func (m *MemoryTodoStats) Remove(ctx context.Context, todoID int, version time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.store(todoID, &todoRow{removed: true, version: version})
+	return nil
+}
+
+// Replace discards every row and counts the given todos instead
+func (m *MemoryTodoStats) Replace(ctx context.Context, todos []*model.Todo, version time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rows = make(map[int]*todoRow, len(todos))
+	m.all = newCounters()
+	m.byOwner = make(map[int]*counters)
+	for _, todo := range todos {
+		m.store(todo.ID, rowOf(todo, version))
+	}
+	m.status.RebuiltAt = &version
+	return nil
+}
+
+// RecordPending notes an event that was published but not yet applied
+func (m *MemoryTodoStats) RecordPending(ctx context.Context, event model.Event) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.pending[event.ID] = event.OccurredAt
+	return nil
+}
+
+// RecordApplied advances the projection status past a handled event
+func (m *MemoryTodoStats) RecordApplied(ctx context.Context, event model.Event, appliedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.pending, event.ID)
+	occurredAt := event.OccurredAt
+	m.status.EventsApplied++
+	m.status.LastEventID = event.ID
+	m.status.LastEventAt = &occurredAt
+	m.status.LastAppliedAt = &appliedAt
+	m.status.LagSeconds = appliedAt.Sub(occurredAt).Seconds()
+	return nil
+}
+
+// statusAt returns the projection status as of now. While events are pending
+// the lag grows with the oldest of them. The caller holds the lock.
+func (m *MemoryTodoStats) statusAt(now time.Time) model.ProjectionStatus {
+	status := m.status
+	status.EventsPending = len(m.pending)
+	var oldest time.Time
+	for _, occurredAt := range m.pending {
+		if oldest.IsZero() || occurredAt.Before(oldest) {
+			oldest = occurredAt
+		}
+	}
+	if !oldest.IsZero() {
+		status.LagSeconds = max(now.Sub(oldest).Seconds(), 0)
+	}
+	return status
+}
+
+// Stats returns the counts matching the filter
+func (m *MemoryTodoStats) Stats(ctx context.Context, filter model.TodoStatsFilter, now time.Time) (*model.TodoStats, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c := m.all
+	if filter.OwnerID != nil {
+		c = m.byOwner[*filter.OwnerID]
+		if c == nil {
+			c = newCounters()
+		}
+	}
+
+	stats := &model.TodoStats{
+		Total:      c.total,
+		ByStatus:   make(map[model.TodoStatus]int, len(c.byStatus)),
+		ByOwner:    make(map[int]int),
+		ByTag:      make(map[int]int, len(c.byTag)),
+		Projection: m.statusAt(now),
+	}
+	for status, n := range c.byStatus {
+		stats.ByStatus[status] = n
+	}
+	for tagID, n := range c.byTag {
+		stats.ByTag[tagID] = n
+	}
+	for ownerID, owner := range m.byOwner {
+		if filter.OwnerID == nil || *filter.OwnerID == ownerID {
+			stats.ByOwner[ownerID] = owner.total
+		}
+	}
+	for _, dueDate := range c.due {
+		if dueDate.Before(now) {
+			stats.Overdue++
+		}
+	}
+	return stats, nil
+}
+
+// Status reports how far the read model has caught up
+func (m *MemoryTodoStats) Status(ctx context.Context, now time.Time) (*model.ProjectionStatus, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	status := m.statusAt(now)
+	return &status, nil
+}
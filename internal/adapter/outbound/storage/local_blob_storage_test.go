@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestLocalBlobStorage(t *testing.T) (*LocalBlobStorage, string) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := NewLocalBlobStorage(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, root
+}
+
+func TestLocalBlobStorage(t *testing.T) {
+	store, _ := newTestLocalBlobStorage(t)
+	testBlobStorage(t, store)
+}
+
+func TestLocalBlobStorageRejectsEscapingKeys(t *testing.T) {
+	store, root := newTestLocalBlobStorage(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/", "../outside.txt", "todos/../../outside.txt", "/todos/1", "todos//1", `todos\1`} {
+		t.Run(key, func(t *testing.T) {
+			if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Errorf("Put(%q) error = nil, want an invalid key error", key)
+			}
+			if _, err := store.Open(ctx, key); err == nil {
+				t.Errorf("Open(%q) error = nil, want an invalid key error", key)
+			}
+			if _, err := store.Exists(ctx, key); err == nil {
+				t.Errorf("Exists(%q) error = nil, want an invalid key error", key)
+			}
+			if err := store.Delete(ctx, key); err == nil {
+				t.Errorf("Delete(%q) error = nil, want an invalid key error", key)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(root, "..", "outside.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a blob was written outside the root: %v", err)
+	}
+}
+
+func TestLocalBlobStorageShortWrite(t *testing.T) {
+	store, root := newTestLocalBlobStorage(t)
+	ctx := context.Background()
+
+	// A body shorter than announced leaves neither the blob nor a temporary file behind
+	if err := store.Put(ctx, "todos/1/short.txt", strings.NewReader("abc"), 10, "text/plain"); err == nil {
+		t.Fatal("Put() error = nil, want a size mismatch")
+	}
+	if exists, err := store.Exists(ctx, "todos/1/short.txt"); err != nil || exists {
+		t.Errorf("Exists() = %v, %v, want false", exists, err)
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "todos", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go-boilerplate/internal/domain"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config holds the connection settings of an S3-compatible object store
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+// S3BlobStorage implements the BlobStoragePort interface on an S3-compatible
+// object store such as AWS S3 or MinIO. Each blob is an object in one bucket.
+type S3BlobStorage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3BlobStorage creates a new S3BlobStorage, creating the bucket if it does not exist
+func NewS3BlobStorage(ctx context.Context, cfg S3Config) (*S3BlobStorage, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("error checking bucket %q: %w", cfg.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("error creating bucket %q: %w", cfg.Bucket, err)
+		}
+	}
+
+	return &S3BlobStorage{client: client, bucket: cfg.Bucket}, nil
+}
+
+// isNoSuchKey reports whether the store answered that the object does not exist
+func isNoSuchKey(err error) bool {
+	return minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
+
+// Put uploads a blob as an object
+func (s *S3BlobStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Open opens an object for reading. Seeking issues ranged GET requests, so
+// serving a byte range does not download the whole object.
+func (s *S3BlobStorage) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// GetObject is lazy; Stat surfaces a missing object before the first read
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if isNoSuchKey(err) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+// Exists reports whether an object is stored under the key
+func (s *S3BlobStorage) Exists(ctx context.Context, key string) (bool, error) {
+	if _, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{}); err != nil {
+		if isNoSuchKey(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Delete removes an object
+func (s *S3BlobStorage) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
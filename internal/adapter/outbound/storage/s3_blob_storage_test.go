@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 is an in-memory stand-in for the path-style S3 API calls the blob
+// storage makes: bucket HEAD and PUT, and object PUT, HEAD, GET and DELETE
+type fakeS3 struct {
+	mu      sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	objects, bucketExists := s.buckets[bucket]
+	if key == "" {
+		switch r.Method {
+		case http.MethodHead:
+			if !bucketExists {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPut:
+			s.buckets[bucket] = map[string][]byte{}
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+		return
+	}
+	if !bucketExists {
+		s3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		objects[key] = body
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodHead, http.MethodGet:
+		body, ok := objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		http.ServeContent(w, r, key, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), bytes.NewReader(body))
+	case http.MethodDelete:
+		delete(objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	io.WriteString(w, "<Error><Code>"+code+"</Code><Message>"+code+"</Message></Error>")
+}
+
+func newTestS3BlobStorage(t *testing.T) (*S3BlobStorage, *fakeS3) {
+	t.Helper()
+	fake := &fakeS3{buckets: map[string]map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	// Anonymous requests keep bodies unsigned so the fake can read them as sent
+	store, err := NewS3BlobStorage(context.Background(), S3Config{
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Region:   "us-east-1",
+		Bucket:   "attachments",
+	})
+	if err != nil {
+		t.Fatalf("NewS3BlobStorage() error = %v", err)
+	}
+	return store, fake
+}
+
+func TestS3BlobStorage(t *testing.T) {
+	store, _ := newTestS3BlobStorage(t)
+	testBlobStorage(t, store)
+}
+
+func TestS3BlobStorageCreatesBucket(t *testing.T) {
+	_, fake := newTestS3BlobStorage(t)
+	if _, ok := fake.buckets["attachments"]; !ok {
+		t.Fatalf("buckets = %v, want attachments created", fake.buckets)
+	}
+
+	// An existing bucket and its objects are kept
+	fake.buckets["attachments"]["todos/1/kept.txt"] = []byte("kept")
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	store, err := NewS3BlobStorage(context.Background(), S3Config{
+		Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Region:   "us-east-1",
+		Bucket:   "attachments",
+	})
+	if err != nil {
+		t.Fatalf("NewS3BlobStorage() error = %v", err)
+	}
+	if exists, err := store.Exists(context.Background(), "todos/1/kept.txt"); err != nil || !exists {
+		t.Errorf("Exists() = %v, %v, want true", exists, err)
+	}
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/port"
+)
+
+// testBlobStorage runs the behavior every BlobStoragePort implementation shares
+func testBlobStorage(t *testing.T, store port.BlobStoragePort) {
+	ctx := context.Background()
+	const key = "todos/1/notes.txt"
+	const content = "hello, blob storage"
+
+	t.Run("missing blob", func(t *testing.T) {
+		exists, err := store.Exists(ctx, "todos/1/missing.txt")
+		if err != nil || exists {
+			t.Errorf("Exists() = %v, %v, want false", exists, err)
+		}
+		if _, err := store.Open(ctx, "todos/1/missing.txt"); !errors.Is(err, domain.ErrNotFound) {
+			t.Errorf("Open() error = %v, want %v", err, domain.ErrNotFound)
+		}
+		if err := store.Delete(ctx, "todos/1/missing.txt"); err != nil {
+			t.Errorf("Delete() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("put, open and delete", func(t *testing.T) {
+		if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		if exists, err := store.Exists(ctx, key); err != nil || !exists {
+			t.Errorf("Exists() = %v, %v, want true", exists, err)
+		}
+
+		blob, err := store.Open(ctx, key)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		defer blob.Close()
+		if got, err := io.ReadAll(blob); err != nil || string(got) != content {
+			t.Errorf("read %q, %v, want %q", got, err, content)
+		}
+		// Seeking serves byte ranges
+		if _, err := blob.Seek(7, io.SeekStart); err != nil {
+			t.Fatalf("Seek() error = %v", err)
+		}
+		if got, err := io.ReadAll(blob); err != nil || string(got) != content[7:] {
+			t.Errorf("read after seek %q, %v, want %q", got, err, content[7:])
+		}
+
+		if err := store.Delete(ctx, key); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if exists, err := store.Exists(ctx, key); err != nil || exists {
+			t.Errorf("Exists() after delete = %v, %v, want false", exists, err)
+		}
+	})
+
+	t.Run("put replaces a blob", func(t *testing.T) {
+		for _, body := range []string{"first", "second version"} {
+			if err := store.Put(ctx, key, strings.NewReader(body), int64(len(body)), "text/plain"); err != nil {
+				t.Fatalf("Put(%q) error = %v", body, err)
+			}
+		}
+		blob, err := store.Open(ctx, key)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		defer blob.Close()
+		if got, err := io.ReadAll(blob); err != nil || string(got) != "second version" {
+			t.Errorf("read %q, %v, want %q", got, err, "second version")
+		}
+	})
+}
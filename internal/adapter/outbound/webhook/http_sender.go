@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// maxResponseBody caps how much of a receiver's response is read before it is discarded
+const maxResponseBody = 64 << 10
+
+// ErrInternalAddress is returned when a webhook URL resolves to an internal address
+var ErrInternalAddress = errors.New("webhook target resolves to an internal address")
+
+// HTTPSender implements the WebhookSenderPort interface with an HTTP client.
+// Redirects are not followed, so a signed payload only ever reaches the
+// configured URL, and unless internal targets are allowed every connection
+// is checked after DNS resolution so a host name that resolves, or later
+// re-resolves, to an internal address is refused.
+type HTTPSender struct {
+	client        *http.Client
+	allowInternal bool
+}
+
+// HTTPSenderOption configures optional HTTPSender behavior
+type HTTPSenderOption func(*HTTPSender)
+
+// WithInternalTargets lets deliveries reach loopback, private and link-local
+// addresses, for receivers on a development machine
+func WithInternalTargets(allow bool) HTTPSenderOption {
+	return func(s *HTTPSender) {
+		s.allowInternal = allow
+	}
+}
+
+// NewHTTPSender creates an HTTPSender that gives up on a delivery after the timeout
+func NewHTTPSender(timeout time.Duration, opts ...HTTPSenderOption) *HTTPSender {
+	s := &HTTPSender{}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: s.checkAddress,
+	}
+	s.client = &http.Client{
+		Timeout: timeout,
+		// No proxy: the dialed address must be the receiver's for the check to mean anything
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// checkAddress runs before every connection with the resolved address and
+// refuses internal ones
+func (s *HTTPSender) checkAddress(network, address string, _ syscall.RawConn) error {
+	if s.allowInternal {
+		return nil
+	}
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInternalAddress, address)
+	}
+	if model.IsInternalAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrInternalAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// Send posts the JSON body to the URL and returns the response status code
+func (s *HTTPSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-boilerplate-webhooks/1.0")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Drain the response so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSenderRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+
+	tests := []struct {
+		name          string
+		url           string
+		allowInternal bool
+		wantErr       error
+		wantStatus    int
+	}{
+		{name: "loopback ip", url: server.URL, wantErr: ErrInternalAddress},
+		// A host name is checked after it resolves, as a rebinding DNS record would
+		{name: "host name resolving to loopback", url: "http://localhost" + port, wantErr: ErrInternalAddress},
+		{name: "link-local metadata address", url: "http://169.254.169.254/latest/meta-data", wantErr: ErrInternalAddress},
+		{name: "loopback when internal targets are allowed", url: server.URL, allowInternal: true, wantStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := NewHTTPSender(time.Second, WithInternalTargets(tt.allowInternal))
+
+			status, err := sender.Send(context.Background(), tt.url, nil, []byte(`{}`))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Send(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("Send(%q) status = %d, want %d", tt.url, status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -1,13 +1,254 @@
 package config
 
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
 type Config struct {
 	Server struct {
 		Address string
 	}
+	API struct {
+		// LegacyDeprecatedAt is when the unversioned routes were
+		// deprecated in favor of /v1
+		LegacyDeprecatedAt time.Time
+		// LegacySunset is when the unversioned routes stop being served
+		LegacySunset time.Time
+	}
+	Auth struct {
+		// TokenSecret signs the bearer tokens users authenticate with;
+		// empty rejects every token
+		TokenSecret string
+		// TokenTTL is how long issued tokens stay valid
+		TokenTTL time.Duration
+		// AllowActorHeader lets requests name their user with the
+		// X-User-ID header or x-user-id metadata. That is not
+		// authentication, anyone can claim any user, so it is for local
+		// development only.
+		AllowActorHeader bool
+	}
+	GRPC struct {
+		// Address is where the gRPC server listens, next to the HTTP
+		// server; empty disables it
+		Address string
+	}
+	Todo struct {
+		// SubtaskRule decides whether closing all subtasks completes the parent
+		// or whether a parent cannot be completed while subtasks are open
+		SubtaskRule model.SubtaskRule
+		// MaxSubtaskDepth limits how deeply subtasks may be nested
+		MaxSubtaskDepth int
+		// Store selects the todo repository: "memory" keeps the current
+		// state only, "event_sourced" keeps every change for history and
+		// time travel
+		Store string
+		// SnapshotEvery is how many events an event-sourced todo replays
+		// at most before a snapshot is taken
+		SnapshotEvery int
+	}
+	User struct {
+		// UsernameGracePeriod is how long a previous username keeps
+		// redirecting to its owner and stays reserved after a rename
+		UsernameGracePeriod time.Duration
+	}
+	Admin struct {
+		// UserIDs lists the users allowed to read the audit log, from the
+		// comma-separated ADMIN_USER_IDS
+		UserIDs []int
+		// APIKey, from ADMIN_API_KEY, lets operators act as one of the
+		// administrators, e.g. with todoctl --admin, without holding the
+		// token secret; empty rejects it
+		APIKey string
+	}
+	Audit struct {
+		// Backend selects the audit log adapter: "memory" or "file"
+		Backend  string
+		FilePath string
+	}
+	Events struct {
+		// AsyncWorkers is how many workers each async subscriber gets;
+		// events of one aggregate are always handled by the same worker
+		AsyncWorkers int
+		// BufferSize is how many events each worker queues before
+		// publishing waits
+		BufferSize int
+		// RetryBackoff is the wait before retrying an async handler that
+		// failed; it doubles with every further failure up to MaxRetryBackoff
+		RetryBackoff    time.Duration
+		MaxRetryBackoff time.Duration
+	}
+	Outbox struct {
+		// RelayInterval is how often pending events are relayed to the bus
+		RelayInterval time.Duration
+		// BatchSize is how many events are relayed per run
+		BatchSize int
+		// MaxBackoff caps the wait between retries of an event that failed to relay
+		MaxBackoff time.Duration
+		// Retention is how long relayed events are kept in the outbox, and
+		// how long consumers remember the events they handled
+		Retention time.Duration
+	}
+	Webhook struct {
+		// DispatchInterval is how often due deliveries are sent
+		DispatchInterval time.Duration
+		// MaxAttempts is how many attempts a delivery gets before it is
+		// moved to the dead-letter queue
+		MaxAttempts int
+		// InitialBackoff is the wait after the first failed attempt; it
+		// doubles with every further failure up to MaxBackoff
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+		// DisableAfterFailures is how many consecutive failed attempts
+		// disable a webhook until its owner re-enables it
+		DisableAfterFailures int
+		// Timeout bounds a single delivery request
+		Timeout time.Duration
+		// AllowInternalTargets lets webhooks point at loopback, private and
+		// link-local addresses. Only for development receivers; it exposes
+		// the server's own network to anyone who can register a webhook.
+		AllowInternalTargets bool
+	}
+	Stream struct {
+		// ReplayBuffer is how many recent todo changes are kept for
+		// clients resuming a stream with Last-Event-ID
+		ReplayBuffer int
+		// ClientBuffer is how many changes may queue for one client
+		// before it is disconnected as too slow
+		ClientBuffer int
+		// HeartbeatInterval is how long a stream may stay idle before a
+		// heartbeat is sent to keep the connection open
+		HeartbeatInterval time.Duration
+	}
+	WebSocket struct {
+		// ClientBuffer is how many messages may queue for one connection
+		// before it is disconnected as too slow
+		ClientBuffer int
+		// PingInterval is how often connections are pinged; one that does
+		// not answer within two intervals is closed
+		PingInterval time.Duration
+		// MaxMessageSize is the largest client message accepted, in bytes
+		MaxMessageSize int64
+	}
+	GraphQL struct {
+		// MaxDepth limits how deeply an operation may nest fields
+		MaxDepth int
+		// MaxComplexity limits the cost of an operation; each field costs
+		// one and list fields cost ten times their selections
+		MaxComplexity int
+		// Introspection lets clients query the schema
+		Introspection bool
+	}
+	Trash struct {
+		// Retention is how long deleted todos and users can be restored
+		// before they are purged
+		Retention time.Duration
+		// PurgeInterval is how often the trash is checked for expired entries
+		PurgeInterval time.Duration
+	}
+	Attachment struct {
+		// MaxSize is the largest accepted upload in bytes
+		MaxSize int64
+		// AllowedTypes lists the accepted content types; an entry ending
+		// in "/" accepts a whole family such as "image/"
+		AllowedTypes []string
+	}
+	Storage struct {
+		// Backend selects the blob storage adapter: "local" or "s3"
+		Backend  string
+		LocalDir string
+		S3       struct {
+			Endpoint  string
+			Region    string
+			Bucket    string
+			AccessKey string
+			SecretKey string
+			UseSSL    bool
+		}
+	}
 }
 
 func Load() (*Config, error) {
-	return &Config{
-		Server: struct{ Address string }{Address: ":8080"},
-	}, nil
-} 
\ No newline at end of file
+	cfg := &Config{}
+	cfg.Server.Address = ":8080"
+	cfg.API.LegacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	cfg.API.LegacySunset = time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+	// The token secret is never kept in code
+	cfg.Auth.TokenSecret = os.Getenv("AUTH_TOKEN_SECRET")
+	cfg.Auth.TokenTTL = 24 * time.Hour
+	cfg.Auth.AllowActorHeader = os.Getenv("AUTH_ALLOW_ACTOR_HEADER") == "true"
+	adminIDs, err := parseIDs(os.Getenv("ADMIN_USER_IDS"))
+	if err != nil {
+		return nil, fmt.Errorf("ADMIN_USER_IDS: %w", err)
+	}
+	cfg.Admin.UserIDs = adminIDs
+	// Like the token secret, the admin key is never kept in code
+	cfg.Admin.APIKey = os.Getenv("ADMIN_API_KEY")
+	cfg.GRPC.Address = ":9090"
+	cfg.Todo.SubtaskRule = model.SubtaskRuleNone
+	cfg.Todo.MaxSubtaskDepth = 3
+	cfg.Todo.Store = "memory"
+	cfg.Todo.SnapshotEvery = 20
+	cfg.User.UsernameGracePeriod = 30 * 24 * time.Hour
+	cfg.Audit.Backend = "file"
+	cfg.Audit.FilePath = "./data/audit.log"
+	cfg.Events.AsyncWorkers = 4
+	cfg.Events.BufferSize = 256
+	cfg.Events.RetryBackoff = 100 * time.Millisecond
+	cfg.Events.MaxRetryBackoff = time.Minute
+	cfg.Outbox.RelayInterval = 500 * time.Millisecond
+	cfg.Outbox.BatchSize = 100
+	cfg.Outbox.MaxBackoff = 5 * time.Minute
+	cfg.Outbox.Retention = 24 * time.Hour
+	cfg.Webhook.DispatchInterval = time.Second
+	cfg.Webhook.MaxAttempts = 8
+	cfg.Webhook.InitialBackoff = 10 * time.Second
+	cfg.Webhook.MaxBackoff = time.Hour
+	cfg.Webhook.DisableAfterFailures = 20
+	cfg.Webhook.Timeout = 10 * time.Second
+	cfg.Webhook.AllowInternalTargets = os.Getenv("WEBHOOK_ALLOW_INTERNAL_TARGETS") == "true"
+	cfg.Stream.ReplayBuffer = 1000
+	cfg.Stream.ClientBuffer = 64
+	cfg.Stream.HeartbeatInterval = 15 * time.Second
+	cfg.WebSocket.ClientBuffer = 64
+	cfg.WebSocket.PingInterval = 30 * time.Second
+	cfg.WebSocket.MaxMessageSize = 64 << 10
+	cfg.GraphQL.MaxDepth = 10
+	cfg.GraphQL.MaxComplexity = 1000
+	cfg.GraphQL.Introspection = true
+	cfg.Trash.Retention = 30 * 24 * time.Hour
+	cfg.Trash.PurgeInterval = time.Hour
+	cfg.Attachment.MaxSize = 10 << 20
+	cfg.Attachment.AllowedTypes = []string{"image/", "text/plain", "application/pdf"}
+	cfg.Storage.Backend = "local"
+	cfg.Storage.LocalDir = "./data/blobs"
+	cfg.Storage.S3.Endpoint = "localhost:9000"
+	cfg.Storage.S3.Region = "us-east-1"
+	cfg.Storage.S3.Bucket = "attachments"
+	// Object store credentials are never kept in code
+	cfg.Storage.S3.AccessKey = os.Getenv("S3_ACCESS_KEY")
+	cfg.Storage.S3.SecretKey = os.Getenv("S3_SECRET_KEY")
+	return cfg, nil
+}
+
+// parseIDs parses a comma-separated list of user IDs
+func parseIDs(s string) ([]int, error) {
+	var ids []int
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		id, err := strconv.Atoi(field)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid user ID %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
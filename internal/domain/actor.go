@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// actorKey is the context key for the ID of the user performing a request
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying the ID of the acting user
+func ContextWithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ActorFromContext returns the ID of the acting user, if the request has one
+func ActorFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(actorKey{}).(int)
+	return userID, ok
+}
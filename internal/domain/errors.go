@@ -10,12 +10,124 @@ var (
 	ErrDuplicate = errors.New("resource already exists")
 )
 
+// Access errors
+var (
+	// ErrUnauthenticated is returned when an operation requires an acting user but none is known
+	ErrUnauthenticated = errors.New("authentication required")
+	// ErrForbidden is returned when the acting user lacks permission for an operation
+	ErrForbidden = errors.New("permission denied")
+)
+
 // Todo business logic errors
 var (
 	// ErrInvalidTodoTitle is returned when todo title is empty or invalid
 	ErrInvalidTodoTitle = errors.New("todo title cannot be empty")
 	// ErrTodoAlreadyCompleted is returned when trying to complete an already completed todo
 	ErrTodoAlreadyCompleted = errors.New("todo is already completed")
+	// ErrInvalidPriority is returned when a todo priority is not a known level
+	ErrInvalidPriority = errors.New("invalid todo priority")
+	// ErrInvalidTimezone is returned when a todo timezone is not a valid IANA zone name
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	// ErrDueDateInPast is returned when a due date is before the todo's creation time
+	ErrDueDateInPast = errors.New("due date cannot be before the todo was created")
+	// ErrInvalidTodoStatus is returned when a todo status is not a known lifecycle state
+	ErrInvalidTodoStatus = errors.New("invalid todo status")
+	// ErrIllegalTransition is returned when the state machine does not allow a status change
+	ErrIllegalTransition = errors.New("illegal todo status transition")
+	// ErrInvalidParentTodo is returned when a todo cannot be placed under the requested parent
+	ErrInvalidParentTodo = errors.New("invalid parent todo")
+	// ErrTodoCycle is returned when a parent change would make a todo its own ancestor
+	ErrTodoCycle = errors.New("todo cannot be nested under its own subtask")
+	// ErrMaxDepthExceeded is returned when a parent change would nest subtasks too deeply
+	ErrMaxDepthExceeded = errors.New("maximum subtask depth exceeded")
+	// ErrOpenSubtasks is returned when completing a todo that still has open subtasks
+	ErrOpenSubtasks = errors.New("todo has open subtasks")
+	// ErrClosedParent is returned when an open todo would sit below a closed parent
+	ErrClosedParent = errors.New("open subtask cannot be placed under a closed todo")
+	// ErrInvalidChecklistItem is returned when a checklist item text is empty
+	ErrInvalidChecklistItem = errors.New("checklist item text cannot be empty")
+	// ErrInvalidRecurrence is returned when a recurrence rule cannot be parsed
+	ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+	// ErrRecurrenceRequiresDueDate is returned when making a todo without a due date recurring
+	ErrRecurrenceRequiresDueDate = errors.New("recurring todos need a due date")
+	// ErrNotRecurring is returned when a recurrence operation targets a one-off todo
+	ErrNotRecurring = errors.New("todo is not recurring")
+	// ErrHistoryUnavailable is returned when reading the past of todos from a store that does not keep it
+	ErrHistoryUnavailable = errors.New("todo history is not recorded by this store")
+)
+
+// Tag business logic errors
+var (
+	// ErrInvalidTagName is returned when tag name is empty or invalid
+	ErrInvalidTagName = errors.New("tag name cannot be empty")
+	// ErrInvalidTagColor is returned when tag color is not a #RRGGBB hex value
+	ErrInvalidTagColor = errors.New("tag color must be a #RRGGBB hex value")
+	// ErrTagNameDuplicate is returned when the owner already has a tag with the same name
+	ErrTagNameDuplicate = errors.New("tag name already exists")
+	// ErrTagOwnerMismatch is returned when a tag is used outside of its owner's namespace
+	ErrTagOwnerMismatch = errors.New("tag belongs to a different owner")
+	// ErrTagMergeIntoSelf is returned when merging a tag into itself
+	ErrTagMergeIntoSelf = errors.New("cannot merge a tag into itself")
+	// ErrInvalidTagMatch is returned when a tag match mode is neither "all" nor "any"
+	ErrInvalidTagMatch = errors.New("tag match must be all or any")
+)
+
+// List business logic errors
+var (
+	// ErrInvalidListName is returned when list name is empty or invalid
+	ErrInvalidListName = errors.New("list name cannot be empty")
+	// ErrListArchived is returned when modifying the todos of an archived list
+	ErrListArchived = errors.New("list is archived")
+	// ErrListNotEmpty is returned when deleting a list that still has todos in restrict mode
+	ErrListNotEmpty = errors.New("list still has todos")
+	// ErrListOwnerMismatch is returned when adding a todo owned by someone else to a list
+	ErrListOwnerMismatch = errors.New("todo belongs to a different owner than the list")
+	// ErrTodoNotInList is returned when a list operation targets a todo outside of the list
+	ErrTodoNotInList = errors.New("todo is not in the list")
+	// ErrInvalidListDeleteMode is returned when a delete mode is not detach, cascade or restrict
+	ErrInvalidListDeleteMode = errors.New("invalid list delete mode")
+	// ErrInvalidPosition is returned when a todo cannot be placed between the requested neighbours
+	ErrInvalidPosition = errors.New("invalid list position")
+	// ErrInvalidListRole is returned when a role cannot be granted to a collaborator
+	ErrInvalidListRole = errors.New("role must be editor or viewer")
+	// ErrAlreadyListMember is returned when inviting a user who already has access to the list
+	ErrAlreadyListMember = errors.New("user already has access to the list")
+	// ErrInvitationPending is returned when the user already has a pending invitation to the list
+	ErrInvitationPending = errors.New("user already has a pending invitation")
+	// ErrInvitationNotPending is returned when responding to an invitation that was already answered
+	ErrInvitationNotPending = errors.New("invitation is no longer pending")
+)
+
+// Comment business logic errors
+var (
+	// ErrInvalidCommentBody is returned when a comment body is empty
+	ErrInvalidCommentBody = errors.New("comment body cannot be empty")
+	// ErrInvalidParentComment is returned when replying to a comment on another todo
+	ErrInvalidParentComment = errors.New("parent comment must belong to the same todo")
+	// ErrCommentDeleted is returned when editing or replying to a deleted comment
+	ErrCommentDeleted = errors.New("comment was deleted")
+)
+
+// Attachment business logic errors
+var (
+	// ErrInvalidAttachmentName is returned when an uploaded file has no usable name
+	ErrInvalidAttachmentName = errors.New("attachment file name cannot be empty")
+	// ErrAttachmentTooLarge is returned when an upload exceeds the configured size limit
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrAttachmentTypeNotAllowed is returned when an upload's content type is not accepted
+	ErrAttachmentTypeNotAllowed = errors.New("attachment type is not allowed")
+)
+
+// Webhook business logic errors
+var (
+	// ErrInvalidWebhookURL is returned when a webhook URL is not an absolute http or https URL
+	ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https url")
+	// ErrInternalWebhookURL is returned when a webhook URL points at a loopback, private or link-local address
+	ErrInternalWebhookURL = errors.New("webhook url must not point to an internal address")
+	// ErrInvalidEventType is returned when subscribing to an unknown event type
+	ErrInvalidEventType = errors.New("unknown event type")
+	// ErrInvalidDeliveryStatus is returned when filtering deliveries by an unknown status
+	ErrInvalidDeliveryStatus = errors.New("invalid delivery status")
 )
 
 // User business logic errors
@@ -26,4 +138,11 @@ var (
 	ErrUsernameDuplicate = errors.New("username already exists")
 	// ErrInvalidEmail is returned when email format is invalid
 	ErrInvalidEmail = errors.New("invalid email format")
+	// ErrUsernameUnchanged is returned when renaming a user to their current username
+	ErrUsernameUnchanged = errors.New("new username is the same as the current one")
+	// ErrUsernameReserved is returned when a username was recently released by another user
+	ErrUsernameReserved = errors.New("username is reserved by a recent rename")
+	// ErrUsernameInTrash is returned when a username belongs to a user in the
+	// trash; it is released when the user is purged
+	ErrUsernameInTrash = errors.New("username belongs to a deleted user")
 )
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// TodoChange records a change to one field of a todo
+type TodoChange struct {
+	TodoID  int       `json:"todo_id" example:"1"`
+	ActorID *int      `json:"actor_id,omitempty" example:"1"`
+	Field   string    `json:"field" example:"priority"`
+	From    string    `json:"from" example:"medium"`
+	To      string    `json:"to" example:"high"`
+	At      time.Time `json:"at"`
+}
+
+// ActivityType identifies what happened in an activity feed entry
+type ActivityType string
+
+// Activity types
+const (
+	ActivityFieldChanged   ActivityType = "field_changed"
+	ActivityStatusChanged  ActivityType = "status_changed"
+	ActivityCommentAdded   ActivityType = "comment_added"
+	ActivityCommentEdited  ActivityType = "comment_edited"
+	ActivityCommentDeleted ActivityType = "comment_deleted"
+)
+
+// Activity is one entry of a todo's activity feed. Field and status changes
+// carry the old and new value; comment entries reference the comment.
+type Activity struct {
+	Type      ActivityType `json:"type" example:"field_changed"`
+	TodoID    int          `json:"todo_id" example:"1"`
+	ActorID   *int         `json:"actor_id,omitempty" example:"1"`
+	CommentID *int         `json:"comment_id,omitempty" example:"1"`
+	Field     string       `json:"field,omitempty" example:"priority"`
+	From      string       `json:"from,omitempty" example:"medium"`
+	To        string       `json:"to,omitempty" example:"high"`
+	At        time.Time    `json:"at"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"io"
+	"time"
+)
+
+// Attachment is a file attached to a todo. Its content is stored once per
+// distinct SHA-256 hash and shared by every attachment with the same content.
+type Attachment struct {
+	ID          int       `json:"id" example:"1"`
+	TodoID      int       `json:"todo_id" example:"1"`
+	UploaderID  *int      `json:"uploader_id,omitempty" example:"1"`
+	Filename    string    `json:"filename" example:"screenshot.png"`
+	ContentType string    `json:"content_type" example:"image/png"`
+	Size        int64     `json:"size" example:"48213"`
+	SHA256      string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BlobKey returns the storage key of the attachment's content
+func (a *Attachment) BlobKey() string {
+	return "sha256/" + a.SHA256[:2] + "/" + a.SHA256
+}
+
+// UploadAttachmentRequest represents an uploaded file. Content is read twice,
+// once to hash it and once to store it, so it must be seekable.
+type UploadAttachmentRequest struct {
+	Filename string
+	Size     int64
+	Content  io.ReadSeeker
+}
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// AuditAction is the kind of change recorded in the audit log
+type AuditAction string
+
+// Audit actions
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionPurge   AuditAction = "purge"
+)
+
+// Audited entity types
+const (
+	AuditEntityTodo = "todo"
+	AuditEntityUser = "user"
+)
+
+// FieldDiff holds the value of a field before and after a change
+type FieldDiff struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditEntry records one change to an entity. Entries form a hash chain: each
+// entry's hash covers its content and the previous entry's hash, so editing or
+// removing an entry breaks every hash after it.
+type AuditEntry struct {
+	Sequence   int64                `json:"sequence" example:"1"`
+	Timestamp  time.Time            `json:"timestamp"`
+	ActorID    *int                 `json:"actor_id,omitempty" example:"1"`
+	RequestID  string               `json:"request_id,omitempty" example:"4f1c2a9e8b7d6c5e4f3a2b1c0d9e8f7a"`
+	Action     AuditAction          `json:"action" example:"update"`
+	EntityType string               `json:"entity_type" example:"todo"`
+	EntityID   int                  `json:"entity_id" example:"1"`
+	Changes    map[string]FieldDiff `json:"changes"`
+	PrevHash   string               `json:"prev_hash"`
+	Hash       string               `json:"hash"`
+}
+
+// ComputeHash returns the SHA-256 of the entry's content, including PrevHash
+// but excluding Hash itself
+func (e *AuditEntry) ComputeHash() string {
+	content := *e
+	content.Hash = ""
+	data, _ := json.Marshal(&content)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Chain appends the entry after prev, or starts the chain when prev is nil
+func (e *AuditEntry) Chain(prev *AuditEntry) {
+	e.Sequence = 1
+	e.PrevHash = ""
+	if prev != nil {
+		e.Sequence = prev.Sequence + 1
+		e.PrevHash = prev.Hash
+	}
+	e.Hash = e.ComputeHash()
+}
+
+// AuditFilter selects audit entries; unset fields match every entry
+type AuditFilter struct {
+	ActorID    *int
+	EntityType string
+	EntityID   *int
+	Action     AuditAction
+	Since      *time.Time
+	Until      *time.Time
+	// Limit caps the number of entries returned, newest first; zero means no limit
+	Limit int
+}
+
+// Matches reports whether the entry satisfies every criterion of the filter
+func (f *AuditFilter) Matches(e *AuditEntry) bool {
+	if f.ActorID != nil && (e.ActorID == nil || *e.ActorID != *f.ActorID) {
+		return false
+	}
+	if f.EntityType != "" && e.EntityType != f.EntityType {
+		return false
+	}
+	if f.EntityID != nil && e.EntityID != *f.EntityID {
+		return false
+	}
+	if f.Action != "" && e.Action != f.Action {
+		return false
+	}
+	if f.Since != nil && e.Timestamp.Before(*f.Since) {
+		return false
+	}
+	if f.Until != nil && !e.Timestamp.Before(*f.Until) {
+		return false
+	}
+	return true
+}
+
+// AuditVerification reports whether the audit log's hash chain is intact
+type AuditVerification struct {
+	Valid   bool `json:"valid" example:"true"`
+	Entries int  `json:"entries" example:"42"`
+	// BrokenAt is the sequence of the first entry whose hash does not match
+	BrokenAt *int64 `json:"broken_at,omitempty" example:"17"`
+}
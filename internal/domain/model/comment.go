@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// CommentAuthor is the public profile of a comment's author
+type CommentAuthor struct {
+	ID       int    `json:"id" example:"1"`
+	Username string `json:"username" example:"johndoe"`
+	Name     string `json:"name" example:"John Doe"`
+}
+
+// Comment is a message in a todo's discussion. Replies point at the comment
+// they answer; deleted comments keep their place in the thread without a body.
+type Comment struct {
+	ID        int            `json:"id" example:"1"`
+	TodoID    int            `json:"todo_id" example:"1"`
+	ParentID  *int           `json:"parent_id,omitempty" example:"1"`
+	AuthorID  int            `json:"author_id" example:"1"`
+	Author    *CommentAuthor `json:"author,omitempty"`
+	Body      string         `json:"body" example:"Can @janedoe take a look?"`
+	Mentions  []int          `json:"mentions"`
+	Edited    bool           `json:"edited"`
+	Deleted   bool           `json:"deleted"`
+	Replies   []*Comment     `json:"replies,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt *time.Time     `json:"deleted_at,omitempty"`
+}
+
+// Clone returns a copy of the comment without its replies
+func (c *Comment) Clone() *Comment {
+	clone := *c
+	clone.Mentions = append([]int{}, c.Mentions...)
+	clone.Replies = nil
+	if c.ParentID != nil {
+		parentID := *c.ParentID
+		clone.ParentID = &parentID
+	}
+	if c.Author != nil {
+		author := *c.Author
+		clone.Author = &author
+	}
+	if c.DeletedAt != nil {
+		deletedAt := *c.DeletedAt
+		clone.DeletedAt = &deletedAt
+	}
+	return &clone
+}
+
+// CommentRevision records the body a comment had before an edit, or that the
+// comment was deleted. Deletions do not keep the body.
+type CommentRevision struct {
+	CommentID int       `json:"comment_id" example:"1"`
+	Body      string    `json:"body" example:"Can @jane take a look?"`
+	Deleted   bool      `json:"deleted"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// CreateCommentRequest represents the request to comment on a todo
+type CreateCommentRequest struct {
+	Body     string `json:"body" binding:"required" example:"Can @janedoe take a look?"`
+	ParentID *int   `json:"parent_id,omitempty" example:"1"`
+}
+
+// UpdateCommentRequest represents the request to edit a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required" example:"Can @janedoe take a look today?"`
+}
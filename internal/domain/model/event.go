@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// EventType names a kind of domain event
+type EventType string
+
+// Todo events
+const (
+	EventTodoCreated   EventType = "todo.created"
+	EventTodoUpdated   EventType = "todo.updated"
+	EventTodoCompleted EventType = "todo.completed"
+	EventTodoDeleted   EventType = "todo.deleted"
+	EventTodoRestored  EventType = "todo.restored"
+	EventTodoPurged    EventType = "todo.purged"
+)
+
+// User events
+const (
+	EventUserRegistered EventType = "user.registered"
+	EventUserUpdated    EventType = "user.updated"
+	EventUserRenamed    EventType = "user.renamed"
+	EventUserDeleted    EventType = "user.deleted"
+	EventUserRestored   EventType = "user.restored"
+	EventUserPurged     EventType = "user.purged"
+)
+
+// List membership events
+const (
+	EventListMemberAdded   EventType = "list.member_added"
+	EventListMemberUpdated EventType = "list.member_updated"
+	EventListMemberRemoved EventType = "list.member_removed"
+)
+
+// IsValid reports whether t is one of the known event types
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventTodoCreated, EventTodoUpdated, EventTodoCompleted, EventTodoDeleted, EventTodoRestored, EventTodoPurged,
+		EventUserRegistered, EventUserUpdated, EventUserRenamed, EventUserDeleted, EventUserRestored, EventUserPurged,
+		EventListMemberAdded, EventListMemberUpdated, EventListMemberRemoved:
+		return true
+	default:
+		return false
+	}
+}
+
+// Aggregate types events are published for
+const (
+	AggregateTodo = "todo"
+	AggregateUser = "user"
+	AggregateList = "list"
+)
+
+// Event records something that happened to an aggregate. It carries a
+// snapshot of the aggregate as it was after the change; for purge events,
+// as it was before removal. Membership events carry the member of the list
+// they are about. The ID stays the same when an event is delivered
+// again, so consumers use it to drop duplicates.
+type Event struct {
+	ID            string    `json:"id" example:"0f8b5c1e9d7a4b3c2e1f0a9b8c7d6e5f"`
+	Type          EventType `json:"type" example:"todo.completed"`
+	AggregateType string    `json:"aggregate_type" example:"todo"`
+	AggregateID   int       `json:"aggregate_id" example:"1"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	ActorID       *int      `json:"actor_id,omitempty" example:"1"`
+	RequestID     string    `json:"request_id,omitempty"`
+	Todo          *Todo     `json:"todo,omitempty"`
+	// PreviousListID is the list an update moved the todo out of
+	PreviousListID *int        `json:"previous_list_id,omitempty" example:"1"`
+	User           *User       `json:"user,omitempty"`
+	Member         *ListMember `json:"member,omitempty"`
+}
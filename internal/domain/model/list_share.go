@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// ListRole is the access level a user has on a todo list
+type ListRole string
+
+// List roles, from least to most privileged
+const (
+	ListRoleViewer ListRole = "viewer"
+	ListRoleEditor ListRole = "editor"
+	ListRoleOwner  ListRole = "owner"
+)
+
+// rank orders roles by privilege; unknown roles rank lowest
+func (r ListRole) rank() int {
+	switch r {
+	case ListRoleViewer:
+		return 1
+	case ListRoleEditor:
+		return 2
+	case ListRoleOwner:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Includes reports whether r grants at least the privileges of other
+func (r ListRole) Includes(other ListRole) bool {
+	return r.rank() > 0 && r.rank() >= other.rank()
+}
+
+// IsShareable reports whether the role can be granted to a collaborator.
+// Ownership is not transferable through sharing.
+func (r ListRole) IsShareable() bool {
+	return r == ListRoleViewer || r == ListRoleEditor
+}
+
+// ListMember grants a collaborator access to a list they do not own
+type ListMember struct {
+	ListID  int       `json:"list_id" example:"1"`
+	UserID  int       `json:"user_id" example:"2"`
+	Role    ListRole  `json:"role" example:"editor"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// InvitationStatus is the state of a list invitation
+type InvitationStatus string
+
+// Invitation states
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+)
+
+// ListInvitation invites a user to collaborate on a list
+type ListInvitation struct {
+	ID              int              `json:"id" example:"1"`
+	ListID          int              `json:"list_id" example:"1"`
+	InviterID       int              `json:"inviter_id" example:"1"`
+	InviteeID       int              `json:"invitee_id" example:"2"`
+	InviteeUsername string           `json:"invitee_username" example:"janedoe"`
+	Role            ListRole         `json:"role" example:"editor"`
+	Status          InvitationStatus `json:"status" example:"pending"`
+	CreatedAt       time.Time        `json:"created_at"`
+	RespondedAt     *time.Time       `json:"responded_at,omitempty"`
+}
+
+// InviteToListRequest represents the request to invite a user to a list
+type InviteToListRequest struct {
+	Username string   `json:"username" binding:"required" example:"janedoe"`
+	Role     ListRole `json:"role" binding:"required" example:"editor"`
+}
+
+// UpdateListMemberRequest represents the request to change a collaborator's role
+type UpdateListMemberRequest struct {
+	Role ListRole `json:"role" binding:"required" example:"viewer"`
+}
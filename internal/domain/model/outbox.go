@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// OutboxEntry is a domain event waiting in the outbox to be relayed to the
+// event publisher. Entries are relayed at least once, so consumers should use
+// the event ID to drop duplicates.
+type OutboxEntry struct {
+	ID            int        `json:"id" example:"1"`
+	Event         Event      `json:"event"`
+	Attempts      int        `json:"attempts" example:"0"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+}
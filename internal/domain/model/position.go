@@ -0,0 +1,82 @@
+package model
+
+import (
+	"strings"
+
+	"go-boilerplate/internal/domain"
+)
+
+// positionDigits are the base-62 digits used in position keys, in ascending order
+const positionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// PositionBetween returns a position key that sorts strictly between before and after.
+// An empty before means the start of the list and an empty after means the end.
+// Keys are compared as plain strings, so an item can be moved by assigning it a
+// single new key without renumbering its neighbours.
+func PositionBetween(before, after string) (string, error) {
+	if after != "" && before >= after {
+		return "", domain.ErrInvalidPosition
+	}
+	if !validPosition(before) || !validPosition(after) {
+		return "", domain.ErrInvalidPosition
+	}
+	return positionMidpoint(before, after), nil
+}
+
+// validPosition reports whether key only uses position digits and does not end
+// with the lowest digit, which would leave no room before it
+func validPosition(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(positionDigits, key[i]) < 0 {
+			return false
+		}
+	}
+	return key == "" || key[len(key)-1] != positionDigits[0]
+}
+
+// positionMidpoint computes a key between a and b, where b may be empty for "no upper bound"
+func positionMidpoint(a, b string) string {
+	if b != "" {
+		// Skip the common prefix, treating missing digits of a as the lowest digit
+		n := 0
+		for n < len(b) {
+			digit := positionDigits[0]
+			if n < len(a) {
+				digit = a[n]
+			}
+			if digit != b[n] {
+				break
+			}
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + positionMidpoint(rest, b[n:])
+		}
+	}
+
+	low := 0
+	if a != "" {
+		low = strings.IndexByte(positionDigits, a[0])
+	}
+	high := len(positionDigits)
+	if b != "" {
+		high = strings.IndexByte(positionDigits, b[0])
+	}
+
+	if high-low > 1 {
+		return string(positionDigits[(low+high)/2])
+	}
+	// The first digits are consecutive: a shorter prefix of b may already fit
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if a != "" {
+		rest = a[1:]
+	}
+	return string(positionDigits[low]) + positionMidpoint(rest, "")
+}
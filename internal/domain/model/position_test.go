@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"go-boilerplate/internal/domain"
+)
+
+func TestPositionBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  string
+		after   string
+		want    string
+		wantErr error
+	}{
+		{name: "empty list", want: "V"},
+		{name: "append", before: "V", want: "k"},
+		{name: "prepend", after: "V", want: "F"},
+		{name: "between", before: "F", after: "V", want: "N"},
+		{name: "adjacent digits", before: "A", after: "B", want: "AV"},
+		{name: "after the highest digit", before: "z", want: "zV"},
+		{name: "before the lowest usable key", after: "1", want: "0V"},
+		{name: "shared prefix", before: "AB", after: "AD", want: "AC"},
+		{name: "shorter prefix fits", before: "A", after: "Bz", want: "B"},
+		{name: "equal keys", before: "V", after: "V", wantErr: domain.ErrInvalidPosition},
+		{name: "reversed keys", before: "k", after: "V", wantErr: domain.ErrInvalidPosition},
+		{name: "invalid digit", before: "V-", wantErr: domain.ErrInvalidPosition},
+		{name: "trailing lowest digit", after: "V0", wantErr: domain.ErrInvalidPosition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PositionBetween(tt.before, tt.after)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PositionBetween(%q, %q) error = %v, want %v", tt.before, tt.after, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PositionBetween(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+			}
+			if got <= tt.before || (tt.after != "" && got >= tt.after) {
+				t.Errorf("PositionBetween(%q, %q) = %q is out of order", tt.before, tt.after, got)
+			}
+		})
+	}
+}
+
+func TestPositionBetweenRepeatedInserts(t *testing.T) {
+	tests := []struct {
+		name string
+		// slot picks where to insert into a list of n keys
+		slot func(n int) int
+	}{
+		{name: "always first", slot: func(n int) int { return 0 }},
+		{name: "always last", slot: func(n int) int { return n }},
+		{name: "always second", slot: func(n int) int { return min(n, 1) }},
+		{name: "always middle", slot: func(n int) int { return n / 2 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			for i := range 500 {
+				slot := tt.slot(len(keys))
+				before, after := "", ""
+				if slot > 0 {
+					before = keys[slot-1]
+				}
+				if slot < len(keys) {
+					after = keys[slot]
+				}
+				key, err := PositionBetween(before, after)
+				if err != nil {
+					t.Fatalf("insert %d between %q and %q: %v", i, before, after, err)
+				}
+				if key <= before || (after != "" && key >= after) || !validPosition(key) {
+					t.Fatalf("insert %d between %q and %q = %q", i, before, after, key)
+				}
+				keys = append(keys[:slot], append([]string{key}, keys[slot:]...)...)
+			}
+		})
+	}
+}
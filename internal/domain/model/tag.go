@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// Tag represents a label that can be attached to todos.
+// Tag names are unique within the namespace of their owner.
+type Tag struct {
+	ID        int       `json:"id" example:"1"`
+	OwnerID   int       `json:"owner_id" example:"1"`
+	Name      string    `json:"name" example:"work"`
+	Color     string    `json:"color,omitempty" example:"#ff8800"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagMatch controls how multiple tags in a todo query are combined
+type TagMatch string
+
+// Tag match modes
+const (
+	// TagMatchAll requires a todo to carry every requested tag
+	TagMatchAll TagMatch = "all"
+	// TagMatchAny requires a todo to carry at least one requested tag
+	TagMatchAny TagMatch = "any"
+)
+
+// CreateTagRequest represents the request to create a new tag
+type CreateTagRequest struct {
+	OwnerID int    `json:"owner_id" example:"1"`
+	Name    string `json:"name" binding:"required" example:"work"`
+	Color   string `json:"color" example:"#ff8800"`
+}
+
+// UpdateTagRequest represents the request to rename or recolor a tag
+type UpdateTagRequest struct {
+	Name  string `json:"name" example:"office"`
+	Color string `json:"color" example:"#0088ff"`
+}
+
+// MergeTagsRequest represents the request to merge a tag into another one
+type MergeTagsRequest struct {
+	TargetID int `json:"target_id" binding:"required" example:"2"`
+}
@@ -1,22 +1,216 @@
 package model
 
+import (
+	"slices"
+	"time"
+)
+
+// Priority represents how urgent a todo is
+type Priority string
+
+// Priority levels, from least to most urgent
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+// IsValid reports whether p is one of the known priority levels
+func (p Priority) IsValid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	default:
+		return false
+	}
+}
+
 // Todo represents a todo item in the domain
 type Todo struct {
-	ID          int    `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
+	ID       int  `json:"id"`
+	OwnerID  int  `json:"owner_id" example:"1"`
+	ParentID *int `json:"parent_id,omitempty" example:"1"`
+	ListID   *int `json:"list_id,omitempty" example:"1"`
+	// Position orders the todo within its list; keys compare as plain strings
+	Position    string     `json:"position,omitempty" example:"V"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`
+	Status      TodoStatus `json:"status" example:"open"`
+	Priority    Priority   `json:"priority" example:"medium"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	// Timezone is the IANA zone the due date was set in, e.g. "Asia/Seoul"
+	Timezone   string          `json:"timezone,omitempty" example:"Asia/Seoul"`
+	TagIDs     []int           `json:"tag_ids"`
+	Checklist  []ChecklistItem `json:"checklist"`
+	Recurrence *Recurrence     `json:"recurrence,omitempty"`
+	// Progress is the completed fraction of the todo's subtasks and checklist,
+	// computed when the todo is read
+	Progress    float64    `json:"progress" example:"0.5"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// DeletedAt is set while the todo is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// IsOverdue reports whether the todo is still open past its due date
+func (t *Todo) IsOverdue(now time.Time) bool {
+	return !t.Status.IsClosed() && t.DueDate != nil && t.DueDate.Before(now)
+}
+
+// Clone returns a deep copy of the todo
+func (t *Todo) Clone() *Todo {
+	clone := *t
+	if t.TagIDs != nil {
+		clone.TagIDs = append(make([]int, 0, len(t.TagIDs)), t.TagIDs...)
+	}
+	if t.Checklist != nil {
+		clone.Checklist = append(make([]ChecklistItem, 0, len(t.Checklist)), t.Checklist...)
+	}
+	if t.Recurrence != nil {
+		clone.Recurrence = t.Recurrence.Clone()
+	}
+	return &clone
+}
+
+// HasTag reports whether the tag is attached to the todo
+func (t *Todo) HasTag(tagID int) bool {
+	for _, id := range t.TagIDs {
+		if id == tagID {
+			return true
+		}
+	}
+	return false
 }
 
 // CreateTodoRequest represents the request to create a new todo
 type CreateTodoRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
+	// OwnerID is only used without an acting user; otherwise the actor owns the todo
+	OwnerID     int        `json:"owner_id" example:"1"`
+	ParentID    *int       `json:"parent_id" example:"1"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	Priority    Priority   `json:"priority" example:"medium"`
+	DueDate     *time.Time `json:"due_date"`
+	Timezone    string     `json:"timezone" example:"Asia/Seoul"`
+	// AllowPastDueDate permits a due date earlier than the creation time
+	AllowPastDueDate bool `json:"allow_past_due_date"`
+	// Recurrence makes the todo repeat, starting at its due date
+	Recurrence *RecurrenceRequest `json:"recurrence"`
 }
 
 // UpdateTodoRequest represents the request to update an existing todo
 type UpdateTodoRequest struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
-	Completed   bool   `json:"completed"`
+	// Completed completes or reopens the todo; nil leaves it unchanged
+	Completed *bool      `json:"completed"`
+	Priority  Priority   `json:"priority" example:"high"`
+	DueDate   *time.Time `json:"due_date"`
+	Timezone  string     `json:"timezone" example:"Asia/Seoul"`
+	// ClearDueDate removes the due date and timezone
+	ClearDueDate bool `json:"clear_due_date"`
+	// ParentID moves the todo under another todo
+	ParentID *int `json:"parent_id" example:"1"`
+	// ClearParent turns a subtask back into a top-level todo
+	ClearParent bool `json:"clear_parent"`
+	// AllowPastDueDate permits a due date earlier than the creation time
+	AllowPastDueDate bool `json:"allow_past_due_date"`
+}
+
+// TodoFilter narrows down the todos returned by a list query.
+// Zero-valued fields do not filter.
+type TodoFilter struct {
+	OwnerID  *int
+	ParentID *int
+	// ParentIDs selects the subtasks of any of the given todos
+	ParentIDs []int
+	ListID    *int
+	Completed *bool
+	Status    TodoStatus
+	Overdue   *bool
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	Priority  Priority
+	// Tags are the tag names requested by the caller, resolved by the service into TagIDSets
+	Tags []string
+	// TagIDSets holds, for each requested tag name, the IDs of the tags carrying that name.
+	// A todo satisfies a set when it carries any tag of the set.
+	TagIDSets [][]int
+	TagMatch  TagMatch
+	// Now is the reference time for the Overdue filter
+	Now time.Time
+	// Deleted selects todos in the trash instead of live todos
+	Deleted bool
+}
+
+// Matches reports whether the todo satisfies every criterion of the filter
+func (f *TodoFilter) Matches(todo *Todo) bool {
+	if (todo.DeletedAt != nil) != f.Deleted {
+		return false
+	}
+	if f.OwnerID != nil && todo.OwnerID != *f.OwnerID {
+		return false
+	}
+	if f.ParentID != nil && (todo.ParentID == nil || *todo.ParentID != *f.ParentID) {
+		return false
+	}
+	if len(f.ParentIDs) > 0 && (todo.ParentID == nil || !slices.Contains(f.ParentIDs, *todo.ParentID)) {
+		return false
+	}
+	if f.ListID != nil && (todo.ListID == nil || *todo.ListID != *f.ListID) {
+		return false
+	}
+	if f.Completed != nil && todo.Completed != *f.Completed {
+		return false
+	}
+	if f.Status != "" && todo.Status != f.Status {
+		return false
+	}
+	if f.Overdue != nil && todo.IsOverdue(f.Now) != *f.Overdue {
+		return false
+	}
+	if f.DueBefore != nil && (todo.DueDate == nil || !todo.DueDate.Before(*f.DueBefore)) {
+		return false
+	}
+	if f.DueAfter != nil && (todo.DueDate == nil || !todo.DueDate.After(*f.DueAfter)) {
+		return false
+	}
+	if f.Priority != "" && todo.Priority != f.Priority {
+		return false
+	}
+	if len(f.TagIDSets) > 0 && !f.matchesTags(todo) {
+		return false
+	}
+	return true
+}
+
+// matchesTags applies the tag criteria according to TagMatch
+func (f *TodoFilter) matchesTags(todo *Todo) bool {
+	hasAny := func(ids []int) bool {
+		for _, id := range ids {
+			if todo.HasTag(id) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if f.TagMatch == TagMatchAny {
+		for _, ids := range f.TagIDSets {
+			if hasAny(ids) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, ids := range f.TagIDSets {
+		if !hasAny(ids) {
+			return false
+		}
+	}
+	return true
 }
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TodoEventType names a kind of stored todo event
+type TodoEventType string
+
+// Stored todo events
+const (
+	TodoEventCreated  TodoEventType = "created"
+	TodoEventUpdated  TodoEventType = "updated"
+	TodoEventDeleted  TodoEventType = "deleted"
+	TodoEventRestored TodoEventType = "restored"
+)
+
+// TodoEvent is an entry in the append-only stream of an event-sourced todo.
+// Replaying a todo's events in version order rebuilds its state.
+type TodoEvent struct {
+	TodoID  int           `json:"todo_id" example:"1"`
+	Version int           `json:"version" example:"3"`
+	Type    TodoEventType `json:"type" example:"updated"`
+	// Changes holds the fields the event set, keyed by their JSON name;
+	// a null value clears the field
+	Changes    map[string]json.RawMessage `json:"changes" swaggertype:"object"`
+	ActorID    *int                       `json:"actor_id,omitempty" example:"1"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// TodoSnapshot is the state of an event-sourced todo after a version, kept so
+// rebuilding the todo only replays the events that came after it
+type TodoSnapshot struct {
+	Version    int       `json:"version"`
+	Todo       *Todo     `json:"todo"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
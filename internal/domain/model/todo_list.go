@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// TodoList groups todos into a project with its own ordering
+type TodoList struct {
+	ID          int        `json:"id" example:"1"`
+	OwnerID     int        `json:"owner_id" example:"1"`
+	Name        string     `json:"name" example:"Home renovation"`
+	Description string     `json:"description" example:"Everything for the new kitchen"`
+	Archived    bool       `json:"archived"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ListDeleteMode decides what happens to member todos when a list is deleted
+type ListDeleteMode string
+
+// List delete modes
+const (
+	// ListDeleteDetach keeps member todos and removes them from the list
+	ListDeleteDetach ListDeleteMode = "detach"
+	// ListDeleteCascade deletes member todos together with the list
+	ListDeleteCascade ListDeleteMode = "cascade"
+	// ListDeleteRestrict refuses to delete a list that still has todos
+	ListDeleteRestrict ListDeleteMode = "restrict"
+)
+
+// IsValid reports whether m is one of the known delete modes
+func (m ListDeleteMode) IsValid() bool {
+	switch m {
+	case ListDeleteDetach, ListDeleteCascade, ListDeleteRestrict:
+		return true
+	default:
+		return false
+	}
+}
+
+// CreateTodoListRequest represents the request to create a new list.
+// The list is owned by the acting user.
+type CreateTodoListRequest struct {
+	Name        string `json:"name" binding:"required" example:"Home renovation"`
+	Description string `json:"description" example:"Everything for the new kitchen"`
+}
+
+// UpdateTodoListRequest represents the request to update an existing list
+type UpdateTodoListRequest struct {
+	Name        string `json:"name" example:"Kitchen renovation"`
+	Description string `json:"description" example:"New cabinets and appliances"`
+}
+
+// MoveTodoRequest represents the request to reorder a todo within its list.
+// The todo is placed after AfterID and before BeforeID; omitting both moves it to the end.
+type MoveTodoRequest struct {
+	AfterID  *int `json:"after_id" example:"3"`
+	BeforeID *int `json:"before_id" example:"4"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// Recurrence makes a todo repeat according to an RFC 5545 recurrence rule.
+// Completing an occurrence generates the todo for the next one.
+type Recurrence struct {
+	// RRule is the recurrence rule without DTSTART, e.g. "FREQ=WEEKLY;BYDAY=SA;COUNT=10"
+	RRule string `json:"rrule" example:"FREQ=WEEKLY;BYDAY=SA"`
+	// Start is the first occurrence of the series (DTSTART), expanded in the todo's timezone
+	Start time.Time `json:"start"`
+	// ExceptionDates are calendar dates on which no occurrence is generated
+	ExceptionDates []time.Time `json:"exception_dates"`
+	// NextTodoID is the todo generated when this occurrence was completed
+	NextTodoID *int `json:"next_todo_id,omitempty" example:"2"`
+}
+
+// Clone returns a deep copy of the recurrence
+func (r *Recurrence) Clone() *Recurrence {
+	clone := *r
+	if r.ExceptionDates != nil {
+		clone.ExceptionDates = append(make([]time.Time, 0, len(r.ExceptionDates)), r.ExceptionDates...)
+	}
+	if r.NextTodoID != nil {
+		nextTodoID := *r.NextTodoID
+		clone.NextTodoID = &nextTodoID
+	}
+	return &clone
+}
+
+// RecurrenceRequest represents the request to make a todo recurring
+type RecurrenceRequest struct {
+	RRule          string      `json:"rrule" binding:"required" example:"FREQ=WEEKLY;BYDAY=SA"`
+	ExceptionDates []time.Time `json:"exception_dates"`
+}
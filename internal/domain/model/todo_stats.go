@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// TodoStatsFilter narrows dashboard statistics to one owner's todos. Users
+// other than administrators only ever see their own.
+type TodoStatsFilter struct {
+	OwnerID *int
+}
+
+// TodoStats are dashboard counts over the live todos, served from a read model
+// that is updated from todo events and may trail the latest changes slightly
+type TodoStats struct {
+	Total    int                `json:"total" example:"12"`
+	ByStatus map[TodoStatus]int `json:"by_status"`
+	ByOwner  map[int]int        `json:"by_owner"`
+	ByTag    map[int]int        `json:"by_tag"`
+	// Overdue counts open todos whose due date has passed
+	Overdue    int              `json:"overdue" example:"2"`
+	Projection ProjectionStatus `json:"projection"`
+}
+
+// ProjectionStatus reports how far a read model has caught up with the events
+// it is built from
+type ProjectionStatus struct {
+	Name          string     `json:"name" example:"todo_stats"`
+	EventsApplied int64      `json:"events_applied" example:"42"`
+	LastEventID   string     `json:"last_event_id,omitempty"`
+	LastEventAt   *time.Time `json:"last_event_at,omitempty"`
+	LastAppliedAt *time.Time `json:"last_applied_at,omitempty"`
+	RebuiltAt     *time.Time `json:"rebuilt_at,omitempty"`
+	// EventsPending counts the events published but not yet applied
+	EventsPending int `json:"events_pending" example:"0"`
+	// LagSeconds is how long the oldest pending event has waited, or how long
+	// the last applied event took to reach the read model when none is pending
+	LagSeconds float64 `json:"lag_seconds" example:"0.4"`
+}
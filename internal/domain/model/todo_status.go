@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// TodoStatus represents a state in the todo lifecycle
+type TodoStatus string
+
+// Todo lifecycle states
+const (
+	TodoStatusOpen       TodoStatus = "open"
+	TodoStatusInProgress TodoStatus = "in_progress"
+	TodoStatusBlocked    TodoStatus = "blocked"
+	TodoStatusDone       TodoStatus = "done"
+	TodoStatusCancelled  TodoStatus = "cancelled"
+)
+
+// IsValid reports whether s is one of the known lifecycle states
+func (s TodoStatus) IsValid() bool {
+	switch s {
+	case TodoStatusOpen, TodoStatusInProgress, TodoStatusBlocked, TodoStatusDone, TodoStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsClosed reports whether no more work is expected in this state
+func (s TodoStatus) IsClosed() bool {
+	return s == TodoStatusDone || s == TodoStatusCancelled
+}
+
+// TodoStateMachine describes which lifecycle transitions are allowed
+type TodoStateMachine struct {
+	transitions map[TodoStatus]map[TodoStatus]bool
+}
+
+// NewTodoStateMachine creates a state machine allowing exactly the given transitions,
+// keyed by source state
+func NewTodoStateMachine(transitions map[TodoStatus][]TodoStatus) *TodoStateMachine {
+	sm := &TodoStateMachine{transitions: make(map[TodoStatus]map[TodoStatus]bool)}
+	for from, targets := range transitions {
+		sm.transitions[from] = make(map[TodoStatus]bool, len(targets))
+		for _, to := range targets {
+			sm.transitions[from][to] = true
+		}
+	}
+	return sm
+}
+
+// DefaultTodoStateMachine returns the standard todo workflow. Closed todos can
+// only be reopened, and blocked todos must be unblocked before being finished.
+func DefaultTodoStateMachine() *TodoStateMachine {
+	return NewTodoStateMachine(map[TodoStatus][]TodoStatus{
+		TodoStatusOpen:       {TodoStatusInProgress, TodoStatusBlocked, TodoStatusDone, TodoStatusCancelled},
+		TodoStatusInProgress: {TodoStatusOpen, TodoStatusBlocked, TodoStatusDone, TodoStatusCancelled},
+		TodoStatusBlocked:    {TodoStatusOpen, TodoStatusInProgress, TodoStatusCancelled},
+		TodoStatusDone:       {TodoStatusOpen},
+		TodoStatusCancelled:  {TodoStatusOpen},
+	})
+}
+
+// CanTransition reports whether moving from one state to another is allowed
+func (sm *TodoStateMachine) CanTransition(from, to TodoStatus) bool {
+	return sm.transitions[from][to]
+}
+
+// TodoTransition records a single lifecycle change of a todo
+type TodoTransition struct {
+	TodoID int        `json:"todo_id" example:"1"`
+	From   TodoStatus `json:"from" example:"open"`
+	To     TodoStatus `json:"to" example:"in_progress"`
+	Reason string     `json:"reason,omitempty" example:"Started working on it"`
+	At     time.Time  `json:"at"`
+}
+
+// TransitionTodoRequest represents the request to move a todo to another state
+type TransitionTodoRequest struct {
+	To     TodoStatus `json:"to" binding:"required" example:"in_progress"`
+	Reason string     `json:"reason" example:"Started working on it"`
+}
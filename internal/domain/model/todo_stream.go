@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// TodoStreamEventType is the kind of change pushed to live todo streams
+type TodoStreamEventType string
+
+// Todo stream event types. A reset tells the client that changes it missed are no
+// longer buffered, or that it joined or left a list and so sees different
+// todos; either way it should refetch its todos and resume from the reset.
+const (
+	TodoStreamCreated TodoStreamEventType = "created"
+	TodoStreamUpdated TodoStreamEventType = "updated"
+	TodoStreamDeleted TodoStreamEventType = "deleted"
+	TodoStreamReset   TodoStreamEventType = "reset"
+)
+
+// TodoStreamEvent is a change to a todo as pushed to live streams. IDs increase
+// with every change, so a client resumes by sending the last ID it received.
+type TodoStreamEvent struct {
+	ID         int                 `json:"id" example:"42"`
+	Type       TodoStreamEventType `json:"type" example:"updated"`
+	TodoID     int                 `json:"todo_id,omitempty" example:"1"`
+	Todo       *Todo               `json:"todo,omitempty"`
+	OccurredAt time.Time           `json:"occurred_at"`
+}
@@ -0,0 +1,43 @@
+package model
+
+// SubtaskRule controls how a parent todo's completion relates to its subtasks
+type SubtaskRule string
+
+// Subtask rules
+const (
+	// SubtaskRuleNone lets parents and subtasks be completed independently
+	SubtaskRuleNone SubtaskRule = "none"
+	// SubtaskRuleAutoComplete completes the parent once all of its subtasks are closed
+	SubtaskRuleAutoComplete SubtaskRule = "auto_complete_parent"
+	// SubtaskRuleRequireClosed prevents completing a parent while a subtask is
+	// still open, and keeps open subtasks from sitting below a closed parent
+	SubtaskRuleRequireClosed SubtaskRule = "require_closed_subtasks"
+)
+
+// IsValid reports whether r is one of the known subtask rules
+func (r SubtaskRule) IsValid() bool {
+	switch r {
+	case SubtaskRuleNone, SubtaskRuleAutoComplete, SubtaskRuleRequireClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// ChecklistItem is a lightweight step inside a todo
+type ChecklistItem struct {
+	ID      int    `json:"id" example:"1"`
+	Text    string `json:"text" example:"Write the summary"`
+	Checked bool   `json:"checked"`
+}
+
+// CreateChecklistItemRequest represents the request to add a checklist item
+type CreateChecklistItemRequest struct {
+	Text string `json:"text" binding:"required" example:"Write the summary"`
+}
+
+// UpdateChecklistItemRequest represents the request to edit or check a checklist item
+type UpdateChecklistItemRequest struct {
+	Text    string `json:"text" example:"Write the executive summary"`
+	Checked *bool  `json:"checked"`
+}
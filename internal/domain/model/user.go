@@ -1,11 +1,24 @@
 package model
 
+import "time"
+
 // User represents a user in the domain
 type User struct {
 	ID       int    `json:"id" example:"1"`
 	Username string `json:"username" example:"johndoe"`
 	Email    string `json:"email" example:"john@example.com"`
 	Name     string `json:"name" example:"John Doe"`
+	// DeletedAt is set while the user is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// UsernameHistory records a username a user previously held. The old name keeps
+// resolving to the user and stays reserved until ExpiresAt.
+type UsernameHistory struct {
+	Username  string    `json:"username" example:"johndoe"`
+	UserID    int       `json:"user_id" example:"1"`
+	ChangedAt time.Time `json:"changed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // CreateUserRequest represents the request to create a new user
@@ -20,3 +33,8 @@ type UpdateUserRequest struct {
 	Email string `json:"email" example:"john.new@example.com"`
 	Name  string `json:"name" example:"John Smith"`
 }
+
+// RenameUserRequest represents the request to change a user's username
+type RenameUserRequest struct {
+	Username string `json:"username" binding:"required" example:"john.doe"`
+}
@@ -0,0 +1,139 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Webhook delivery headers
+const (
+	WebhookHeaderID        = "X-Webhook-ID"
+	WebhookHeaderEvent     = "X-Webhook-Event"
+	WebhookHeaderDelivery  = "X-Webhook-Delivery"
+	WebhookHeaderTimestamp = "X-Webhook-Timestamp"
+	WebhookHeaderSignature = "X-Webhook-Signature"
+)
+
+// Webhook subscribes a URL to the events of its owner's todos, account and list memberships
+type Webhook struct {
+	ID      int    `json:"id" example:"1"`
+	OwnerID int    `json:"owner_id" example:"1"`
+	URL     string `json:"url" example:"https://example.com/hooks/todos"`
+	// EventTypes lists the subscribed events; empty subscribes to all of them
+	EventTypes []EventType `json:"event_types"`
+	// Secret signs deliveries. It is only returned when the webhook is created.
+	Secret string `json:"secret,omitempty" example:"s3cr3t"`
+	Active bool   `json:"active" example:"true"`
+	// ConsecutiveFailures counts failed attempts since the last successful
+	// delivery; the webhook is disabled when it reaches the configured limit
+	ConsecutiveFailures int        `json:"consecutive_failures" example:"0"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason      string     `json:"disabled_reason,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// Wants reports whether the webhook is subscribed to the event type
+func (w *Webhook) Wants(eventType EventType) bool {
+	return len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), as internal as the private ranges
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// IsInternalAddress reports whether an address is one webhooks must not
+// reach, so that they cannot be used to probe the server's own network:
+// loopback, private, link-local, unspecified and multicast addresses
+func IsInternalAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return !addr.IsValid() ||
+		addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		sharedAddressSpace.Contains(addr)
+}
+
+// SignWebhookPayload returns the signature of a delivery: the hex HMAC-SHA256,
+// keyed with the webhook secret, of the Unix timestamp, a dot and the body
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+// Delivery states. Dead deliveries gave up after the maximum number of
+// attempts and form the dead-letter queue; they can be replayed.
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// IsValid reports whether s is one of the known delivery states
+func (s DeliveryStatus) IsValid() bool {
+	return s == DeliveryPending || s == DeliverySucceeded || s == DeliveryDead
+}
+
+// DeliveryAttempt logs one try to deliver an event
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty" example:"500"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms" example:"120"`
+}
+
+// WebhookDelivery is an event on its way to a webhook, with the log of every attempt
+type WebhookDelivery struct {
+	ID        int             `json:"id" example:"1"`
+	WebhookID int             `json:"webhook_id" example:"1"`
+	EventID   string          `json:"event_id"`
+	EventType EventType       `json:"event_type" example:"todo.completed"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Status    DeliveryStatus  `json:"status" example:"pending"`
+	// ReplayOf is the delivery this one was replayed from
+	ReplayOf      *int              `json:"replay_of,omitempty" example:"1"`
+	Attempts      []DeliveryAttempt `json:"attempts"`
+	NextAttemptAt time.Time         `json:"next_attempt_at"`
+	CreatedAt     time.Time         `json:"created_at"`
+	DeliveredAt   *time.Time        `json:"delivered_at,omitempty"`
+}
+
+// Clone returns a copy of the delivery that shares no attempt log with the original
+func (d *WebhookDelivery) Clone() *WebhookDelivery {
+	clone := *d
+	clone.Attempts = append(make([]DeliveryAttempt, 0, len(d.Attempts)), d.Attempts...)
+	return &clone
+}
+
+// CreateWebhookRequest represents the request to create a webhook
+type CreateWebhookRequest struct {
+	URL        string      `json:"url" binding:"required" example:"https://example.com/hooks/todos"`
+	EventTypes []EventType `json:"event_types" example:"todo.created,todo.completed"`
+	// Secret signs deliveries; one is generated when empty
+	Secret string `json:"secret" example:"s3cr3t"`
+}
+
+// UpdateWebhookRequest represents the request to update a webhook. Activating
+// a disabled webhook resets its failure count.
+type UpdateWebhookRequest struct {
+	URL string `json:"url" example:"https://example.com/hooks/todos"`
+	// EventTypes replaces the subscribed events when present; an empty list subscribes to all
+	EventTypes []EventType `json:"event_types" example:"todo.completed"`
+	Secret     string      `json:"secret" example:"n3w-s3cr3t"`
+	Active     *bool       `json:"active" example:"true"`
+}
@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// AttachmentRepositoryPort defines the interface for attachment metadata persistence
+type AttachmentRepositoryPort interface {
+	Create(ctx context.Context, attachment *model.Attachment) error
+	GetByID(ctx context.Context, id int) (*model.Attachment, error)
+	ListByTodo(ctx context.Context, todoID int) ([]*model.Attachment, error)
+	Delete(ctx context.Context, id int) error
+	// CountByHash returns how many attachments share the content with the given hash
+	CountByHash(ctx context.Context, sha256 string) (int, error)
+}
+
+// AttachmentServicePort defines the interface for attachment business logic
+type AttachmentServicePort interface {
+	UploadAttachment(ctx context.Context, todoID int, req *model.UploadAttachmentRequest) (*model.Attachment, error)
+	ListAttachments(ctx context.Context, todoID int) ([]*model.Attachment, error)
+	GetAttachment(ctx context.Context, todoID, id int) (*model.Attachment, error)
+	// OpenAttachment returns the attachment with a reader of its content; the caller closes the reader
+	OpenAttachment(ctx context.Context, todoID, id int) (*model.Attachment, io.ReadSeekCloser, error)
+	DeleteAttachment(ctx context.Context, todoID, id int) error
+	// DeleteTodoAttachments removes every attachment of a todo and any content no longer referenced
+	DeleteTodoAttachments(ctx context.Context, todoID int) error
+}
@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// AuditPort defines the interface for the append-only audit log
+type AuditPort interface {
+	// Append chains the entry after the last one and stores it. The entry's
+	// Sequence, PrevHash and Hash are set by the log.
+	Append(ctx context.Context, entry *model.AuditEntry) error
+	// List returns the entries matching the filter, newest first
+	List(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+	// All returns every entry in chain order, for verification
+	All(ctx context.Context) ([]*model.AuditEntry, error)
+}
+
+// AuditServicePort defines the interface for querying the audit log
+type AuditServicePort interface {
+	ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+	VerifyAuditLog(ctx context.Context) (*model.AuditVerification, error)
+}
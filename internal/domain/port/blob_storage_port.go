@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStoragePort defines the interface for storing binary content such as
+// attachment files. Keys are slash-separated paths chosen by the caller.
+type BlobStoragePort interface {
+	// Put stores size bytes read from r under key, replacing any existing blob
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns a seekable reader of the blob so byte ranges can be served.
+	// It returns domain.ErrNotFound if no blob is stored under key.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes the blob; deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// CommentRepositoryPort defines the interface for comment persistence
+type CommentRepositoryPort interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByID(ctx context.Context, id int) (*model.Comment, error)
+	// ListByTodo returns the comments of a todo, oldest first
+	ListByTodo(ctx context.Context, todoID int) ([]*model.Comment, error)
+	Update(ctx context.Context, comment *model.Comment) error
+	AppendRevision(ctx context.Context, revision *model.CommentRevision) error
+	// ListRevisions returns the previous bodies of a comment, oldest first
+	ListRevisions(ctx context.Context, commentID int) ([]*model.CommentRevision, error)
+}
+
+// CommentServicePort defines the interface for comment and activity feed business logic
+type CommentServicePort interface {
+	AddComment(ctx context.Context, todoID int, req *model.CreateCommentRequest) (*model.Comment, error)
+	// ListComments returns the todo's top-level comments with their replies nested
+	ListComments(ctx context.Context, todoID int) ([]*model.Comment, error)
+	UpdateComment(ctx context.Context, todoID, id int, req *model.UpdateCommentRequest) (*model.Comment, error)
+	DeleteComment(ctx context.Context, todoID, id int) error
+	ListCommentRevisions(ctx context.Context, todoID, id int) ([]*model.CommentRevision, error)
+	// GetActivity returns the todo's comments and field changes, oldest first
+	GetActivity(ctx context.Context, todoID int) ([]*model.Activity, error)
+}
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// EventPublisherPort defines the interface services use to announce domain events
+type EventPublisherPort interface {
+	Publish(ctx context.Context, events ...model.Event) error
+}
+
+// EventHandler reacts to a domain event
+type EventHandler func(ctx context.Context, event model.Event) error
+
+// EventSubscriberPort defines the interface for reacting to domain events.
+// Without event types a handler receives every event.
+type EventSubscriberPort interface {
+	// SubscribeSync runs the handler before Publish returns
+	SubscribeSync(handler EventHandler, types ...model.EventType)
+	// SubscribeAsync runs the handler in the background. Events of the same
+	// aggregate reach the handler in the order they were published.
+	SubscribeAsync(handler EventHandler, types ...model.EventType)
+}
@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// ListShareRepositoryPort defines the interface for list membership and invitation persistence
+type ListShareRepositoryPort interface {
+	SaveMember(ctx context.Context, member *model.ListMember) error
+	GetMember(ctx context.Context, listID, userID int) (*model.ListMember, error)
+	ListMembers(ctx context.Context, listID int) ([]*model.ListMember, error)
+	// ListMemberships returns every list membership of a user
+	ListMemberships(ctx context.Context, userID int) ([]*model.ListMember, error)
+	DeleteMember(ctx context.Context, listID, userID int) error
+	CreateInvitation(ctx context.Context, invitation *model.ListInvitation) error
+	GetInvitation(ctx context.Context, id int) (*model.ListInvitation, error)
+	// ListInvitations returns the invitations addressed to a user, optionally only those with the given status
+	ListInvitations(ctx context.Context, inviteeID int, status model.InvitationStatus) ([]*model.ListInvitation, error)
+	ListInvitationsForList(ctx context.Context, listID int) ([]*model.ListInvitation, error)
+	UpdateInvitation(ctx context.Context, invitation *model.ListInvitation) error
+	// DeleteByList removes all members and invitations of a list
+	DeleteByList(ctx context.Context, listID int) error
+}
+
+// ListShareServicePort defines the interface for list sharing business logic
+type ListShareServicePort interface {
+	ListMembers(ctx context.Context, listID int) ([]*model.ListMember, error)
+	UpdateMember(ctx context.Context, listID, userID int, req *model.UpdateListMemberRequest) (*model.ListMember, error)
+	RemoveMember(ctx context.Context, listID, userID int) error
+	Invite(ctx context.Context, listID int, req *model.InviteToListRequest) (*model.ListInvitation, error)
+	ListListInvitations(ctx context.Context, listID int) ([]*model.ListInvitation, error)
+	ListMyInvitations(ctx context.Context) ([]*model.ListInvitation, error)
+	AcceptInvitation(ctx context.Context, id int) (*model.ListMember, error)
+	DeclineInvitation(ctx context.Context, id int) (*model.ListInvitation, error)
+	ListSharedWithMe(ctx context.Context) ([]*model.TodoList, error)
+}
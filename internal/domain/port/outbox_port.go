@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// OutboxRepositoryPort defines the interface for outbox persistence
+type OutboxRepositoryPort interface {
+	// Append stores the entries in order, assigning their IDs
+	Append(ctx context.Context, entries []*model.OutboxEntry) error
+	// ListPending returns up to limit unpublished entries, oldest first
+	ListPending(ctx context.Context, limit int) ([]*model.OutboxEntry, error)
+	MarkPublished(ctx context.Context, id int, publishedAt time.Time) error
+	// MarkFailed records a failed attempt and when to try again
+	MarkFailed(ctx context.Context, id int, lastError string, nextAttemptAt time.Time) error
+	// DeletePublished removes entries published before the cutoff and returns how many were removed
+	DeletePublished(ctx context.Context, before time.Time) (int, error)
+}
+
+// ProcessedEventRepositoryPort defines the interface consumers use to remember
+// which events they already handled
+type ProcessedEventRepositoryPort interface {
+	// IsProcessed reports whether the consumer recorded the event as handled
+	IsProcessed(ctx context.Context, consumer, eventID string) (bool, error)
+	// MarkProcessed records that the consumer handled the event at the given
+	// time and reports false if it had already been recorded
+	MarkProcessed(ctx context.Context, consumer, eventID string, processedAt time.Time) (bool, error)
+	// DeleteProcessedBefore forgets the records made before the cutoff and returns how many were removed
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int, error)
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+	"go-boilerplate/internal/domain/model"
+)
+
+// TagRepositoryPort defines the interface for tag persistence
+type TagRepositoryPort interface {
+	Create(ctx context.Context, tag *model.Tag) error
+	GetByID(ctx context.Context, id int) (*model.Tag, error)
+	GetByName(ctx context.Context, ownerID int, name string) (*model.Tag, error)
+	// ListByNames returns the tags with any of the given names, across all owners
+	ListByNames(ctx context.Context, names []string) ([]*model.Tag, error)
+	List(ctx context.Context, ownerID *int) ([]*model.Tag, error)
+	Update(ctx context.Context, tag *model.Tag) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TagServicePort defines the interface for tag business logic
+type TagServicePort interface {
+	CreateTag(ctx context.Context, req *model.CreateTagRequest) (*model.Tag, error)
+	GetTag(ctx context.Context, id int) (*model.Tag, error)
+	ListTags(ctx context.Context, ownerID *int) ([]*model.Tag, error)
+	UpdateTag(ctx context.Context, id int, req *model.UpdateTagRequest) (*model.Tag, error)
+	MergeTags(ctx context.Context, sourceID int, req *model.MergeTagsRequest) (*model.Tag, error)
+	DeleteTag(ctx context.Context, id int) error
+}
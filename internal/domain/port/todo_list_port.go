@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoListRepositoryPort defines the interface for todo list persistence
+type TodoListRepositoryPort interface {
+	Create(ctx context.Context, list *model.TodoList) error
+	GetByID(ctx context.Context, id int) (*model.TodoList, error)
+	List(ctx context.Context, ownerID *int, includeArchived bool) ([]*model.TodoList, error)
+	Update(ctx context.Context, list *model.TodoList) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TodoListServicePort defines the interface for todo list business logic
+type TodoListServicePort interface {
+	CreateList(ctx context.Context, req *model.CreateTodoListRequest) (*model.TodoList, error)
+	GetList(ctx context.Context, id int) (*model.TodoList, error)
+	ListLists(ctx context.Context, includeArchived bool) ([]*model.TodoList, error)
+	UpdateList(ctx context.Context, id int, req *model.UpdateTodoListRequest) (*model.TodoList, error)
+	ArchiveList(ctx context.Context, id int) (*model.TodoList, error)
+	UnarchiveList(ctx context.Context, id int) (*model.TodoList, error)
+	DeleteList(ctx context.Context, id int, mode model.ListDeleteMode) error
+	ListMemberTodos(ctx context.Context, id int) ([]*model.Todo, error)
+	AddTodo(ctx context.Context, id int, todoID int) (*model.Todo, error)
+	RemoveTodo(ctx context.Context, id int, todoID int) (*model.Todo, error)
+	MoveTodo(ctx context.Context, id int, todoID int, req *model.MoveTodoRequest) (*model.Todo, error)
+}
@@ -2,23 +2,70 @@ package port
 
 import (
 	"context"
+	"time"
+
 	"go-boilerplate/internal/domain/model"
 )
 
 // TodoRepositoryPort defines the interface for todo persistence
 type TodoRepositoryPort interface {
 	Create(ctx context.Context, todo *model.Todo) error
+	// GetByID and Update only see live todos; List selects live or trashed
+	// todos through TodoFilter.Deleted
 	GetByID(ctx context.Context, id int) (*model.Todo, error)
-	List(ctx context.Context) ([]*model.Todo, error)
+	List(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error)
 	Update(ctx context.Context, todo *model.Todo) error
+	// Delete permanently removes a todo, whether live or trashed
 	Delete(ctx context.Context, id int) error
+	// SoftDelete moves a live todo to the trash
+	SoftDelete(ctx context.Context, id int, deletedAt time.Time) error
+	// Restore moves a trashed todo back out of the trash
+	Restore(ctx context.Context, id int) error
+	AppendTransition(ctx context.Context, transition *model.TodoTransition) error
+	ListTransitions(ctx context.Context, todoID int) ([]*model.TodoTransition, error)
+	AppendChanges(ctx context.Context, changes []*model.TodoChange) error
+	// ListChanges returns the field changes of a todo, oldest first
+	ListChanges(ctx context.Context, todoID int) ([]*model.TodoChange, error)
+	// ReplaceTag swaps a tag for another one on every todo carrying it
+	ReplaceTag(ctx context.Context, oldTagID, newTagID int) error
+	// RemoveTag detaches a tag from every todo carrying it
+	RemoveTag(ctx context.Context, tagID int) error
+}
+
+// TodoHistoryPort defines the interface for reading the past of todos kept in
+// an event-sourced store
+type TodoHistoryPort interface {
+	// ListEvents returns the stored events of a live or trashed todo, oldest first
+	ListEvents(ctx context.Context, todoID int) ([]*model.TodoEvent, error)
+	// GetAsOf rebuilds a todo as it was at the given time. It returns
+	// ErrNotFound if the todo did not exist yet or was in the trash then.
+	GetAsOf(ctx context.Context, id int, at time.Time) (*model.Todo, error)
 }
 
 // TodoServicePort defines the interface for todo business logic
 type TodoServicePort interface {
 	CreateTodo(ctx context.Context, req *model.CreateTodoRequest) (*model.Todo, error)
 	GetTodo(ctx context.Context, id int) (*model.Todo, error)
-	ListTodos(ctx context.Context) ([]*model.Todo, error)
+	// GetTodoAsOf returns a todo as it was at the given time
+	GetTodoAsOf(ctx context.Context, id int, at time.Time) (*model.Todo, error)
+	GetTodoHistory(ctx context.Context, id int) ([]*model.TodoEvent, error)
+	ListTodos(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error)
 	UpdateTodo(ctx context.Context, id int, req *model.UpdateTodoRequest) (*model.Todo, error)
+	// DeleteTodo moves a todo to the trash
 	DeleteTodo(ctx context.Context, id int) error
+	ListDeletedTodos(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error)
+	RestoreTodo(ctx context.Context, id int) (*model.Todo, error)
+	// PurgeDeletedTodos permanently removes todos trashed before the cutoff and returns how many were removed
+	PurgeDeletedTodos(ctx context.Context, before time.Time) (int, error)
+	TransitionTodo(ctx context.Context, id int, req *model.TransitionTodoRequest) (*model.Todo, error)
+	ListTodoTransitions(ctx context.Context, id int) ([]*model.TodoTransition, error)
+	AttachTag(ctx context.Context, id int, tagID int) (*model.Todo, error)
+	DetachTag(ctx context.Context, id int, tagID int) (*model.Todo, error)
+	ListSubtasks(ctx context.Context, id int) ([]*model.Todo, error)
+	AddChecklistItem(ctx context.Context, id int, req *model.CreateChecklistItemRequest) (*model.Todo, error)
+	UpdateChecklistItem(ctx context.Context, id int, itemID int, req *model.UpdateChecklistItemRequest) (*model.Todo, error)
+	DeleteChecklistItem(ctx context.Context, id int, itemID int) (*model.Todo, error)
+	SetRecurrence(ctx context.Context, id int, req *model.RecurrenceRequest) (*model.Todo, error)
+	ClearRecurrence(ctx context.Context, id int) (*model.Todo, error)
+	PreviewOccurrences(ctx context.Context, id int, count int) ([]time.Time, error)
 }
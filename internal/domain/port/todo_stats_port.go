@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoStatsReadModelPort defines the interface for the read model behind todo
+// dashboards. Every write carries the version of the data it is based on, and
+// writes older than what is stored for a todo are ignored, so events applied
+// late or twice cannot roll a todo back.
+type TodoStatsReadModelPort interface {
+	// Upsert counts a live todo
+	Upsert(ctx context.Context, todo *model.Todo, version time.Time) error
+	// Remove stops counting a todo
+	Remove(ctx context.Context, todoID int, version time.Time) error
+	// Replace discards the read model and counts the given todos instead
+	Replace(ctx context.Context, todos []*model.Todo, version time.Time) error
+	// RecordPending notes an event that was published but not yet applied
+	RecordPending(ctx context.Context, event model.Event) error
+	// RecordApplied advances the projection status past a handled event
+	RecordApplied(ctx context.Context, event model.Event, appliedAt time.Time) error
+	// Stats returns the counts matching the filter, judging due dates and lag against now
+	Stats(ctx context.Context, filter model.TodoStatsFilter, now time.Time) (*model.TodoStats, error)
+	// Status reports how far the read model has caught up, judging lag against now
+	Status(ctx context.Context, now time.Time) (*model.ProjectionStatus, error)
+}
+
+// TodoStatsServicePort defines the interface for querying todo dashboards
+type TodoStatsServicePort interface {
+	// GetTodoStats counts the acting user's todos, or anyone's for administrators
+	GetTodoStats(ctx context.Context, filter model.TodoStatsFilter) (*model.TodoStats, error)
+	GetProjectionStatus(ctx context.Context) (*model.ProjectionStatus, error)
+	// RebuildTodoStats recomputes the read model from the todo repository
+	RebuildTodoStats(ctx context.Context) (*model.ProjectionStatus, error)
+}
@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// TodoSubscription is one client's live stream of todo changes
+type TodoSubscription interface {
+	// Replay returns the buffered changes the client missed, oldest first,
+	// to be sent before anything from Changes
+	Replay() []model.TodoStreamEvent
+	// Changes delivers live changes. It is closed when the client falls too
+	// far behind, after which the client reconnects and resumes.
+	Changes() <-chan model.TodoStreamEvent
+	// Close ends the subscription
+	Close()
+}
+
+// TodoStreamServicePort defines the interface for streaming live todo changes
+type TodoStreamServicePort interface {
+	// Subscribe streams changes to the todos the acting user can see. Given
+	// the last change ID the client received, buffered changes after it are
+	// replayed first.
+	Subscribe(ctx context.Context, lastEventID *int) (TodoSubscription, error)
+}
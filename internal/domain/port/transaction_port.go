@@ -0,0 +1,18 @@
+package port
+
+import "context"
+
+// TransactionManagerPort defines the interface for running multi-step
+// operations atomically. The transaction travels in the context: repository
+// calls made with the context passed to fn take part in it, whatever the
+// adapter keeps there (held locks and snapshots in memory, a *sql.Tx for SQL).
+type TransactionManagerPort interface {
+	// WithinTx runs fn in a transaction, committing if it returns nil and
+	// rolling back otherwise. A call made within a transaction joins it.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// BeforeCommit runs fn as the last step of the transaction in the
+	// context, once the transaction's work has succeeded; an error from fn
+	// rolls the transaction back. Outside a transaction fn runs right away.
+	// Writes to stores that cannot roll back are deferred this way.
+	BeforeCommit(ctx context.Context, fn func(ctx context.Context) error) error
+}
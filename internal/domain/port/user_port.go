@@ -2,24 +2,54 @@ package port
 
 import (
 	"context"
+	"time"
+
 	"go-boilerplate/internal/domain/model"
 )
 
 // UserRepositoryPort defines the interface for user persistence
 type UserRepositoryPort interface {
+	// Create fails with ErrUsernameDuplicate when a live user holds the
+	// username, and ErrUsernameInTrash when a trashed one does
 	Create(ctx context.Context, user *model.User) error
+	// GetByID, GetByUsername, List and Update only see live users. A trashed
+	// user keeps their username until they are permanently deleted.
 	GetByID(ctx context.Context, id int) (*model.User, error)
+	// GetByIDs retrieves the live users among the IDs in one call; unknown
+	// IDs are left out rather than reported as errors
+	GetByIDs(ctx context.Context, ids []int) ([]*model.User, error)
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
 	List(ctx context.Context) ([]*model.User, error)
 	Update(ctx context.Context, user *model.User) error
+	// Delete permanently removes a user, whether live or trashed
 	Delete(ctx context.Context, id int) error
+	// SoftDelete moves a live user to the trash
+	SoftDelete(ctx context.Context, id int, deletedAt time.Time) error
+	// Restore moves a trashed user back out of the trash
+	Restore(ctx context.Context, id int) error
+	ListDeleted(ctx context.Context) ([]*model.User, error)
+	// Rename moves the user to a new username and records the old one in the
+	// username history in a single step. It checks that the username is free
+	// and not reserved by another user's rename as of history.ChangedAt in the
+	// same step, failing like Create or with ErrUsernameReserved.
+	Rename(ctx context.Context, id int, username string, history *model.UsernameHistory) (*model.User, error)
+	GetUsernameHistory(ctx context.Context, username string) (*model.UsernameHistory, error)
 }
 
 // UserServicePort defines the interface for user business logic
 type UserServicePort interface {
 	CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.User, error)
 	GetUser(ctx context.Context, id int) (*model.User, error)
+	// GetUsers retrieves several users at once, leaving out unknown IDs
+	GetUsers(ctx context.Context, ids []int) ([]*model.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
 	ListUsers(ctx context.Context) ([]*model.User, error)
 	UpdateUser(ctx context.Context, id int, req *model.UpdateUserRequest) (*model.User, error)
+	RenameUser(ctx context.Context, id int, req *model.RenameUserRequest) (*model.User, error)
+	// DeleteUser moves a user to the trash
 	DeleteUser(ctx context.Context, id int) error
+	ListDeletedUsers(ctx context.Context) ([]*model.User, error)
+	RestoreUser(ctx context.Context, id int) (*model.User, error)
+	// PurgeDeletedUsers permanently removes users trashed before the cutoff and returns how many were removed
+	PurgeDeletedUsers(ctx context.Context, before time.Time) (int, error)
 }
@@ -0,0 +1,49 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+)
+
+// WebhookRepositoryPort defines the interface for webhook and delivery persistence
+type WebhookRepositoryPort interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	GetByID(ctx context.Context, id int) (*model.Webhook, error)
+	ListByOwner(ctx context.Context, ownerID int) ([]*model.Webhook, error)
+	// ListActive returns the webhooks that receive deliveries
+	ListActive(ctx context.Context) ([]*model.Webhook, error)
+	Update(ctx context.Context, webhook *model.Webhook) error
+	// Delete removes a webhook together with its deliveries
+	Delete(ctx context.Context, id int) error
+	CreateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id int) (*model.WebhookDelivery, error)
+	// ListDeliveries returns a webhook's deliveries, newest first, optionally only those in a status
+	ListDeliveries(ctx context.Context, webhookID int, status model.DeliveryStatus) ([]*model.WebhookDelivery, error)
+	// ListDueDeliveries returns up to limit pending deliveries of active
+	// webhooks that are due at now, oldest first. A webhook's deliveries stop
+	// at its first one that is not due yet.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+}
+
+// WebhookSenderPort defines the interface for posting a delivery to a webhook URL
+type WebhookSenderPort interface {
+	// Send posts the body and returns the response status code. An error
+	// means no response was received.
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
+
+// WebhookServicePort defines the interface for managing webhooks
+type WebhookServicePort interface {
+	CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*model.Webhook, error)
+	GetWebhook(ctx context.Context, id int) (*model.Webhook, error)
+	UpdateWebhook(ctx context.Context, id int, req *model.UpdateWebhookRequest) (*model.Webhook, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	ListDeliveries(ctx context.Context, id int, status model.DeliveryStatus) ([]*model.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, id int, deliveryID int) (*model.WebhookDelivery, error)
+	// ReplayDelivery queues a new delivery of the same event
+	ReplayDelivery(ctx context.Context, id int, deliveryID int) (*model.WebhookDelivery, error)
+}
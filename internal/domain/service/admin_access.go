@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain"
+)
+
+// adminAccess checks whether the acting user is one of the configured administrators
+type adminAccess struct {
+	ids map[int]bool
+}
+
+// newAdminAccess creates an adminAccess for the given administrator user IDs
+func newAdminAccess(ids []int) adminAccess {
+	admins := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		admins[id] = true
+	}
+	return adminAccess{ids: admins}
+}
+
+// authorize checks that the acting user is an administrator
+func (a adminAccess) authorize(ctx context.Context) error {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+	if !a.isAdmin(actorID) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// isAdmin reports whether the user is one of the administrators
+func (a adminAccess) isAdmin(userID int) bool {
+	return a.ids[userID]
+}
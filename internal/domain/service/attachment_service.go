@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// AttachmentService implements the AttachmentServicePort interface
+type AttachmentService struct {
+	repo         port.AttachmentRepositoryPort
+	todoRepo     port.TodoRepositoryPort
+	storage      port.BlobStoragePort
+	maxSize      int64
+	allowedTypes []string
+	// blobMu serializes the check-then-act steps of storing and releasing
+	// shared content so a blob is never deleted while a new reference is added
+	blobMu sync.Mutex
+	// access, when enabled, subjects the attachments of todos in shared lists to the list roles
+	access todoAccess
+	now    func() time.Time
+}
+
+// AttachmentServiceOption configures optional AttachmentService behavior
+type AttachmentServiceOption func(*AttachmentService)
+
+// WithMaxAttachmentSize limits the size of uploaded files in bytes
+func WithMaxAttachmentSize(size int64) AttachmentServiceOption {
+	return func(s *AttachmentService) {
+		s.maxSize = size
+	}
+}
+
+// WithAllowedAttachmentTypes restricts the accepted content types. An entry
+// ending in "/" accepts a whole family, such as "image/".
+func WithAllowedAttachmentTypes(types []string) AttachmentServiceOption {
+	return func(s *AttachmentService) {
+		s.allowedTypes = types
+	}
+}
+
+// WithAttachmentListRoles enforces the list roles on the attachments of todos
+// in shared lists: viewers may download them, editors may also upload and delete
+func WithAttachmentListRoles(lists port.TodoListRepositoryPort, shares port.ListShareRepositoryPort) AttachmentServiceOption {
+	return func(s *AttachmentService) {
+		s.access = newTodoAccess(lists, shares)
+	}
+}
+
+// NewAttachmentService creates a new AttachmentService storing content in the given blob storage
+func NewAttachmentService(repo port.AttachmentRepositoryPort, todoRepo port.TodoRepositoryPort, storage port.BlobStoragePort, opts ...AttachmentServiceOption) *AttachmentService {
+	s := &AttachmentService{
+		repo:         repo,
+		todoRepo:     todoRepo,
+		storage:      storage,
+		maxSize:      10 << 20,
+		allowedTypes: []string{"image/", "text/plain", "application/pdf"},
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// sanitizeFilename strips any directory components a client sent with the file name
+func sanitizeFilename(filename string) (string, error) {
+	name := path.Base(strings.ReplaceAll(strings.TrimSpace(filename), `\`, "/"))
+	if name == "." || name == "/" || name == "" {
+		return "", domain.ErrInvalidAttachmentName
+	}
+	return name, nil
+}
+
+// detectContentType sniffs the content type from the first bytes of the content
+// rather than trusting the client, then rewinds the content
+func detectContentType(content io.ReadSeeker) (string, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if err != nil {
+		return "application/octet-stream", nil
+	}
+	return mediaType, nil
+}
+
+// isAllowedType reports whether a content type is in the allow list
+func (s *AttachmentService) isAllowedType(contentType string) bool {
+	for _, allowed := range s.allowedTypes {
+		if contentType == allowed || (strings.HasSuffix(allowed, "/") && strings.HasPrefix(contentType, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hashContent computes the SHA-256 and size of the content, failing once it
+// grows past the size limit, then rewinds the content
+func (s *AttachmentService) hashContent(content io.ReadSeeker) (string, int64, error) {
+	h := sha256.New()
+	size, err := io.Copy(h, io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		return "", 0, err
+	}
+	if size > s.maxSize {
+		return "", 0, domain.ErrAttachmentTooLarge
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// getTodoAttachment retrieves an attachment that belongs to the given live
+// todo, on which the acting user holds at least the required role
+func (s *AttachmentService) getTodoAttachment(ctx context.Context, todoID, id int, required model.ListRole) (*model.Attachment, error) {
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, required); err != nil {
+		return nil, err
+	}
+	attachment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.TodoID != todoID {
+		return nil, domain.ErrNotFound
+	}
+	return attachment, nil
+}
+
+// releaseBlob deletes the content of an attachment once no attachment references it.
+// The caller holds blobMu.
+func (s *AttachmentService) releaseBlob(ctx context.Context, attachment *model.Attachment) error {
+	count, err := s.repo.CountByHash(ctx, attachment.SHA256)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return s.storage.Delete(ctx, attachment.BlobKey())
+}
+
+// UploadAttachment attaches a file to a todo. Content that is already stored
+// for another attachment is not stored again.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, todoID int, req *model.UploadAttachmentRequest) (*model.Attachment, error) {
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, model.ListRoleEditor); err != nil {
+		return nil, err
+	}
+	filename, err := sanitizeFilename(req.Filename)
+	if err != nil {
+		return nil, err
+	}
+	if req.Size > s.maxSize {
+		return nil, domain.ErrAttachmentTooLarge
+	}
+
+	contentType, err := detectContentType(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isAllowedType(contentType) {
+		return nil, domain.ErrAttachmentTypeNotAllowed
+	}
+	hash, size, err := s.hashContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &model.Attachment{
+		TodoID:      todoID,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		SHA256:      hash,
+		CreatedAt:   s.now(),
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		attachment.UploaderID = &actorID
+	}
+
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+
+	exists, err := s.storage.Exists(ctx, attachment.BlobKey())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := s.storage.Put(ctx, attachment.BlobKey(), req.Content, size, contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, attachment); err != nil {
+		if !exists {
+			_ = s.storage.Delete(ctx, attachment.BlobKey())
+		}
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ListAttachments retrieves the attachments of a todo
+func (s *AttachmentService) ListAttachments(ctx context.Context, todoID int) ([]*model.Attachment, error) {
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTodo(ctx, todoID)
+}
+
+// GetAttachment retrieves an attachment of a todo
+func (s *AttachmentService) GetAttachment(ctx context.Context, todoID, id int) (*model.Attachment, error) {
+	return s.getTodoAttachment(ctx, todoID, id, model.ListRoleViewer)
+}
+
+// OpenAttachment retrieves an attachment of a todo together with its content
+func (s *AttachmentService) OpenAttachment(ctx context.Context, todoID, id int) (*model.Attachment, io.ReadSeekCloser, error) {
+	attachment, err := s.getTodoAttachment(ctx, todoID, id, model.ListRoleViewer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.storage.Open(ctx, attachment.BlobKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment from a todo
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, todoID, id int) error {
+	attachment, err := s.getTodoAttachment(ctx, todoID, id, model.ListRoleEditor)
+	if err != nil {
+		return err
+	}
+
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	return s.releaseBlob(ctx, attachment)
+}
+
+// DeleteTodoAttachments removes every attachment of a todo
+func (s *AttachmentService) DeleteTodoAttachments(ctx context.Context, todoID int) error {
+	attachments, err := s.repo.ListByTodo(ctx, todoID)
+	if err != nil {
+		return err
+	}
+
+	s.blobMu.Lock()
+	defer s.blobMu.Unlock()
+
+	for _, attachment := range attachments {
+		if err := s.repo.Delete(ctx, attachment.ID); err != nil {
+			return err
+		}
+		if err := s.releaseBlob(ctx, attachment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/adapter/outbound/storage"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// attachmentFixture holds alice's (1) todo in a list she shares with bob (2)
+// as editor and carol (3) as viewer; dave (4) is a stranger to the list
+type attachmentFixture struct {
+	*listFixture
+	attachments *AttachmentService
+	todoID      int
+}
+
+func newAttachmentFixture(t *testing.T) *attachmentFixture {
+	t.Helper()
+	f := newListFixture()
+	blobs, err := storage.NewLocalBlobStorage(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachments := NewAttachmentService(persistence.NewAttachmentRepository(), f.todos.TodoRepository(), blobs,
+		WithAttachmentListRoles(f.listRepo, f.shares))
+
+	_, todoID := f.sharedTodo(t)
+	return &attachmentFixture{listFixture: f, attachments: attachments, todoID: todoID}
+}
+
+func (f *attachmentFixture) upload(ctx context.Context, todoID int, content string) (*model.Attachment, error) {
+	return f.attachments.UploadAttachment(ctx, todoID, &model.UploadAttachmentRequest{
+		Filename: "notes.txt",
+		Size:     int64(len(content)),
+		Content:  strings.NewReader(content),
+	})
+}
+
+func (f *attachmentFixture) mustUpload(t *testing.T, content string) *model.Attachment {
+	t.Helper()
+	attachment, err := f.upload(as(1), f.todoID, content)
+	if err != nil {
+		t.Fatalf("UploadAttachment() error = %v", err)
+	}
+	return attachment
+}
+
+func TestAttachmentListRoles(t *testing.T) {
+	actors := []struct {
+		name      string
+		actorID   int
+		wantRead  error
+		wantWrite error
+	}{
+		{name: "list owner", actorID: 1},
+		{name: "editor", actorID: 2},
+		{name: "viewer", actorID: 3, wantWrite: domain.ErrForbidden},
+		{name: "stranger", actorID: 4, wantRead: domain.ErrNotFound, wantWrite: domain.ErrNotFound},
+		{name: "anonymous", wantRead: domain.ErrUnauthenticated, wantWrite: domain.ErrUnauthenticated},
+	}
+	ops := []struct {
+		name  string
+		write bool
+		op    func(ctx context.Context, f *attachmentFixture, attachmentID int) error
+	}{
+		{name: "upload", write: true, op: func(ctx context.Context, f *attachmentFixture, _ int) error {
+			_, err := f.upload(ctx, f.todoID, "more notes")
+			return err
+		}},
+		{name: "list", op: func(ctx context.Context, f *attachmentFixture, _ int) error {
+			_, err := f.attachments.ListAttachments(ctx, f.todoID)
+			return err
+		}},
+		{name: "get", op: func(ctx context.Context, f *attachmentFixture, attachmentID int) error {
+			_, err := f.attachments.GetAttachment(ctx, f.todoID, attachmentID)
+			return err
+		}},
+		{name: "open", op: func(ctx context.Context, f *attachmentFixture, attachmentID int) error {
+			_, content, err := f.attachments.OpenAttachment(ctx, f.todoID, attachmentID)
+			if content != nil {
+				content.Close()
+			}
+			return err
+		}},
+		{name: "delete", write: true, op: func(ctx context.Context, f *attachmentFixture, attachmentID int) error {
+			return f.attachments.DeleteAttachment(ctx, f.todoID, attachmentID)
+		}},
+	}
+
+	for _, actor := range actors {
+		for _, op := range ops {
+			t.Run(actor.name+"/"+op.name, func(t *testing.T) {
+				f := newAttachmentFixture(t)
+				attachment := f.mustUpload(t, "notes")
+
+				want := actor.wantRead
+				if op.write {
+					want = actor.wantWrite
+				}
+				if err := op.op(as(actor.actorID), f, attachment.ID); !errors.Is(err, want) {
+					t.Errorf("error = %v, want %v", err, want)
+				}
+			})
+		}
+	}
+}
+
+func TestAttachmentsOfTrashedTodos(t *testing.T) {
+	ops := []struct {
+		name string
+		op   func(ctx context.Context, f *attachmentFixture, attachmentID int) error
+	}{
+		{name: "upload", op: func(ctx context.Context, f *attachmentFixture, _ int) error {
+			_, err := f.upload(ctx, f.todoID, "more notes")
+			return err
+		}},
+		{name: "list", op: func(ctx context.Context, f *attachmentFixture, _ int) error {
+			_, err := f.attachments.ListAttachments(ctx, f.todoID)
+			return err
+		}},
+		{name: "get", op: func(ctx context.Context, f *attachmentFixture, attachmentID int) error {
+			_, err := f.attachments.GetAttachment(ctx, f.todoID, attachmentID)
+			return err
+		}},
+		{name: "open", op: func(ctx context.Context, f *attachmentFixture, attachmentID int) error {
+			_, _, err := f.attachments.OpenAttachment(ctx, f.todoID, attachmentID)
+			return err
+		}},
+		{name: "delete", op: func(ctx context.Context, f *attachmentFixture, attachmentID int) error {
+			return f.attachments.DeleteAttachment(ctx, f.todoID, attachmentID)
+		}},
+	}
+
+	for _, op := range ops {
+		t.Run(op.name, func(t *testing.T) {
+			f := newAttachmentFixture(t)
+			attachment := f.mustUpload(t, "notes")
+			if err := f.todos.DeleteTodo(as(1), f.todoID); err != nil {
+				t.Fatal(err)
+			}
+			if err := op.op(as(1), f, attachment.ID); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("error = %v, want %v", err, domain.ErrNotFound)
+			}
+		})
+	}
+}
+
+func TestAttachmentContentIsShared(t *testing.T) {
+	f := newAttachmentFixture(t)
+	first := f.mustUpload(t, "same content")
+	second := f.mustUpload(t, "same content")
+	if first.SHA256 != second.SHA256 {
+		t.Fatalf("hashes differ: %s and %s", first.SHA256, second.SHA256)
+	}
+
+	// Another todo's ID does not reach the attachment
+	other := mustCreateTodo(t, f.todos, "other", nil)
+	if _, err := f.attachments.GetAttachment(as(1), other.ID, first.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("GetAttachment() through another todo: error = %v, want %v", err, domain.ErrNotFound)
+	}
+
+	// The content stays until its last attachment is deleted
+	if err := f.attachments.DeleteAttachment(as(1), f.todoID, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, content, err := f.attachments.OpenAttachment(as(1), f.todoID, second.ID)
+	if err != nil {
+		t.Fatalf("OpenAttachment() error = %v", err)
+	}
+	got, err := io.ReadAll(content)
+	content.Close()
+	if err != nil || string(got) != "same content" {
+		t.Errorf("content = %q, %v, want %q", got, err, "same content")
+	}
+	if err := f.attachments.DeleteAttachment(as(1), f.todoID, second.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := f.attachments.OpenAttachment(as(1), f.todoID, second.ID); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("OpenAttachment() after delete: error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
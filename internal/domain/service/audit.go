@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// fieldsOf flattens an entity into its JSON fields, or nil for no entity
+func fieldsOf(entity any) (map[string]any, error) {
+	if entity == nil || reflect.ValueOf(entity).IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffEntities lists the JSON fields that differ between two versions of an
+// entity. A nil before or after records a creation or removal.
+func diffEntities(before, after any) (map[string]model.FieldDiff, error) {
+	beforeFields, err := fieldsOf(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fieldsOf(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]model.FieldDiff)
+	for name, value := range afterFields {
+		if old, ok := beforeFields[name]; !ok || !reflect.DeepEqual(old, value) {
+			changes[name] = model.FieldDiff{Before: beforeFields[name], After: value}
+		}
+	}
+	for name, old := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			changes[name] = model.FieldDiff{Before: old}
+		}
+	}
+	return changes, nil
+}
+
+// auditRecorder writes audit entries attributed to the actor and request in the context
+type auditRecorder struct {
+	log port.AuditPort
+	tx  port.TransactionManagerPort
+	now func() time.Time
+}
+
+// record appends an entry for a change to an entity. Within a transaction the
+// entry is only appended once the transaction is about to commit, so changes
+// that are rolled back leave no trace in the log.
+func (r auditRecorder) record(ctx context.Context, action model.AuditAction, entityType string, entityID int, before, after any) error {
+	changes, err := diffEntities(before, after)
+	if err != nil {
+		return err
+	}
+
+	entry := &model.AuditEntry{
+		Timestamp:  r.now().UTC(),
+		RequestID:  domain.RequestIDFromContext(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		entry.ActorID = &actorID
+	}
+	return r.tx.BeforeCommit(ctx, func(ctx context.Context) error {
+		return r.log.Append(ctx, entry)
+	})
+}
+
+// auditedTodoRepository records every change written through a todo repository,
+// so changes made as side effects (auto-completed parents, spawned occurrences)
+// are audited as well as direct ones
+type auditedTodoRepository struct {
+	port.TodoRepositoryPort
+	audit auditRecorder
+}
+
+// Create creates the todo and records its creation
+func (r *auditedTodoRepository) Create(ctx context.Context, todo *model.Todo) error {
+	if err := r.TodoRepositoryPort.Create(ctx, todo); err != nil {
+		return err
+	}
+	return r.audit.record(ctx, model.AuditActionCreate, model.AuditEntityTodo, todo.ID, nil, todo)
+}
+
+// Update updates the todo and records the changed fields
+func (r *auditedTodoRepository) Update(ctx context.Context, todo *model.Todo) error {
+	before, err := r.TodoRepositoryPort.GetByID(ctx, todo.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.TodoRepositoryPort.Update(ctx, todo); err != nil {
+		return err
+	}
+	// Progress is derived on read and not part of the stored todo
+	after := todo.Clone()
+	after.Progress = before.Progress
+	return r.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityTodo, todo.ID, before, after)
+}
+
+// SoftDelete moves the todo to the trash and records the deletion
+func (r *auditedTodoRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	before, err := r.TodoRepositoryPort.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.TodoRepositoryPort.SoftDelete(ctx, id, deletedAt); err != nil {
+		return err
+	}
+	after := before.Clone()
+	after.DeletedAt = &deletedAt
+	return r.audit.record(ctx, model.AuditActionDelete, model.AuditEntityTodo, id, before, after)
+}
+
+// Restore moves the todo out of the trash and records the restoration
+func (r *auditedTodoRepository) Restore(ctx context.Context, id int) error {
+	before, err := getTodoIncludingDeleted(ctx, r.TodoRepositoryPort, id)
+	if err != nil {
+		return err
+	}
+	if err := r.TodoRepositoryPort.Restore(ctx, id); err != nil {
+		return err
+	}
+	after := before.Clone()
+	after.DeletedAt = nil
+	return r.audit.record(ctx, model.AuditActionRestore, model.AuditEntityTodo, id, before, after)
+}
+
+// Delete permanently deletes the todo and records its final state
+func (r *auditedTodoRepository) Delete(ctx context.Context, id int) error {
+	before, err := getTodoIncludingDeleted(ctx, r.TodoRepositoryPort, id)
+	if err != nil {
+		return err
+	}
+	if err := r.TodoRepositoryPort.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.audit.record(ctx, model.AuditActionPurge, model.AuditEntityTodo, id, before, nil)
+}
+
+// ReplaceTag swaps a tag for another one and records the change of every todo carrying it
+func (r *auditedTodoRepository) ReplaceTag(ctx context.Context, oldTagID, newTagID int) error {
+	return r.recordRetag(ctx, oldTagID, func(ctx context.Context) error {
+		return r.TodoRepositoryPort.ReplaceTag(ctx, oldTagID, newTagID)
+	})
+}
+
+// RemoveTag detaches a tag and records the change of every todo carrying it
+func (r *auditedTodoRepository) RemoveTag(ctx context.Context, tagID int) error {
+	return r.recordRetag(ctx, tagID, func(ctx context.Context) error {
+		return r.TodoRepositoryPort.RemoveTag(ctx, tagID)
+	})
+}
+
+// recordRetag runs retag and records an update of every todo it changed
+func (r *auditedTodoRepository) recordRetag(ctx context.Context, tagID int, retag func(ctx context.Context) error) error {
+	before, after, err := retagTodos(ctx, r.TodoRepositoryPort, tagID, retag)
+	if err != nil {
+		return err
+	}
+	for i := range before {
+		if err := r.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityTodo, before[i].ID, before[i], after[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditedUserRepository records every change written through a user repository
+type auditedUserRepository struct {
+	port.UserRepositoryPort
+	audit auditRecorder
+}
+
+// Create creates the user and records its creation
+func (r *auditedUserRepository) Create(ctx context.Context, user *model.User) error {
+	if err := r.UserRepositoryPort.Create(ctx, user); err != nil {
+		return err
+	}
+	return r.audit.record(ctx, model.AuditActionCreate, model.AuditEntityUser, user.ID, nil, user)
+}
+
+// Update updates the user and records the changed fields
+func (r *auditedUserRepository) Update(ctx context.Context, user *model.User) error {
+	before, err := r.UserRepositoryPort.GetByID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepositoryPort.Update(ctx, user); err != nil {
+		return err
+	}
+	return r.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityUser, user.ID, before, user)
+}
+
+// Rename renames the user and records the new username
+func (r *auditedUserRepository) Rename(ctx context.Context, id int, username string, history *model.UsernameHistory) (*model.User, error) {
+	before, err := r.UserRepositoryPort.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	after, err := r.UserRepositoryPort.Rename(ctx, id, username, history)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.audit.record(ctx, model.AuditActionUpdate, model.AuditEntityUser, id, before, after); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// SoftDelete moves the user to the trash and records the deletion
+func (r *auditedUserRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	before, err := r.UserRepositoryPort.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepositoryPort.SoftDelete(ctx, id, deletedAt); err != nil {
+		return err
+	}
+	after := *before
+	after.DeletedAt = &deletedAt
+	return r.audit.record(ctx, model.AuditActionDelete, model.AuditEntityUser, id, before, &after)
+}
+
+// Restore moves the user out of the trash and records the restoration
+func (r *auditedUserRepository) Restore(ctx context.Context, id int) error {
+	before, err := getUserIncludingDeleted(ctx, r.UserRepositoryPort, id)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepositoryPort.Restore(ctx, id); err != nil {
+		return err
+	}
+	after := *before
+	after.DeletedAt = nil
+	return r.audit.record(ctx, model.AuditActionRestore, model.AuditEntityUser, id, before, &after)
+}
+
+// Delete permanently deletes the user and records its final state
+func (r *auditedUserRepository) Delete(ctx context.Context, id int) error {
+	before, err := getUserIncludingDeleted(ctx, r.UserRepositoryPort, id)
+	if err != nil {
+		return err
+	}
+	if err := r.UserRepositoryPort.Delete(ctx, id); err != nil {
+		return err
+	}
+	return r.audit.record(ctx, model.AuditActionPurge, model.AuditEntityUser, id, before, nil)
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// AuditService implements the AuditServicePort interface. Only administrators
+// may read the audit log.
+type AuditService struct {
+	log    port.AuditPort
+	admins adminAccess
+}
+
+// NewAuditService creates a new AuditService for the given administrator user IDs
+func NewAuditService(log port.AuditPort, adminIDs []int) *AuditService {
+	return &AuditService{
+		log:    log,
+		admins: newAdminAccess(adminIDs),
+	}
+}
+
+// ListAuditEntries retrieves the audit entries matching the filter, newest first
+func (s *AuditService) ListAuditEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	if err := s.admins.authorize(ctx); err != nil {
+		return nil, err
+	}
+	return s.log.List(ctx, filter)
+}
+
+// VerifyAuditLog recomputes the hash chain and reports the first entry that
+// was altered, removed or inserted out of order
+func (s *AuditService) VerifyAuditLog(ctx context.Context) (*model.AuditVerification, error) {
+	if err := s.admins.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.log.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *model.AuditEntry
+	for _, entry := range entries {
+		wantSequence, wantPrevHash := int64(1), ""
+		if prev != nil {
+			wantSequence, wantPrevHash = prev.Sequence+1, prev.Hash
+		}
+		if entry.Sequence != wantSequence || entry.PrevHash != wantPrevHash || entry.Hash != entry.ComputeHash() {
+			brokenAt := entry.Sequence
+			return &model.AuditVerification{Valid: false, Entries: len(entries), BrokenAt: &brokenAt}, nil
+		}
+		prev = entry
+	}
+	return &model.AuditVerification{Valid: true, Entries: len(entries)}, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/audit"
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain/model"
+)
+
+func TestAuditEntriesWrittenOnCommit(t *testing.T) {
+	errAbort := errors.New("abort")
+	tests := []struct {
+		name      string
+		op        func(ctx context.Context, todos *TodoService, users *UserService) error
+		abort     bool
+		wantCount int
+	}{
+		{
+			name: "todo committed",
+			op: func(ctx context.Context, todos *TodoService, users *UserService) error {
+				_, err := todos.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: 1, Title: "todo"})
+				return err
+			},
+			wantCount: 1,
+		},
+		{
+			name: "todo rolled back",
+			op: func(ctx context.Context, todos *TodoService, users *UserService) error {
+				_, err := todos.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: 1, Title: "todo"})
+				return err
+			},
+			abort: true,
+		},
+		{
+			name: "user committed",
+			op: func(ctx context.Context, todos *TodoService, users *UserService) error {
+				_, err := users.CreateUser(ctx, &model.CreateUserRequest{Username: "bob", Email: "bob@example.com", Name: "Bob"})
+				return err
+			},
+			wantCount: 1,
+		},
+		{
+			name: "user rolled back",
+			op: func(ctx context.Context, todos *TodoService, users *UserService) error {
+				_, err := users.CreateUser(ctx, &model.CreateUserRequest{Username: "bob", Email: "bob@example.com", Name: "Bob"})
+				return err
+			},
+			abort: true,
+		},
+		{
+			name: "several changes rolled back",
+			op: func(ctx context.Context, todos *TodoService, users *UserService) error {
+				todo, err := todos.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: 1, Title: "todo"})
+				if err != nil {
+					return err
+				}
+				_, err = todos.UpdateTodo(ctx, todo.ID, &model.UpdateTodoRequest{Title: "renamed"})
+				return err
+			},
+			abort: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := persistence.NewTodoRepository()
+			userRepo := persistence.NewUserRepository()
+			tagRepo := persistence.NewTagRepository()
+			outboxRepo := persistence.NewOutboxRepository()
+			tx := persistence.NewTxManager(todoRepo, userRepo, tagRepo, outboxRepo)
+			log := audit.NewMemoryAuditLog()
+			publisher := NewOutboxPublisher(outboxRepo)
+			todos := NewTodoService(todoRepo, tagRepo, tx, WithTodoAuditLog(log), WithTodoEventPublisher(publisher))
+			users := NewUserService(userRepo, tx, WithUserAuditLog(log), WithUserEventPublisher(publisher))
+
+			err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+				if err := tt.op(ctx, todos, users); err != nil {
+					t.Fatalf("operation failed: %v", err)
+				}
+				if tt.abort {
+					return errAbort
+				}
+				return nil
+			})
+			if tt.abort != errors.Is(err, errAbort) || (!tt.abort && err != nil) {
+				t.Fatalf("WithinTx() error = %v", err)
+			}
+
+			entries, err := log.All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != tt.wantCount {
+				t.Errorf("audit entries = %d, want %d", len(entries), tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// mentionPattern matches @username mentions that are not part of a longer word
+// such as an email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]*\w)`)
+
+// CommentService implements the CommentServicePort interface
+type CommentService struct {
+	repo     port.CommentRepositoryPort
+	todoRepo port.TodoRepositoryPort
+	userRepo port.UserRepositoryPort
+	tx       port.TransactionManagerPort
+	// access, when enabled, subjects the discussions of todos in shared lists to the list roles
+	access todoAccess
+	now    func() time.Time
+}
+
+// CommentServiceOption configures optional CommentService behavior
+type CommentServiceOption func(*CommentService)
+
+// WithCommentListRoles enforces the list roles on the discussions of todos in
+// shared lists: viewers may read them, editors may also comment
+func WithCommentListRoles(lists port.TodoListRepositoryPort, shares port.ListShareRepositoryPort) CommentServiceOption {
+	return func(s *CommentService) {
+		s.access = newTodoAccess(lists, shares)
+	}
+}
+
+// NewCommentService creates a new CommentService. Authors and mentions are
+// resolved through the user repository.
+func NewCommentService(repo port.CommentRepositoryPort, todoRepo port.TodoRepositoryPort, userRepo port.UserRepositoryPort, tx port.TransactionManagerPort, opts ...CommentServiceOption) *CommentService {
+	s := &CommentService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		userRepo: userRepo,
+		tx:       tx,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// resolveMentions returns the IDs of the existing users mentioned in a body,
+// in order of first mention. Unknown usernames are ignored.
+func (s *CommentService) resolveMentions(ctx context.Context, body string) ([]int, error) {
+	mentions := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		user, err := s.userRepo.GetByUsername(ctx, match[1])
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			mentions = append(mentions, user.ID)
+		}
+	}
+	return mentions, nil
+}
+
+// fillAuthors attaches the public profile of each comment's author
+func (s *CommentService) fillAuthors(ctx context.Context, comments ...*model.Comment) error {
+	authors := make(map[int]*model.CommentAuthor)
+	for _, comment := range comments {
+		author, ok := authors[comment.AuthorID]
+		if !ok {
+			user, err := s.userRepo.GetByID(ctx, comment.AuthorID)
+			if err != nil && !errors.Is(err, domain.ErrNotFound) {
+				return err
+			}
+			if user != nil {
+				author = &model.CommentAuthor{ID: user.ID, Username: user.Username, Name: user.Name}
+			}
+			authors[comment.AuthorID] = author
+		}
+		comment.Author = author
+	}
+	return nil
+}
+
+// getTodoComment retrieves a comment that belongs to the given live todo, on
+// which the acting user holds at least the required role
+func (s *CommentService) getTodoComment(ctx context.Context, todoID, id int, required model.ListRole) (*model.Comment, error) {
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, required); err != nil {
+		return nil, err
+	}
+	comment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TodoID != todoID {
+		return nil, domain.ErrNotFound
+	}
+	return comment, nil
+}
+
+// getOwnComment retrieves a live comment written by the acting user
+func (s *CommentService) getOwnComment(ctx context.Context, todoID, id int) (*model.Comment, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	comment, err := s.getTodoComment(ctx, todoID, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted {
+		return nil, domain.ErrCommentDeleted
+	}
+	if comment.AuthorID != actorID {
+		return nil, domain.ErrForbidden
+	}
+	return comment, nil
+}
+
+// AddComment comments on a todo as the acting user, optionally replying to another comment
+func (s *CommentService) AddComment(ctx context.Context, todoID int, req *model.CreateCommentRequest) (*model.Comment, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, domain.ErrInvalidCommentBody
+	}
+	if _, err := s.userRepo.GetByID(ctx, actorID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.ErrUnauthenticated
+		}
+		return nil, err
+	}
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, model.ListRoleEditor); err != nil {
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *req.ParentID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.ErrInvalidParentComment
+			}
+			return nil, err
+		}
+		if parent.TodoID != todoID {
+			return nil, domain.ErrInvalidParentComment
+		}
+		if parent.Deleted {
+			return nil, domain.ErrCommentDeleted
+		}
+	}
+
+	mentions, err := s.resolveMentions(ctx, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	comment := &model.Comment{
+		TodoID:    todoID,
+		ParentID:  req.ParentID,
+		AuthorID:  actorID,
+		Body:      req.Body,
+		Mentions:  mentions,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	if err := s.fillAuthors(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListComments retrieves the discussion of a todo as threads
+func (s *CommentService) ListComments(ctx context.Context, todoID int) ([]*model.Comment, error) {
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListByTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillAuthors(ctx, comments...); err != nil {
+		return nil, err
+	}
+
+	// Comments are ordered by ID, so parents always come before their replies
+	byID := make(map[int]*model.Comment, len(comments))
+	threads := make([]*model.Comment, 0)
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+		if comment.ParentID == nil {
+			threads = append(threads, comment)
+			continue
+		}
+		if parent, ok := byID[*comment.ParentID]; ok {
+			parent.Replies = append(parent.Replies, comment)
+		}
+	}
+	return threads, nil
+}
+
+// UpdateComment edits the acting user's comment, keeping the previous body in its history
+func (s *CommentService) UpdateComment(ctx context.Context, todoID, id int, req *model.UpdateCommentRequest) (*model.Comment, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Comment, error) {
+		return s.updateComment(ctx, todoID, id, req)
+	})
+}
+
+// updateComment edits the acting user's comment, keeping the previous body in its history
+func (s *CommentService) updateComment(ctx context.Context, todoID, id int, req *model.UpdateCommentRequest) (*model.Comment, error) {
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, domain.ErrInvalidCommentBody
+	}
+	comment, err := s.getOwnComment(ctx, todoID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body == comment.Body {
+		if err := s.fillAuthors(ctx, comment); err != nil {
+			return nil, err
+		}
+		return comment, nil
+	}
+
+	mentions, err := s.resolveMentions(ctx, req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	revision := &model.CommentRevision{
+		CommentID: comment.ID,
+		Body:      comment.Body,
+		ChangedAt: now,
+	}
+	comment.Body = req.Body
+	comment.Mentions = mentions
+	comment.Edited = true
+	comment.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AppendRevision(ctx, revision); err != nil {
+		return nil, err
+	}
+	if err := s.fillAuthors(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteComment deletes the acting user's comment. The comment stays in its
+// thread without a body so replies keep their context.
+func (s *CommentService) DeleteComment(ctx context.Context, todoID, id int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.deleteComment(ctx, todoID, id)
+	})
+}
+
+// deleteComment deletes the acting user's comment. The comment stays in its
+// thread without a body so replies keep their context.
+func (s *CommentService) deleteComment(ctx context.Context, todoID, id int) error {
+	comment, err := s.getOwnComment(ctx, todoID, id)
+	if err != nil {
+		return err
+	}
+
+	// The deletion is recorded without the body so it cannot be read back
+	now := s.now()
+	revision := &model.CommentRevision{
+		CommentID: comment.ID,
+		Deleted:   true,
+		ChangedAt: now,
+	}
+	comment.Body = ""
+	comment.Mentions = []int{}
+	comment.Deleted = true
+	comment.UpdatedAt = now
+	comment.DeletedAt = &now
+
+	if err := s.repo.Update(ctx, comment); err != nil {
+		return err
+	}
+	return s.repo.AppendRevision(ctx, revision)
+}
+
+// ListCommentRevisions retrieves the previous bodies of a comment. A deletion
+// revision carries no body.
+func (s *CommentService) ListCommentRevisions(ctx context.Context, todoID, id int) ([]*model.CommentRevision, error) {
+	if _, err := s.getTodoComment(ctx, todoID, id, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRevisions(ctx, id)
+}
+
+// GetActivity builds the activity feed of a todo from its field changes,
+// status transitions and comment history
+func (s *CommentService) GetActivity(ctx context.Context, todoID int) ([]*model.Activity, error) {
+	if _, err := s.access.getTodo(ctx, s.todoRepo, todoID, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	changes, err := s.todoRepo.ListChanges(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	transitions, err := s.todoRepo.ListTransitions(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.repo.ListByTodo(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	activities := make([]*model.Activity, 0, len(changes)+len(transitions)+len(comments))
+	for _, change := range changes {
+		activities = append(activities, &model.Activity{
+			Type:    model.ActivityFieldChanged,
+			TodoID:  todoID,
+			ActorID: change.ActorID,
+			Field:   change.Field,
+			From:    change.From,
+			To:      change.To,
+			At:      change.At,
+		})
+	}
+	for _, transition := range transitions {
+		activities = append(activities, &model.Activity{
+			Type:   model.ActivityStatusChanged,
+			TodoID: todoID,
+			Field:  "status",
+			From:   string(transition.From),
+			To:     string(transition.To),
+			At:     transition.At,
+		})
+	}
+	for _, comment := range comments {
+		commentID, authorID := comment.ID, comment.AuthorID
+		activities = append(activities, &model.Activity{
+			Type:      model.ActivityCommentAdded,
+			TodoID:    todoID,
+			ActorID:   &authorID,
+			CommentID: &commentID,
+			At:        comment.CreatedAt,
+		})
+
+		revisions, err := s.repo.ListRevisions(ctx, comment.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, revision := range revisions {
+			activityType := model.ActivityCommentEdited
+			if revision.Deleted {
+				activityType = model.ActivityCommentDeleted
+			}
+			activities = append(activities, &model.Activity{
+				Type:      activityType,
+				TodoID:    todoID,
+				ActorID:   &authorID,
+				CommentID: &commentID,
+				At:        revision.ChangedAt,
+			})
+		}
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool { return activities[i].At.Before(activities[j].At) })
+	return activities, nil
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// commentFixture holds alice's (1) todo in a list she shares with bob (2) as
+// editor and carol (3) as viewer; dave (4) is a stranger to the list
+type commentFixture struct {
+	*listFixture
+	comments *CommentService
+	listID   int
+	todoID   int
+}
+
+func newCommentFixture(t *testing.T) *commentFixture {
+	t.Helper()
+	ctx := context.Background()
+	f := newListFixture()
+	userRepo := persistence.NewUserRepository()
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		if err := userRepo.Create(ctx, &model.User{Username: name, Email: name + "@example.com", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commentRepo := persistence.NewCommentRepository()
+	comments := NewCommentService(commentRepo, f.todos.TodoRepository(), userRepo, persistence.NewTxManager(commentRepo),
+		WithCommentListRoles(f.listRepo, f.shares))
+
+	listID, todoID := f.sharedTodo(t)
+	return &commentFixture{listFixture: f, comments: comments, listID: listID, todoID: todoID}
+}
+
+// as returns a context acting as the user, or an anonymous one for 0
+func as(userID int) context.Context {
+	if userID == 0 {
+		return context.Background()
+	}
+	return domain.ContextWithActor(context.Background(), userID)
+}
+
+func (f *commentFixture) mustComment(t *testing.T, userID int, body string, parentID *int) *model.Comment {
+	t.Helper()
+	comment, err := f.comments.AddComment(as(userID), f.todoID, &model.CreateCommentRequest{Body: body, ParentID: parentID})
+	if err != nil {
+		t.Fatalf("AddComment(%q) error = %v", body, err)
+	}
+	return comment
+}
+
+func TestCommentListRoles(t *testing.T) {
+	actors := []struct {
+		name      string
+		actorID   int
+		wantRead  error
+		wantWrite error
+	}{
+		{name: "list owner", actorID: 1},
+		{name: "editor", actorID: 2},
+		{name: "viewer", actorID: 3, wantWrite: domain.ErrForbidden},
+		{name: "stranger", actorID: 4, wantRead: domain.ErrNotFound, wantWrite: domain.ErrNotFound},
+		{name: "anonymous", wantRead: domain.ErrUnauthenticated, wantWrite: domain.ErrUnauthenticated},
+	}
+	ops := []struct {
+		name  string
+		write bool
+		op    func(ctx context.Context, f *commentFixture, commentID int) error
+	}{
+		{name: "add comment", write: true, op: func(ctx context.Context, f *commentFixture, _ int) error {
+			_, err := f.comments.AddComment(ctx, f.todoID, &model.CreateCommentRequest{Body: "hello"})
+			return err
+		}},
+		{name: "list comments", op: func(ctx context.Context, f *commentFixture, _ int) error {
+			_, err := f.comments.ListComments(ctx, f.todoID)
+			return err
+		}},
+		{name: "list revisions", op: func(ctx context.Context, f *commentFixture, commentID int) error {
+			_, err := f.comments.ListCommentRevisions(ctx, f.todoID, commentID)
+			return err
+		}},
+		{name: "activity", op: func(ctx context.Context, f *commentFixture, _ int) error {
+			_, err := f.comments.GetActivity(ctx, f.todoID)
+			return err
+		}},
+	}
+
+	for _, actor := range actors {
+		for _, op := range ops {
+			t.Run(actor.name+"/"+op.name, func(t *testing.T) {
+				f := newCommentFixture(t)
+				comment := f.mustComment(t, 1, "first", nil)
+
+				want := actor.wantRead
+				if op.write {
+					want = actor.wantWrite
+				}
+				if err := op.op(as(actor.actorID), f, comment.ID); !errors.Is(err, want) {
+					t.Errorf("error = %v, want %v", err, want)
+				}
+			})
+		}
+	}
+}
+
+func TestEditingOwnCommentNeedsEditor(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func(ctx context.Context, f *commentFixture, commentID int) error
+		wantErr error
+	}{
+		{name: "update", op: func(ctx context.Context, f *commentFixture, commentID int) error {
+			_, err := f.comments.UpdateComment(ctx, f.todoID, commentID, &model.UpdateCommentRequest{Body: "edited"})
+			return err
+		}, wantErr: domain.ErrForbidden},
+		{name: "delete", op: func(ctx context.Context, f *commentFixture, commentID int) error {
+			return f.comments.DeleteComment(ctx, f.todoID, commentID)
+		}, wantErr: domain.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCommentFixture(t)
+			comment := f.mustComment(t, 2, "written as editor", nil)
+			if err := f.shares.SaveMember(as(1), &model.ListMember{ListID: f.listID, UserID: 2, Role: model.ListRoleViewer}); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.op(as(2), f, comment.ID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCommentsOnMissingTodos(t *testing.T) {
+	ops := []struct {
+		name string
+		op   func(ctx context.Context, f *commentFixture, todoID int) error
+	}{
+		{name: "add comment", op: func(ctx context.Context, f *commentFixture, todoID int) error {
+			_, err := f.comments.AddComment(ctx, todoID, &model.CreateCommentRequest{Body: "hello"})
+			return err
+		}},
+		{name: "list comments", op: func(ctx context.Context, f *commentFixture, todoID int) error {
+			_, err := f.comments.ListComments(ctx, todoID)
+			return err
+		}},
+		{name: "list revisions", op: func(ctx context.Context, f *commentFixture, todoID int) error {
+			_, err := f.comments.ListCommentRevisions(ctx, todoID, 1)
+			return err
+		}},
+		{name: "activity", op: func(ctx context.Context, f *commentFixture, todoID int) error {
+			_, err := f.comments.GetActivity(ctx, todoID)
+			return err
+		}},
+	}
+	todos := []struct {
+		name  string
+		setup func(t *testing.T, f *commentFixture) int
+	}{
+		{name: "unknown todo", setup: func(t *testing.T, f *commentFixture) int { return 99 }},
+		{name: "trashed todo", setup: func(t *testing.T, f *commentFixture) int {
+			if err := f.todos.DeleteTodo(as(1), f.todoID); err != nil {
+				t.Fatal(err)
+			}
+			return f.todoID
+		}},
+	}
+
+	for _, todo := range todos {
+		for _, op := range ops {
+			t.Run(todo.name+"/"+op.name, func(t *testing.T) {
+				f := newCommentFixture(t)
+				f.mustComment(t, 1, "first", nil)
+				todoID := todo.setup(t, f)
+				if err := op.op(as(1), f, todoID); !errors.Is(err, domain.ErrNotFound) {
+					t.Errorf("error = %v, want %v", err, domain.ErrNotFound)
+				}
+			})
+		}
+	}
+}
+
+func TestCommentThreadsAndMentions(t *testing.T) {
+	f := newCommentFixture(t)
+	ctx := as(1)
+	parent := f.mustComment(t, 1, "@bob and @carol, see bob@example.com or @nobody", nil)
+	if !slices.Equal(parent.Mentions, []int{2, 3}) {
+		t.Errorf("mentions = %v, want [2 3]", parent.Mentions)
+	}
+	if parent.Author == nil || parent.Author.Username != "alice" {
+		t.Errorf("author = %+v, want alice", parent.Author)
+	}
+	reply := f.mustComment(t, 2, "on it", &parent.ID)
+
+	other := mustCreateTodo(t, f.todos, "other", nil)
+	_, err := f.comments.AddComment(ctx, other.ID, &model.CreateCommentRequest{Body: "misplaced", ParentID: &parent.ID})
+	if !errors.Is(err, domain.ErrInvalidParentComment) {
+		t.Errorf("reply on another todo: error = %v, want %v", err, domain.ErrInvalidParentComment)
+	}
+	if _, err := f.comments.AddComment(ctx, f.todoID, &model.CreateCommentRequest{Body: "  "}); !errors.Is(err, domain.ErrInvalidCommentBody) {
+		t.Errorf("blank comment: error = %v, want %v", err, domain.ErrInvalidCommentBody)
+	}
+
+	threads, err := f.comments.ListComments(ctx, f.todoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 1 || threads[0].ID != parent.ID || len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Errorf("threads = %+v, want comment %d with reply %d", threads, parent.ID, reply.ID)
+	}
+}
+
+func TestCommentRevisions(t *testing.T) {
+	f := newCommentFixture(t)
+	comment := f.mustComment(t, 2, "first draft", nil)
+	if _, err := f.comments.UpdateComment(as(3), f.todoID, comment.ID, &model.UpdateCommentRequest{Body: "hijacked"}); !errors.Is(err, domain.ErrForbidden) {
+		t.Errorf("editing someone else's comment: error = %v, want %v", err, domain.ErrForbidden)
+	}
+	edited, err := f.comments.UpdateComment(as(2), f.todoID, comment.ID, &model.UpdateCommentRequest{Body: "second draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !edited.Edited || edited.Body != "second draft" {
+		t.Errorf("edited comment = %+v", edited)
+	}
+	if err := f.comments.DeleteComment(as(2), f.todoID, comment.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.comments.DeleteComment(as(2), f.todoID, comment.ID); !errors.Is(err, domain.ErrCommentDeleted) {
+		t.Errorf("deleting twice: error = %v, want %v", err, domain.ErrCommentDeleted)
+	}
+
+	// A viewer sees the history without the body the author deleted
+	revisions, err := f.comments.ListCommentRevisions(as(3), f.todoID, comment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[0].Body != "first draft" || revisions[0].Deleted || revisions[1].Body != "" || !revisions[1].Deleted {
+		t.Errorf("revisions = %+v, want the first draft then a deletion without body", revisions)
+	}
+	threads, err := f.comments.ListComments(as(3), f.todoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(threads) != 1 || !threads[0].Deleted || threads[0].Body != "" {
+		t.Errorf("threads = %+v, want the deleted comment without body", threads)
+	}
+
+	activity, err := f.comments.GetActivity(as(3), f.todoID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []model.ActivityType
+	for _, entry := range activity {
+		if entry.CommentID != nil {
+			types = append(types, entry.Type)
+		}
+	}
+	want := []model.ActivityType{model.ActivityCommentAdded, model.ActivityCommentEdited, model.ActivityCommentDeleted}
+	if !slices.Equal(types, want) {
+		t.Errorf("comment activity = %v, want %v", types, want)
+	}
+}
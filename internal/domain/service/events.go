@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// eventFactory stamps domain events with an ID, time, actor and request. The
+// eventing repositories store a change and publish its events in one
+// transaction, so with an outbox publisher neither is kept without the other.
+type eventFactory struct {
+	publisher port.EventPublisherPort
+	tx        port.TransactionManagerPort
+	now       func() time.Time
+}
+
+// newEvent creates an event about an aggregate attributed to the actor and request in the context
+func (f eventFactory) newEvent(ctx context.Context, eventType model.EventType, aggregateType string, aggregateID int) model.Event {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	event := model.Event{
+		ID:            hex.EncodeToString(id),
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredAt:    f.now().UTC(),
+		RequestID:     domain.RequestIDFromContext(ctx),
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		event.ActorID = &actorID
+	}
+	return event
+}
+
+// todoEvents creates events carrying a snapshot of the todo
+func (f eventFactory) todoEvents(ctx context.Context, todo *model.Todo, types ...model.EventType) []model.Event {
+	events := make([]model.Event, len(types))
+	for i, eventType := range types {
+		events[i] = f.newEvent(ctx, eventType, model.AggregateTodo, todo.ID)
+		events[i].Todo = todo.Clone()
+	}
+	return events
+}
+
+// userEvent creates an event carrying a snapshot of the user
+func (f eventFactory) userEvent(ctx context.Context, user *model.User, eventType model.EventType) model.Event {
+	event := f.newEvent(ctx, eventType, model.AggregateUser, user.ID)
+	snapshot := *user
+	event.User = &snapshot
+	return event
+}
+
+// memberEvent creates an event carrying a snapshot of the list member
+func (f eventFactory) memberEvent(ctx context.Context, member *model.ListMember, eventType model.EventType) model.Event {
+	event := f.newEvent(ctx, eventType, model.AggregateList, member.ListID)
+	snapshot := *member
+	event.Member = &snapshot
+	return event
+}
+
+// eventingTodoRepository publishes a domain event for every change written
+// through a todo repository, so changes made as side effects (auto-completed
+// parents, spawned occurrences) are announced as well as direct ones
+type eventingTodoRepository struct {
+	port.TodoRepositoryPort
+	events eventFactory
+}
+
+// Create creates the todo and publishes TodoCreated
+func (r *eventingTodoRepository) Create(ctx context.Context, todo *model.Todo) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.TodoRepositoryPort.Create(ctx, todo); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.todoEvents(ctx, todo, model.EventTodoCreated)...)
+	})
+}
+
+// Update updates the todo and publishes TodoUpdated, followed by TodoCompleted
+// when the update moved the todo to done
+func (r *eventingTodoRepository) Update(ctx context.Context, todo *model.Todo) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := r.TodoRepositoryPort.GetByID(ctx, todo.ID)
+		if err != nil {
+			return err
+		}
+		if err := r.TodoRepositoryPort.Update(ctx, todo); err != nil {
+			return err
+		}
+
+		types := []model.EventType{model.EventTodoUpdated}
+		if before.Status != model.TodoStatusDone && todo.Status == model.TodoStatusDone {
+			types = append(types, model.EventTodoCompleted)
+		}
+		events := r.events.todoEvents(ctx, todo, types...)
+		if before.ListID != nil && (todo.ListID == nil || *todo.ListID != *before.ListID) {
+			for i := range events {
+				events[i].PreviousListID = before.ListID
+			}
+		}
+		return r.events.publisher.Publish(ctx, events...)
+	})
+}
+
+// SoftDelete moves the todo to the trash and publishes TodoDeleted
+func (r *eventingTodoRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		todo, err := r.TodoRepositoryPort.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := r.TodoRepositoryPort.SoftDelete(ctx, id, deletedAt); err != nil {
+			return err
+		}
+		todo.DeletedAt = &deletedAt
+		return r.events.publisher.Publish(ctx, r.events.todoEvents(ctx, todo, model.EventTodoDeleted)...)
+	})
+}
+
+// Restore moves the todo out of the trash and publishes TodoRestored
+func (r *eventingTodoRepository) Restore(ctx context.Context, id int) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.TodoRepositoryPort.Restore(ctx, id); err != nil {
+			return err
+		}
+		todo, err := r.TodoRepositoryPort.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.todoEvents(ctx, todo, model.EventTodoRestored)...)
+	})
+}
+
+// Delete permanently deletes the todo and publishes TodoPurged
+func (r *eventingTodoRepository) Delete(ctx context.Context, id int) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		todo, err := getTodoIncludingDeleted(ctx, r.TodoRepositoryPort, id)
+		if err != nil {
+			return err
+		}
+		if err := r.TodoRepositoryPort.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.todoEvents(ctx, todo, model.EventTodoPurged)...)
+	})
+}
+
+// ReplaceTag swaps a tag for another one and publishes TodoUpdated for every todo carrying it
+func (r *eventingTodoRepository) ReplaceTag(ctx context.Context, oldTagID, newTagID int) error {
+	return r.publishRetag(ctx, oldTagID, func(ctx context.Context) error {
+		return r.TodoRepositoryPort.ReplaceTag(ctx, oldTagID, newTagID)
+	})
+}
+
+// RemoveTag detaches a tag and publishes TodoUpdated for every todo carrying it
+func (r *eventingTodoRepository) RemoveTag(ctx context.Context, tagID int) error {
+	return r.publishRetag(ctx, tagID, func(ctx context.Context) error {
+		return r.TodoRepositoryPort.RemoveTag(ctx, tagID)
+	})
+}
+
+// publishRetag runs retag and publishes TodoUpdated for every todo it changed
+func (r *eventingTodoRepository) publishRetag(ctx context.Context, tagID int, retag func(ctx context.Context) error) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		_, after, err := retagTodos(ctx, r.TodoRepositoryPort, tagID, retag)
+		if err != nil {
+			return err
+		}
+		var events []model.Event
+		for _, todo := range after {
+			events = append(events, r.events.todoEvents(ctx, todo, model.EventTodoUpdated)...)
+		}
+		return r.events.publisher.Publish(ctx, events...)
+	})
+}
+
+// eventingUserRepository publishes a domain event for every change written through a user repository
+type eventingUserRepository struct {
+	port.UserRepositoryPort
+	events eventFactory
+}
+
+// Create creates the user and publishes UserRegistered
+func (r *eventingUserRepository) Create(ctx context.Context, user *model.User) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.UserRepositoryPort.Create(ctx, user); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.userEvent(ctx, user, model.EventUserRegistered))
+	})
+}
+
+// Update updates the user and publishes UserUpdated
+func (r *eventingUserRepository) Update(ctx context.Context, user *model.User) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.UserRepositoryPort.Update(ctx, user); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.userEvent(ctx, user, model.EventUserUpdated))
+	})
+}
+
+// Rename renames the user and publishes UserRenamed
+func (r *eventingUserRepository) Rename(ctx context.Context, id int, username string, history *model.UsernameHistory) (*model.User, error) {
+	return withinTx(ctx, r.events.tx, func(ctx context.Context) (*model.User, error) {
+		user, err := r.UserRepositoryPort.Rename(ctx, id, username, history)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.events.publisher.Publish(ctx, r.events.userEvent(ctx, user, model.EventUserRenamed)); err != nil {
+			return nil, err
+		}
+		return user, nil
+	})
+}
+
+// SoftDelete moves the user to the trash and publishes UserDeleted
+func (r *eventingUserRepository) SoftDelete(ctx context.Context, id int, deletedAt time.Time) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := r.UserRepositoryPort.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := r.UserRepositoryPort.SoftDelete(ctx, id, deletedAt); err != nil {
+			return err
+		}
+		user.DeletedAt = &deletedAt
+		return r.events.publisher.Publish(ctx, r.events.userEvent(ctx, user, model.EventUserDeleted))
+	})
+}
+
+// Restore moves the user out of the trash and publishes UserRestored
+func (r *eventingUserRepository) Restore(ctx context.Context, id int) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.UserRepositoryPort.Restore(ctx, id); err != nil {
+			return err
+		}
+		user, err := r.UserRepositoryPort.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.userEvent(ctx, user, model.EventUserRestored))
+	})
+}
+
+// Delete permanently deletes the user and publishes UserPurged
+func (r *eventingUserRepository) Delete(ctx context.Context, id int) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		user, err := getUserIncludingDeleted(ctx, r.UserRepositoryPort, id)
+		if err != nil {
+			return err
+		}
+		if err := r.UserRepositoryPort.Delete(ctx, id); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.userEvent(ctx, user, model.EventUserPurged))
+	})
+}
+
+// eventingListShareRepository publishes a domain event for every change to
+// list memberships written through a list share repository
+type eventingListShareRepository struct {
+	port.ListShareRepositoryPort
+	events eventFactory
+}
+
+// SaveMember saves the member and publishes ListMemberAdded, or
+// ListMemberUpdated when they already were a member
+func (r *eventingListShareRepository) SaveMember(ctx context.Context, member *model.ListMember) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		eventType := model.EventListMemberAdded
+		if _, err := r.ListShareRepositoryPort.GetMember(ctx, member.ListID, member.UserID); err == nil {
+			eventType = model.EventListMemberUpdated
+		} else if !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if err := r.ListShareRepositoryPort.SaveMember(ctx, member); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.memberEvent(ctx, member, eventType))
+	})
+}
+
+// DeleteMember removes the member and publishes ListMemberRemoved
+func (r *eventingListShareRepository) DeleteMember(ctx context.Context, listID, userID int) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		member, err := r.ListShareRepositoryPort.GetMember(ctx, listID, userID)
+		if err != nil {
+			return err
+		}
+		if err := r.ListShareRepositoryPort.DeleteMember(ctx, listID, userID); err != nil {
+			return err
+		}
+		return r.events.publisher.Publish(ctx, r.events.memberEvent(ctx, member, model.EventListMemberRemoved))
+	})
+}
+
+// DeleteByList removes a list's members and invitations and publishes
+// ListMemberRemoved for every member
+func (r *eventingListShareRepository) DeleteByList(ctx context.Context, listID int) error {
+	return r.events.tx.WithinTx(ctx, func(ctx context.Context) error {
+		members, err := r.ListShareRepositoryPort.ListMembers(ctx, listID)
+		if err != nil {
+			return err
+		}
+		if err := r.ListShareRepositoryPort.DeleteByList(ctx, listID); err != nil {
+			return err
+		}
+		events := make([]model.Event, len(members))
+		for i, member := range members {
+			events[i] = r.events.memberEvent(ctx, member, model.EventListMemberRemoved)
+		}
+		return r.events.publisher.Publish(ctx, events...)
+	})
+}
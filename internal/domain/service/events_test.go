@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/audit"
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// recordingPublisher keeps the events published to it
+type recordingPublisher struct {
+	events []model.Event
+}
+
+// Publish records the events
+func (p *recordingPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func TestSideEffectTodoChangesAreRecorded(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(ctx context.Context, tags *TagService, lists *TodoListService, ids fixtureIDs) error
+		// wantTodos lists the todos whose change must be audited and announced, in order
+		wantTodos []int
+	}{
+		{
+			name: "merge tags",
+			op: func(ctx context.Context, tags *TagService, lists *TodoListService, ids fixtureIDs) error {
+				_, err := tags.MergeTags(ctx, ids.tag, &model.MergeTagsRequest{TargetID: ids.otherTag})
+				return err
+			},
+			wantTodos: []int{1, 2},
+		},
+		{
+			name: "delete tag",
+			op: func(ctx context.Context, tags *TagService, lists *TodoListService, ids fixtureIDs) error {
+				return tags.DeleteTag(ctx, ids.tag)
+			},
+			wantTodos: []int{1, 2},
+		},
+		{
+			name: "add to list",
+			op: func(ctx context.Context, tags *TagService, lists *TodoListService, ids fixtureIDs) error {
+				_, err := lists.AddTodo(ctx, ids.list, 3)
+				return err
+			},
+			wantTodos: []int{3},
+		},
+		{
+			name: "move in list",
+			op: func(ctx context.Context, tags *TagService, lists *TodoListService, ids fixtureIDs) error {
+				if _, err := lists.AddTodo(ctx, ids.list, 3); err != nil {
+					return err
+				}
+				_, err := lists.MoveTodo(ctx, ids.list, 1, &model.MoveTodoRequest{AfterID: ptr(3)})
+				return err
+			},
+			wantTodos: []int{3, 1},
+		},
+		{
+			name: "remove from list",
+			op: func(ctx context.Context, tags *TagService, lists *TodoListService, ids fixtureIDs) error {
+				_, err := lists.RemoveTodo(ctx, ids.list, 1)
+				return err
+			},
+			wantTodos: []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := persistence.NewTodoRepository()
+			tagRepo := persistence.NewTagRepository()
+			listRepo := persistence.NewTodoListRepository()
+			shareRepo := persistence.NewListShareRepository()
+			tx := persistence.NewTxManager(todoRepo, tagRepo, listRepo, shareRepo)
+			log := audit.NewMemoryAuditLog()
+			publisher := &recordingPublisher{}
+			todos := NewTodoService(todoRepo, tagRepo, tx, WithTodoAuditLog(log), WithTodoEventPublisher(publisher))
+			tags := NewTagService(tagRepo, todos.TodoRepository(), tx)
+			lists := NewTodoListService(listRepo, shareRepo, todos.TodoRepository(), todos, tx)
+
+			ctx := domain.ContextWithActor(context.Background(), 1)
+			ids := fixtureIDs{}
+			tag, err := tags.CreateTag(ctx, &model.CreateTagRequest{OwnerID: 1, Name: "work"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			otherTag, err := tags.CreateTag(ctx, &model.CreateTagRequest{OwnerID: 1, Name: "home"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			list, err := lists.CreateList(ctx, &model.CreateTodoListRequest{Name: "chores"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids.tag, ids.otherTag, ids.list = tag.ID, otherTag.ID, list.ID
+			for _, title := range []string{"listed", "trashed", "loose"} {
+				mustCreateTodo(t, todos, title, nil)
+			}
+			for _, id := range []int{1, 2} {
+				if _, err := todos.AttachTag(ctx, id, tag.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := lists.AddTodo(ctx, list.ID, 1); err != nil {
+				t.Fatal(err)
+			}
+			if err := todos.DeleteTodo(ctx, 2); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := log.All(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			auditedBefore, publishedBefore := len(entries), len(publisher.events)
+
+			if err := tt.op(ctx, tags, lists, ids); err != nil {
+				t.Fatalf("operation failed: %v", err)
+			}
+
+			entries, err = log.All(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var audited []int
+			for _, entry := range entries[auditedBefore:] {
+				if entry.EntityType == model.AuditEntityTodo && entry.Action == model.AuditActionUpdate {
+					audited = append(audited, entry.EntityID)
+				}
+			}
+			var announced []int
+			for _, event := range publisher.events[publishedBefore:] {
+				if event.Type == model.EventTodoUpdated {
+					announced = append(announced, event.AggregateID)
+				}
+			}
+			if !slices.Equal(audited, tt.wantTodos) {
+				t.Errorf("audited todo updates = %v, want %v", audited, tt.wantTodos)
+			}
+			if !slices.Equal(announced, tt.wantTodos) {
+				t.Errorf("announced todo updates = %v, want %v", announced, tt.wantTodos)
+			}
+		})
+	}
+}
+
+// fixtureIDs names the tags and list set up for a test
+type fixtureIDs struct {
+	tag, otherTag, list int
+}
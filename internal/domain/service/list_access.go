@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// listAccess resolves what the acting user may do on a todo list. The list
+// owner holds the owner role; collaborators hold the role of their membership.
+type listAccess struct {
+	shareRepo port.ListShareRepositoryPort
+}
+
+// roleOf returns the user's role on the list, or an empty role without access
+func (a listAccess) roleOf(ctx context.Context, list *model.TodoList, userID int) (model.ListRole, error) {
+	if list.OwnerID == userID {
+		return model.ListRoleOwner, nil
+	}
+
+	member, err := a.shareRepo.GetMember(ctx, list.ID, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// authorize checks that the acting user holds at least the required role on
+// the list and returns their user ID
+func (a listAccess) authorize(ctx context.Context, list *model.TodoList, required model.ListRole) (int, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return 0, domain.ErrUnauthenticated
+	}
+
+	role, err := a.roleOf(ctx, list, actorID)
+	if err != nil {
+		return 0, err
+	}
+	if role == "" {
+		// Do not reveal lists the user cannot see
+		return 0, domain.ErrNotFound
+	}
+	if !role.Includes(required) {
+		return 0, domain.ErrForbidden
+	}
+	return actorID, nil
+}
@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// ListShareService implements the ListShareServicePort interface
+type ListShareService struct {
+	repo     port.ListShareRepositoryPort
+	listRepo port.TodoListRepositoryPort
+	userRepo port.UserRepositoryPort
+	access   listAccess
+	tx       port.TransactionManagerPort
+	now      func() time.Time
+}
+
+// ListShareServiceOption configures optional ListShareService behavior
+type ListShareServiceOption func(*ListShareService)
+
+// WithListShareEventPublisher publishes a domain event whenever someone joins
+// or leaves a list or their role changes, including when a list is deleted
+func WithListShareEventPublisher(publisher port.EventPublisherPort) ListShareServiceOption {
+	return func(s *ListShareService) {
+		s.repo = &eventingListShareRepository{
+			ListShareRepositoryPort: s.repo,
+			events:                  eventFactory{publisher: publisher, tx: s.tx, now: time.Now},
+		}
+	}
+}
+
+// NewListShareService creates a new ListShareService. Invitees are looked up
+// by username through the user repository.
+func NewListShareService(repo port.ListShareRepositoryPort, listRepo port.TodoListRepositoryPort, userRepo port.UserRepositoryPort, tx port.TransactionManagerPort, opts ...ListShareServiceOption) *ListShareService {
+	s := &ListShareService{
+		repo:     repo,
+		listRepo: listRepo,
+		userRepo: userRepo,
+		tx:       tx,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.access = listAccess{shareRepo: s.repo}
+	return s
+}
+
+// ShareRepository returns the repository the service writes memberships
+// through, which announces every change it is configured to. Deleting a list
+// removes its members through it too.
+func (s *ListShareService) ShareRepository() port.ListShareRepositoryPort {
+	return s.repo
+}
+
+// getAuthorizedList retrieves a list the acting user holds at least the required role on
+func (s *ListShareService) getAuthorizedList(ctx context.Context, id int, required model.ListRole) (*model.TodoList, int, error) {
+	list, err := s.listRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	actorID, err := s.access.authorize(ctx, list, required)
+	if err != nil {
+		return nil, 0, err
+	}
+	return list, actorID, nil
+}
+
+// ListMembers retrieves everyone with access to a list, starting with its owner
+func (s *ListShareService) ListMembers(ctx context.Context, listID int) ([]*model.ListMember, error) {
+	list, _, err := s.getAuthorizedList(ctx, listID, model.ListRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.ListMembers(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	owner := &model.ListMember{
+		ListID:  list.ID,
+		UserID:  list.OwnerID,
+		Role:    model.ListRoleOwner,
+		AddedAt: list.CreatedAt,
+	}
+	return append([]*model.ListMember{owner}, members...), nil
+}
+
+// UpdateMember changes a collaborator's role
+func (s *ListShareService) UpdateMember(ctx context.Context, listID, userID int, req *model.UpdateListMemberRequest) (*model.ListMember, error) {
+	if !req.Role.IsShareable() {
+		return nil, domain.ErrInvalidListRole
+	}
+	if _, _, err := s.getAuthorizedList(ctx, listID, model.ListRoleOwner); err != nil {
+		return nil, err
+	}
+
+	member, err := s.repo.GetMember(ctx, listID, userID)
+	if err != nil {
+		return nil, err
+	}
+	member.Role = req.Role
+
+	if err := s.repo.SaveMember(ctx, member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember revokes a collaborator's access. The owner may remove anyone;
+// collaborators may only remove themselves to leave the list.
+func (s *ListShareService) RemoveMember(ctx context.Context, listID, userID int) error {
+	list, err := s.listRepo.GetByID(ctx, listID)
+	if err != nil {
+		return err
+	}
+	required := model.ListRoleOwner
+	if actorID, ok := domain.ActorFromContext(ctx); ok && actorID == userID {
+		required = model.ListRoleViewer
+	}
+	if _, err := s.access.authorize(ctx, list, required); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetMember(ctx, listID, userID); err != nil {
+		return err
+	}
+	return s.repo.DeleteMember(ctx, listID, userID)
+}
+
+// Invite invites a user, by username, to collaborate on a list
+func (s *ListShareService) Invite(ctx context.Context, listID int, req *model.InviteToListRequest) (*model.ListInvitation, error) {
+	if !req.Role.IsShareable() {
+		return nil, domain.ErrInvalidListRole
+	}
+	list, actorID, err := s.getAuthorizedList(ctx, listID, model.ListRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if list.Archived {
+		return nil, domain.ErrListArchived
+	}
+
+	invitee, err := s.userRepo.GetByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.access.roleOf(ctx, list, invitee.ID)
+	if err != nil {
+		return nil, err
+	}
+	if role != "" {
+		return nil, domain.ErrAlreadyListMember
+	}
+
+	invitations, err := s.repo.ListInvitations(ctx, invitee.ID, model.InvitationPending)
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if invitation.ListID == listID {
+			return nil, domain.ErrInvitationPending
+		}
+	}
+
+	invitation := &model.ListInvitation{
+		ListID:          listID,
+		InviterID:       actorID,
+		InviteeID:       invitee.ID,
+		InviteeUsername: invitee.Username,
+		Role:            req.Role,
+		Status:          model.InvitationPending,
+		CreatedAt:       s.now(),
+	}
+	if err := s.repo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListListInvitations retrieves every invitation sent for a list
+func (s *ListShareService) ListListInvitations(ctx context.Context, listID int) ([]*model.ListInvitation, error) {
+	if _, _, err := s.getAuthorizedList(ctx, listID, model.ListRoleOwner); err != nil {
+		return nil, err
+	}
+	return s.repo.ListInvitationsForList(ctx, listID)
+}
+
+// ListMyInvitations retrieves the acting user's pending invitations
+func (s *ListShareService) ListMyInvitations(ctx context.Context) ([]*model.ListInvitation, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	return s.repo.ListInvitations(ctx, actorID, model.InvitationPending)
+}
+
+// getPendingInvitation retrieves a pending invitation addressed to the acting user
+func (s *ListShareService) getPendingInvitation(ctx context.Context, id int) (*model.ListInvitation, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	invitation, err := s.repo.GetInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.InviteeID != actorID {
+		return nil, domain.ErrNotFound
+	}
+	if invitation.Status != model.InvitationPending {
+		return nil, domain.ErrInvitationNotPending
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation makes the acting user a collaborator on the invited list
+func (s *ListShareService) AcceptInvitation(ctx context.Context, id int) (*model.ListMember, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.ListMember, error) {
+		return s.acceptInvitation(ctx, id)
+	})
+}
+
+// acceptInvitation makes the acting user a collaborator on the invited list
+func (s *ListShareService) acceptInvitation(ctx context.Context, id int) (*model.ListMember, error) {
+	invitation, err := s.getPendingInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	member := &model.ListMember{
+		ListID:  invitation.ListID,
+		UserID:  invitation.InviteeID,
+		Role:    invitation.Role,
+		AddedAt: now,
+	}
+	if err := s.repo.SaveMember(ctx, member); err != nil {
+		return nil, err
+	}
+
+	invitation.Status = model.InvitationAccepted
+	invitation.RespondedAt = &now
+	if err := s.repo.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// DeclineInvitation declines an invitation addressed to the acting user
+func (s *ListShareService) DeclineInvitation(ctx context.Context, id int) (*model.ListInvitation, error) {
+	invitation, err := s.getPendingInvitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	invitation.Status = model.InvitationDeclined
+	invitation.RespondedAt = &now
+	if err := s.repo.UpdateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListSharedWithMe retrieves the active lists other users have shared with the acting user
+func (s *ListShareService) ListSharedWithMe(ctx context.Context) ([]*model.TodoList, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	memberships, err := s.repo.ListMemberships(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	lists := make([]*model.TodoList, 0, len(memberships))
+	for _, membership := range memberships {
+		list, err := s.listRepo.GetByID(ctx, membership.ListID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		if !list.Archived {
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// OutboxPublisher implements the EventPublisherPort interface by storing events
+// in the outbox instead of delivering them. Services publish through it so an
+// event is recorded together with the change that caused it; the OutboxRelay
+// delivers it afterwards.
+type OutboxPublisher struct {
+	repo port.OutboxRepositoryPort
+	now  func() time.Time
+}
+
+// NewOutboxPublisher creates a new OutboxPublisher
+func NewOutboxPublisher(repo port.OutboxRepositoryPort) *OutboxPublisher {
+	return &OutboxPublisher{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Publish stores the events in the outbox, ready to be relayed immediately
+func (p *OutboxPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	now := p.now()
+	entries := make([]*model.OutboxEntry, len(events))
+	for i, event := range events {
+		entries[i] = &model.OutboxEntry{
+			Event:         event,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+	}
+	return p.repo.Append(ctx, entries)
+}
+
+// OutboxRelay delivers outbox entries to an event publisher. Entries are
+// marked published only after the publisher accepted them, so a crash in
+// between delivers them again: delivery is at least once. A failed entry is
+// retried with exponential backoff and holds back the later entries of its
+// aggregate, keeping per-aggregate order.
+type OutboxRelay struct {
+	repo      port.OutboxRepositoryPort
+	publisher port.EventPublisherPort
+	// processed, when set, is cleaned up together with the outbox
+	processed  port.ProcessedEventRepositoryPort
+	batchSize  int
+	maxBackoff time.Duration
+	retention  time.Duration
+	now        func() time.Time
+}
+
+// OutboxRelayOption configures optional OutboxRelay behavior
+type OutboxRelayOption func(*OutboxRelay)
+
+// WithOutboxBatchSize sets how many entries are relayed per run
+func WithOutboxBatchSize(n int) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.batchSize = n
+	}
+}
+
+// WithOutboxMaxBackoff caps the wait between retries of a failing entry
+func WithOutboxMaxBackoff(d time.Duration) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.maxBackoff = d
+	}
+}
+
+// WithOutboxRetention sets how long published entries are kept before they are removed
+func WithOutboxRetention(d time.Duration) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.retention = d
+	}
+}
+
+// WithProcessedEventCleanup removes the records consumers keep to skip
+// duplicate events once they are past the retention period, like published
+// entries. An event is only relayed again while it is pending, so its records
+// are not needed after it left the outbox.
+func WithProcessedEventCleanup(repo port.ProcessedEventRepositoryPort) OutboxRelayOption {
+	return func(r *OutboxRelay) {
+		r.processed = repo
+	}
+}
+
+// NewOutboxRelay creates a new OutboxRelay
+func NewOutboxRelay(repo port.OutboxRepositoryPort, publisher port.EventPublisherPort, opts ...OutboxRelayOption) *OutboxRelay {
+	r := &OutboxRelay{
+		repo:       repo,
+		publisher:  publisher,
+		batchSize:  100,
+		maxBackoff: 5 * time.Minute,
+		retention:  24 * time.Hour,
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// backoff returns the wait before the next attempt after the given number of failed attempts
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	d := time.Second
+	for i := 1; i < attempts && d < r.maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, r.maxBackoff)
+}
+
+// RelayOnce publishes the pending entries that are due and returns how many were published
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	entries, err := r.repo.ListPending(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	type aggregateKey struct {
+		aggregateType string
+		aggregateID   int
+	}
+	blocked := make(map[aggregateKey]bool)
+
+	published := 0
+	for _, entry := range entries {
+		key := aggregateKey{aggregateType: entry.Event.AggregateType, aggregateID: entry.Event.AggregateID}
+		if blocked[key] {
+			continue
+		}
+		if r.now().Before(entry.NextAttemptAt) {
+			blocked[key] = true
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, entry.Event); err != nil {
+			blocked[key] = true
+			next := r.now().Add(r.backoff(entry.Attempts + 1))
+			log.Printf("Failed to relay event %s (attempt %d), retrying at %s: %v", entry.Event.ID, entry.Attempts+1, next.Format(time.RFC3339), err)
+			if err := r.repo.MarkFailed(ctx, entry.ID, err.Error(), next); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := r.repo.MarkPublished(ctx, entry.ID, r.now()); err != nil {
+			return published, err
+		}
+		published++
+	}
+	return published, nil
+}
+
+// CleanUp removes the published entries, and the records of processed events,
+// that are past the retention period
+func (r *OutboxRelay) CleanUp(ctx context.Context) error {
+	cutoff := r.now().Add(-r.retention)
+	if _, err := r.repo.DeletePublished(ctx, cutoff); err != nil {
+		return err
+	}
+	if r.processed != nil {
+		if _, err := r.processed.DeleteProcessedBefore(ctx, cutoff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run relays the outbox at every interval until the context is cancelled,
+// cleaning up after every run
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayOnce(ctx); err != nil {
+			log.Printf("Failed to relay outbox: %v", err)
+		}
+		if err := r.CleanUp(ctx); err != nil {
+			log.Printf("Failed to clean up outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Deduplicate wraps an event handler so it succeeds at most once per event for
+// the named consumer, using the event ID as the dedupe key. The event is
+// recorded as processed only after the handler succeeded; a failure is
+// returned, so the event bus retries the handler until it succeeds and a
+// redelivery from the relay handles the event again.
+//
+// Deliveries of one event to a consumer must not overlap, or both may run the
+// handler. The event bus guarantees this by handing every event of an
+// aggregate to the same worker of each async subscriber.
+func Deduplicate(consumer string, repo port.ProcessedEventRepositoryPort, handler port.EventHandler) port.EventHandler {
+	return func(ctx context.Context, event model.Event) error {
+		processed, err := repo.IsProcessed(ctx, consumer, event.ID)
+		if err != nil {
+			return err
+		}
+		if processed {
+			return nil
+		}
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+		_, err = repo.MarkProcessed(ctx, consumer, event.ID, time.Now())
+		return err
+	}
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/eventbus"
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain/model"
+)
+
+// flakyPublisher fails each event a set number of times before accepting it
+type flakyPublisher struct {
+	failures  map[string]int
+	published []string
+}
+
+// Publish accepts the events unless one still has failures left
+func (p *flakyPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	for _, event := range events {
+		if p.failures[event.ID] > 0 {
+			p.failures[event.ID]--
+			return errors.New("subscriber unavailable")
+		}
+		p.published = append(p.published, event.ID)
+	}
+	return nil
+}
+
+// outboxEvent creates an event about a todo
+func outboxEvent(id string, todoID int) model.Event {
+	return model.Event{ID: id, Type: model.EventTodoUpdated, AggregateType: model.AggregateTodo, AggregateID: todoID}
+}
+
+func TestOutboxRelay(t *testing.T) {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		events   []model.Event
+		failures map[string]int
+		// runs are the offsets from start at which the relay runs
+		runs []time.Duration
+		want []string
+	}{
+		{
+			name:   "in order",
+			events: []model.Event{outboxEvent("a", 1), outboxEvent("b", 2), outboxEvent("c", 1)},
+			runs:   []time.Duration{0},
+			want:   []string{"a", "b", "c"},
+		},
+		{
+			name:     "failure holds back its aggregate only",
+			events:   []model.Event{outboxEvent("a", 1), outboxEvent("b", 2), outboxEvent("c", 1)},
+			failures: map[string]int{"a": 1},
+			runs:     []time.Duration{0},
+			want:     []string{"b"},
+		},
+		{
+			name:     "retried once the backoff passed",
+			events:   []model.Event{outboxEvent("a", 1), outboxEvent("c", 1)},
+			failures: map[string]int{"a": 1},
+			runs:     []time.Duration{0, 500 * time.Millisecond, time.Second},
+			want:     []string{"a", "c"},
+		},
+		{
+			name:     "backoff doubles",
+			events:   []model.Event{outboxEvent("a", 1)},
+			failures: map[string]int{"a": 2},
+			// failed at 0s (retry at 1s) and 1s (retry at 3s)
+			runs: []time.Duration{0, time.Second, 2 * time.Second},
+		},
+		{
+			name:     "backoff is capped",
+			events:   []model.Event{outboxEvent("a", 1)},
+			failures: map[string]int{"a": 3},
+			// failed at 0s, 1s and 3s; the next wait would be 4s but is capped at 2s
+			runs: []time.Duration{0, time.Second, 3 * time.Second, 5 * time.Second},
+			want: []string{"a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := persistence.NewOutboxRepository()
+			outbox := NewOutboxPublisher(repo)
+			outbox.now = func() time.Time { return start }
+			if err := outbox.Publish(ctx, tt.events...); err != nil {
+				t.Fatal(err)
+			}
+
+			publisher := &flakyPublisher{failures: tt.failures}
+			relay := NewOutboxRelay(repo, publisher, WithOutboxMaxBackoff(2*time.Second))
+			for _, offset := range tt.runs {
+				relay.now = func() time.Time { return start.Add(offset) }
+				if _, err := relay.RelayOnce(ctx); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !slices.Equal(publisher.published, tt.want) {
+				t.Errorf("published = %v, want %v", publisher.published, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutboxAppendIsPartOfTheTransaction(t *testing.T) {
+	errAbort := errors.New("abort")
+	tests := []struct {
+		name        string
+		abort       bool
+		wantPending int
+	}{
+		{name: "committed", wantPending: 1},
+		{name: "rolled back", abort: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoRepo := persistence.NewTodoRepository()
+			tagRepo := persistence.NewTagRepository()
+			outboxRepo := persistence.NewOutboxRepository()
+			tx := persistence.NewTxManager(todoRepo, tagRepo, outboxRepo)
+			todos := NewTodoService(todoRepo, tagRepo, tx, WithTodoEventPublisher(NewOutboxPublisher(outboxRepo)))
+
+			err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+				if _, err := todos.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: 1, Title: "todo"}); err != nil {
+					t.Fatal(err)
+				}
+				if tt.abort {
+					return errAbort
+				}
+				return nil
+			})
+			if tt.abort != errors.Is(err, errAbort) {
+				t.Fatalf("WithinTx() error = %v", err)
+			}
+
+			pending, err := outboxRepo.ListPending(context.Background(), 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != tt.wantPending {
+				t.Errorf("pending entries = %d, want %d", len(pending), tt.wantPending)
+			}
+			todosLeft, err := todoRepo.List(context.Background(), model.TodoFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(todosLeft) != tt.wantPending {
+				t.Errorf("todos = %d, want %d", len(todosLeft), tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	tests := []struct {
+		name string
+		// results are what the handler returns on each call
+		results       []error
+		delivers      int
+		want          int
+		wantErr       error
+		wantProcessed bool
+	}{
+		{name: "single delivery", results: []error{nil}, delivers: 1, want: 1, wantProcessed: true},
+		{name: "redelivery skipped", results: []error{nil}, delivers: 3, want: 1, wantProcessed: true},
+		{name: "failure not recorded", results: []error{errHandler}, delivers: 1, want: 1, wantErr: errHandler},
+		{name: "redelivery after failure retried", results: []error{errHandler, nil}, delivers: 3, want: 2, wantProcessed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := persistence.NewProcessedEventRepository()
+			calls := 0
+			handler := Deduplicate("consumer", repo, func(ctx context.Context, event model.Event) error {
+				err := tt.results[calls]
+				calls++
+				return err
+			})
+			var err error
+			for range tt.delivers {
+				err = handler(ctx, outboxEvent("a", 1))
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("last delivery error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.want {
+				t.Errorf("handler calls = %d, want %d", calls, tt.want)
+			}
+			processed, err := repo.IsProcessed(ctx, "consumer", "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if processed != tt.wantProcessed {
+				t.Errorf("recorded as processed = %v, want %v", processed, tt.wantProcessed)
+			}
+		})
+	}
+}
+
+func TestRelayedEventReachesFailingConsumer(t *testing.T) {
+	ctx := context.Background()
+	outboxRepo := persistence.NewOutboxRepository()
+	processed := persistence.NewProcessedEventRepository()
+	if err := NewOutboxPublisher(outboxRepo).Publish(ctx, outboxEvent("a", 1), outboxEvent("b", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The consumer fails twice; the bus keeps retrying it after the relay has moved on
+	bus := eventbus.New(1, 4, eventbus.WithRetryBackoff(time.Millisecond, time.Millisecond))
+	var mu sync.Mutex
+	var handled []string
+	failures := 2
+	bus.SubscribeAsync(Deduplicate("consumer", processed, func(ctx context.Context, event model.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if failures > 0 {
+			failures--
+			return errors.New("consumer unavailable")
+		}
+		handled = append(handled, event.ID)
+		return nil
+	}))
+	relay := NewOutboxRelay(outboxRepo, bus)
+	if published, err := relay.RelayOnce(ctx); err != nil || published != 2 {
+		t.Fatalf("RelayOnce() = %d, %v, want 2 published", published, err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		done := len(handled) == 2
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	bus.Close()
+
+	if !slices.Equal(handled, []string{"a", "b"}) {
+		t.Errorf("handled = %v, want [a b]", handled)
+	}
+	for _, id := range []string{"a", "b"} {
+		if ok, err := processed.IsProcessed(ctx, "consumer", id); err != nil || !ok {
+			t.Errorf("event %s recorded as processed = %v, %v, want true", id, ok, err)
+		}
+	}
+}
+
+func TestOutboxCleanUp(t *testing.T) {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		// after is how long after the delivery the clean-up runs
+		after          time.Duration
+		wantPublished  int
+		wantRedelivery bool
+	}{
+		{name: "within retention", after: time.Hour, wantPublished: 1},
+		{name: "past retention", after: 25 * time.Hour, wantRedelivery: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			outboxRepo := persistence.NewOutboxRepository()
+			processed := persistence.NewProcessedEventRepository()
+			outbox := NewOutboxPublisher(outboxRepo)
+			outbox.now = func() time.Time { return start }
+			if err := outbox.Publish(ctx, outboxEvent("a", 1)); err != nil {
+				t.Fatal(err)
+			}
+			relay := NewOutboxRelay(outboxRepo, &flakyPublisher{}, WithOutboxRetention(24*time.Hour), WithProcessedEventCleanup(processed))
+			relay.now = func() time.Time { return start }
+			if _, err := relay.RelayOnce(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := processed.MarkProcessed(ctx, "consumer", "a", start); err != nil {
+				t.Fatal(err)
+			}
+
+			relay.now = func() time.Time { return start.Add(tt.after) }
+			if err := relay.CleanUp(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			remaining, err := outboxRepo.DeletePublished(ctx, start.Add(time.Nanosecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if remaining != tt.wantPublished {
+				t.Errorf("published entries kept = %d, want %d", remaining, tt.wantPublished)
+			}
+			first, err := processed.MarkProcessed(ctx, "consumer", "a", start)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first != tt.wantRedelivery {
+				t.Errorf("record forgotten = %v, want %v", first, tt.wantRedelivery)
+			}
+		})
+	}
+}
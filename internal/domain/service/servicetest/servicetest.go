@@ -0,0 +1,93 @@
+// Package servicetest wires the domain services over in-memory repositories
+// for the tests of the inbound adapters.
+package servicetest
+
+import (
+	"context"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+	"go-boilerplate/internal/domain/service"
+)
+
+// Services are the todo, user, list and sharing services over one set of
+// in-memory repositories, wired the way the server wires them
+type Services struct {
+	Todos  *service.TodoService
+	Users  *service.UserService
+	Lists  *service.TodoListService
+	Shares *service.ListShareService
+
+	// ListRepo and ShareRepo are the list repositories, for services built on top
+	ListRepo  *persistence.TodoListRepository
+	ShareRepo *persistence.ListShareRepository
+}
+
+// New creates the services. Changes to todos and list memberships are
+// published to the publisher, unless it is nil.
+func New(publisher port.EventPublisherPort) *Services {
+	todoRepo := persistence.NewTodoRepository()
+	tagRepo := persistence.NewTagRepository()
+	userRepo := persistence.NewUserRepository()
+	listRepo := persistence.NewTodoListRepository()
+	shareRepo := persistence.NewListShareRepository()
+	tx := persistence.NewTxManager(todoRepo, tagRepo, userRepo, listRepo, shareRepo)
+
+	todoOpts := []service.TodoServiceOption{service.WithListRoles(listRepo, shareRepo)}
+	var shareOpts []service.ListShareServiceOption
+	if publisher != nil {
+		todoOpts = append(todoOpts, service.WithTodoEventPublisher(publisher))
+		shareOpts = append(shareOpts, service.WithListShareEventPublisher(publisher))
+	}
+	todos := service.NewTodoService(todoRepo, tagRepo, tx, todoOpts...)
+	shares := service.NewListShareService(shareRepo, listRepo, userRepo, tx, shareOpts...)
+	return &Services{
+		Todos:     todos,
+		Users:     service.NewUserService(userRepo, tx),
+		Lists:     service.NewTodoListService(listRepo, shares.ShareRepository(), todos.TodoRepository(), todos, tx),
+		Shares:    shares,
+		ListRepo:  listRepo,
+		ShareRepo: shareRepo,
+	}
+}
+
+// CreateUsers creates a user for each name, numbered from 1 in order
+func (s *Services) CreateUsers(t testing.TB, names ...string) {
+	t.Helper()
+	for i, name := range names {
+		ctx := domain.ContextWithActor(context.Background(), i+1)
+		if _, err := s.Users.CreateUser(ctx, &model.CreateUserRequest{Username: name, Email: name + "@example.com", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// CreateTodo creates a todo of the owner
+func (s *Services) CreateTodo(t testing.TB, ownerID int, title string) *model.Todo {
+	t.Helper()
+	ctx := domain.ContextWithActor(context.Background(), ownerID)
+	todo, err := s.Todos.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: ownerID, Title: title})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return todo
+}
+
+// CreateSharedList creates a list of the owner with the given members
+func (s *Services) CreateSharedList(t testing.TB, ownerID int, name string, members map[int]model.ListRole) *model.TodoList {
+	t.Helper()
+	ctx := domain.ContextWithActor(context.Background(), ownerID)
+	list, err := s.Lists.CreateList(ctx, &model.CreateTodoListRequest{Name: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for userID, role := range members {
+		if err := s.Shares.ShareRepository().SaveMember(ctx, &model.ListMember{ListID: list.ID, UserID: userID, Role: role}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return list
+}
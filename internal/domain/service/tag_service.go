@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// tagColorPattern matches #RRGGBB hex colors
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TagService implements the TagServicePort interface
+type TagService struct {
+	repo     port.TagRepositoryPort
+	todoRepo port.TodoRepositoryPort
+	tx       port.TransactionManagerPort
+	now      func() time.Time
+}
+
+// NewTagService creates a new TagService
+func NewTagService(repo port.TagRepositoryPort, todoRepo port.TodoRepositoryPort, tx port.TransactionManagerPort) *TagService {
+	return &TagService{
+		repo:     repo,
+		todoRepo: todoRepo,
+		tx:       tx,
+		now:      time.Now,
+	}
+}
+
+// validateTagColor checks that a non-empty color is a #RRGGBB hex value
+func validateTagColor(color string) error {
+	if color != "" && !tagColorPattern.MatchString(color) {
+		return domain.ErrInvalidTagColor
+	}
+	return nil
+}
+
+// checkTagNameAvailable verifies that the owner has no other tag with the name
+func (s *TagService) checkTagNameAvailable(ctx context.Context, ownerID int, name string, tagID int) error {
+	existing, err := s.repo.GetByName(ctx, ownerID, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != tagID {
+		return domain.ErrTagNameDuplicate
+	}
+	return nil
+}
+
+// CreateTag creates a new tag in the owner's namespace
+func (s *TagService) CreateTag(ctx context.Context, req *model.CreateTagRequest) (*model.Tag, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || strings.Contains(name, ",") {
+		return nil, domain.ErrInvalidTagName
+	}
+	if err := validateTagColor(req.Color); err != nil {
+		return nil, err
+	}
+	if err := s.checkTagNameAvailable(ctx, req.OwnerID, name, 0); err != nil {
+		return nil, err
+	}
+
+	tag := &model.Tag{
+		OwnerID:   req.OwnerID,
+		Name:      name,
+		Color:     req.Color,
+		CreatedAt: s.now(),
+	}
+
+	if err := s.repo.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// GetTag retrieves a tag by ID
+func (s *TagService) GetTag(ctx context.Context, id int) (*model.Tag, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListTags retrieves all tags, optionally restricted to one owner
+func (s *TagService) ListTags(ctx context.Context, ownerID *int) ([]*model.Tag, error) {
+	return s.repo.List(ctx, ownerID)
+}
+
+// UpdateTag renames or recolors a tag
+func (s *TagService) UpdateTag(ctx context.Context, id int, req *model.UpdateTagRequest) (*model.Tag, error) {
+	tag, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		name := strings.TrimSpace(req.Name)
+		if name == "" || strings.Contains(name, ",") {
+			return nil, domain.ErrInvalidTagName
+		}
+		if err := s.checkTagNameAvailable(ctx, tag.OwnerID, name, id); err != nil {
+			return nil, err
+		}
+		tag.Name = name
+	}
+
+	if req.Color != "" {
+		if err := validateTagColor(req.Color); err != nil {
+			return nil, err
+		}
+		tag.Color = req.Color
+	}
+
+	if err := s.repo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// MergeTags moves every todo from the source tag to the target tag and deletes
+// the source. Both tags must belong to the same owner.
+func (s *TagService) MergeTags(ctx context.Context, sourceID int, req *model.MergeTagsRequest) (*model.Tag, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Tag, error) {
+		return s.mergeTags(ctx, sourceID, req)
+	})
+}
+
+// mergeTags moves every todo from the source tag to the target tag and deletes
+// the source. Both tags must belong to the same owner.
+func (s *TagService) mergeTags(ctx context.Context, sourceID int, req *model.MergeTagsRequest) (*model.Tag, error) {
+	if sourceID == req.TargetID {
+		return nil, domain.ErrTagMergeIntoSelf
+	}
+
+	source, err := s.repo.GetByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.repo.GetByID(ctx, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if source.OwnerID != target.OwnerID {
+		return nil, domain.ErrTagOwnerMismatch
+	}
+
+	if err := s.todoRepo.ReplaceTag(ctx, source.ID, target.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Delete(ctx, source.ID); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// DeleteTag deletes a tag and detaches it from all todos
+func (s *TagService) DeleteTag(ctx context.Context, id int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.deleteTag(ctx, id)
+	})
+}
+
+// deleteTag deletes a tag and detaches it from all todos
+func (s *TagService) deleteTag(ctx context.Context, id int) error {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := s.todoRepo.RemoveTag(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// newTagServices creates a tag and a todo service sharing the repositories
+func newTagServices(repo todoStore) (*TagService, *TodoService) {
+	tagRepo := persistence.NewTagRepository()
+	tx := persistence.NewTxManager(repo, tagRepo)
+	return NewTagService(tagRepo, repo, tx), NewTodoService(repo, tagRepo, tx)
+}
+
+func mustCreateTag(t *testing.T, s *TagService, ownerID int, name string) *model.Tag {
+	t.Helper()
+	tag, err := s.CreateTag(context.Background(), &model.CreateTagRequest{OwnerID: ownerID, Name: name})
+	if err != nil {
+		t.Fatalf("CreateTag(%d, %q) error = %v", ownerID, name, err)
+	}
+	return tag
+}
+
+func mustAttachTag(t *testing.T, s *TodoService, todoID, tagID int) {
+	t.Helper()
+	if _, err := s.AttachTag(context.Background(), todoID, tagID); err != nil {
+		t.Fatalf("AttachTag(%d, %d) error = %v", todoID, tagID, err)
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      model.CreateTagRequest
+		wantErr  error
+		wantName string
+	}{
+		{name: "name and color", req: model.CreateTagRequest{OwnerID: 1, Name: "home", Color: "#FF8800"}, wantName: "home"},
+		{name: "surrounding spaces are trimmed", req: model.CreateTagRequest{OwnerID: 1, Name: "  home "}, wantName: "home"},
+		{name: "empty name", req: model.CreateTagRequest{OwnerID: 1, Name: "  "}, wantErr: domain.ErrInvalidTagName},
+		{name: "comma in name", req: model.CreateTagRequest{OwnerID: 1, Name: "a,b"}, wantErr: domain.ErrInvalidTagName},
+		{name: "color without hash", req: model.CreateTagRequest{OwnerID: 1, Name: "home", Color: "ff8800"}, wantErr: domain.ErrInvalidTagColor},
+		{name: "short color", req: model.CreateTagRequest{OwnerID: 1, Name: "home", Color: "#f80"}, wantErr: domain.ErrInvalidTagColor},
+		{name: "duplicate in the owner's namespace", req: model.CreateTagRequest{OwnerID: 1, Name: "work"}, wantErr: domain.ErrTagNameDuplicate},
+		{name: "same name for another owner", req: model.CreateTagRequest{OwnerID: 2, Name: "work"}, wantName: "work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, _ := newTagServices(persistence.NewTodoRepository())
+			mustCreateTag(t, tags, 1, "work")
+
+			tag, err := tags.CreateTag(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tag.Name != tt.wantName || tag.OwnerID != tt.req.OwnerID || tag.Color != tt.req.Color {
+				t.Errorf("tag = %+v", tag)
+			}
+		})
+	}
+}
+
+func TestUpdateTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       model.UpdateTagRequest
+		wantErr   error
+		wantName  string
+		wantColor string
+	}{
+		{name: "rename", req: model.UpdateTagRequest{Name: "office"}, wantName: "office", wantColor: "#000000"},
+		{name: "recolor", req: model.UpdateTagRequest{Color: "#0088ff"}, wantName: "work", wantColor: "#0088ff"},
+		{name: "rename to own name", req: model.UpdateTagRequest{Name: "work"}, wantName: "work", wantColor: "#000000"},
+		{name: "rename to a taken name", req: model.UpdateTagRequest{Name: "home"}, wantErr: domain.ErrTagNameDuplicate},
+		{name: "rename to another owner's name", req: model.UpdateTagRequest{Name: "theirs"}, wantName: "theirs", wantColor: "#000000"},
+		{name: "blank name", req: model.UpdateTagRequest{Name: " "}, wantErr: domain.ErrInvalidTagName},
+		{name: "invalid color", req: model.UpdateTagRequest{Color: "orange"}, wantErr: domain.ErrInvalidTagColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tags, _ := newTagServices(persistence.NewTodoRepository())
+			work, err := tags.CreateTag(ctx, &model.CreateTagRequest{OwnerID: 1, Name: "work", Color: "#000000"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			mustCreateTag(t, tags, 1, "home")
+			mustCreateTag(t, tags, 2, "theirs")
+
+			_, err = tags.UpdateTag(ctx, work.ID, &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			stored, err := tags.GetTag(ctx, work.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Name != tt.wantName || stored.Color != tt.wantColor {
+				t.Errorf("tag = %q %q, want %q %q", stored.Name, stored.Color, tt.wantName, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestListTagsByOwner(t *testing.T) {
+	ctx := context.Background()
+	tags, _ := newTagServices(persistence.NewTodoRepository())
+	mustCreateTag(t, tags, 1, "work")
+	mustCreateTag(t, tags, 1, "home")
+	mustCreateTag(t, tags, 2, "work")
+
+	tests := []struct {
+		name    string
+		ownerID *int
+		want    []string
+	}{
+		{name: "all owners", want: []string{"home", "work", "work"}},
+		{name: "one owner", ownerID: ptr(2), want: []string{"work"}},
+		{name: "owner without tags", ownerID: ptr(3), want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := tags.ListTags(ctx, tt.ownerID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, len(list))
+			for i, tag := range list {
+				names[i] = tag.Name
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.want) {
+				t.Errorf("tags = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachTag(t *testing.T) {
+	ctx := context.Background()
+	tags, todos := newTagServices(persistence.NewTodoRepository())
+	todo := mustCreateTodo(t, todos, "t", nil)
+	own := mustCreateTag(t, tags, 1, "work")
+	foreign := mustCreateTag(t, tags, 2, "work")
+
+	if _, err := todos.AttachTag(ctx, todo.ID, foreign.ID); !errors.Is(err, domain.ErrTagOwnerMismatch) {
+		t.Errorf("attaching another owner's tag: err = %v, want %v", err, domain.ErrTagOwnerMismatch)
+	}
+	if _, err := todos.AttachTag(ctx, todo.ID, 99); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("attaching a missing tag: err = %v, want %v", err, domain.ErrNotFound)
+	}
+	mustAttachTag(t, todos, todo.ID, own.ID)
+	attached, err := todos.AttachTag(ctx, todo.ID, own.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(attached.TagIDs, []int{own.ID}) {
+		t.Errorf("tags after attaching twice = %v, want [%d]", attached.TagIDs, own.ID)
+	}
+
+	detached, err := todos.DetachTag(ctx, todo.ID, own.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detached.TagIDs) != 0 {
+		t.Errorf("tags after detaching = %v, want none", detached.TagIDs)
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		// merge returns the source and target of the merge under test
+		merge   func(work, office, foreign *model.Tag) (int, int)
+		wantErr error
+	}{
+		{name: "into another tag", merge: func(work, office, _ *model.Tag) (int, int) { return work.ID, office.ID }},
+		{name: "into itself", merge: func(work, _, _ *model.Tag) (int, int) { return work.ID, work.ID }, wantErr: domain.ErrTagMergeIntoSelf},
+		{name: "into another owner's tag", merge: func(work, _, foreign *model.Tag) (int, int) { return work.ID, foreign.ID }, wantErr: domain.ErrTagOwnerMismatch},
+		{name: "missing target", merge: func(work, _, _ *model.Tag) (int, int) { return work.ID, 99 }, wantErr: domain.ErrNotFound},
+	}
+
+	for _, store := range todoStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+				ctx := context.Background()
+				tags, todos := newTagServices(store.new())
+				work := mustCreateTag(t, tags, 1, "work")
+				office := mustCreateTag(t, tags, 1, "office")
+				foreign := mustCreateTag(t, tags, 2, "work")
+				onlyWork := mustCreateTodo(t, todos, "only work", nil)
+				both := mustCreateTodo(t, todos, "both", nil)
+				mustAttachTag(t, todos, onlyWork.ID, work.ID)
+				mustAttachTag(t, todos, both.ID, work.ID)
+				mustAttachTag(t, todos, both.ID, office.ID)
+
+				source, target := tt.merge(work, office, foreign)
+				_, err := tags.MergeTags(ctx, source, &model.MergeTagsRequest{TargetID: target})
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+
+				wantTags := map[int][]int{onlyWork.ID: {office.ID}, both.ID: {office.ID}}
+				if err != nil {
+					// A failed merge leaves tags and todos untouched
+					wantTags = map[int][]int{onlyWork.ID: {work.ID}, both.ID: {work.ID, office.ID}}
+				}
+				for id, want := range wantTags {
+					todo, err := todos.GetTodo(ctx, id)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !slices.Equal(todo.TagIDs, want) {
+						t.Errorf("todo %d tags = %v, want %v", id, todo.TagIDs, want)
+					}
+				}
+				if _, err := tags.GetTag(ctx, work.ID); (err == nil) != (tt.wantErr != nil) {
+					t.Errorf("source tag lookup err = %v after merge err %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestDeleteTagDetachesTodos(t *testing.T) {
+	for _, store := range todoStores {
+		t.Run(store.name, func(t *testing.T) {
+			ctx := context.Background()
+			tags, todos := newTagServices(store.new())
+			work := mustCreateTag(t, tags, 1, "work")
+			home := mustCreateTag(t, tags, 1, "home")
+			todo := mustCreateTodo(t, todos, "t", nil)
+			mustAttachTag(t, todos, todo.ID, work.ID)
+			mustAttachTag(t, todos, todo.ID, home.ID)
+
+			if err := tags.DeleteTag(ctx, work.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := tags.DeleteTag(ctx, work.ID); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("deleting twice: err = %v, want %v", err, domain.ErrNotFound)
+			}
+			got, err := todos.GetTodo(ctx, todo.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got.TagIDs, []int{home.ID}) {
+				t.Errorf("tags = %v, want [%d]", got.TagIDs, home.ID)
+			}
+		})
+	}
+}
+
+func TestListTodosByTags(t *testing.T) {
+	// seed creates, in ID order: 1 work, 2 work and urgent, 3 untagged,
+	// 4 owned by user 2 and tagged with their own work tag
+	seed := func(t *testing.T, tags *TagService, todos *TodoService) {
+		t.Helper()
+		ctx := context.Background()
+		work := mustCreateTag(t, tags, 1, "work")
+		urgent := mustCreateTag(t, tags, 1, "Urgent")
+		theirWork := mustCreateTag(t, tags, 2, "work")
+		for _, title := range []string{"work", "work and urgent", "untagged"} {
+			mustCreateTodo(t, todos, title, nil)
+		}
+		theirs, err := todos.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: 2, Title: "theirs"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		mustAttachTag(t, todos, 1, work.ID)
+		mustAttachTag(t, todos, 2, work.ID)
+		mustAttachTag(t, todos, 2, urgent.ID)
+		mustAttachTag(t, todos, theirs.ID, theirWork.ID)
+	}
+
+	tests := []struct {
+		name    string
+		filter  model.TodoFilter
+		wantIDs []int
+		wantErr error
+	}{
+		{name: "one tag across owners", filter: model.TodoFilter{Tags: []string{"work"}}, wantIDs: []int{1, 2, 4}},
+		{name: "one tag in an owner's namespace", filter: model.TodoFilter{OwnerID: ptr(1), Tags: []string{"work"}}, wantIDs: []int{1, 2}},
+		{name: "names match case-insensitively", filter: model.TodoFilter{Tags: []string{"urgent"}}, wantIDs: []int{2}},
+		{name: "all is the default", filter: model.TodoFilter{Tags: []string{"work", "urgent"}}, wantIDs: []int{2}},
+		{name: "all", filter: model.TodoFilter{Tags: []string{"work", "urgent"}, TagMatch: model.TagMatchAll}, wantIDs: []int{2}},
+		{name: "any", filter: model.TodoFilter{OwnerID: ptr(1), Tags: []string{"urgent", "work"}, TagMatch: model.TagMatchAny}, wantIDs: []int{1, 2}},
+		{name: "unknown tag matches nothing", filter: model.TodoFilter{Tags: []string{"missing"}}, wantIDs: []int{}},
+		{name: "unknown tag with all", filter: model.TodoFilter{Tags: []string{"work", "missing"}}, wantIDs: []int{}},
+		{name: "unknown tag with any", filter: model.TodoFilter{OwnerID: ptr(1), Tags: []string{"work", "missing"}, TagMatch: model.TagMatchAny}, wantIDs: []int{1, 2}},
+		{name: "invalid match", filter: model.TodoFilter{Tags: []string{"work"}, TagMatch: "some"}, wantErr: domain.ErrInvalidTagMatch},
+	}
+
+	for _, store := range todoStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+				tags, todos := newTagServices(store.new())
+				seed(t, tags, todos)
+
+				list, err := todos.ListTodos(context.Background(), tt.filter)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				ids := make([]int, len(list))
+				for i, todo := range list {
+					ids[i] = todo.ID
+				}
+				slices.Sort(ids)
+				if err == nil && !slices.Equal(ids, tt.wantIDs) {
+					t.Errorf("todos = %v, want %v", ids, tt.wantIDs)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// todoAccess subjects the todos of shared lists to the roles collaborators
+// hold on those lists. The zero value restricts nothing.
+type todoAccess struct {
+	lists  port.TodoListRepositoryPort
+	shares listAccess
+}
+
+// newTodoAccess creates a todoAccess reading lists and their members from the repositories
+func newTodoAccess(lists port.TodoListRepositoryPort, shares port.ListShareRepositoryPort) todoAccess {
+	return todoAccess{lists: lists, shares: listAccess{shareRepo: shares}}
+}
+
+// enabled reports whether list roles are enforced
+func (a todoAccess) enabled() bool {
+	return a.lists != nil
+}
+
+// authorize checks that the acting user holds at least the required role on
+// the list the todo belongs to. Todos outside lists are not restricted, and
+// neither is the todo's owner.
+func (a todoAccess) authorize(ctx context.Context, todo *model.Todo, required model.ListRole) error {
+	if a.lists == nil || todo.ListID == nil {
+		return nil
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok && actorID == todo.OwnerID {
+		return nil
+	}
+
+	list, err := a.lists.GetByID(ctx, *todo.ListID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	_, err = a.shares.authorize(ctx, list, required)
+	return err
+}
+
+// getTodo retrieves a live todo the acting user holds at least the required role on
+func (a todoAccess) getTodo(ctx context.Context, repo port.TodoRepositoryPort, id int, required model.ListRole) (*model.Todo, error) {
+	todo, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.authorize(ctx, todo, required); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// WithListRoles enforces the roles collaborators hold on shared lists for the
+// todos in those lists: viewers may read them, editors and the list owner may
+// also change them. A todo's own owner keeps full access to it.
+func WithListRoles(lists port.TodoListRepositoryPort, shares port.ListShareRepositoryPort) TodoServiceOption {
+	return func(s *TodoService) {
+		s.access = newTodoAccess(lists, shares)
+	}
+}
+
+// authorizeTodo checks that the acting user holds at least the required role
+// on the list the todo belongs to
+func (s *TodoService) authorizeTodo(ctx context.Context, todo *model.Todo, required model.ListRole) error {
+	return s.access.authorize(ctx, todo, required)
+}
+
+// getAuthorizedTodo retrieves a live todo the acting user holds at least the required role on
+func (s *TodoService) getAuthorizedTodo(ctx context.Context, id int, required model.ListRole) (*model.Todo, error) {
+	return s.access.getTodo(ctx, s.repo, id, required)
+}
+
+// visibleTodos drops the todos in lists the acting user may not view
+func (s *TodoService) visibleTodos(ctx context.Context, todos []*model.Todo) ([]*model.Todo, error) {
+	if !s.access.enabled() {
+		return todos, nil
+	}
+
+	actorID, hasActor := domain.ActorFromContext(ctx)
+	canView := make(map[int]bool)
+	visible := make([]*model.Todo, 0, len(todos))
+	for _, todo := range todos {
+		if todo.ListID != nil && (!hasActor || actorID != todo.OwnerID) {
+			allowed, cached := canView[*todo.ListID]
+			if !cached {
+				err := s.authorizeTodo(ctx, todo, model.ListRoleViewer)
+				if err != nil && !isAccessDenied(err) {
+					return nil, err
+				}
+				allowed = err == nil
+				canView[*todo.ListID] = allowed
+			}
+			if !allowed {
+				continue
+			}
+		}
+		visible = append(visible, todo)
+	}
+	return visible, nil
+}
+
+// isAccessDenied reports whether err tells the acting user they may not see a resource
+func isAccessDenied(err error) bool {
+	return errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrForbidden) || errors.Is(err, domain.ErrUnauthenticated)
+}
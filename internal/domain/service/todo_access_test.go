@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+func TestTodoListRoles(t *testing.T) {
+	const (
+		ownerID    = 1
+		editorID   = 2
+		viewerID   = 3
+		strangerID = 4
+	)
+	actors := []struct {
+		name      string
+		actorID   int
+		wantRead  error
+		wantWrite error
+	}{
+		{name: "list owner", actorID: ownerID},
+		{name: "editor", actorID: editorID},
+		{name: "viewer", actorID: viewerID, wantWrite: domain.ErrForbidden},
+		{name: "stranger", actorID: strangerID, wantRead: domain.ErrNotFound, wantWrite: domain.ErrNotFound},
+		{name: "anonymous", wantRead: domain.ErrUnauthenticated, wantWrite: domain.ErrUnauthenticated},
+	}
+	ops := []struct {
+		name  string
+		write bool
+		op    func(ctx context.Context, s *TodoService, id int) error
+	}{
+		{name: "get", op: func(ctx context.Context, s *TodoService, id int) error {
+			_, err := s.GetTodo(ctx, id)
+			return err
+		}},
+		{name: "list subtasks", op: func(ctx context.Context, s *TodoService, id int) error {
+			_, err := s.ListSubtasks(ctx, id)
+			return err
+		}},
+		{name: "list transitions", op: func(ctx context.Context, s *TodoService, id int) error {
+			_, err := s.ListTodoTransitions(ctx, id)
+			return err
+		}},
+		{name: "update", write: true, op: func(ctx context.Context, s *TodoService, id int) error {
+			_, err := s.UpdateTodo(ctx, id, &model.UpdateTodoRequest{Title: "renamed"})
+			return err
+		}},
+		{name: "transition", write: true, op: func(ctx context.Context, s *TodoService, id int) error {
+			_, err := s.TransitionTodo(ctx, id, &model.TransitionTodoRequest{To: model.TodoStatusInProgress})
+			return err
+		}},
+		{name: "add checklist item", write: true, op: func(ctx context.Context, s *TodoService, id int) error {
+			_, err := s.AddChecklistItem(ctx, id, &model.CreateChecklistItemRequest{Text: "step"})
+			return err
+		}},
+		{name: "delete", write: true, op: func(ctx context.Context, s *TodoService, id int) error {
+			return s.DeleteTodo(ctx, id)
+		}},
+	}
+
+	for _, actor := range actors {
+		for _, op := range ops {
+			t.Run(actor.name+"/"+op.name, func(t *testing.T) {
+				f := newListFixture()
+				ownerCtx := domain.ContextWithActor(context.Background(), ownerID)
+				list, err := f.lists.CreateList(ownerCtx, &model.CreateTodoListRequest{Name: "shared"})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for userID, role := range map[int]model.ListRole{editorID: model.ListRoleEditor, viewerID: model.ListRoleViewer} {
+					if err := f.shares.SaveMember(ownerCtx, &model.ListMember{ListID: list.ID, UserID: userID, Role: role}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				todo := mustCreateTodo(t, f.todos, "shared todo", nil)
+				if _, err := f.lists.AddTodo(ownerCtx, list.ID, todo.ID); err != nil {
+					t.Fatal(err)
+				}
+
+				ctx := context.Background()
+				if actor.actorID != 0 {
+					ctx = domain.ContextWithActor(ctx, actor.actorID)
+				}
+				want := actor.wantRead
+				if op.write {
+					want = actor.wantWrite
+				}
+				if err := op.op(ctx, f.todos, todo.ID); !errors.Is(err, want) {
+					t.Errorf("error = %v, want %v", err, want)
+				}
+			})
+		}
+	}
+}
+
+func TestListTodosHidesForeignLists(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int
+		want    []string
+	}{
+		{name: "list owner", actorID: 1, want: []string{"loose", "shared"}},
+		{name: "member", actorID: 2, want: []string{"loose", "shared"}},
+		{name: "stranger", actorID: 3, want: []string{"loose"}},
+		{name: "anonymous", want: []string{"loose"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newListFixture()
+			ownerCtx := domain.ContextWithActor(context.Background(), 1)
+			list, err := f.lists.CreateList(ownerCtx, &model.CreateTodoListRequest{Name: "shared"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := f.shares.SaveMember(ownerCtx, &model.ListMember{ListID: list.ID, UserID: 2, Role: model.ListRoleViewer}); err != nil {
+				t.Fatal(err)
+			}
+			mustCreateTodo(t, f.todos, "loose", nil)
+			shared := mustCreateTodo(t, f.todos, "shared", nil)
+			if _, err := f.lists.AddTodo(ownerCtx, list.ID, shared.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			if tt.actorID != 0 {
+				ctx = domain.ContextWithActor(ctx, tt.actorID)
+			}
+			todos, err := f.todos.ListTodos(ctx, model.TodoFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, todo := range todos {
+				got = append(got, todo.Title)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListTodos() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddTodoFromAnotherList(t *testing.T) {
+	tests := []struct {
+		name string
+		// sourceRole is the collaborator's role on the list the todo leaves
+		sourceRole model.ListRole
+		ownTodo    bool
+		wantErr    error
+	}{
+		{name: "editor on both lists", sourceRole: model.ListRoleEditor},
+		{name: "viewer on the source list", sourceRole: model.ListRoleViewer, wantErr: domain.ErrForbidden},
+		{name: "not a member of the source list", wantErr: domain.ErrNotFound},
+		{name: "own todo in a viewed list", sourceRole: model.ListRoleViewer, ownTodo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newListFixture()
+			ownerCtx := domain.ContextWithActor(context.Background(), 1)
+			collaboratorCtx := domain.ContextWithActor(context.Background(), 2)
+			var lists []*model.TodoList
+			for _, name := range []string{"source", "target"} {
+				list, err := f.lists.CreateList(ownerCtx, &model.CreateTodoListRequest{Name: name})
+				if err != nil {
+					t.Fatal(err)
+				}
+				lists = append(lists, list)
+			}
+			source, target := lists[0], lists[1]
+			for _, listID := range []int{source.ID, target.ID} {
+				if err := f.shares.SaveMember(ownerCtx, &model.ListMember{ListID: listID, UserID: 2, Role: model.ListRoleEditor}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			todoCtx := ownerCtx
+			if tt.ownTodo {
+				todoCtx = collaboratorCtx
+			}
+			todo, err := f.todos.CreateTodo(todoCtx, &model.CreateTodoRequest{Title: "moved"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := f.lists.AddTodo(todoCtx, source.ID, todo.ID); err != nil {
+				t.Fatal(err)
+			}
+			// Settle the collaborator's role on the source list once the todo is in it
+			if tt.sourceRole == "" {
+				err = f.shares.DeleteMember(ownerCtx, source.ID, 2)
+			} else {
+				err = f.shares.SaveMember(ownerCtx, &model.ListMember{ListID: source.ID, UserID: 2, Role: tt.sourceRole})
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = f.lists.AddTodo(collaboratorCtx, target.ID, todo.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			wantList := target.ID
+			if err != nil {
+				wantList = source.ID
+			}
+			stored, err := f.todos.GetTodo(ownerCtx, todo.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.ListID == nil || *stored.ListID != wantList {
+				t.Errorf("todo list = %v, want %d", stored.ListID, wantList)
+			}
+		})
+	}
+}
+
+func TestCreateTodoOwnedByActor(t *testing.T) {
+	tests := []struct {
+		name      string
+		actorID   int
+		ownerID   int
+		wantOwner int
+	}{
+		{name: "actor owns the todo", actorID: 2, wantOwner: 2},
+		{name: "actor overrides the requested owner", actorID: 2, ownerID: 1, wantOwner: 2},
+		{name: "requested owner without an actor", ownerID: 1, wantOwner: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestTodoService()
+			ctx := context.Background()
+			if tt.actorID != 0 {
+				ctx = domain.ContextWithActor(ctx, tt.actorID)
+			}
+			todo, err := s.CreateTodo(ctx, &model.CreateTodoRequest{OwnerID: tt.ownerID, Title: "t"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if todo.OwnerID != tt.wantOwner {
+				t.Errorf("owner = %d, want %d", todo.OwnerID, tt.wantOwner)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+var dueNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+// todoStore is a todo repository that takes part in in-memory transactions
+type todoStore interface {
+	port.TodoRepositoryPort
+	persistence.Transactional
+}
+
+// todoStores are the todo repository adapters the due date tests run against
+var todoStores = []struct {
+	name string
+	new  func() todoStore
+}{
+	{name: "memory", new: func() todoStore { return persistence.NewTodoRepository() }},
+	{name: "event sourced", new: func() todoStore { return persistence.NewEventSourcedTodoRepository(3) }},
+}
+
+// newClockedTodoService creates a TodoService over the repository whose
+// clock reads dueNow
+func newClockedTodoService(repo todoStore) *TodoService {
+	tagRepo := persistence.NewTagRepository()
+	s := NewTodoService(repo, tagRepo, persistence.NewTxManager(repo, tagRepo))
+	s.now = func() time.Time { return dueNow }
+	return s
+}
+
+func TestCreateTodoDueDates(t *testing.T) {
+	tomorrow := dueNow.Add(24 * time.Hour)
+	yesterday := dueNow.Add(-24 * time.Hour)
+	tests := []struct {
+		name         string
+		req          model.CreateTodoRequest
+		wantErr      error
+		wantPriority model.Priority
+		wantDue      *time.Time
+		wantZone     string
+	}{
+		{name: "no due date defaults to medium", req: model.CreateTodoRequest{Title: "t"}, wantPriority: model.PriorityMedium},
+		{name: "priority", req: model.CreateTodoRequest{Title: "t", Priority: model.PriorityUrgent}, wantPriority: model.PriorityUrgent},
+		{name: "invalid priority", req: model.CreateTodoRequest{Title: "t", Priority: "whenever"}, wantErr: domain.ErrInvalidPriority},
+		{name: "future due date", req: model.CreateTodoRequest{Title: "t", DueDate: &tomorrow}, wantPriority: model.PriorityMedium, wantDue: &tomorrow},
+		{name: "past due date", req: model.CreateTodoRequest{Title: "t", DueDate: &yesterday}, wantErr: domain.ErrDueDateInPast},
+		{name: "past due date allowed", req: model.CreateTodoRequest{Title: "t", DueDate: &yesterday, AllowPastDueDate: true}, wantPriority: model.PriorityMedium, wantDue: &yesterday},
+		{name: "due date in a timezone", req: model.CreateTodoRequest{Title: "t", DueDate: &tomorrow, Timezone: "Asia/Seoul"}, wantPriority: model.PriorityMedium, wantDue: &tomorrow, wantZone: "Asia/Seoul"},
+		{name: "unknown timezone", req: model.CreateTodoRequest{Title: "t", DueDate: &tomorrow, Timezone: "Mars/Olympus"}, wantErr: domain.ErrInvalidTimezone},
+		{name: "timezone without due date", req: model.CreateTodoRequest{Title: "t", Timezone: "Asia/Seoul"}, wantErr: domain.ErrInvalidTimezone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newClockedTodoService(persistence.NewTodoRepository())
+			todo, err := s.CreateTodo(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if todo.Priority != tt.wantPriority {
+				t.Errorf("priority = %s, want %s", todo.Priority, tt.wantPriority)
+			}
+			if !todo.CreatedAt.Equal(dueNow) || !todo.UpdatedAt.Equal(dueNow) || todo.CompletedAt != nil {
+				t.Errorf("timestamps = %v %v %v, want created and updated now, not completed", todo.CreatedAt, todo.UpdatedAt, todo.CompletedAt)
+			}
+			switch {
+			case tt.wantDue == nil && todo.DueDate != nil:
+				t.Errorf("due date = %v, want none", todo.DueDate)
+			case tt.wantDue != nil && (todo.DueDate == nil || !todo.DueDate.Equal(*tt.wantDue)):
+				t.Errorf("due date = %v, want %v", todo.DueDate, tt.wantDue)
+			}
+			if tt.wantZone != "" && (todo.Timezone != tt.wantZone || todo.DueDate.Location().String() != tt.wantZone) {
+				t.Errorf("timezone = %q, due date in %s; want %s", todo.Timezone, todo.DueDate.Location(), tt.wantZone)
+			}
+		})
+	}
+}
+
+func TestUpdateTodoDueDates(t *testing.T) {
+	tomorrow := dueNow.Add(24 * time.Hour)
+	nextWeek := dueNow.Add(7 * 24 * time.Hour)
+	beforeCreation := dueNow.Add(-time.Hour)
+	tests := []struct {
+		name     string
+		req      model.UpdateTodoRequest
+		wantErr  error
+		wantDue  *time.Time
+		wantZone string
+	}{
+		{name: "unchanged", req: model.UpdateTodoRequest{Title: "renamed"}, wantDue: &tomorrow, wantZone: "Asia/Seoul"},
+		{name: "move due date", req: model.UpdateTodoRequest{DueDate: &nextWeek}, wantDue: &nextWeek, wantZone: "Asia/Seoul"},
+		{name: "change timezone only", req: model.UpdateTodoRequest{Timezone: "Europe/Berlin"}, wantDue: &tomorrow, wantZone: "Europe/Berlin"},
+		{name: "clear due date", req: model.UpdateTodoRequest{ClearDueDate: true}},
+		{name: "before creation", req: model.UpdateTodoRequest{DueDate: &beforeCreation}, wantErr: domain.ErrDueDateInPast},
+		{name: "before creation allowed", req: model.UpdateTodoRequest{DueDate: &beforeCreation, AllowPastDueDate: true}, wantDue: &beforeCreation, wantZone: "Asia/Seoul"},
+		{name: "unknown timezone", req: model.UpdateTodoRequest{Timezone: "Mars/Olympus"}, wantErr: domain.ErrInvalidTimezone},
+		{name: "invalid priority", req: model.UpdateTodoRequest{Priority: "whenever"}, wantErr: domain.ErrInvalidPriority},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newClockedTodoService(persistence.NewTodoRepository())
+			created, err := s.CreateTodo(ctx, &model.CreateTodoRequest{Title: "t", DueDate: &tomorrow, Timezone: "Asia/Seoul"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			todo, err := s.UpdateTodo(ctx, created.ID, &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if (todo.DueDate == nil) != (tt.wantDue == nil) || (tt.wantDue != nil && !todo.DueDate.Equal(*tt.wantDue)) {
+				t.Errorf("due date = %v, want %v", todo.DueDate, tt.wantDue)
+			}
+			if todo.Timezone != tt.wantZone {
+				t.Errorf("timezone = %q, want %q", todo.Timezone, tt.wantZone)
+			}
+		})
+	}
+}
+
+func TestListTodosDueFilters(t *testing.T) {
+	day := 24 * time.Hour
+	lastWeek, yesterday, tomorrow := dueNow.Add(-7*day), dueNow.Add(-day), dueNow.Add(day)
+	// seed creates, in ID order: 1 overdue and high, 2 overdue but done,
+	// 3 due tomorrow and high, 4 undated and low, 5 overdue since last week
+	seed := func(t *testing.T, s *TodoService) {
+		t.Helper()
+		ctx := context.Background()
+		reqs := []model.CreateTodoRequest{
+			{Title: "overdue", Priority: model.PriorityHigh, DueDate: &yesterday, AllowPastDueDate: true},
+			{Title: "overdue but done", DueDate: &yesterday, AllowPastDueDate: true},
+			{Title: "due tomorrow", Priority: model.PriorityHigh, DueDate: &tomorrow},
+			{Title: "undated", Priority: model.PriorityLow},
+			{Title: "long overdue", DueDate: &lastWeek, AllowPastDueDate: true},
+		}
+		for _, req := range reqs {
+			if _, err := s.CreateTodo(ctx, &req); err != nil {
+				t.Fatal(err)
+			}
+		}
+		mustTransition(t, s, 2, model.TodoStatusDone)
+	}
+
+	beforeToday, afterLastWeek := dueNow, lastWeek
+	tests := []struct {
+		name    string
+		filter  model.TodoFilter
+		wantIDs []int
+		wantErr error
+	}{
+		{name: "overdue", filter: model.TodoFilter{Overdue: ptr(true)}, wantIDs: []int{1, 5}},
+		{name: "not overdue", filter: model.TodoFilter{Overdue: ptr(false)}, wantIDs: []int{2, 3, 4}},
+		{name: "due before", filter: model.TodoFilter{DueBefore: &beforeToday}, wantIDs: []int{1, 2, 5}},
+		{name: "due after", filter: model.TodoFilter{DueAfter: &afterLastWeek}, wantIDs: []int{1, 2, 3}},
+		{name: "due window", filter: model.TodoFilter{DueAfter: &afterLastWeek, DueBefore: &beforeToday}, wantIDs: []int{1, 2}},
+		{name: "priority", filter: model.TodoFilter{Priority: model.PriorityHigh}, wantIDs: []int{1, 3}},
+		{name: "overdue and high", filter: model.TodoFilter{Overdue: ptr(true), Priority: model.PriorityHigh}, wantIDs: []int{1}},
+		{name: "invalid priority", filter: model.TodoFilter{Priority: "whenever"}, wantErr: domain.ErrInvalidPriority},
+	}
+
+	for _, store := range todoStores {
+		for _, tt := range tests {
+			t.Run(store.name+"/"+tt.name, func(t *testing.T) {
+				s := newClockedTodoService(store.new())
+				seed(t, s)
+
+				todos, err := s.ListTodos(context.Background(), tt.filter)
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				ids := make([]int, len(todos))
+				for i, todo := range todos {
+					ids[i] = todo.ID
+				}
+				slices.Sort(ids)
+				if err == nil && !slices.Equal(ids, tt.wantIDs) {
+					t.Errorf("todos = %v, want %v", ids, tt.wantIDs)
+				}
+			})
+		}
+	}
+}
+
+func TestCompletionTimestamps(t *testing.T) {
+	ctx := context.Background()
+	s := newClockedTodoService(persistence.NewTodoRepository())
+	todo := mustCreateTodo(t, s, "t", nil)
+
+	doneAt := dueNow.Add(time.Hour)
+	s.now = func() time.Time { return doneAt }
+	done, err := s.TransitionTodo(ctx, todo.ID, &model.TransitionTodoRequest{To: model.TodoStatusDone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.CompletedAt == nil || !done.CompletedAt.Equal(doneAt) || !done.UpdatedAt.Equal(doneAt) || !done.CreatedAt.Equal(dueNow) {
+		t.Errorf("done: created %v, updated %v, completed %v", done.CreatedAt, done.UpdatedAt, done.CompletedAt)
+	}
+
+	reopened, err := s.TransitionTodo(ctx, todo.ID, &model.TransitionTodoRequest{To: model.TodoStatusOpen})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reopened.CompletedAt != nil {
+		t.Errorf("reopened todo keeps completed_at %v", reopened.CompletedAt)
+	}
+}
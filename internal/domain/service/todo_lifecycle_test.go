@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+func TestUpdateTodoCompletion(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          model.TodoStatus
+		req             model.UpdateTodoRequest
+		wantErr         error
+		wantStatus      model.TodoStatus
+		wantTransitions int
+	}{
+		{name: "partial update keeps a done todo done", status: model.TodoStatusDone, req: model.UpdateTodoRequest{Title: "renamed"}, wantStatus: model.TodoStatusDone, wantTransitions: 1},
+		{name: "partial update keeps a blocked todo blocked", status: model.TodoStatusBlocked, req: model.UpdateTodoRequest{Title: "renamed"}, wantStatus: model.TodoStatusBlocked, wantTransitions: 1},
+		{name: "completing an open todo", status: model.TodoStatusOpen, req: model.UpdateTodoRequest{Completed: ptr(true)}, wantStatus: model.TodoStatusDone, wantTransitions: 1},
+		{name: "clearing completed reopens a done todo", status: model.TodoStatusDone, req: model.UpdateTodoRequest{Completed: ptr(false)}, wantStatus: model.TodoStatusOpen, wantTransitions: 2},
+		{name: "clearing completed on an open todo", status: model.TodoStatusOpen, req: model.UpdateTodoRequest{Completed: ptr(false)}, wantStatus: model.TodoStatusOpen},
+		{name: "completing a done todo", status: model.TodoStatusDone, req: model.UpdateTodoRequest{Completed: ptr(true)}, wantErr: domain.ErrTodoAlreadyCompleted},
+		{name: "completing a blocked todo", status: model.TodoStatusBlocked, req: model.UpdateTodoRequest{Completed: ptr(true)}, wantErr: domain.ErrIllegalTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _ := newTestTodoService()
+			todo := mustCreateTodo(t, s, "t", nil)
+			if tt.status != model.TodoStatusOpen {
+				mustTransition(t, s, todo.ID, tt.status)
+			}
+
+			updated, err := s.UpdateTodo(ctx, todo.ID, &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if updated.Status != tt.wantStatus || updated.Completed != (tt.wantStatus == model.TodoStatusDone) {
+				t.Errorf("status = %s, completed = %v; want %s", updated.Status, updated.Completed, tt.wantStatus)
+			}
+			if (updated.CompletedAt != nil) != updated.Completed {
+				t.Errorf("completed_at = %v with completed = %v", updated.CompletedAt, updated.Completed)
+			}
+			transitions, err := s.ListTodoTransitions(ctx, todo.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(transitions) != tt.wantTransitions {
+				t.Errorf("%d transitions recorded, want %d", len(transitions), tt.wantTransitions)
+			}
+		})
+	}
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// TodoListService implements the TodoListServicePort interface
+type TodoListService struct {
+	repo        port.TodoListRepositoryPort
+	access      listAccess
+	todoRepo    port.TodoRepositoryPort
+	todoService port.TodoServicePort
+	tx          port.TransactionManagerPort
+	now         func() time.Time
+}
+
+// NewTodoListService creates a new TodoListService. Member todos are read and
+// deleted through the todo service so their own rules keep applying.
+func NewTodoListService(repo port.TodoListRepositoryPort, shareRepo port.ListShareRepositoryPort, todoRepo port.TodoRepositoryPort, todoService port.TodoServicePort, tx port.TransactionManagerPort) *TodoListService {
+	return &TodoListService{
+		repo:        repo,
+		access:      listAccess{shareRepo: shareRepo},
+		todoRepo:    todoRepo,
+		todoService: todoService,
+		tx:          tx,
+		now:         time.Now,
+	}
+}
+
+// sortByPosition orders todos by their list position, breaking ties by ID
+func sortByPosition(todos []*model.Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		if todos[i].Position != todos[j].Position {
+			return todos[i].Position < todos[j].Position
+		}
+		return todos[i].ID < todos[j].ID
+	})
+}
+
+// endOfList returns a position key after every todo of a list
+func endOfList(ctx context.Context, todoRepo port.TodoRepositoryPort, listID int) (string, error) {
+	members, err := todoRepo.List(ctx, model.TodoFilter{ListID: &listID})
+	if err != nil {
+		return "", err
+	}
+	last := ""
+	for _, member := range members {
+		last = max(last, member.Position)
+	}
+	return model.PositionBetween(last, "")
+}
+
+// getAuthorizedList retrieves a list the acting user holds at least the required role on
+func (s *TodoListService) getAuthorizedList(ctx context.Context, id int, required model.ListRole) (*model.TodoList, error) {
+	list, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.access.authorize(ctx, list, required); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// CreateList creates a new list owned by the acting user
+func (s *TodoListService) CreateList(ctx context.Context, req *model.CreateTodoListRequest) (*model.TodoList, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, domain.ErrInvalidListName
+	}
+
+	now := s.now()
+	list := &model.TodoList{
+		OwnerID:     actorID,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.repo.Create(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetList retrieves a list by ID
+func (s *TodoListService) GetList(ctx context.Context, id int) (*model.TodoList, error) {
+	return s.getAuthorizedList(ctx, id, model.ListRoleViewer)
+}
+
+// ListLists retrieves the lists owned by the acting user
+func (s *TodoListService) ListLists(ctx context.Context, includeArchived bool) ([]*model.TodoList, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	return s.repo.List(ctx, &actorID, includeArchived)
+}
+
+// UpdateList updates an existing list
+func (s *TodoListService) UpdateList(ctx context.Context, id int, req *model.UpdateTodoListRequest) (*model.TodoList, error) {
+	list, err := s.getAuthorizedList(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		if strings.TrimSpace(req.Name) == "" {
+			return nil, domain.ErrInvalidListName
+		}
+		list.Name = req.Name
+	}
+	if req.Description != "" {
+		list.Description = req.Description
+	}
+	list.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// ArchiveList hides a list from default listings and freezes its membership
+func (s *TodoListService) ArchiveList(ctx context.Context, id int) (*model.TodoList, error) {
+	list, err := s.getAuthorizedList(ctx, id, model.ListRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if list.Archived {
+		return list, nil
+	}
+
+	now := s.now()
+	list.Archived = true
+	list.ArchivedAt = &now
+	list.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// UnarchiveList restores an archived list
+func (s *TodoListService) UnarchiveList(ctx context.Context, id int) (*model.TodoList, error) {
+	list, err := s.getAuthorizedList(ctx, id, model.ListRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	if !list.Archived {
+		return list, nil
+	}
+
+	list.Archived = false
+	list.ArchivedAt = nil
+	list.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// DeleteList deletes a list, handling its member todos according to the mode
+func (s *TodoListService) DeleteList(ctx context.Context, id int, mode model.ListDeleteMode) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.deleteList(ctx, id, mode)
+	})
+}
+
+// deleteList deletes a list, handling its member todos according to the mode
+func (s *TodoListService) deleteList(ctx context.Context, id int, mode model.ListDeleteMode) error {
+	if mode == "" {
+		mode = model.ListDeleteDetach
+	}
+	if !mode.IsValid() {
+		return domain.ErrInvalidListDeleteMode
+	}
+
+	if _, err := s.getAuthorizedList(ctx, id, model.ListRoleOwner); err != nil {
+		return err
+	}
+	members, err := s.todoRepo.List(ctx, model.TodoFilter{ListID: &id})
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case model.ListDeleteRestrict:
+		if len(members) > 0 {
+			return domain.ErrListNotEmpty
+		}
+	case model.ListDeleteCascade:
+		for _, todo := range members {
+			if err := s.todoService.DeleteTodo(ctx, todo.ID); err != nil {
+				return err
+			}
+		}
+	case model.ListDeleteDetach:
+		for _, todo := range members {
+			todo.ListID = nil
+			todo.Position = ""
+			todo.UpdatedAt = s.now()
+			if err := s.todoRepo.Update(ctx, todo); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.access.shareRepo.DeleteByList(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ListMemberTodos retrieves the todos of a list in list order
+func (s *TodoListService) ListMemberTodos(ctx context.Context, id int) ([]*model.Todo, error) {
+	if _, err := s.getAuthorizedList(ctx, id, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+
+	todos, err := s.todoService.ListTodos(ctx, model.TodoFilter{ListID: &id})
+	if err != nil {
+		return nil, err
+	}
+	sortByPosition(todos)
+	return todos, nil
+}
+
+// getActiveList retrieves a list whose todos the acting user may rearrange
+func (s *TodoListService) getActiveList(ctx context.Context, id int) (*model.TodoList, error) {
+	list, err := s.getAuthorizedList(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if list.Archived {
+		return nil, domain.ErrListArchived
+	}
+	return list, nil
+}
+
+// authorizeLeave checks that the acting user may take the todo out of the list
+// it is in: its owner always may, anyone else needs editor on that list
+func (s *TodoListService) authorizeLeave(ctx context.Context, todo *model.Todo) error {
+	if todo.ListID == nil {
+		return nil
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok && actorID == todo.OwnerID {
+		return nil
+	}
+
+	current, err := s.repo.GetByID(ctx, *todo.ListID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	_, err = s.access.authorize(ctx, current, model.ListRoleEditor)
+	return err
+}
+
+// AddTodo appends a todo to the end of a list, moving it out of any other list.
+// Collaborators may add their own todos as well as the list owner's, and need
+// editor on the list the todo leaves unless they own the todo.
+func (s *TodoListService) AddTodo(ctx context.Context, id int, todoID int) (*model.Todo, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Todo, error) {
+		return s.addTodo(ctx, id, todoID)
+	})
+}
+
+// addTodo appends a todo to the end of a list, moving it out of any other list
+func (s *TodoListService) addTodo(ctx context.Context, id int, todoID int) (*model.Todo, error) {
+	list, err := s.getActiveList(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID, _ := domain.ActorFromContext(ctx); todo.OwnerID != list.OwnerID && todo.OwnerID != actorID {
+		return nil, domain.ErrListOwnerMismatch
+	}
+	if todo.ListID != nil && *todo.ListID == id {
+		return s.todoService.GetTodo(ctx, todoID)
+	}
+	if err := s.authorizeLeave(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	position, err := endOfList(ctx, s.todoRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	todo.ListID = &id
+	todo.Position = position
+	todo.UpdatedAt = s.now()
+	if err := s.todoRepo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	return s.todoService.GetTodo(ctx, todoID)
+}
+
+// RemoveTodo takes a todo out of a list without deleting it
+func (s *TodoListService) RemoveTodo(ctx context.Context, id int, todoID int) (*model.Todo, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Todo, error) {
+		return s.removeTodo(ctx, id, todoID)
+	})
+}
+
+// removeTodo takes a todo out of a list without deleting it
+func (s *TodoListService) removeTodo(ctx context.Context, id int, todoID int) (*model.Todo, error) {
+	if _, err := s.getActiveList(ctx, id); err != nil {
+		return nil, err
+	}
+	todo, err := s.todoRepo.GetByID(ctx, todoID)
+	if err != nil {
+		return nil, err
+	}
+	if todo.ListID == nil || *todo.ListID != id {
+		return nil, domain.ErrTodoNotInList
+	}
+
+	todo.ListID = nil
+	todo.Position = ""
+	todo.UpdatedAt = s.now()
+	if err := s.todoRepo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	return s.todoService.GetTodo(ctx, todoID)
+}
+
+// MoveTodo reorders a todo within its list by giving it a position between its
+// new neighbours. Only the moved todo is updated.
+func (s *TodoListService) MoveTodo(ctx context.Context, id int, todoID int, req *model.MoveTodoRequest) (*model.Todo, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Todo, error) {
+		return s.moveTodo(ctx, id, todoID, req)
+	})
+}
+
+// moveTodo reorders a todo within its list
+func (s *TodoListService) moveTodo(ctx context.Context, id int, todoID int, req *model.MoveTodoRequest) (*model.Todo, error) {
+	if _, err := s.getActiveList(ctx, id); err != nil {
+		return nil, err
+	}
+
+	members, err := s.todoRepo.List(ctx, model.TodoFilter{ListID: &id})
+	if err != nil {
+		return nil, err
+	}
+	sortByPosition(members)
+
+	// Work on the order without the moved todo
+	var todo *model.Todo
+	others := make([]*model.Todo, 0, len(members))
+	for _, member := range members {
+		if member.ID == todoID {
+			todo = member
+		} else {
+			others = append(others, member)
+		}
+	}
+	if todo == nil {
+		return nil, domain.ErrTodoNotInList
+	}
+
+	indexOf := func(neighbourID int) int {
+		for i, other := range others {
+			if other.ID == neighbourID {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// Resolve the slot as the index of the first todo after the moved one
+	slot := len(others)
+	switch {
+	case req.AfterID != nil:
+		i := indexOf(*req.AfterID)
+		if i < 0 {
+			return nil, domain.ErrTodoNotInList
+		}
+		slot = i + 1
+		if req.BeforeID != nil && indexOf(*req.BeforeID) != slot {
+			return nil, domain.ErrInvalidPosition
+		}
+	case req.BeforeID != nil:
+		slot = indexOf(*req.BeforeID)
+		if slot < 0 {
+			return nil, domain.ErrTodoNotInList
+		}
+	}
+
+	after, before := "", ""
+	if slot > 0 {
+		after = others[slot-1].Position
+	}
+	if slot < len(others) {
+		before = others[slot].Position
+	}
+	position, err := model.PositionBetween(after, before)
+	if err != nil {
+		return nil, err
+	}
+
+	todo.Position = position
+	todo.UpdatedAt = s.now()
+	if err := s.todoRepo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	return s.todoService.GetTodo(ctx, todoID)
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// listFixture wires a TodoListService and a ListShareService to a TodoService
+// over shared repositories
+type listFixture struct {
+	todos    *TodoService
+	lists    *TodoListService
+	sharing  *ListShareService
+	listRepo *persistence.TodoListRepository
+	shares   *persistence.ListShareRepository
+	users    *persistence.UserRepository
+}
+
+func newListFixture() *listFixture {
+	return newPublishingListFixture(nil)
+}
+
+// newPublishingListFixture creates a listFixture whose changes to todos and
+// list memberships are published to the publisher, unless it is nil
+func newPublishingListFixture(publisher port.EventPublisherPort) *listFixture {
+	todoRepo := persistence.NewTodoRepository()
+	tagRepo := persistence.NewTagRepository()
+	userRepo := persistence.NewUserRepository()
+	listRepo := persistence.NewTodoListRepository()
+	shareRepo := persistence.NewListShareRepository()
+	tx := persistence.NewTxManager(todoRepo, tagRepo, userRepo, listRepo, shareRepo)
+
+	todoOpts := []TodoServiceOption{WithListRoles(listRepo, shareRepo)}
+	var shareOpts []ListShareServiceOption
+	if publisher != nil {
+		todoOpts = append(todoOpts, WithTodoEventPublisher(publisher))
+		shareOpts = append(shareOpts, WithListShareEventPublisher(publisher))
+	}
+	todos := NewTodoService(todoRepo, tagRepo, tx, todoOpts...)
+	sharing := NewListShareService(shareRepo, listRepo, userRepo, tx, shareOpts...)
+	return &listFixture{
+		todos:    todos,
+		lists:    NewTodoListService(listRepo, sharing.ShareRepository(), todos.TodoRepository(), todos, tx),
+		sharing:  sharing,
+		listRepo: listRepo,
+		shares:   shareRepo,
+		users:    userRepo,
+	}
+}
+
+// sharedTodo creates a todo of user 1 in a list they share with user 2 as
+// editor and user 3 as viewer; user 4 is a stranger to the list
+func (f *listFixture) sharedTodo(t *testing.T) (listID, todoID int) {
+	t.Helper()
+	ownerCtx := domain.ContextWithActor(context.Background(), 1)
+	list, err := f.lists.CreateList(ownerCtx, &model.CreateTodoListRequest{Name: "shared"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for userID, role := range map[int]model.ListRole{2: model.ListRoleEditor, 3: model.ListRoleViewer} {
+		if err := f.shares.SaveMember(ownerCtx, &model.ListMember{ListID: list.ID, UserID: userID, Role: role}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	todo := mustCreateTodo(t, f.todos, "shared todo", nil)
+	if _, err := f.lists.AddTodo(ownerCtx, list.ID, todo.ID); err != nil {
+		t.Fatal(err)
+	}
+	return list.ID, todo.ID
+}
+
+// order returns the IDs of a list's todos in list order
+func (f *listFixture) order(t *testing.T, ctx context.Context, listID int) []int {
+	t.Helper()
+	todos, err := f.lists.ListMemberTodos(ctx, listID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]int, len(todos))
+	for i, todo := range todos {
+		ids[i] = todo.ID
+	}
+	return ids
+}
+
+func TestTodoListOrdering(t *testing.T) {
+	tests := []struct {
+		name      string
+		move      func(ctx context.Context, f *listFixture, listID int) error
+		wantOrder []int
+		wantErr   error
+	}{
+		{
+			name:      "appended in order",
+			move:      func(ctx context.Context, f *listFixture, listID int) error { return nil },
+			wantOrder: []int{1, 2, 3},
+		},
+		{
+			name: "move to the front",
+			move: func(ctx context.Context, f *listFixture, listID int) error {
+				_, err := f.lists.MoveTodo(ctx, listID, 3, &model.MoveTodoRequest{BeforeID: ptr(1)})
+				return err
+			},
+			wantOrder: []int{3, 1, 2},
+		},
+		{
+			name: "move after a todo",
+			move: func(ctx context.Context, f *listFixture, listID int) error {
+				_, err := f.lists.MoveTodo(ctx, listID, 1, &model.MoveTodoRequest{AfterID: ptr(2)})
+				return err
+			},
+			wantOrder: []int{2, 1, 3},
+		},
+		{
+			name: "move to the end",
+			move: func(ctx context.Context, f *listFixture, listID int) error {
+				_, err := f.lists.MoveTodo(ctx, listID, 1, &model.MoveTodoRequest{})
+				return err
+			},
+			wantOrder: []int{2, 3, 1},
+		},
+		{
+			name: "neighbours that are not adjacent",
+			move: func(ctx context.Context, f *listFixture, listID int) error {
+				_, err := f.lists.MoveTodo(ctx, listID, 3, &model.MoveTodoRequest{AfterID: ptr(1), BeforeID: ptr(1)})
+				return err
+			},
+			wantOrder: []int{1, 2, 3},
+			wantErr:   domain.ErrInvalidPosition,
+		},
+		{
+			name: "remove and add again",
+			move: func(ctx context.Context, f *listFixture, listID int) error {
+				if _, err := f.lists.RemoveTodo(ctx, listID, 1); err != nil {
+					return err
+				}
+				_, err := f.lists.AddTodo(ctx, listID, 1)
+				return err
+			},
+			wantOrder: []int{2, 3, 1},
+		},
+		{
+			name: "remove a todo outside the list",
+			move: func(ctx context.Context, f *listFixture, listID int) error {
+				_, err := f.lists.RemoveTodo(ctx, listID, 4)
+				return err
+			},
+			wantOrder: []int{1, 2, 3},
+			wantErr:   domain.ErrTodoNotInList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := domain.ContextWithActor(context.Background(), 1)
+			f := newListFixture()
+			list, err := f.lists.CreateList(ctx, &model.CreateTodoListRequest{Name: "groceries"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, title := range []string{"milk", "eggs", "bread", "elsewhere"} {
+				todo := mustCreateTodo(t, f.todos, title, nil)
+				if i == 3 {
+					break
+				}
+				if _, err := f.lists.AddTodo(ctx, list.ID, todo.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := tt.move(ctx, f, list.ID); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := f.order(t, ctx, list.ID); !slices.Equal(got, tt.wantOrder) {
+				t.Errorf("order = %v, want %v", got, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestTodoListConcurrentAdds(t *testing.T) {
+	ctx := domain.ContextWithActor(context.Background(), 1)
+	f := newListFixture()
+	list, err := f.lists.CreateList(ctx, &model.CreateTodoListRequest{Name: "inbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 50
+	ids := make([]int, n)
+	for i := range ids {
+		ids[i] = mustCreateTodo(t, f.todos, "todo", nil).ID
+	}
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := f.lists.AddTodo(ctx, list.ID, id); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	todos, err := f.lists.ListMemberTodos(ctx, list.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	positions := make(map[string]int, len(todos))
+	for _, todo := range todos {
+		if other, taken := positions[todo.Position]; taken {
+			t.Errorf("todos %d and %d share position %q", other, todo.ID, todo.Position)
+		}
+		positions[todo.Position] = todo.ID
+	}
+	if len(todos) != n {
+		t.Errorf("list holds %d todos, want %d", len(todos), n)
+	}
+}
+
+func TestRecurringTodoJoinsEndOfList(t *testing.T) {
+	tests := []struct {
+		name   string
+		inList bool
+	}{
+		{name: "in a list", inList: true},
+		{name: "outside lists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := domain.ContextWithActor(context.Background(), 1)
+			f := newListFixture()
+			list, err := f.lists.CreateList(ctx, &model.CreateTodoListRequest{Name: "chores"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			due := time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC)
+			recurring, err := f.todos.CreateTodo(ctx, &model.CreateTodoRequest{
+				OwnerID:    1,
+				Title:      "laundry",
+				DueDate:    &due,
+				Recurrence: &model.RecurrenceRequest{RRule: "FREQ=WEEKLY"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			other := mustCreateTodo(t, f.todos, "dishes", nil)
+			if tt.inList {
+				for _, id := range []int{recurring.ID, other.ID} {
+					if _, err := f.lists.AddTodo(ctx, list.ID, id); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			done, err := f.todos.TransitionTodo(ctx, recurring.ID, &model.TransitionTodoRequest{To: model.TodoStatusDone})
+			if err != nil {
+				t.Fatal(err)
+			}
+			next, err := f.todos.GetTodo(ctx, *done.Recurrence.NextTodoID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !tt.inList {
+				if next.ListID != nil || next.Position != "" {
+					t.Errorf("next occurrence list = %v, position = %q", next.ListID, next.Position)
+				}
+				return
+			}
+			if next.ListID == nil || *next.ListID != list.ID {
+				t.Fatalf("next occurrence list = %v, want %d", next.ListID, list.ID)
+			}
+			want := []int{recurring.ID, other.ID, next.ID}
+			if got := f.order(t, ctx, list.ID); !slices.Equal(got, want) {
+				t.Errorf("order = %v, want %v", got, want)
+			}
+			positions := []string{done.Position, next.Position}
+			if done.Position == next.Position || !sort.StringsAreSorted(positions) {
+				t.Errorf("positions of completed and next occurrence = %q", positions)
+			}
+		})
+	}
+}
+
+func TestRestoreTodoIntoList(t *testing.T) {
+	tests := []struct {
+		name string
+		// trash runs while todo 3 is in the trash
+		trash     func(ctx context.Context, f *listFixture, listID int) error
+		wantList  bool
+		wantOrder []int
+	}{
+		{
+			name:      "position still free",
+			trash:     func(ctx context.Context, f *listFixture, listID int) error { return nil },
+			wantList:  true,
+			wantOrder: []int{1, 2, 3},
+		},
+		{
+			name: "position taken by a new todo",
+			trash: func(ctx context.Context, f *listFixture, listID int) error {
+				_, err := f.lists.AddTodo(ctx, listID, 4)
+				return err
+			},
+			wantList:  true,
+			wantOrder: []int{1, 2, 4, 3},
+		},
+		{
+			name: "list deleted",
+			trash: func(ctx context.Context, f *listFixture, listID int) error {
+				return f.lists.DeleteList(ctx, listID, model.ListDeleteDetach)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := domain.ContextWithActor(context.Background(), 1)
+			f := newListFixture()
+			list, err := f.lists.CreateList(ctx, &model.CreateTodoListRequest{Name: "groceries"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, title := range []string{"milk", "eggs", "bread", "butter"} {
+				todo := mustCreateTodo(t, f.todos, title, nil)
+				if i == 3 {
+					break
+				}
+				if _, err := f.lists.AddTodo(ctx, list.ID, todo.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := f.todos.DeleteTodo(ctx, 3); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.trash(ctx, f, list.ID); err != nil {
+				t.Fatal(err)
+			}
+			restored, err := f.todos.RestoreTodo(ctx, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !tt.wantList {
+				if restored.ListID != nil || restored.Position != "" {
+					t.Errorf("restored todo list = %v, position = %q; want none", restored.ListID, restored.Position)
+				}
+				return
+			}
+			if restored.ListID == nil || *restored.ListID != list.ID {
+				t.Fatalf("restored todo list = %v, want %d", restored.ListID, list.ID)
+			}
+			if got := f.order(t, ctx, list.ID); !slices.Equal(got, tt.wantOrder) {
+				t.Errorf("order = %v, want %v", got, tt.wantOrder)
+			}
+			// Distinct positions keep every todo movable
+			if _, err := f.lists.MoveTodo(ctx, list.ID, 1, &model.MoveTodoRequest{AfterID: ptr(tt.wantOrder[len(tt.wantOrder)-2])}); err != nil {
+				t.Errorf("moving after the restore: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+
+	"github.com/teambition/rrule-go"
+)
+
+// maxPreviewOccurrences caps how many occurrences can be previewed at once
+const maxPreviewOccurrences = 100
+
+// todoLocation returns the timezone a todo's dates are expressed in
+func todoLocation(todo *model.Todo) *time.Location {
+	if loc, err := time.LoadLocation(todo.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// parseRecurrenceRule builds the rule for a series starting at start, expanded in loc
+// so that occurrences keep their wall-clock time across DST changes
+func parseRecurrenceRule(rule string, start time.Time, loc *time.Location) (*rrule.RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if rule == "" || strings.Contains(rule, "DTSTART") {
+		return nil, domain.ErrInvalidRecurrence
+	}
+
+	opt, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return nil, domain.ErrInvalidRecurrence
+	}
+	opt.Dtstart = start.In(loc)
+
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, domain.ErrInvalidRecurrence
+	}
+	return r, nil
+}
+
+// isExceptionDate reports whether t falls on one of the skipped calendar dates in loc
+func isExceptionDate(t time.Time, exceptions []time.Time, loc *time.Location) bool {
+	y, m, d := t.In(loc).Date()
+	for _, ex := range exceptions {
+		ey, em, ed := ex.In(loc).Date()
+		if y == ey && m == em && d == ed {
+			return true
+		}
+	}
+	return false
+}
+
+// nextOccurrences expands the todo's recurrence and returns up to n occurrences
+// after the given time, skipping exception dates
+func nextOccurrences(todo *model.Todo, after time.Time, inclusive bool, n int) ([]time.Time, error) {
+	loc := todoLocation(todo)
+	r, err := parseRecurrenceRule(todo.Recurrence.RRule, todo.Recurrence.Start, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]time.Time, 0, n)
+	next := r.Iterator()
+	for len(occurrences) < n {
+		t, ok := next()
+		if !ok {
+			break
+		}
+		if t.Before(after) || (!inclusive && t.Equal(after)) {
+			continue
+		}
+		if isExceptionDate(t, todo.Recurrence.ExceptionDates, loc) {
+			continue
+		}
+		occurrences = append(occurrences, t)
+	}
+	return occurrences, nil
+}
+
+// newRecurrence validates a recurrence request for a todo whose series starts at its due date
+func newRecurrence(todo *model.Todo, req *model.RecurrenceRequest) (*model.Recurrence, error) {
+	if todo.DueDate == nil {
+		return nil, domain.ErrRecurrenceRequiresDueDate
+	}
+
+	rule := strings.TrimPrefix(strings.TrimSpace(req.RRule), "RRULE:")
+	if _, err := parseRecurrenceRule(rule, *todo.DueDate, todoLocation(todo)); err != nil {
+		return nil, err
+	}
+
+	exceptionDates := req.ExceptionDates
+	if exceptionDates == nil {
+		exceptionDates = []time.Time{}
+	}
+	return &model.Recurrence{
+		RRule:          rule,
+		Start:          *todo.DueDate,
+		ExceptionDates: exceptionDates,
+	}, nil
+}
+
+// spawnNextOccurrence creates the todo for the next occurrence once a recurring
+// todo is completed. It does nothing if the series has ended or the next
+// occurrence was already generated.
+func (s *TodoService) spawnNextOccurrence(ctx context.Context, todo *model.Todo) error {
+	if todo.Recurrence == nil || todo.Recurrence.NextTodoID != nil || todo.DueDate == nil {
+		return nil
+	}
+
+	occurrences, err := nextOccurrences(todo, *todo.DueDate, false, 1)
+	if err != nil {
+		return err
+	}
+	if len(occurrences) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	dueDate := occurrences[0]
+	checklist := make([]model.ChecklistItem, len(todo.Checklist))
+	for i, item := range todo.Checklist {
+		checklist[i] = model.ChecklistItem{ID: item.ID, Text: item.Text}
+	}
+	recurrence := todo.Recurrence.Clone()
+	recurrence.NextTodoID = nil
+	// The occurrence joins the end of the list rather than sharing the
+	// completed todo's position
+	position := ""
+	if todo.ListID != nil {
+		if position, err = endOfList(ctx, s.repo, *todo.ListID); err != nil {
+			return err
+		}
+	}
+
+	next := &model.Todo{
+		OwnerID:     todo.OwnerID,
+		ParentID:    todo.ParentID,
+		ListID:      todo.ListID,
+		Position:    position,
+		Title:       todo.Title,
+		Description: todo.Description,
+		Status:      model.TodoStatusOpen,
+		Priority:    todo.Priority,
+		DueDate:     &dueDate,
+		Timezone:    todo.Timezone,
+		TagIDs:      append([]int{}, todo.TagIDs...),
+		Checklist:   checklist,
+		Recurrence:  recurrence,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.repo.Create(ctx, next); err != nil {
+		return err
+	}
+
+	todo.Recurrence.NextTodoID = &next.ID
+	return s.repo.Update(ctx, todo)
+}
+
+// SetRecurrence makes a todo repeat according to an RRULE, starting at its due date
+func (s *TodoService) SetRecurrence(ctx context.Context, id int, req *model.RecurrenceRequest) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence, err := newRecurrence(todo, req)
+	if err != nil {
+		return nil, err
+	}
+	todo.Recurrence = recurrence
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// ClearRecurrence turns a recurring todo into a one-off todo
+func (s *TodoService) ClearRecurrence(ctx context.Context, id int) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if todo.Recurrence == nil {
+		return nil, domain.ErrNotRecurring
+	}
+
+	todo.Recurrence = nil
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// PreviewOccurrences returns the next occurrences of a recurring todo, starting with its current due date
+func (s *TodoService) PreviewOccurrences(ctx context.Context, id int, count int) ([]time.Time, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if todo.Recurrence == nil || todo.DueDate == nil {
+		return nil, domain.ErrNotRecurring
+	}
+
+	return nextOccurrences(todo, *todo.DueDate, true, min(max(count, 1), maxPreviewOccurrences))
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s unavailable: %v", name, err)
+	}
+	return loc
+}
+
+func TestNewRecurrence(t *testing.T) {
+	due := time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		dueDate  *time.Time
+		rule     string
+		wantRule string
+		wantErr  error
+	}{
+		{name: "weekly", dueDate: &due, rule: "FREQ=WEEKLY;BYDAY=SA", wantRule: "FREQ=WEEKLY;BYDAY=SA"},
+		{name: "prefix and spaces are dropped", dueDate: &due, rule: "  RRULE:FREQ=DAILY;COUNT=3 ", wantRule: "FREQ=DAILY;COUNT=3"},
+		{name: "no due date", rule: "FREQ=DAILY", wantErr: domain.ErrRecurrenceRequiresDueDate},
+		{name: "empty rule", dueDate: &due, rule: " ", wantErr: domain.ErrInvalidRecurrence},
+		{name: "unknown frequency", dueDate: &due, rule: "FREQ=SOMETIMES", wantErr: domain.ErrInvalidRecurrence},
+		{name: "start is taken from the due date", dueDate: &due, rule: "DTSTART:20300101T000000Z\nFREQ=DAILY", wantErr: domain.ErrInvalidRecurrence},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &model.Todo{DueDate: tt.dueDate}
+			got, err := newRecurrence(todo, &model.RecurrenceRequest{RRule: tt.rule})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("newRecurrence() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.RRule != tt.wantRule || !got.Start.Equal(due) || got.ExceptionDates == nil {
+				t.Errorf("newRecurrence() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestNextOccurrences(t *testing.T) {
+	seoul := mustLoadLocation(t, "Asia/Seoul")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	tests := []struct {
+		name       string
+		start      time.Time
+		timezone   string
+		rule       string
+		exceptions []time.Time
+		after      time.Time
+		inclusive  bool
+		n          int
+		want       []time.Time
+	}{
+		{
+			name:  "weekly from the start",
+			start: time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC),
+			rule:  "FREQ=WEEKLY",
+			after: time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC), inclusive: true, n: 3,
+			want: []time.Time{
+				time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC),
+				time.Date(2030, 3, 9, 9, 0, 0, 0, time.UTC),
+				time.Date(2030, 3, 16, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:  "exclusive skips the current occurrence",
+			start: time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC),
+			rule:  "FREQ=DAILY",
+			after: time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC), n: 1,
+			want: []time.Time{time.Date(2030, 3, 3, 9, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "exception dates are skipped in the todo's timezone",
+			start:      time.Date(2030, 3, 2, 9, 0, 0, 0, seoul),
+			timezone:   "Asia/Seoul",
+			rule:       "FREQ=DAILY",
+			exceptions: []time.Time{time.Date(2030, 3, 2, 16, 0, 0, 0, time.UTC)}, // March 3rd in Seoul
+			after:      time.Date(2030, 3, 2, 9, 0, 0, 0, seoul), n: 2,
+			want: []time.Time{
+				time.Date(2030, 3, 4, 9, 0, 0, 0, seoul),
+				time.Date(2030, 3, 5, 9, 0, 0, 0, seoul),
+			},
+		},
+		{
+			name:     "wall-clock time is kept across DST",
+			start:    time.Date(2030, 3, 30, 9, 0, 0, 0, berlin),
+			timezone: "Europe/Berlin",
+			rule:     "FREQ=DAILY",
+			after:    time.Date(2030, 3, 30, 9, 0, 0, 0, berlin), inclusive: true, n: 2,
+			want: []time.Time{
+				time.Date(2030, 3, 30, 9, 0, 0, 0, berlin),
+				time.Date(2030, 3, 31, 9, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:  "series ends",
+			start: time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC),
+			rule:  "FREQ=DAILY;COUNT=2",
+			after: time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC), n: 5,
+			want: []time.Time{time.Date(2030, 3, 3, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todo := &model.Todo{
+				Timezone:   tt.timezone,
+				Recurrence: &model.Recurrence{RRule: tt.rule, Start: tt.start, ExceptionDates: tt.exceptions},
+			}
+			got, err := nextOccurrences(todo, tt.after, tt.inclusive, tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("nextOccurrences() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompletingRecurringTodo(t *testing.T) {
+	due := time.Date(2030, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		rule     string
+		complete func(t *testing.T, s *TodoService, id int)
+		wantNext *time.Time
+	}{
+		{
+			name:     "transition to done",
+			rule:     "FREQ=WEEKLY",
+			complete: func(t *testing.T, s *TodoService, id int) { mustTransition(t, s, id, model.TodoStatusDone) },
+			wantNext: ptr(due.AddDate(0, 0, 7)),
+		},
+		{
+			name: "completed flag",
+			rule: "FREQ=DAILY",
+			complete: func(t *testing.T, s *TodoService, id int) {
+				if _, err := s.UpdateTodo(context.Background(), id, &model.UpdateTodoRequest{Completed: ptr(true)}); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantNext: ptr(due.AddDate(0, 0, 1)),
+		},
+		{
+			name:     "cancelling does not continue the series",
+			rule:     "FREQ=DAILY",
+			complete: func(t *testing.T, s *TodoService, id int) { mustTransition(t, s, id, model.TodoStatusCancelled) },
+		},
+		{
+			name:     "last occurrence",
+			rule:     "FREQ=DAILY;COUNT=1",
+			complete: func(t *testing.T, s *TodoService, id int) { mustTransition(t, s, id, model.TodoStatusDone) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _ := newTestTodoService()
+			todo, err := s.CreateTodo(ctx, &model.CreateTodoRequest{
+				OwnerID:    1,
+				Title:      "water the plants",
+				DueDate:    &due,
+				Recurrence: &model.RecurrenceRequest{RRule: tt.rule},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.AddChecklistItem(ctx, todo.ID, &model.CreateChecklistItemRequest{Text: "fill the can"}); err != nil {
+				t.Fatal(err)
+			}
+			checked := true
+			if _, err := s.UpdateChecklistItem(ctx, todo.ID, 1, &model.UpdateChecklistItemRequest{Checked: &checked}); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.complete(t, s, todo.ID)
+
+			completed, err := s.GetTodo(ctx, todo.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantNext == nil {
+				if completed.Recurrence.NextTodoID != nil {
+					t.Fatalf("next occurrence %d generated", *completed.Recurrence.NextTodoID)
+				}
+				return
+			}
+			if completed.Recurrence.NextTodoID == nil {
+				t.Fatal("no next occurrence generated")
+			}
+			next, err := s.GetTodo(ctx, *completed.Recurrence.NextTodoID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.DueDate == nil || !next.DueDate.Equal(*tt.wantNext) {
+				t.Errorf("next due date = %v, want %v", next.DueDate, *tt.wantNext)
+			}
+			if next.Status != model.TodoStatusOpen || next.Recurrence == nil || next.Recurrence.NextTodoID != nil {
+				t.Errorf("next occurrence = %+v", next)
+			}
+			wantChecklist := []model.ChecklistItem{{ID: 1, Text: "fill the can"}}
+			if !reflect.DeepEqual(next.Checklist, wantChecklist) {
+				t.Errorf("next checklist = %+v, want %+v", next.Checklist, wantChecklist)
+			}
+
+			// Reopening and completing again does not generate a second occurrence
+			if _, err := s.UpdateTodo(ctx, todo.ID, &model.UpdateTodoRequest{Completed: ptr(false)}); err != nil {
+				t.Fatal(err)
+			}
+			tt.complete(t, s, todo.ID)
+			all, err := s.ListTodos(ctx, model.TodoFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != 2 {
+				t.Errorf("todos after completing twice = %d, want 2", len(all))
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"go-boilerplate/internal/domain"
 	"go-boilerplate/internal/domain/model"
@@ -11,27 +15,260 @@ import (
 
 // TodoService implements the TodoServicePort interface
 type TodoService struct {
-	repo port.TodoRepositoryPort
+	repo         port.TodoRepositoryPort
+	tagRepo      port.TagRepositoryPort
+	tx           port.TransactionManagerPort
+	stateMachine *model.TodoStateMachine
+	// subtaskRule and maxSubtaskDepth govern parent/subtask relationships
+	subtaskRule     model.SubtaskRule
+	maxSubtaskDepth int
+	// attachments, when set, removes the files of purged todos
+	attachments port.AttachmentServicePort
+	// history, when set, answers questions about the past of todos
+	history port.TodoHistoryPort
+	// access, when enabled, subjects the todos of shared lists to the list roles
+	access todoAccess
+	now    func() time.Time
+}
+
+// TodoServiceOption configures optional TodoService behavior
+type TodoServiceOption func(*TodoService)
+
+// WithTodoStateMachine replaces the default todo lifecycle workflow
+func WithTodoStateMachine(sm *model.TodoStateMachine) TodoServiceOption {
+	return func(s *TodoService) {
+		s.stateMachine = sm
+	}
+}
+
+// WithAttachmentCleanup removes a todo's attachments when the todo is purged from the trash
+func WithAttachmentCleanup(attachments port.AttachmentServicePort) TodoServiceOption {
+	return func(s *TodoService) {
+		s.attachments = attachments
+	}
+}
+
+// WithTodoHistory enables reading the event history and past states of todos
+// from a store that keeps them
+func WithTodoHistory(history port.TodoHistoryPort) TodoServiceOption {
+	return func(s *TodoService) {
+		s.history = history
+	}
+}
+
+// WithTodoAuditLog records every change to todos in the audit log, including
+// changes made as side effects of other operations
+func WithTodoAuditLog(log port.AuditPort) TodoServiceOption {
+	return func(s *TodoService) {
+		s.repo = &auditedTodoRepository{
+			TodoRepositoryPort: s.repo,
+			audit:              auditRecorder{log: log, tx: s.tx, now: time.Now},
+		}
+	}
+}
+
+// TodoRepository returns the repository the service writes todos through, which
+// audits and announces every change it is configured to. Services changing
+// todos as a side effect, such as tag merges and list moves, write through it
+// too so their changes are recorded like direct ones.
+func (s *TodoService) TodoRepository() port.TodoRepositoryPort {
+	return s.repo
+}
+
+// WithTodoEventPublisher publishes a domain event for every change to todos,
+// including changes made as side effects of other operations
+func WithTodoEventPublisher(publisher port.EventPublisherPort) TodoServiceOption {
+	return func(s *TodoService) {
+		s.repo = &eventingTodoRepository{
+			TodoRepositoryPort: s.repo,
+			events:             eventFactory{publisher: publisher, tx: s.tx, now: time.Now},
+		}
+	}
 }
 
 // NewTodoService creates a new TodoService
-func NewTodoService(repo port.TodoRepositoryPort) *TodoService {
-	return &TodoService{
-		repo: repo,
+func NewTodoService(repo port.TodoRepositoryPort, tagRepo port.TagRepositoryPort, tx port.TransactionManagerPort, opts ...TodoServiceOption) *TodoService {
+	s := &TodoService{
+		repo:            repo,
+		tagRepo:         tagRepo,
+		tx:              tx,
+		stateMachine:    model.DefaultTodoStateMachine(),
+		subtaskRule:     model.SubtaskRuleNone,
+		maxSubtaskDepth: 3,
+		now:             time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// getTodoIncludingDeleted retrieves a todo whether it is live or in the trash
+func getTodoIncludingDeleted(ctx context.Context, repo port.TodoRepositoryPort, id int) (*model.Todo, error) {
+	todo, err := repo.GetByID(ctx, id)
+	if err == nil {
+		return todo, nil
+	}
+	deleted, listErr := repo.List(ctx, model.TodoFilter{Deleted: true})
+	if listErr != nil {
+		return nil, listErr
+	}
+	for _, todo := range deleted {
+		if todo.ID == id {
+			return todo, nil
+		}
+	}
+	return nil, err
+}
+
+// retagTodos runs retag, which changes a tag on every todo carrying it, and
+// returns the affected todos, live or in the trash, as they were before and after
+func retagTodos(ctx context.Context, repo port.TodoRepositoryPort, tagID int, retag func(ctx context.Context) error) (before, after []*model.Todo, err error) {
+	for _, deleted := range []bool{false, true} {
+		tagged, err := repo.List(ctx, model.TodoFilter{TagIDSets: [][]int{{tagID}}, Deleted: deleted})
+		if err != nil {
+			return nil, nil, err
+		}
+		before = append(before, tagged...)
+	}
+	if err := retag(ctx); err != nil {
+		return nil, nil, err
+	}
+	after = make([]*model.Todo, len(before))
+	for i, todo := range before {
+		if after[i], err = getTodoIncludingDeleted(ctx, repo, todo.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return before, after, nil
+}
+
+// resolveDueDate validates a due date against the todo's creation time and
+// expresses it in the given IANA timezone, if any
+func resolveDueDate(dueDate time.Time, timezone string, createdAt time.Time, allowPast bool) (time.Time, error) {
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return time.Time{}, domain.ErrInvalidTimezone
+		}
+		dueDate = dueDate.In(loc)
+	}
+	if !allowPast && dueDate.Before(createdAt) {
+		return time.Time{}, domain.ErrDueDateInPast
+	}
+	return dueDate, nil
+}
+
+// applyTransition moves the todo to another lifecycle state if the state machine
+// and the subtask rule allow it, keeping the completion fields in sync
+func (s *TodoService) applyTransition(ctx context.Context, todo *model.Todo, to model.TodoStatus, reason string, now time.Time) (*model.TodoTransition, error) {
+	if !to.IsValid() {
+		return nil, domain.ErrInvalidTodoStatus
+	}
+	if !s.stateMachine.CanTransition(todo.Status, to) {
+		return nil, domain.ErrIllegalTransition
+	}
+	if to == model.TodoStatusDone {
+		if err := s.checkSubtasksClosed(ctx, todo); err != nil {
+			return nil, err
+		}
+	}
+	if todo.Status.IsClosed() && !to.IsClosed() {
+		if err := s.checkParentOpen(ctx, todo); err != nil {
+			return nil, err
+		}
+	}
+
+	transition := &model.TodoTransition{
+		TodoID: todo.ID,
+		From:   todo.Status,
+		To:     to,
+		Reason: reason,
+		At:     now,
 	}
+
+	todo.Status = to
+	todo.Completed = to == model.TodoStatusDone
+	if todo.Completed {
+		todo.CompletedAt = &now
+	} else {
+		todo.CompletedAt = nil
+	}
+	todo.UpdatedAt = now
+	return transition, nil
 }
 
-// CreateTodo creates a new todo
+// afterTransition runs the follow-up rules of a persisted status change:
+// generating the next occurrence of a completed recurring todo and
+// completing parents whose subtasks are all closed
+func (s *TodoService) afterTransition(ctx context.Context, todo *model.Todo, transition *model.TodoTransition) error {
+	if transition.To == model.TodoStatusDone {
+		if err := s.spawnNextOccurrence(ctx, todo); err != nil {
+			return err
+		}
+	}
+	return s.autoCompleteParents(ctx, todo)
+}
+
+// CreateTodo creates a new todo. The acting user, when there is one, owns the
+// todo whatever owner the request names.
 func (s *TodoService) CreateTodo(ctx context.Context, req *model.CreateTodoRequest) (*model.Todo, error) {
 	// Business logic validation
 	if strings.TrimSpace(req.Title) == "" {
 		return nil, domain.ErrInvalidTodoTitle
 	}
 
+	ownerID := req.OwnerID
+	if actorID, ok := domain.ActorFromContext(ctx); ok {
+		ownerID = actorID
+	}
+
+	priority := req.Priority
+	if priority == "" {
+		priority = model.PriorityMedium
+	}
+	if !priority.IsValid() {
+		return nil, domain.ErrInvalidPriority
+	}
+
+	now := s.now()
 	todo := &model.Todo{
+		OwnerID:     ownerID,
+		ParentID:    req.ParentID,
 		Title:       req.Title,
 		Description: req.Description,
 		Completed:   false,
+		Status:      model.TodoStatusOpen,
+		Priority:    priority,
+		TagIDs:      []int{},
+		Checklist:   []model.ChecklistItem{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if req.DueDate != nil {
+		dueDate, err := resolveDueDate(*req.DueDate, req.Timezone, now, req.AllowPastDueDate)
+		if err != nil {
+			return nil, err
+		}
+		todo.DueDate = &dueDate
+		todo.Timezone = req.Timezone
+	} else if req.Timezone != "" {
+		return nil, domain.ErrInvalidTimezone
+	}
+
+	if req.ParentID != nil {
+		if err := s.validateParent(ctx, todo, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Recurrence != nil {
+		recurrence, err := newRecurrence(todo, req.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		todo.Recurrence = recurrence
 	}
 
 	if err := s.repo.Create(ctx, todo); err != nil {
@@ -43,20 +280,182 @@ func (s *TodoService) CreateTodo(ctx context.Context, req *model.CreateTodoReque
 
 // GetTodo retrieves a todo by ID
 func (s *TodoService) GetTodo(ctx context.Context, id int) (*model.Todo, error) {
-	return s.repo.GetByID(ctx, id)
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// GetTodoAsOf retrieves a todo as it was at the given time. Progress is only
+// computed for current todos.
+func (s *TodoService) GetTodoAsOf(ctx context.Context, id int, at time.Time) (*model.Todo, error) {
+	if s.history == nil {
+		return nil, domain.ErrHistoryUnavailable
+	}
+	if err := s.authorizeHistory(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.history.GetAsOf(ctx, id, at)
 }
 
-// ListTodos retrieves all todos
-func (s *TodoService) ListTodos(ctx context.Context) ([]*model.Todo, error) {
-	return s.repo.List(ctx)
+// GetTodoHistory retrieves the stored events of a todo, oldest first
+func (s *TodoService) GetTodoHistory(ctx context.Context, id int) ([]*model.TodoEvent, error) {
+	if s.history == nil {
+		return nil, domain.ErrHistoryUnavailable
+	}
+	if err := s.authorizeHistory(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.history.ListEvents(ctx, id)
+}
+
+// authorizeHistory checks that the acting user may view a live or trashed todo
+func (s *TodoService) authorizeHistory(ctx context.Context, id int) error {
+	if !s.access.enabled() {
+		return nil
+	}
+	todo, err := getTodoIncludingDeleted(ctx, s.repo, id)
+	if err != nil {
+		return err
+	}
+	return s.authorizeTodo(ctx, todo, model.ListRoleViewer)
+}
+
+// ListTodos retrieves all todos matching the filter
+func (s *TodoService) ListTodos(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error) {
+	if filter.Priority != "" && !filter.Priority.IsValid() {
+		return nil, domain.ErrInvalidPriority
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, domain.ErrInvalidTodoStatus
+	}
+	if filter.Now.IsZero() {
+		filter.Now = s.now()
+	}
+
+	if len(filter.Tags) > 0 {
+		switch filter.TagMatch {
+		case "":
+			filter.TagMatch = model.TagMatchAll
+		case model.TagMatchAll, model.TagMatchAny:
+		default:
+			return nil, domain.ErrInvalidTagMatch
+		}
+
+		tagIDSets, err := s.resolveTagNames(ctx, filter.Tags, filter.OwnerID)
+		if err != nil {
+			return nil, err
+		}
+		filter.TagIDSets = tagIDSets
+	}
+
+	todos, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if todos, err = s.visibleTodos(ctx, todos); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todos...); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// resolveTagNames maps each tag name to the IDs of the tags carrying it, within
+// the owner's namespace when an owner is given or across all owners otherwise
+func (s *TodoService) resolveTagNames(ctx context.Context, names []string, ownerID *int) ([][]int, error) {
+	tags, err := s.tagRepo.ListByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDSets := make([][]int, len(names))
+	for i, name := range names {
+		tagIDSets[i] = []int{}
+		for _, tag := range tags {
+			if ownerID != nil && tag.OwnerID != *ownerID {
+				continue
+			}
+			if strings.EqualFold(tag.Name, name) {
+				tagIDSets[i] = append(tagIDSets[i], tag.ID)
+			}
+		}
+	}
+	return tagIDSets, nil
+}
+
+// formatOptionalTime formats a time for the activity feed, empty when unset
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatOptionalID formats an ID for the activity feed, empty when unset
+func formatOptionalID(id *int) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.Itoa(*id)
+}
+
+// todoFieldChanges lists the fields that differ between two versions of a todo.
+// Status changes are recorded as transitions and are not included.
+func todoFieldChanges(ctx context.Context, before, after *model.Todo, at time.Time) []*model.TodoChange {
+	var actorID *int
+	if id, ok := domain.ActorFromContext(ctx); ok {
+		actorID = &id
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"priority", string(before.Priority), string(after.Priority)},
+		{"due_date", formatOptionalTime(before.DueDate), formatOptionalTime(after.DueDate)},
+		{"timezone", before.Timezone, after.Timezone},
+		{"parent_id", formatOptionalID(before.ParentID), formatOptionalID(after.ParentID)},
+	}
+
+	var changes []*model.TodoChange
+	for _, field := range fields {
+		if field.from == field.to {
+			continue
+		}
+		changes = append(changes, &model.TodoChange{
+			TodoID:  after.ID,
+			ActorID: actorID,
+			Field:   field.name,
+			From:    field.from,
+			To:      field.to,
+			At:      at,
+		})
+	}
+	return changes
 }
 
 // UpdateTodo updates an existing todo
 func (s *TodoService) UpdateTodo(ctx context.Context, id int, req *model.UpdateTodoRequest) (*model.Todo, error) {
-	todo, err := s.repo.GetByID(ctx, id)
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Todo, error) {
+		return s.updateTodo(ctx, id, req)
+	})
+}
+
+// updateTodo updates an existing todo
+func (s *TodoService) updateTodo(ctx context.Context, id int, req *model.UpdateTodoRequest) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
 	if err != nil {
 		return nil, err
 	}
+	before := todo.Clone()
 
 	// Business logic validation
 	if req.Title != "" {
@@ -70,20 +469,347 @@ func (s *TodoService) UpdateTodo(ctx context.Context, id int, req *model.UpdateT
 		todo.Description = req.Description
 	}
 
+	if req.Priority != "" {
+		if !req.Priority.IsValid() {
+			return nil, domain.ErrInvalidPriority
+		}
+		todo.Priority = req.Priority
+	}
+
+	switch {
+	case req.ClearDueDate:
+		todo.DueDate = nil
+		todo.Timezone = ""
+	case req.DueDate != nil || req.Timezone != "":
+		dueDate := todo.DueDate
+		if req.DueDate != nil {
+			dueDate = req.DueDate
+		}
+		if dueDate == nil {
+			return nil, domain.ErrInvalidTimezone
+		}
+		timezone := todo.Timezone
+		if req.Timezone != "" {
+			timezone = req.Timezone
+		}
+		resolved, err := resolveDueDate(*dueDate, timezone, todo.CreatedAt, req.AllowPastDueDate)
+		if err != nil {
+			return nil, err
+		}
+		todo.DueDate = &resolved
+		todo.Timezone = timezone
+	}
+
+	switch {
+	case req.ClearParent:
+		todo.ParentID = nil
+	case req.ParentID != nil:
+		if err := s.validateParent(ctx, todo, *req.ParentID); err != nil {
+			return nil, err
+		}
+		todo.ParentID = req.ParentID
+	}
+
 	// Business logic: prevent completing already completed todos
-	if req.Completed && todo.Completed {
+	if req.Completed != nil && *req.Completed && todo.Completed {
 		return nil, domain.ErrTodoAlreadyCompleted
 	}
-	todo.Completed = req.Completed
+
+	// Completion goes through the state machine: completing moves the todo to done,
+	// clearing the flag on a done todo reopens it. Leaving it out keeps the status.
+	now := s.now()
+	var transition *model.TodoTransition
+	switch {
+	case req.Completed == nil:
+	case *req.Completed && !todo.Completed:
+		transition, err = s.applyTransition(ctx, todo, model.TodoStatusDone, "", now)
+	case !*req.Completed && todo.Completed:
+		transition, err = s.applyTransition(ctx, todo, model.TodoStatusOpen, "", now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	todo.UpdatedAt = now
 
 	if err := s.repo.Update(ctx, todo); err != nil {
 		return nil, err
 	}
+	if changes := todoFieldChanges(ctx, before, todo, now); len(changes) > 0 {
+		if err := s.repo.AppendChanges(ctx, changes); err != nil {
+			return nil, err
+		}
+	}
+	if transition != nil {
+		if err := s.repo.AppendTransition(ctx, transition); err != nil {
+			return nil, err
+		}
+		if err := s.afterTransition(ctx, todo, transition); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// TransitionTodo moves a todo to another lifecycle state
+func (s *TodoService) TransitionTodo(ctx context.Context, id int, req *model.TransitionTodoRequest) (*model.Todo, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Todo, error) {
+		return s.transitionTodo(ctx, id, req)
+	})
+}
+
+// transitionTodo moves a todo to another lifecycle state
+func (s *TodoService) transitionTodo(ctx context.Context, id int, req *model.TransitionTodoRequest) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	transition, err := s.applyTransition(ctx, todo, req.To, req.Reason, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AppendTransition(ctx, transition); err != nil {
+		return nil, err
+	}
+	if err := s.afterTransition(ctx, todo, transition); err != nil {
+		return nil, err
+	}
 
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
 	return todo, nil
 }
 
-// DeleteTodo deletes a todo
+// ListTodoTransitions retrieves the status history of a todo
+func (s *TodoService) ListTodoTransitions(ctx context.Context, id int) ([]*model.TodoTransition, error) {
+	if _, err := s.getAuthorizedTodo(ctx, id, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTransitions(ctx, id)
+}
+
+// DeleteTodo moves a todo to the trash. Its subtasks become top-level todos.
 func (s *TodoService) DeleteTodo(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.deleteTodo(ctx, id)
+	})
+}
+
+// deleteTodo moves a todo to the trash. Its subtasks become top-level todos.
+func (s *TodoService) deleteTodo(ctx context.Context, id int) error {
+	if _, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor); err != nil {
+		return err
+	}
+
+	children, err := s.listChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		child.ParentID = nil
+		child.UpdatedAt = s.now()
+		if err := s.repo.Update(ctx, child); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.SoftDelete(ctx, id, s.now())
+}
+
+// ListDeletedTodos retrieves the todos in the trash matching the filter
+func (s *TodoService) ListDeletedTodos(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error) {
+	filter.Deleted = true
+	todos, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleTodos(ctx, todos)
+}
+
+// RestoreTodo moves a todo out of the trash. If its parent is no longer
+// available the todo is restored as a top-level todo, and if its list is gone
+// it is restored outside lists.
+func (s *TodoService) RestoreTodo(ctx context.Context, id int) (*model.Todo, error) {
+	return withinTx(ctx, s.tx, func(ctx context.Context) (*model.Todo, error) {
+		return s.restoreTodo(ctx, id)
+	})
+}
+
+// restoreTodo moves a todo out of the trash. If its parent is no longer
+// available, or is closed while the subtask rule requires open subtasks to
+// sit below open parents, the todo is restored as a top-level todo. A todo
+// whose list was deleted is restored outside lists.
+func (s *TodoService) restoreTodo(ctx context.Context, id int) (*model.Todo, error) {
+	if s.access.enabled() {
+		trashed, err := getTodoIncludingDeleted(ctx, s.repo, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.authorizeTodo(ctx, trashed, model.ListRoleEditor); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	todo, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo.ParentID != nil {
+		parent, err := s.repo.GetByID(ctx, *todo.ParentID)
+		switch {
+		case errors.Is(err, domain.ErrNotFound):
+			todo.ParentID = nil
+		case err != nil:
+			return nil, err
+		case s.subtaskRule == model.SubtaskRuleRequireClosed && parent.Status.IsClosed() && !todo.Status.IsClosed():
+			todo.ParentID = nil
+		}
+	}
+	if todo.ListID != nil {
+		if err := s.placeRestoredTodo(ctx, todo); err != nil {
+			return nil, err
+		}
+	}
+	todo.UpdatedAt = s.now()
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// placeRestoredTodo takes a restored todo out of a list that no longer exists,
+// and moves it to the end of its list when another todo took its position
+// while it was in the trash
+func (s *TodoService) placeRestoredTodo(ctx context.Context, todo *model.Todo) error {
+	if s.access.enabled() {
+		_, err := s.access.lists.GetByID(ctx, *todo.ListID)
+		if errors.Is(err, domain.ErrNotFound) {
+			todo.ListID = nil
+			todo.Position = ""
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	members, err := s.repo.List(ctx, model.TodoFilter{ListID: todo.ListID})
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if member.ID != todo.ID && member.Position == todo.Position {
+			position, err := endOfList(ctx, s.repo, *todo.ListID)
+			if err != nil {
+				return err
+			}
+			todo.Position = position
+			return nil
+		}
+	}
+	return nil
+}
+
+// PurgeDeletedTodos permanently removes todos trashed before the cutoff,
+// together with their attachments
+func (s *TodoService) PurgeDeletedTodos(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := s.repo.List(ctx, model.TodoFilter{Deleted: true})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, todo := range deleted {
+		if !todo.DeletedAt.Before(before) {
+			continue
+		}
+		if err := s.repo.Delete(ctx, todo.ID); err != nil {
+			return purged, err
+		}
+		if s.attachments != nil {
+			if err := s.attachments.DeleteTodoAttachments(ctx, todo.ID); err != nil {
+				return purged, err
+			}
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// AttachTag attaches a tag from the todo owner's namespace to the todo
+func (s *TodoService) AttachTag(ctx context.Context, id int, tagID int) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.tagRepo.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.OwnerID != todo.OwnerID {
+		return nil, domain.ErrTagOwnerMismatch
+	}
+
+	if todo.HasTag(tagID) {
+		return s.GetTodo(ctx, id)
+	}
+
+	todo.TagIDs = append(todo.TagIDs, tagID)
+	sort.Ints(todo.TagIDs)
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// DetachTag removes a tag from the todo
+func (s *TodoService) DetachTag(ctx context.Context, id int, tagID int) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	if !todo.HasTag(tagID) {
+		return s.GetTodo(ctx, id)
+	}
+
+	tagIDs := make([]int, 0, len(todo.TagIDs))
+	for _, existing := range todo.TagIDs {
+		if existing != tagID {
+			tagIDs = append(tagIDs, existing)
+		}
+	}
+	todo.TagIDs = tagIDs
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
 }
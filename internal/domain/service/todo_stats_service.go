@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// TodoStatsEvents are the events the todo stats projection is built from
+var TodoStatsEvents = []model.EventType{
+	model.EventTodoCreated,
+	model.EventTodoUpdated,
+	model.EventTodoCompleted,
+	model.EventTodoDeleted,
+	model.EventTodoRestored,
+	model.EventTodoPurged,
+}
+
+// TodoStatsService implements the TodoStatsServicePort interface. It serves
+// dashboard queries from a read model and keeps that read model up to date
+// by projecting todo events onto it. Users see the counts of their own todos;
+// only administrators see everyone's and may rebuild the read model.
+type TodoStatsService struct {
+	readModel port.TodoStatsReadModelPort
+	todoRepo  port.TodoRepositoryPort
+	admins    adminAccess
+	now       func() time.Time
+}
+
+// NewTodoStatsService creates a new TodoStatsService. Rebuilds read the todos
+// from the todo repository.
+func NewTodoStatsService(readModel port.TodoStatsReadModelPort, todoRepo port.TodoRepositoryPort, adminIDs []int) *TodoStatsService {
+	return &TodoStatsService{
+		readModel: readModel,
+		todoRepo:  todoRepo,
+		admins:    newAdminAccess(adminIDs),
+		now:       time.Now,
+	}
+}
+
+// TrackEvent records a todo event as published but not yet projected. Run it
+// synchronously on publish, so the lag also covers events queued for HandleEvent.
+func (s *TodoStatsService) TrackEvent(ctx context.Context, event model.Event) error {
+	if !slices.Contains(TodoStatsEvents, event.Type) {
+		return nil
+	}
+	return s.readModel.RecordPending(ctx, event)
+}
+
+// HandleEvent projects a todo event onto the read model. Events carry the full
+// todo, so handling an event again or after a newer one changes nothing.
+func (s *TodoStatsService) HandleEvent(ctx context.Context, event model.Event) error {
+	var err error
+	switch event.Type {
+	case model.EventTodoCreated, model.EventTodoUpdated, model.EventTodoCompleted, model.EventTodoRestored:
+		err = s.readModel.Upsert(ctx, event.Todo, event.OccurredAt)
+	case model.EventTodoDeleted, model.EventTodoPurged:
+		err = s.readModel.Remove(ctx, event.AggregateID, event.OccurredAt)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.readModel.RecordApplied(ctx, event, s.now())
+}
+
+// GetTodoStats retrieves the dashboard counts matching the filter. Users other
+// than administrators only count their own todos, and may not ask for anyone else's.
+func (s *TodoStatsService) GetTodoStats(ctx context.Context, filter model.TodoStatsFilter) (*model.TodoStats, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	if !s.admins.isAdmin(actorID) {
+		if filter.OwnerID != nil && *filter.OwnerID != actorID {
+			return nil, domain.ErrForbidden
+		}
+		filter.OwnerID = &actorID
+	}
+	return s.readModel.Stats(ctx, filter, s.now())
+}
+
+// GetProjectionStatus reports how far the read model has caught up
+func (s *TodoStatsService) GetProjectionStatus(ctx context.Context) (*model.ProjectionStatus, error) {
+	return s.readModel.Status(ctx, s.now())
+}
+
+// RebuildTodoStats recomputes the read model from the live todos. Events that
+// occurred before the rebuild started and arrive afterwards are ignored.
+func (s *TodoStatsService) RebuildTodoStats(ctx context.Context) (*model.ProjectionStatus, error) {
+	if err := s.admins.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	version := s.now()
+	todos, err := s.todoRepo.List(ctx, model.TodoFilter{})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.readModel.Replace(ctx, todos, version); err != nil {
+		return nil, err
+	}
+	return s.readModel.Status(ctx, s.now())
+}
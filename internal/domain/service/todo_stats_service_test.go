@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/adapter/outbound/readmodel"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+var statsStart = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// statsEvent creates a todo event occurring the given number of minutes after statsStart
+func statsEvent(eventType model.EventType, minute int, todo model.Todo) model.Event {
+	return model.Event{
+		ID:            fmt.Sprintf("%s-%d-%d", eventType, todo.ID, minute),
+		Type:          eventType,
+		AggregateType: model.AggregateTodo,
+		AggregateID:   todo.ID,
+		OccurredAt:    statsStart.Add(time.Duration(minute) * time.Minute),
+		Todo:          &todo,
+	}
+}
+
+func TestTodoStatsProjection(t *testing.T) {
+	overdue := statsStart.Add(-time.Hour)
+	open := model.Todo{ID: 1, OwnerID: 1, Status: model.TodoStatusOpen, TagIDs: []int{7}}
+	done := open
+	done.Status = model.TodoStatusDone
+	late := model.Todo{ID: 2, OwnerID: 2, Status: model.TodoStatusOpen, DueDate: &overdue}
+
+	tests := []struct {
+		name        string
+		events      []model.Event
+		ownerID     *int
+		wantTotal   int
+		wantStatus  map[model.TodoStatus]int
+		wantOwner   map[int]int
+		wantTag     map[int]int
+		wantOverdue int
+	}{
+		{
+			name:       "created",
+			events:     []model.Event{statsEvent(model.EventTodoCreated, 1, open)},
+			wantTotal:  1,
+			wantStatus: map[model.TodoStatus]int{model.TodoStatusOpen: 1},
+			wantOwner:  map[int]int{1: 1},
+			wantTag:    map[int]int{7: 1},
+		},
+		{
+			name:       "completed",
+			events:     []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoCompleted, 2, done)},
+			wantTotal:  1,
+			wantStatus: map[model.TodoStatus]int{model.TodoStatusDone: 1},
+			wantOwner:  map[int]int{1: 1},
+			wantTag:    map[int]int{7: 1},
+		},
+		{
+			name:       "out of order",
+			events:     []model.Event{statsEvent(model.EventTodoCompleted, 2, done), statsEvent(model.EventTodoCreated, 1, open)},
+			wantTotal:  1,
+			wantStatus: map[model.TodoStatus]int{model.TodoStatusDone: 1},
+			wantOwner:  map[int]int{1: 1},
+			wantTag:    map[int]int{7: 1},
+		},
+		{
+			name:       "redelivered",
+			events:     []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoCreated, 1, open)},
+			wantTotal:  1,
+			wantStatus: map[model.TodoStatus]int{model.TodoStatusOpen: 1},
+			wantOwner:  map[int]int{1: 1},
+			wantTag:    map[int]int{7: 1},
+		},
+		{
+			name:   "deleted",
+			events: []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoDeleted, 2, open)},
+		},
+		{
+			name:   "late update after delete",
+			events: []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoDeleted, 3, open), statsEvent(model.EventTodoUpdated, 2, done)},
+		},
+		{
+			name:       "restored",
+			events:     []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoDeleted, 2, open), statsEvent(model.EventTodoRestored, 3, open)},
+			wantTotal:  1,
+			wantStatus: map[model.TodoStatus]int{model.TodoStatusOpen: 1},
+			wantOwner:  map[int]int{1: 1},
+			wantTag:    map[int]int{7: 1},
+		},
+		{
+			name:        "overdue",
+			events:      []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoCreated, 1, late)},
+			wantTotal:   2,
+			wantStatus:  map[model.TodoStatus]int{model.TodoStatusOpen: 2},
+			wantOwner:   map[int]int{1: 1, 2: 1},
+			wantTag:     map[int]int{7: 1},
+			wantOverdue: 1,
+		},
+		{
+			name:        "filtered by owner",
+			events:      []model.Event{statsEvent(model.EventTodoCreated, 1, open), statsEvent(model.EventTodoCreated, 1, late)},
+			ownerID:     ptr(2),
+			wantTotal:   1,
+			wantStatus:  map[model.TodoStatus]int{model.TodoStatusOpen: 1},
+			wantOwner:   map[int]int{2: 1},
+			wantOverdue: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := domain.ContextWithActor(context.Background(), 9)
+			s := NewTodoStatsService(readmodel.NewMemoryTodoStats(), persistence.NewTodoRepository(), []int{9})
+			s.now = func() time.Time { return statsStart.Add(time.Hour) }
+			for _, event := range tt.events {
+				if err := s.HandleEvent(ctx, event); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stats, err := s.GetTodoStats(ctx, model.TodoStatsFilter{OwnerID: tt.ownerID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Total != tt.wantTotal || stats.Overdue != tt.wantOverdue {
+				t.Errorf("total, overdue = %d, %d, want %d, %d", stats.Total, stats.Overdue, tt.wantTotal, tt.wantOverdue)
+			}
+			if !maps.Equal(stats.ByStatus, orEmpty(tt.wantStatus)) {
+				t.Errorf("by status = %v, want %v", stats.ByStatus, tt.wantStatus)
+			}
+			if !maps.Equal(stats.ByOwner, orEmpty(tt.wantOwner)) {
+				t.Errorf("by owner = %v, want %v", stats.ByOwner, tt.wantOwner)
+			}
+			if !maps.Equal(stats.ByTag, orEmpty(tt.wantTag)) {
+				t.Errorf("by tag = %v, want %v", stats.ByTag, tt.wantTag)
+			}
+			if stats.Projection.EventsApplied != int64(len(tt.events)) {
+				t.Errorf("events applied = %d, want %d", stats.Projection.EventsApplied, len(tt.events))
+			}
+		})
+	}
+}
+
+// orEmpty returns m, or an empty map if m is nil
+func orEmpty[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return map[K]V{}
+	}
+	return m
+}
+
+func TestRebuildTodoStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorID int
+		// staleEvent is delivered after the rebuild, having occurred before it
+		staleEvent bool
+		wantTotal  int
+		wantErr    error
+	}{
+		{name: "administrator", actorID: 9, wantTotal: 2},
+		{name: "stale event ignored", actorID: 9, staleEvent: true, wantTotal: 2},
+		{name: "not an administrator", actorID: 1, wantTotal: 0, wantErr: domain.ErrForbidden},
+		{name: "anonymous", wantTotal: 0, wantErr: domain.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			todos, _ := newTestTodoService()
+			mustCreateTodo(t, todos, "a", nil)
+			mustCreateTodo(t, todos, "b", nil)
+			s := NewTodoStatsService(readmodel.NewMemoryTodoStats(), todos.repo, []int{9})
+			s.now = func() time.Time { return statsStart.Add(time.Hour) }
+
+			if tt.actorID != 0 {
+				ctx = domain.ContextWithActor(ctx, tt.actorID)
+			}
+			if _, err := s.RebuildTodoStats(ctx); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RebuildTodoStats() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.staleEvent {
+				if err := s.HandleEvent(ctx, statsEvent(model.EventTodoDeleted, 1, model.Todo{ID: 1})); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			stats, err := s.GetTodoStats(domain.ContextWithActor(ctx, 9), model.TodoStatsFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stats.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", stats.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestTodoStatsScope(t *testing.T) {
+	mine := model.Todo{ID: 1, OwnerID: 1, Status: model.TodoStatusOpen}
+	theirs := model.Todo{ID: 2, OwnerID: 2, Status: model.TodoStatusOpen}
+
+	tests := []struct {
+		name      string
+		actorID   int
+		ownerID   *int
+		wantOwner map[int]int
+		wantErr   error
+	}{
+		{name: "user counts their own", actorID: 1, wantOwner: map[int]int{1: 1}},
+		{name: "user asks for their own", actorID: 1, ownerID: ptr(1), wantOwner: map[int]int{1: 1}},
+		{name: "user asks for someone else's", actorID: 1, ownerID: ptr(2), wantErr: domain.ErrForbidden},
+		{name: "administrator counts everyone's", actorID: 9, wantOwner: map[int]int{1: 1, 2: 1}},
+		{name: "administrator asks for an owner", actorID: 9, ownerID: ptr(2), wantOwner: map[int]int{2: 1}},
+		{name: "anonymous", wantErr: domain.ErrUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewTodoStatsService(readmodel.NewMemoryTodoStats(), persistence.NewTodoRepository(), []int{9})
+			for _, event := range []model.Event{statsEvent(model.EventTodoCreated, 1, mine), statsEvent(model.EventTodoCreated, 1, theirs)} {
+				if err := s.HandleEvent(ctx, event); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.actorID != 0 {
+				ctx = domain.ContextWithActor(ctx, tt.actorID)
+			}
+			stats, err := s.GetTodoStats(ctx, model.TodoStatsFilter{OwnerID: tt.ownerID})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetTodoStats() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !maps.Equal(stats.ByOwner, tt.wantOwner) {
+				t.Errorf("by owner = %v, want %v", stats.ByOwner, tt.wantOwner)
+			}
+		})
+	}
+}
+
+func TestTodoStatsLag(t *testing.T) {
+	ctx := context.Background()
+	now := statsStart
+	s := NewTodoStatsService(readmodel.NewMemoryTodoStats(), persistence.NewTodoRepository(), nil)
+	s.now = func() time.Time { return now }
+
+	first := statsEvent(model.EventTodoCreated, 0, model.Todo{ID: 1, OwnerID: 1})
+	second := statsEvent(model.EventTodoCreated, 1, model.Todo{ID: 2, OwnerID: 1})
+	lag := func(wantPending int, wantLag time.Duration) {
+		t.Helper()
+		status, err := s.GetProjectionStatus(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.EventsPending != wantPending || status.LagSeconds != wantLag.Seconds() {
+			t.Errorf("pending, lag = %d, %vs, want %d, %vs", status.EventsPending, status.LagSeconds, wantPending, wantLag.Seconds())
+		}
+	}
+
+	for _, event := range []model.Event{first, second} {
+		if err := s.TrackEvent(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Events of other types are not the projection's to wait for
+	if err := s.TrackEvent(ctx, model.Event{ID: "other", Type: model.EventListMemberAdded, OccurredAt: statsStart}); err != nil {
+		t.Fatal(err)
+	}
+
+	// An idle projection with queued events falls further behind as time passes
+	now = statsStart.Add(2 * time.Minute)
+	lag(2, 2*time.Minute)
+	now = statsStart.Add(10 * time.Minute)
+	lag(2, 10*time.Minute)
+
+	// Applying the oldest event leaves the lag of the next one
+	if err := s.HandleEvent(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	lag(1, 9*time.Minute)
+
+	// Once caught up the lag is that of the last applied event
+	now = statsStart.Add(11 * time.Minute)
+	if err := s.HandleEvent(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	lag(0, 10*time.Minute)
+}
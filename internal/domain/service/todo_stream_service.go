@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// TodoStreamEvents are the events that live streams are built from.
+// Completions are left out since every one comes with an update; joining and
+// leaving a list change which todos a user sees.
+var TodoStreamEvents = []model.EventType{
+	model.EventTodoCreated,
+	model.EventTodoUpdated,
+	model.EventTodoDeleted,
+	model.EventTodoRestored,
+	model.EventTodoPurged,
+	model.EventListMemberAdded,
+	model.EventListMemberRemoved,
+}
+
+// TodoStreamService implements the TodoStreamServicePort interface. It turns
+// todo events into numbered changes, keeps the most recent ones for clients
+// resuming after a disconnect, and fans them out to subscribers. A subscriber
+// whose queue is full is dropped rather than holding up the others; it
+// resumes from the replay buffer when it reconnects.
+type TodoStreamService struct {
+	listRepo     port.TodoListRepositoryPort
+	shareRepo    port.ListShareRepositoryPort
+	replaySize   int
+	clientBuffer int
+	now          func() time.Time
+
+	mu          sync.Mutex
+	replay      []streamChange
+	lastID      int
+	subscribers map[*todoSubscription]struct{}
+}
+
+// TodoStreamOption configures optional TodoStreamService behavior
+type TodoStreamOption func(*TodoStreamService)
+
+// WithStreamReplayBuffer sets how many recent changes are kept for resuming clients
+func WithStreamReplayBuffer(n int) TodoStreamOption {
+	return func(s *TodoStreamService) {
+		s.replaySize = n
+	}
+}
+
+// WithStreamClientBuffer sets how many changes may queue for one client
+// before it is considered too slow and disconnected
+func WithStreamClientBuffer(n int) TodoStreamOption {
+	return func(s *TodoStreamService) {
+		s.clientBuffer = n
+	}
+}
+
+// NewTodoStreamService creates a new TodoStreamService
+func NewTodoStreamService(listRepo port.TodoListRepositoryPort, shareRepo port.ListShareRepositoryPort, opts ...TodoStreamOption) *TodoStreamService {
+	s := &TodoStreamService{
+		listRepo:     listRepo,
+		shareRepo:    shareRepo,
+		replaySize:   1000,
+		clientBuffer: 64,
+		now:          time.Now,
+		subscribers:  make(map[*todoSubscription]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// streamChange is a buffered change together with who may receive it. Its
+// recipients are resolved once, before it is numbered, so neither delivering
+// nor replaying it calls the repositories while the service is locked.
+type streamChange struct {
+	event model.TodoStreamEvent
+	// previousListID is the list an update moved the todo out of
+	previousListID *int
+	// recipients can see the todo; a membership reset only goes to the member
+	recipients map[int]bool
+	// formerRecipients saw the todo only through the list it was moved out of
+	formerRecipients map[int]bool
+}
+
+// todoSubscription implements the TodoSubscription interface
+type todoSubscription struct {
+	service *TodoStreamService
+	userID  int
+	replay  []model.TodoStreamEvent
+	changes chan model.TodoStreamEvent
+	// closed is guarded by the service's lock
+	closed bool
+}
+
+// Replay returns the buffered changes the client missed
+func (sub *todoSubscription) Replay() []model.TodoStreamEvent {
+	return sub.replay
+}
+
+// Changes delivers live changes until the subscription ends
+func (sub *todoSubscription) Changes() <-chan model.TodoStreamEvent {
+	return sub.changes
+}
+
+// Close ends the subscription
+func (sub *todoSubscription) Close() {
+	sub.service.mu.Lock()
+	defer sub.service.mu.Unlock()
+
+	sub.service.unsubscribe(sub)
+}
+
+// unsubscribe removes a subscriber and closes its channel. The caller holds the lock.
+func (s *TodoStreamService) unsubscribe(sub *todoSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(s.subscribers, sub)
+	close(sub.changes)
+}
+
+// addListAudience adds the owner and members of a list to a set of users
+func (s *TodoStreamService) addListAudience(ctx context.Context, users map[int]bool, listID int) {
+	list, err := s.listRepo.GetByID(ctx, listID)
+	if err != nil {
+		return
+	}
+	users[list.OwnerID] = true
+	members, err := s.shareRepo.ListMembers(ctx, list.ID)
+	if err != nil {
+		log.Printf("Failed to list members of list %d for todo streams: %v", list.ID, err)
+		return
+	}
+	for _, member := range members {
+		users[member.UserID] = true
+	}
+}
+
+// resolveRecipients finds who can see a todo change: its owner and everyone on
+// its list. Users who saw it only through the list it was moved out of become
+// former recipients.
+func (s *TodoStreamService) resolveRecipients(ctx context.Context, change *streamChange) {
+	todo := change.event.Todo
+	change.recipients = map[int]bool{todo.OwnerID: true}
+	if todo.ListID != nil {
+		s.addListAudience(ctx, change.recipients, *todo.ListID)
+	}
+	if change.previousListID == nil {
+		return
+	}
+	change.formerRecipients = make(map[int]bool)
+	s.addListAudience(ctx, change.formerRecipients, *change.previousListID)
+	for userID := range change.recipients {
+		delete(change.formerRecipients, userID)
+	}
+}
+
+// changeFor returns a change as a user receives it, or false if it is not for
+// them. Former recipients see the todo deleted, without its new state.
+func changeFor(change streamChange, userID int) (model.TodoStreamEvent, bool) {
+	if change.recipients[userID] {
+		return change.event, true
+	}
+	if change.formerRecipients[userID] {
+		gone := change.event
+		gone.Type = model.TodoStreamDeleted
+		gone.Todo = nil
+		return gone, true
+	}
+	return model.TodoStreamEvent{}, false
+}
+
+// Subscribe streams changes to the todos the acting user can see
+func (s *TodoStreamService) Subscribe(ctx context.Context, lastEventID *int) (port.TodoSubscription, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &todoSubscription{
+		service: s,
+		userID:  actorID,
+		changes: make(chan model.TodoStreamEvent, s.clientBuffer),
+	}
+	if lastEventID != nil {
+		sub.replay = s.replaySince(*lastEventID, actorID)
+	}
+	s.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// replaySince returns the buffered changes after the given ID that the user
+// can see. If changes after it are no longer buffered, or the ID is unknown,
+// a single reset is returned instead. The caller holds the lock.
+func (s *TodoStreamService) replaySince(lastEventID int, userID int) []model.TodoStreamEvent {
+	oldest := s.lastID - len(s.replay) + 1
+	if lastEventID > s.lastID || lastEventID < oldest-1 {
+		return []model.TodoStreamEvent{{ID: s.lastID, Type: model.TodoStreamReset, OccurredAt: s.now()}}
+	}
+
+	changes := make([]model.TodoStreamEvent, 0)
+	for _, change := range s.replay[lastEventID-oldest+1:] {
+		if event, ok := changeFor(change, userID); ok {
+			changes = append(changes, event)
+		}
+	}
+	return changes
+}
+
+// todoStreamEventTypeOf maps a todo event to the change clients see. Restored
+// todos reappear as created; purging a todo that is already in the trash
+// changes nothing for them.
+func todoStreamEventTypeOf(event model.Event) (model.TodoStreamEventType, bool) {
+	switch event.Type {
+	case model.EventTodoCreated, model.EventTodoRestored:
+		return model.TodoStreamCreated, true
+	case model.EventTodoUpdated:
+		return model.TodoStreamUpdated, true
+	case model.EventTodoDeleted:
+		return model.TodoStreamDeleted, true
+	case model.EventTodoPurged:
+		return model.TodoStreamDeleted, event.Todo.DeletedAt == nil
+	default:
+		return "", false
+	}
+}
+
+// streamChangeOf turns an event into a change. Todo events become changes to
+// the todo; joining or leaving a list resets the member's streams, since the
+// todos they can see changed all at once.
+func streamChangeOf(event model.Event) (streamChange, bool) {
+	if event.Member != nil {
+		reset := streamChange{
+			event:      model.TodoStreamEvent{Type: model.TodoStreamReset, OccurredAt: event.OccurredAt},
+			recipients: map[int]bool{event.Member.UserID: true},
+		}
+		return reset, event.Type == model.EventListMemberAdded || event.Type == model.EventListMemberRemoved
+	}
+	if event.Todo == nil {
+		return streamChange{}, false
+	}
+	eventType, ok := todoStreamEventTypeOf(event)
+	if !ok {
+		return streamChange{}, false
+	}
+	return streamChange{
+		event: model.TodoStreamEvent{
+			Type:       eventType,
+			TodoID:     event.AggregateID,
+			Todo:       event.Todo,
+			OccurredAt: event.OccurredAt,
+		},
+		previousListID: event.PreviousListID,
+	}, true
+}
+
+// HandleEvent numbers an event as a change, buffers it for replay and sends
+// it to every subscriber it is for
+func (s *TodoStreamService) HandleEvent(ctx context.Context, event model.Event) error {
+	change, ok := streamChangeOf(event)
+	if !ok {
+		return nil
+	}
+	if change.event.Todo != nil {
+		s.resolveRecipients(ctx, &change)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastID++
+	change.event.ID = s.lastID
+	s.replay = append(s.replay, change)
+	if len(s.replay) > s.replaySize {
+		s.replay = s.replay[len(s.replay)-s.replaySize:]
+	}
+
+	for sub := range s.subscribers {
+		event, ok := changeFor(change, sub.userID)
+		if !ok {
+			continue
+		}
+
+		select {
+		case sub.changes <- event:
+		default:
+			log.Printf("Dropping todo stream of user %d: %d changes queued", sub.userID, len(sub.changes))
+			s.unsubscribe(sub)
+		}
+	}
+	return nil
+}
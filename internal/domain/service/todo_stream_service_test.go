@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// streamFixture feeds the changes of a listFixture to a todo stream
+type streamFixture struct {
+	*listFixture
+	stream    *TodoStreamService
+	publisher *recordingPublisher
+	listID    int
+}
+
+// newStreamFixture creates a list owned by user 1, shared with user 2 as a
+// viewer and holding todo 1, and a user 3 with no access to it
+func newStreamFixture(t *testing.T) *streamFixture {
+	t.Helper()
+	publisher := &recordingPublisher{}
+	lf := newPublishingListFixture(publisher)
+	f := &streamFixture{
+		listFixture: lf,
+		stream:      NewTodoStreamService(lf.listRepo, lf.shares),
+		publisher:   publisher,
+	}
+
+	ctx := domain.ContextWithActor(context.Background(), 1)
+	for _, name := range []string{"alice", "bob", "carol"} {
+		if err := f.users.Create(ctx, &model.User{Username: name, Email: name + "@example.com", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	list, err := f.lists.CreateList(ctx, &model.CreateTodoListRequest{Name: "chores"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.listID = list.ID
+	if err := f.sharing.ShareRepository().SaveMember(ctx, &model.ListMember{ListID: list.ID, UserID: 2, Role: model.ListRoleViewer}); err != nil {
+		t.Fatal(err)
+	}
+	mustCreateTodo(t, f.todos, "dishes", nil)
+	if _, err := f.lists.AddTodo(ctx, list.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+	publisher.events = nil
+	return f
+}
+
+// describeStreamEvent renders a change as type:todo, marking changes that carry the todo
+func describeStreamEvent(event model.TodoStreamEvent) string {
+	s := string(event.Type)
+	if event.TodoID != 0 {
+		s += fmt.Sprintf(":%d", event.TodoID)
+	}
+	if event.Todo != nil {
+		s += "+todo"
+	}
+	return s
+}
+
+func TestTodoStreamFollowsListMembership(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(f *streamFixture) error
+		// want lists the changes each user receives
+		want map[int][]string
+	}{
+		{
+			name: "todo on the list changes",
+			op: func(f *streamFixture) error {
+				_, err := f.todos.UpdateTodo(domain.ContextWithActor(context.Background(), 1), 1, &model.UpdateTodoRequest{Title: "wash dishes"})
+				return err
+			},
+			want: map[int][]string{1: {"updated:1+todo"}, 2: {"updated:1+todo"}},
+		},
+		{
+			name: "invited user joins",
+			op: func(f *streamFixture) error {
+				invitation, err := f.sharing.Invite(domain.ContextWithActor(context.Background(), 1), f.listID, &model.InviteToListRequest{Username: "carol", Role: model.ListRoleViewer})
+				if err != nil {
+					return err
+				}
+				_, err = f.sharing.AcceptInvitation(domain.ContextWithActor(context.Background(), 3), invitation.ID)
+				return err
+			},
+			want: map[int][]string{3: {"reset"}},
+		},
+		{
+			name: "owner removes a member",
+			op: func(f *streamFixture) error {
+				return f.sharing.RemoveMember(domain.ContextWithActor(context.Background(), 1), f.listID, 2)
+			},
+			want: map[int][]string{2: {"reset"}},
+		},
+		{
+			name: "member leaves",
+			op: func(f *streamFixture) error {
+				return f.sharing.RemoveMember(domain.ContextWithActor(context.Background(), 2), f.listID, 2)
+			},
+			want: map[int][]string{2: {"reset"}},
+		},
+		{
+			name: "role change keeps the todos visible",
+			op: func(f *streamFixture) error {
+				_, err := f.sharing.UpdateMember(domain.ContextWithActor(context.Background(), 1), f.listID, 2, &model.UpdateListMemberRequest{Role: model.ListRoleEditor})
+				return err
+			},
+			want: map[int][]string{},
+		},
+		{
+			name: "todo moved out of the list",
+			op: func(f *streamFixture) error {
+				_, err := f.lists.RemoveTodo(domain.ContextWithActor(context.Background(), 1), f.listID, 1)
+				return err
+			},
+			want: map[int][]string{1: {"updated:1+todo"}, 2: {"deleted:1"}},
+		},
+		{
+			name: "list deleted",
+			op: func(f *streamFixture) error {
+				return f.lists.DeleteList(domain.ContextWithActor(context.Background(), 1), f.listID, model.ListDeleteDetach)
+			},
+			want: map[int][]string{1: {"updated:1+todo"}, 2: {"reset"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newStreamFixture(t)
+			live := make(map[int]<-chan model.TodoStreamEvent)
+			for userID := 1; userID <= 3; userID++ {
+				sub, err := f.stream.Subscribe(domain.ContextWithActor(context.Background(), userID), nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer sub.Close()
+				live[userID] = sub.Changes()
+			}
+
+			if err := tt.op(f); err != nil {
+				t.Fatalf("operation failed: %v", err)
+			}
+			for _, event := range f.publisher.events {
+				if err := f.stream.HandleEvent(context.Background(), event); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for userID := 1; userID <= 3; userID++ {
+				var got []string
+				for len(live[userID]) > 0 {
+					got = append(got, describeStreamEvent(<-live[userID]))
+				}
+				if want := strings.Join(tt.want[userID], " "); strings.Join(got, " ") != want {
+					t.Errorf("user %d received %v, want [%s]", userID, got, want)
+				}
+
+				// A client resuming from before the change gets the same changes
+				lastEventID := 0
+				resumed, err := f.stream.Subscribe(domain.ContextWithActor(context.Background(), userID), &lastEventID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var replayed []string
+				for _, event := range resumed.Replay() {
+					replayed = append(replayed, describeStreamEvent(event))
+				}
+				resumed.Close()
+				if strings.Join(replayed, " ") != strings.Join(got, " ") {
+					t.Errorf("user %d replayed %v, received %v", userID, replayed, got)
+				}
+			}
+		})
+	}
+}
+
+// blockingListRepository holds up list lookups until released
+type blockingListRepository struct {
+	port.TodoListRepositoryPort
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingListRepository) GetByID(ctx context.Context, id int) (*model.TodoList, error) {
+	r.entered <- struct{}{}
+	<-r.release
+	return r.TodoListRepositoryPort.GetByID(ctx, id)
+}
+
+func TestTodoStreamSlowRepositoryDoesNotBlockStreams(t *testing.T) {
+	listRepo := persistence.NewTodoListRepository()
+	list := &model.TodoList{Name: "chores", OwnerID: 1}
+	if err := listRepo.Create(context.Background(), list); err != nil {
+		t.Fatal(err)
+	}
+	slow := &blockingListRepository{TodoListRepositoryPort: listRepo, entered: make(chan struct{}), release: make(chan struct{})}
+	stream := NewTodoStreamService(slow, persistence.NewListShareRepository())
+
+	owner, err := stream.Subscribe(domain.ContextWithActor(context.Background(), 1), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handled := make(chan error, 1)
+	go func() {
+		handled <- stream.HandleEvent(context.Background(), model.Event{
+			Type:        model.EventTodoCreated,
+			AggregateID: 1,
+			Todo:        &model.Todo{ID: 1, OwnerID: 2, ListID: &list.ID},
+		})
+	}()
+	<-slow.entered
+
+	// While the event's recipients are looked up, streams come and go
+	subscribed := make(chan struct{})
+	go func() {
+		sub, err := stream.Subscribe(domain.ContextWithActor(context.Background(), 3), nil)
+		if err == nil {
+			sub.Close()
+		}
+		close(subscribed)
+	}()
+	select {
+	case <-subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribe() waited for a repository call of another event")
+	}
+
+	close(slow.release)
+	if err := <-handled; err != nil {
+		t.Fatal(err)
+	}
+	if got := describeStreamEvent(<-owner.Changes()); got != "created:1+todo" {
+		t.Errorf("list owner got %s, want created:1+todo", got)
+	}
+}
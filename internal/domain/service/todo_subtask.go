@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// WithSubtaskRule sets how a parent todo's completion relates to its subtasks
+func WithSubtaskRule(rule model.SubtaskRule) TodoServiceOption {
+	return func(s *TodoService) {
+		s.subtaskRule = rule
+	}
+}
+
+// WithMaxSubtaskDepth sets how many levels of subtasks may be nested below a top-level todo
+func WithMaxSubtaskDepth(depth int) TodoServiceOption {
+	return func(s *TodoService) {
+		s.maxSubtaskDepth = depth
+	}
+}
+
+// listChildren retrieves the direct subtasks of a todo
+func (s *TodoService) listChildren(ctx context.Context, id int) ([]*model.Todo, error) {
+	return s.repo.List(ctx, model.TodoFilter{ParentID: &id})
+}
+
+// subtreeHeight returns how many levels of subtasks are nested below a todo
+func (s *TodoService) subtreeHeight(ctx context.Context, id int) (int, error) {
+	children, err := s.listChildren(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	height := 0
+	for _, child := range children {
+		h, err := s.subtreeHeight(ctx, child.ID)
+		if err != nil {
+			return 0, err
+		}
+		if h+1 > height {
+			height = h + 1
+		}
+	}
+	return height, nil
+}
+
+// validateParent checks that the todo can be nested under the parent without
+// creating a cycle, crossing owners or exceeding the maximum depth.
+// A todo that is not persisted yet has ID 0.
+func (s *TodoService) validateParent(ctx context.Context, todo *model.Todo, parentID int) error {
+	if parentID == todo.ID {
+		return domain.ErrTodoCycle
+	}
+
+	parent, err := s.repo.GetByID(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.ErrInvalidParentTodo
+		}
+		return err
+	}
+	if parent.OwnerID != todo.OwnerID {
+		return domain.ErrInvalidParentTodo
+	}
+	if s.subtaskRule == model.SubtaskRuleRequireClosed && parent.Status.IsClosed() && !todo.Status.IsClosed() {
+		return domain.ErrClosedParent
+	}
+
+	// Walk up from the parent: the todo must not be among its ancestors
+	depth := 1
+	for ancestor := parent; ancestor.ParentID != nil; depth++ {
+		if *ancestor.ParentID == todo.ID {
+			return domain.ErrTodoCycle
+		}
+		if ancestor, err = s.repo.GetByID(ctx, *ancestor.ParentID); err != nil {
+			return err
+		}
+	}
+
+	height := 0
+	if todo.ID != 0 {
+		if height, err = s.subtreeHeight(ctx, todo.ID); err != nil {
+			return err
+		}
+	}
+	if depth+height > s.maxSubtaskDepth {
+		return domain.ErrMaxDepthExceeded
+	}
+	return nil
+}
+
+// checkSubtasksClosed enforces SubtaskRuleRequireClosed before a todo is completed
+func (s *TodoService) checkSubtasksClosed(ctx context.Context, todo *model.Todo) error {
+	if s.subtaskRule != model.SubtaskRuleRequireClosed || todo.ID == 0 {
+		return nil
+	}
+
+	children, err := s.listChildren(ctx, todo.ID)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		if !child.Status.IsClosed() {
+			return domain.ErrOpenSubtasks
+		}
+	}
+	return nil
+}
+
+// checkParentOpen enforces SubtaskRuleRequireClosed before a subtask is reopened
+func (s *TodoService) checkParentOpen(ctx context.Context, todo *model.Todo) error {
+	if s.subtaskRule != model.SubtaskRuleRequireClosed || todo.ParentID == nil {
+		return nil
+	}
+
+	parent, err := s.repo.GetByID(ctx, *todo.ParentID)
+	if err != nil {
+		return err
+	}
+	if parent.Status.IsClosed() {
+		return domain.ErrClosedParent
+	}
+	return nil
+}
+
+// autoCompleteParents enforces SubtaskRuleAutoComplete after a subtask is closed,
+// completing ancestors whose subtasks are now all closed
+func (s *TodoService) autoCompleteParents(ctx context.Context, todo *model.Todo) error {
+	if s.subtaskRule != model.SubtaskRuleAutoComplete {
+		return nil
+	}
+
+	for todo.ParentID != nil && todo.Status.IsClosed() {
+		parent, err := s.repo.GetByID(ctx, *todo.ParentID)
+		if err != nil {
+			return err
+		}
+		if parent.Status.IsClosed() || !s.stateMachine.CanTransition(parent.Status, model.TodoStatusDone) {
+			return nil
+		}
+
+		children, err := s.listChildren(ctx, parent.ID)
+		if err != nil {
+			return err
+		}
+		anyDone := false
+		for _, child := range children {
+			if !child.Status.IsClosed() {
+				return nil
+			}
+			anyDone = anyDone || child.Status == model.TodoStatusDone
+		}
+		if !anyDone {
+			return nil
+		}
+
+		transition, err := s.applyTransition(ctx, parent, model.TodoStatusDone, "all subtasks closed", s.now())
+		if err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, parent); err != nil {
+			return err
+		}
+		if err := s.repo.AppendTransition(ctx, transition); err != nil {
+			return err
+		}
+		if err := s.spawnNextOccurrence(ctx, parent); err != nil {
+			return err
+		}
+		todo = parent
+	}
+	return nil
+}
+
+// listDescendants retrieves the subtasks below the todos, level by level,
+// grouped by parent ID
+func (s *TodoService) listDescendants(ctx context.Context, todos []*model.Todo) (map[int][]*model.Todo, error) {
+	children := make(map[int][]*model.Todo)
+	seen := make(map[int]bool, len(todos))
+	level := make([]int, 0, len(todos))
+	for _, todo := range todos {
+		if !seen[todo.ID] {
+			seen[todo.ID] = true
+			level = append(level, todo.ID)
+		}
+	}
+
+	for len(level) > 0 {
+		found, err := s.repo.List(ctx, model.TodoFilter{ParentIDs: level})
+		if err != nil {
+			return nil, err
+		}
+		level = level[:0]
+		for _, child := range found {
+			children[*child.ParentID] = append(children[*child.ParentID], child)
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				level = append(level, child.ID)
+			}
+		}
+	}
+	return children, nil
+}
+
+// fillProgress computes the progress of each todo from its checklist and the
+// progress of its subtasks, ignoring cancelled subtasks
+func (s *TodoService) fillProgress(ctx context.Context, todos ...*model.Todo) error {
+	children, err := s.listDescendants(ctx, todos)
+	if err != nil {
+		return err
+	}
+
+	memo := make(map[int]float64)
+	var progress func(todo *model.Todo) float64
+	progress = func(todo *model.Todo) float64 {
+		if p, ok := memo[todo.ID]; ok {
+			return p
+		}
+
+		var done float64
+		units := 0
+		for _, item := range todo.Checklist {
+			units++
+			if item.Checked {
+				done++
+			}
+		}
+		for _, child := range children[todo.ID] {
+			if child.Status == model.TodoStatusCancelled {
+				continue
+			}
+			units++
+			done += progress(child)
+		}
+
+		p := done / float64(max(units, 1))
+		if todo.Status == model.TodoStatusDone {
+			p = 1
+		}
+		memo[todo.ID] = p
+		return p
+	}
+
+	for _, todo := range todos {
+		todo.Progress = progress(todo)
+	}
+	return nil
+}
+
+// ListSubtasks retrieves the direct subtasks of a todo
+func (s *TodoService) ListSubtasks(ctx context.Context, id int) ([]*model.Todo, error) {
+	if _, err := s.getAuthorizedTodo(ctx, id, model.ListRoleViewer); err != nil {
+		return nil, err
+	}
+
+	children, err := s.listChildren(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if children, err = s.visibleTodos(ctx, children); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, children...); err != nil {
+		return nil, err
+	}
+	return children, nil
+}
+
+// AddChecklistItem appends a checklist item to a todo
+func (s *TodoService) AddChecklistItem(ctx context.Context, id int, req *model.CreateChecklistItemRequest) (*model.Todo, error) {
+	if strings.TrimSpace(req.Text) == "" {
+		return nil, domain.ErrInvalidChecklistItem
+	}
+
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	nextID := 1
+	for _, item := range todo.Checklist {
+		if item.ID >= nextID {
+			nextID = item.ID + 1
+		}
+	}
+	todo.Checklist = append(todo.Checklist, model.ChecklistItem{ID: nextID, Text: req.Text})
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// UpdateChecklistItem edits the text of a checklist item or checks it off
+func (s *TodoService) UpdateChecklistItem(ctx context.Context, id int, itemID int, req *model.UpdateChecklistItemRequest) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for i := range todo.Checklist {
+		if todo.Checklist[i].ID != itemID {
+			continue
+		}
+		found = true
+		if req.Text != "" {
+			if strings.TrimSpace(req.Text) == "" {
+				return nil, domain.ErrInvalidChecklistItem
+			}
+			todo.Checklist[i].Text = req.Text
+		}
+		if req.Checked != nil {
+			todo.Checklist[i].Checked = *req.Checked
+		}
+	}
+	if !found {
+		return nil, domain.ErrNotFound
+	}
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
+
+// DeleteChecklistItem removes a checklist item from a todo
+func (s *TodoService) DeleteChecklistItem(ctx context.Context, id int, itemID int) (*model.Todo, error) {
+	todo, err := s.getAuthorizedTodo(ctx, id, model.ListRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	checklist := make([]model.ChecklistItem, 0, len(todo.Checklist))
+	for _, item := range todo.Checklist {
+		if item.ID != itemID {
+			checklist = append(checklist, item)
+		}
+	}
+	if len(checklist) == len(todo.Checklist) {
+		return nil, domain.ErrNotFound
+	}
+	todo.Checklist = checklist
+	todo.UpdatedAt = s.now()
+
+	if err := s.repo.Update(ctx, todo); err != nil {
+		return nil, err
+	}
+	if err := s.fillProgress(ctx, todo); err != nil {
+		return nil, err
+	}
+	return todo, nil
+}
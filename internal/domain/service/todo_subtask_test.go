@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// recordingTodoRepository records the filters of the list queries it serves
+type recordingTodoRepository struct {
+	port.TodoRepositoryPort
+	mu      sync.Mutex
+	filters []model.TodoFilter
+}
+
+func (r *recordingTodoRepository) List(ctx context.Context, filter model.TodoFilter) ([]*model.Todo, error) {
+	r.mu.Lock()
+	r.filters = append(r.filters, filter)
+	r.mu.Unlock()
+	return r.TodoRepositoryPort.List(ctx, filter)
+}
+
+func (r *recordingTodoRepository) reset() {
+	r.mu.Lock()
+	r.filters = nil
+	r.mu.Unlock()
+}
+
+// newTestTodoService creates a TodoService over fresh in-memory repositories
+func newTestTodoService(opts ...TodoServiceOption) (*TodoService, *recordingTodoRepository) {
+	repo := &recordingTodoRepository{TodoRepositoryPort: persistence.NewTodoRepository()}
+	tagRepo := persistence.NewTagRepository()
+	tx := persistence.NewTxManager(repo.TodoRepositoryPort.(*persistence.TodoRepository), tagRepo)
+	return NewTodoService(repo, tagRepo, tx, opts...), repo
+}
+
+func mustCreateTodo(t *testing.T, s *TodoService, title string, parentID *int) *model.Todo {
+	t.Helper()
+	todo, err := s.CreateTodo(context.Background(), &model.CreateTodoRequest{OwnerID: 1, Title: title, ParentID: parentID})
+	if err != nil {
+		t.Fatalf("CreateTodo(%q) error = %v", title, err)
+	}
+	return todo
+}
+
+func mustTransition(t *testing.T, s *TodoService, id int, to model.TodoStatus) {
+	t.Helper()
+	if _, err := s.TransitionTodo(context.Background(), id, &model.TransitionTodoRequest{To: to}); err != nil {
+		t.Fatalf("TransitionTodo(%d, %s) error = %v", id, to, err)
+	}
+}
+
+func TestFillProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(t *testing.T, s *TodoService) *model.Todo
+		want  float64
+	}{
+		{
+			name: "no checklist or subtasks",
+			build: func(t *testing.T, s *TodoService) *model.Todo {
+				return mustCreateTodo(t, s, "root", nil)
+			},
+			want: 0,
+		},
+		{
+			name: "checklist only",
+			build: func(t *testing.T, s *TodoService) *model.Todo {
+				root := mustCreateTodo(t, s, "root", nil)
+				ctx := context.Background()
+				for _, text := range []string{"a", "b", "c", "d"} {
+					if _, err := s.AddChecklistItem(ctx, root.ID, &model.CreateChecklistItemRequest{Text: text}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				checked := true
+				if _, err := s.UpdateChecklistItem(ctx, root.ID, 1, &model.UpdateChecklistItemRequest{Checked: &checked}); err != nil {
+					t.Fatal(err)
+				}
+				return root
+			},
+			want: 0.25,
+		},
+		{
+			name: "nested subtasks",
+			build: func(t *testing.T, s *TodoService) *model.Todo {
+				root := mustCreateTodo(t, s, "root", nil)
+				done := mustCreateTodo(t, s, "done", &root.ID)
+				mustTransition(t, s, done.ID, model.TodoStatusDone)
+				half := mustCreateTodo(t, s, "half", &root.ID)
+				leaf := mustCreateTodo(t, s, "leaf", &half.ID)
+				mustTransition(t, s, leaf.ID, model.TodoStatusDone)
+				mustCreateTodo(t, s, "open leaf", &half.ID)
+				return root
+			},
+			want: 0.75,
+		},
+		{
+			name: "cancelled subtasks are ignored",
+			build: func(t *testing.T, s *TodoService) *model.Todo {
+				root := mustCreateTodo(t, s, "root", nil)
+				done := mustCreateTodo(t, s, "done", &root.ID)
+				mustTransition(t, s, done.ID, model.TodoStatusDone)
+				cancelled := mustCreateTodo(t, s, "cancelled", &root.ID)
+				mustTransition(t, s, cancelled.ID, model.TodoStatusCancelled)
+				return root
+			},
+			want: 1,
+		},
+		{
+			name: "unrelated todos do not count",
+			build: func(t *testing.T, s *TodoService) *model.Todo {
+				other := mustCreateTodo(t, s, "other", nil)
+				mustCreateTodo(t, s, "other child", &other.ID)
+				root := mustCreateTodo(t, s, "root", nil)
+				child := mustCreateTodo(t, s, "child", &root.ID)
+				mustTransition(t, s, child.ID, model.TodoStatusDone)
+				return root
+			},
+			want: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestTodoService()
+			root := tt.build(t, s)
+			repo.reset()
+
+			got, err := s.GetTodo(context.Background(), root.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got.Progress-tt.want) > 1e-9 {
+				t.Errorf("Progress = %v, want %v", got.Progress, tt.want)
+			}
+			for _, filter := range repo.filters {
+				if filter.ParentID == nil && len(filter.ParentIDs) == 0 {
+					t.Errorf("progress scanned all todos with filter %+v", filter)
+				}
+			}
+		})
+	}
+}
+
+func TestSubtasksBelowClosedParents(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    model.SubtaskRule
+		run     func(t *testing.T, s *TodoService) error
+		wantErr error
+	}{
+		{
+			name: "create under closed parent",
+			rule: model.SubtaskRuleRequireClosed,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				mustTransition(t, s, parent.ID, model.TodoStatusDone)
+				_, err := s.CreateTodo(context.Background(), &model.CreateTodoRequest{OwnerID: 1, Title: "child", ParentID: &parent.ID})
+				return err
+			},
+			wantErr: domain.ErrClosedParent,
+		},
+		{
+			name: "create under closed parent without the rule",
+			rule: model.SubtaskRuleNone,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				mustTransition(t, s, parent.ID, model.TodoStatusDone)
+				_, err := s.CreateTodo(context.Background(), &model.CreateTodoRequest{OwnerID: 1, Title: "child", ParentID: &parent.ID})
+				return err
+			},
+		},
+		{
+			name: "create under open parent",
+			rule: model.SubtaskRuleRequireClosed,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				_, err := s.CreateTodo(context.Background(), &model.CreateTodoRequest{OwnerID: 1, Title: "child", ParentID: &parent.ID})
+				return err
+			},
+		},
+		{
+			name: "move open todo under closed parent",
+			rule: model.SubtaskRuleRequireClosed,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				mustTransition(t, s, parent.ID, model.TodoStatusCancelled)
+				todo := mustCreateTodo(t, s, "todo", nil)
+				_, err := s.UpdateTodo(context.Background(), todo.ID, &model.UpdateTodoRequest{ParentID: &parent.ID})
+				return err
+			},
+			wantErr: domain.ErrClosedParent,
+		},
+		{
+			name: "move closed todo under closed parent",
+			rule: model.SubtaskRuleRequireClosed,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				mustTransition(t, s, parent.ID, model.TodoStatusDone)
+				todo := mustCreateTodo(t, s, "todo", nil)
+				mustTransition(t, s, todo.ID, model.TodoStatusDone)
+				closed, err := s.GetTodo(context.Background(), todo.ID)
+				if err != nil {
+					return err
+				}
+				return s.validateParent(context.Background(), closed, parent.ID)
+			},
+		},
+		{
+			name: "reopen subtask of closed parent",
+			rule: model.SubtaskRuleRequireClosed,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				child := mustCreateTodo(t, s, "child", &parent.ID)
+				mustTransition(t, s, child.ID, model.TodoStatusDone)
+				mustTransition(t, s, parent.ID, model.TodoStatusDone)
+				_, err := s.UpdateTodo(context.Background(), child.ID, &model.UpdateTodoRequest{Completed: ptr(false)})
+				return err
+			},
+			wantErr: domain.ErrClosedParent,
+		},
+		{
+			name: "reopen subtask of open parent",
+			rule: model.SubtaskRuleRequireClosed,
+			run: func(t *testing.T, s *TodoService) error {
+				parent := mustCreateTodo(t, s, "parent", nil)
+				child := mustCreateTodo(t, s, "child", &parent.ID)
+				mustTransition(t, s, child.ID, model.TodoStatusDone)
+				_, err := s.UpdateTodo(context.Background(), child.ID, &model.UpdateTodoRequest{Completed: ptr(false)})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestTodoService(WithSubtaskRule(tt.rule))
+			if err := tt.run(t, s); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRestoreSubtaskOfClosedParent(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       model.SubtaskRule
+		wantParent bool
+	}{
+		{name: "rule requires open parents", rule: model.SubtaskRuleRequireClosed, wantParent: false},
+		{name: "no rule", rule: model.SubtaskRuleNone, wantParent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s, _ := newTestTodoService(WithSubtaskRule(tt.rule))
+			parent := mustCreateTodo(t, s, "parent", nil)
+			child := mustCreateTodo(t, s, "child", &parent.ID)
+			if err := s.DeleteTodo(ctx, child.ID); err != nil {
+				t.Fatal(err)
+			}
+			mustTransition(t, s, parent.ID, model.TodoStatusDone)
+
+			restored, err := s.RestoreTodo(ctx, child.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := restored.ParentID != nil; got != tt.wantParent {
+				t.Errorf("restored under parent = %v, want %v", got, tt.wantParent)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"go-boilerplate/internal/domain/port"
+)
+
+// withinTx runs fn in a transaction and returns its result
+func withinTx[T any](ctx context.Context, tx port.TransactionManagerPort, fn func(ctx context.Context) (T, error)) (T, error) {
+	var result T
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		return err
+	})
+	return result, err
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go-boilerplate/internal/domain/port"
+)
+
+// TrashPurger permanently removes todos and users that have been in the
+// trash for longer than the retention period
+type TrashPurger struct {
+	todoService port.TodoServicePort
+	userService port.UserServicePort
+	retention   time.Duration
+	now         func() time.Time
+}
+
+// NewTrashPurger creates a new TrashPurger
+func NewTrashPurger(todoService port.TodoServicePort, userService port.UserServicePort, retention time.Duration) *TrashPurger {
+	return &TrashPurger{
+		todoService: todoService,
+		userService: userService,
+		retention:   retention,
+		now:         time.Now,
+	}
+}
+
+// PurgeOnce removes everything trashed before the retention cutoff
+func (p *TrashPurger) PurgeOnce(ctx context.Context) error {
+	cutoff := p.now().Add(-p.retention)
+
+	todos, err := p.todoService.PurgeDeletedTodos(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	users, err := p.userService.PurgeDeletedUsers(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if todos > 0 || users > 0 {
+		log.Printf("Purged %d todos and %d users from the trash", todos, users)
+	}
+	return nil
+}
+
+// Run purges the trash at every interval until the context is cancelled
+func (p *TrashPurger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.PurgeOnce(ctx); err != nil {
+			log.Printf("Failed to purge trash: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"time"
+	"unicode"
 
 	"go-boilerplate/internal/domain"
 	"go-boilerplate/internal/domain/model"
@@ -11,26 +14,115 @@ import (
 
 // UserService implements the UserServicePort interface
 type UserService struct {
-	repo port.UserRepositoryPort
+	repo                port.UserRepositoryPort
+	tx                  port.TransactionManagerPort
+	usernameGracePeriod time.Duration
+	now                 func() time.Time
+}
+
+// UserServiceOption configures optional UserService behavior
+type UserServiceOption func(*UserService)
+
+// WithUsernameGracePeriod sets how long a previous username keeps resolving
+// to its owner and stays reserved after a rename
+func WithUsernameGracePeriod(d time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		s.usernameGracePeriod = d
+	}
+}
+
+// WithUserAuditLog records every change to users in the audit log
+func WithUserAuditLog(log port.AuditPort) UserServiceOption {
+	return func(s *UserService) {
+		s.repo = &auditedUserRepository{
+			UserRepositoryPort: s.repo,
+			audit:              auditRecorder{log: log, tx: s.tx, now: time.Now},
+		}
+	}
+}
+
+// WithUserEventPublisher publishes a domain event for every change to users
+func WithUserEventPublisher(publisher port.EventPublisherPort) UserServiceOption {
+	return func(s *UserService) {
+		s.repo = &eventingUserRepository{
+			UserRepositoryPort: s.repo,
+			events:             eventFactory{publisher: publisher, tx: s.tx, now: time.Now},
+		}
+	}
 }
 
 // NewUserService creates a new UserService
-func NewUserService(repo port.UserRepositoryPort) *UserService {
-	return &UserService{
+func NewUserService(repo port.UserRepositoryPort, tx port.TransactionManagerPort, opts ...UserServiceOption) *UserService {
+	s := &UserService{
 		repo: repo,
+		tx:   tx,
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// getUserIncludingDeleted retrieves a user whether they are live or in the trash
+func getUserIncludingDeleted(ctx context.Context, repo port.UserRepositoryPort, id int) (*model.User, error) {
+	user, err := repo.GetByID(ctx, id)
+	if err == nil {
+		return user, nil
+	}
+	deleted, listErr := repo.ListDeleted(ctx)
+	if listErr != nil {
+		return nil, listErr
 	}
+	for _, user := range deleted {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, err
+}
+
+// validateUsername checks that a username is non-empty and safe to use in URL paths
+func validateUsername(username string) error {
+	if strings.TrimSpace(username) == "" {
+		return domain.ErrInvalidUsername
+	}
+	if strings.ContainsFunc(username, func(r rune) bool { return unicode.IsSpace(r) || r == '/' }) {
+		return domain.ErrInvalidUsername
+	}
+	return nil
+}
+
+// checkUsernameAvailable verifies that no live user holds the username and
+// that it has not been recently released by another user than userID
+func (s *UserService) checkUsernameAvailable(ctx context.Context, username string, userID int) error {
+	if existingUser, _ := s.repo.GetByUsername(ctx, username); existingUser != nil {
+		return domain.ErrUsernameDuplicate
+	}
+
+	history, err := s.repo.GetUsernameHistory(ctx, username)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if history.UserID != userID && s.now().Before(history.ExpiresAt) {
+		return domain.ErrUsernameReserved
+	}
+	return nil
 }
 
 // CreateUser creates a new user
 func (s *UserService) CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.User, error) {
 	// Business logic validation
-	if strings.TrimSpace(req.Username) == "" {
-		return nil, domain.ErrInvalidUsername
+	if err := validateUsername(req.Username); err != nil {
+		return nil, err
 	}
 
-	// Check for duplicate username
-	if existingUser, _ := s.repo.GetByUsername(ctx, req.Username); existingUser != nil {
-		return nil, domain.ErrUsernameDuplicate
+	// Check for duplicate or recently released username
+	if err := s.checkUsernameAvailable(ctx, req.Username, 0); err != nil {
+		return nil, err
 	}
 
 	user := &model.User{
@@ -51,6 +143,33 @@ func (s *UserService) GetUser(ctx context.Context, id int) (*model.User, error)
 	return s.repo.GetByID(ctx, id)
 }
 
+// GetUsers retrieves several users at once, leaving out unknown IDs
+func (s *UserService) GetUsers(ctx context.Context, ids []int) ([]*model.User, error) {
+	return s.repo.GetByIDs(ctx, ids)
+}
+
+// GetUserByUsername retrieves a user by their current username, falling back to
+// usernames released within the grace period. Callers can compare the returned
+// user's Username with the requested one to detect a redirect.
+func (s *UserService) GetUserByUsername(ctx context.Context, username string) (*model.User, error) {
+	user, err := s.repo.GetByUsername(ctx, username)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	history, err := s.repo.GetUsernameHistory(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if !s.now().Before(history.ExpiresAt) {
+		return nil, domain.ErrNotFound
+	}
+	return s.repo.GetByID(ctx, history.UserID)
+}
+
 // ListUsers retrieves all users
 func (s *UserService) ListUsers(ctx context.Context) ([]*model.User, error) {
 	return s.repo.List(ctx)
@@ -77,7 +196,66 @@ func (s *UserService) UpdateUser(ctx context.Context, id int, req *model.UpdateU
 	return user, nil
 }
 
-// DeleteUser deletes a user
+// RenameUser changes a user's username. The previous username is kept in the
+// history so it redirects to the user and cannot be claimed by anyone else
+// until the grace period ends. The repository checks that the new username is
+// available while claiming it.
+func (s *UserService) RenameUser(ctx context.Context, id int, req *model.RenameUserRequest) (*model.User, error) {
+	if err := validateUsername(req.Username); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	history := &model.UsernameHistory{
+		Username:  user.Username,
+		UserID:    id,
+		ChangedAt: now,
+		ExpiresAt: now.Add(s.usernameGracePeriod),
+	}
+
+	return s.repo.Rename(ctx, id, req.Username, history)
+}
+
+// DeleteUser moves a user to the trash
 func (s *UserService) DeleteUser(ctx context.Context, id int) error {
-	return s.repo.Delete(ctx, id)
+	return s.repo.SoftDelete(ctx, id, s.now())
+}
+
+// ListDeletedUsers retrieves the users in the trash
+func (s *UserService) ListDeletedUsers(ctx context.Context) ([]*model.User, error) {
+	return s.repo.ListDeleted(ctx)
+}
+
+// RestoreUser moves a user out of the trash
+func (s *UserService) RestoreUser(ctx context.Context, id int) (*model.User, error) {
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// PurgeDeletedUsers permanently removes users trashed before the cutoff,
+// releasing their usernames
+func (s *UserService) PurgeDeletedUsers(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := s.repo.ListDeleted(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range deleted {
+		if !user.DeletedAt.Before(before) {
+			continue
+		}
+		if err := s.repo.Delete(ctx, user.ID); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
 }
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+const usernameGrace = 24 * time.Hour
+
+// newUserService returns a user service whose clock the test can move, with
+// alice (1) and bob (2) already created
+func newUserService(t *testing.T) (*UserService, *time.Time) {
+	t.Helper()
+	repo := persistence.NewUserRepository()
+	s := NewUserService(repo, persistence.NewTxManager(repo), WithUsernameGracePeriod(usernameGrace))
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := s.CreateUser(context.Background(), &model.CreateUserRequest{Username: name, Email: name + "@example.com", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s, &now
+}
+
+func TestRenameUser(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		// setup runs before the rename under test
+		setup    func(t *testing.T, s *UserService, now *time.Time)
+		userID   int
+		username string
+		wantErr  error
+	}{
+		{name: "free username", userID: 1, username: "alicia"},
+		{name: "invalid username", userID: 1, username: "al ice", wantErr: domain.ErrInvalidUsername},
+		{name: "unchanged", userID: 1, username: "alice", wantErr: domain.ErrUsernameUnchanged},
+		{name: "held by another user", userID: 1, username: "bob", wantErr: domain.ErrUsernameDuplicate},
+		{name: "unknown user", userID: 9, username: "carol", wantErr: domain.ErrNotFound},
+		{
+			name: "released by another user within the grace period",
+			setup: func(t *testing.T, s *UserService, now *time.Time) {
+				mustRename(t, s, 2, "robert")
+			},
+			userID: 1, username: "bob", wantErr: domain.ErrUsernameReserved,
+		},
+		{
+			name: "released by another user after the grace period",
+			setup: func(t *testing.T, s *UserService, now *time.Time) {
+				mustRename(t, s, 2, "robert")
+				*now = now.Add(usernameGrace)
+			},
+			userID: 1, username: "bob",
+		},
+		{
+			name: "own previous username",
+			setup: func(t *testing.T, s *UserService, now *time.Time) {
+				mustRename(t, s, 1, "alicia")
+			},
+			userID: 1, username: "alice",
+		},
+		{
+			name: "held by a deleted user",
+			setup: func(t *testing.T, s *UserService, now *time.Time) {
+				if err := s.DeleteUser(ctx, 2); err != nil {
+					t.Fatal(err)
+				}
+			},
+			userID: 1, username: "bob", wantErr: domain.ErrUsernameInTrash,
+		},
+		{
+			name: "released by a purged user",
+			setup: func(t *testing.T, s *UserService, now *time.Time) {
+				if err := s.DeleteUser(ctx, 2); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := s.PurgeDeletedUsers(ctx, now.Add(time.Second)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			userID: 1, username: "bob",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, now := newUserService(t)
+			if tt.setup != nil {
+				tt.setup(t, s, now)
+			}
+
+			user, err := s.RenameUser(ctx, tt.userID, &model.RenameUserRequest{Username: tt.username})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if user.Username != tt.username {
+				t.Errorf("username = %q, want %q", user.Username, tt.username)
+			}
+			found, err := s.GetUserByUsername(ctx, tt.username)
+			if err != nil || found.ID != tt.userID {
+				t.Errorf("GetUserByUsername(%q) = %v, %v; want user %d", tt.username, found, err, tt.userID)
+			}
+		})
+	}
+}
+
+func mustRename(t *testing.T, s *UserService, id int, username string) {
+	t.Helper()
+	if _, err := s.RenameUser(context.Background(), id, &model.RenameUserRequest{Username: username}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUsernameHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		lookup  string
+		wantID  int
+		wantErr error
+	}{
+		{name: "current username", lookup: "alicia", wantID: 1},
+		{name: "previous username redirects", lookup: "alice", wantID: 1},
+		{name: "redirect just before expiry", elapsed: usernameGrace - time.Second, lookup: "alice", wantID: 1},
+		{name: "redirect expired", elapsed: usernameGrace, lookup: "alice", wantErr: domain.ErrNotFound},
+		{name: "never used", lookup: "carol", wantErr: domain.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, now := newUserService(t)
+			mustRename(t, s, 1, "alicia")
+			*now = now.Add(tt.elapsed)
+
+			user, err := s.GetUserByUsername(context.Background(), tt.lookup)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (user.ID != tt.wantID || user.Username != "alicia") {
+				t.Errorf("user = %d %q, want %d \"alicia\"", user.ID, user.Username, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCreateUserUsernameConflicts(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, s *UserService)
+		username string
+		wantErr  error
+	}{
+		{name: "held by a live user", username: "bob", wantErr: domain.ErrUsernameDuplicate},
+		{name: "reserved by a rename", setup: func(t *testing.T, s *UserService) { mustRename(t, s, 2, "robert") }, username: "bob", wantErr: domain.ErrUsernameReserved},
+		{name: "held by a deleted user", setup: func(t *testing.T, s *UserService) {
+			if err := s.DeleteUser(ctx, 2); err != nil {
+				t.Fatal(err)
+			}
+		}, username: "bob", wantErr: domain.ErrUsernameInTrash},
+		{name: "free", username: "carol"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newUserService(t)
+			if tt.setup != nil {
+				tt.setup(t, s)
+			}
+			_, err := s.CreateUser(ctx, &model.CreateUserRequest{Username: tt.username, Email: "x@example.com", Name: "X"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConcurrentRenamesClaimOnce(t *testing.T) {
+	ctx := context.Background()
+	s, _ := newUserService(t)
+	const renamers = 8
+	for i := 3; i <= renamers; i++ {
+		if _, err := s.CreateUser(ctx, &model.CreateUserRequest{Username: "user" + string(rune('0'+i)), Email: "u@example.com", Name: "U"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, renamers)
+	for i := range renamers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = s.RenameUser(ctx, i+1, &model.RenameUserRequest{Username: "winner"})
+		}()
+	}
+	wg.Wait()
+
+	won := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			won++
+		case !errors.Is(err, domain.ErrUsernameDuplicate):
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if won != 1 {
+		t.Errorf("%d renames claimed the username, want 1", won)
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// WebhookDispatcher sends due webhook deliveries. Each webhook's deliveries are
+// sent in order, with different webhooks served concurrently; a failed
+// delivery is retried with exponential backoff and holds back the later
+// deliveries of its webhook. A delivery that exhausts its attempts is dead
+// lettered, and a webhook that keeps failing is disabled.
+type WebhookDispatcher struct {
+	repo           port.WebhookRepositoryPort
+	sender         port.WebhookSenderPort
+	batchSize      int
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	disableAfter   int
+	now            func() time.Time
+}
+
+// WebhookDispatcherOption configures optional WebhookDispatcher behavior
+type WebhookDispatcherOption func(*WebhookDispatcher)
+
+// WithWebhookBatchSize sets how many deliveries are sent per run
+func WithWebhookBatchSize(n int) WebhookDispatcherOption {
+	return func(d *WebhookDispatcher) {
+		d.batchSize = n
+	}
+}
+
+// WithWebhookMaxAttempts sets how many attempts a delivery gets before it is dead lettered
+func WithWebhookMaxAttempts(n int) WebhookDispatcherOption {
+	return func(d *WebhookDispatcher) {
+		d.maxAttempts = n
+	}
+}
+
+// WithWebhookBackoff sets the wait after the first failed attempt, which
+// doubles with every further failure up to max
+func WithWebhookBackoff(initial, max time.Duration) WebhookDispatcherOption {
+	return func(d *WebhookDispatcher) {
+		d.initialBackoff = initial
+		d.maxBackoff = max
+	}
+}
+
+// WithWebhookDisableAfter sets how many consecutive failed attempts disable a webhook
+func WithWebhookDisableAfter(n int) WebhookDispatcherOption {
+	return func(d *WebhookDispatcher) {
+		d.disableAfter = n
+	}
+}
+
+// NewWebhookDispatcher creates a new WebhookDispatcher
+func NewWebhookDispatcher(repo port.WebhookRepositoryPort, sender port.WebhookSenderPort, opts ...WebhookDispatcherOption) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		repo:           repo,
+		sender:         sender,
+		batchSize:      100,
+		maxAttempts:    8,
+		initialBackoff: 10 * time.Second,
+		maxBackoff:     time.Hour,
+		disableAfter:   20,
+		now:            time.Now,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// backoff returns the wait before the next attempt after the given number of failed attempts
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	wait := d.initialBackoff
+	for i := 1; i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, d.maxBackoff)
+}
+
+// DispatchOnce sends the pending deliveries that are due and returns how many succeeded
+func (d *WebhookDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ListDueDeliveries(ctx, d.now(), d.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var order []int
+	byWebhook := make(map[int][]*model.WebhookDelivery)
+	for _, delivery := range deliveries {
+		if _, exists := byWebhook[delivery.WebhookID]; !exists {
+			order = append(order, delivery.WebhookID)
+		}
+		byWebhook[delivery.WebhookID] = append(byWebhook[delivery.WebhookID], delivery)
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		firstErr  error
+	)
+	for _, webhookID := range order {
+		wg.Add(1)
+		go func(webhookID int) {
+			defer wg.Done()
+			n, err := d.dispatchWebhook(ctx, webhookID, byWebhook[webhookID])
+
+			mu.Lock()
+			defer mu.Unlock()
+			succeeded += n
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}(webhookID)
+	}
+	wg.Wait()
+	return succeeded, firstErr
+}
+
+// dispatchWebhook sends a webhook's deliveries in order, stopping at the first
+// one that is not due yet or fails. Deliveries of a disabled webhook stay
+// pending until it is re-enabled.
+func (d *WebhookDispatcher) dispatchWebhook(ctx context.Context, webhookID int, deliveries []*model.WebhookDelivery) (int, error) {
+	webhook, err := d.repo.GetByID(ctx, webhookID)
+	if err != nil {
+		return 0, err
+	}
+	if !webhook.Active {
+		return 0, nil
+	}
+
+	succeeded := 0
+	for _, delivery := range deliveries {
+		if d.now().Before(delivery.NextAttemptAt) {
+			break
+		}
+		ok, err := d.deliver(ctx, webhook, delivery)
+		if err != nil {
+			return succeeded, err
+		}
+		if !ok {
+			break
+		}
+		succeeded++
+	}
+	return succeeded, nil
+}
+
+// deliver makes one attempt to send a delivery, records it and reports whether it succeeded
+func (d *WebhookDispatcher) deliver(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) (bool, error) {
+	timestamp := d.now()
+	headers := map[string]string{
+		model.WebhookHeaderID:        strconv.Itoa(webhook.ID),
+		model.WebhookHeaderEvent:     string(delivery.EventType),
+		model.WebhookHeaderDelivery:  strconv.Itoa(delivery.ID),
+		model.WebhookHeaderTimestamp: strconv.FormatInt(timestamp.Unix(), 10),
+		model.WebhookHeaderSignature: model.SignWebhookPayload(webhook.Secret, timestamp, delivery.Payload),
+	}
+
+	statusCode, sendErr := d.sender.Send(ctx, webhook.URL, headers, delivery.Payload)
+	finished := d.now()
+	attempt := model.DeliveryAttempt{
+		At:         timestamp,
+		StatusCode: statusCode,
+		DurationMs: finished.Sub(timestamp).Milliseconds(),
+	}
+	ok := sendErr == nil && statusCode >= 200 && statusCode < 300
+	switch {
+	case sendErr != nil:
+		attempt.Error = sendErr.Error()
+	case !ok:
+		attempt.Error = fmt.Sprintf("receiver responded with status %d", statusCode)
+	}
+	delivery.Attempts = append(delivery.Attempts, attempt)
+
+	switch {
+	case ok:
+		delivery.Status = model.DeliverySucceeded
+		delivery.DeliveredAt = &finished
+	case len(delivery.Attempts) >= d.maxAttempts:
+		delivery.Status = model.DeliveryDead
+		log.Printf("Webhook %d delivery %d dead lettered after %d attempts: %s", webhook.ID, delivery.ID, len(delivery.Attempts), attempt.Error)
+	default:
+		delivery.NextAttemptAt = finished.Add(d.backoff(len(delivery.Attempts)))
+	}
+	if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return false, err
+	}
+
+	if err := d.recordHealth(ctx, webhook, ok, finished); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// recordHealth tracks a webhook's consecutive failures and disables it once
+// they reach the limit. The webhook is re-read so concurrent edits by its
+// owner are kept.
+func (d *WebhookDispatcher) recordHealth(ctx context.Context, webhook *model.Webhook, ok bool, now time.Time) error {
+	if ok && webhook.ConsecutiveFailures == 0 {
+		return nil
+	}
+
+	current, err := d.repo.GetByID(ctx, webhook.ID)
+	if err != nil {
+		return err
+	}
+	if ok {
+		current.ConsecutiveFailures = 0
+	} else {
+		current.ConsecutiveFailures++
+		if d.disableAfter > 0 && current.ConsecutiveFailures >= d.disableAfter && current.Active {
+			current.Active = false
+			current.DisabledAt = &now
+			current.UpdatedAt = now
+			current.DisabledReason = fmt.Sprintf("disabled after %d consecutive failed deliveries", current.ConsecutiveFailures)
+			log.Printf("Webhook %d disabled after %d consecutive failed deliveries", current.ID, current.ConsecutiveFailures)
+		}
+	}
+	if err := d.repo.Update(ctx, current); err != nil {
+		return err
+	}
+	webhook.ConsecutiveFailures = current.ConsecutiveFailures
+	return nil
+}
+
+// Run dispatches due deliveries at every interval until the context is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DispatchOnce(ctx); err != nil {
+			log.Printf("Failed to dispatch webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
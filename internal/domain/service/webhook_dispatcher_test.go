@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/adapter/outbound/webhook"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+// webhookReceiver is a webhook endpoint that checks every delivery's
+// signature and answers with scripted status codes
+type webhookReceiver struct {
+	t      *testing.T
+	secret string
+
+	mu        sync.Mutex
+	responses []int
+	received  []string
+}
+
+// ServeHTTP verifies the signature and responds with the next scripted status; the last one repeats
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		r.t.Errorf("reading delivery: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	unix, err := strconv.ParseInt(req.Header.Get(model.WebhookHeaderTimestamp), 10, 64)
+	if err != nil {
+		r.t.Errorf("timestamp header = %q: %v", req.Header.Get(model.WebhookHeaderTimestamp), err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	if got, want := req.Header.Get(model.WebhookHeaderSignature), model.SignWebhookPayload(r.secret, time.Unix(unix, 0), body); got != want {
+		r.t.Errorf("signature = %q, want %q", got, want)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.received = append(r.received, req.Header.Get(model.WebhookHeaderDelivery))
+	status := r.responses[min(len(r.received), len(r.responses))-1]
+	w.WriteHeader(status)
+}
+
+// receivedDeliveries returns the delivery IDs received so far, in order
+func (r *webhookReceiver) receivedDeliveries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.received...)
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		maxAttempts  int
+		disableAfter int
+		deliveries   int
+		// responses are the receiver's status codes in order; the last repeats
+		responses []int
+		// rounds are when DispatchOnce runs, as offsets from the start
+		rounds []time.Duration
+
+		wantReceived []string
+		wantStatus   []model.DeliveryStatus
+		wantAttempts []int
+		// wantNext is when each still pending delivery is retried, as an offset from the start
+		wantNext     []time.Duration
+		wantActive   bool
+		wantFailures int
+	}{
+		{
+			name:         "signed deliveries are sent in order",
+			maxAttempts:  5,
+			disableAfter: 10,
+			deliveries:   2,
+			responses:    []int{http.StatusOK},
+			rounds:       []time.Duration{0},
+			wantReceived: []string{"1", "2"},
+			wantStatus:   []model.DeliveryStatus{model.DeliverySucceeded, model.DeliverySucceeded},
+			wantAttempts: []int{1, 1},
+			wantActive:   true,
+		},
+		{
+			name:         "failed delivery backs off exponentially",
+			maxAttempts:  5,
+			disableAfter: 10,
+			deliveries:   1,
+			responses:    []int{http.StatusInternalServerError},
+			rounds:       []time.Duration{0, 5 * time.Second, 10 * time.Second, 29 * time.Second, 30 * time.Second},
+			wantReceived: []string{"1", "1", "1"},
+			wantStatus:   []model.DeliveryStatus{model.DeliveryPending},
+			wantAttempts: []int{3},
+			wantNext:     []time.Duration{70 * time.Second},
+			wantActive:   true,
+			wantFailures: 3,
+		},
+		{
+			name:         "failed delivery holds back later ones until it succeeds",
+			maxAttempts:  5,
+			disableAfter: 10,
+			deliveries:   2,
+			responses:    []int{http.StatusServiceUnavailable, http.StatusNoContent},
+			rounds:       []time.Duration{0, 10 * time.Second},
+			wantReceived: []string{"1", "1", "2"},
+			wantStatus:   []model.DeliveryStatus{model.DeliverySucceeded, model.DeliverySucceeded},
+			wantAttempts: []int{2, 1},
+			wantActive:   true,
+		},
+		{
+			name:         "delivery is dead lettered after its last attempt",
+			maxAttempts:  2,
+			disableAfter: 10,
+			deliveries:   2,
+			responses:    []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			rounds:       []time.Duration{0, 10 * time.Second, 10 * time.Second},
+			wantReceived: []string{"1", "1", "2"},
+			wantStatus:   []model.DeliveryStatus{model.DeliveryDead, model.DeliverySucceeded},
+			wantAttempts: []int{2, 1},
+			wantActive:   true,
+		},
+		{
+			name:         "webhook is disabled after consecutive failures",
+			maxAttempts:  10,
+			disableAfter: 2,
+			deliveries:   1,
+			responses:    []int{http.StatusInternalServerError},
+			rounds:       []time.Duration{0, 10 * time.Second, time.Hour},
+			wantReceived: []string{"1", "1"},
+			wantStatus:   []model.DeliveryStatus{model.DeliveryPending},
+			wantAttempts: []int{2},
+			wantNext:     []time.Duration{30 * time.Second},
+			wantActive:   false,
+			wantFailures: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &webhookReceiver{t: t, secret: "s3cr3t", responses: tt.responses}
+			server := httptest.NewServer(receiver)
+			defer server.Close()
+
+			now := start
+			repo := persistence.NewWebhookRepository()
+			webhooks := NewWebhookService(repo, WithInternalWebhookURLs(true))
+			webhooks.now = func() time.Time { return now }
+			dispatcher := NewWebhookDispatcher(repo, webhook.NewHTTPSender(time.Second, webhook.WithInternalTargets(true)),
+				WithWebhookMaxAttempts(tt.maxAttempts),
+				WithWebhookBackoff(10*time.Second, time.Minute),
+				WithWebhookDisableAfter(tt.disableAfter),
+			)
+			dispatcher.now = func() time.Time { return now }
+
+			ctx := domain.ContextWithActor(context.Background(), 1)
+			hook, err := webhooks.CreateWebhook(ctx, &model.CreateWebhookRequest{URL: server.URL, Secret: receiver.secret})
+			if err != nil {
+				t.Fatalf("CreateWebhook: %v", err)
+			}
+			for i := range tt.deliveries {
+				event := model.Event{
+					ID:            fmt.Sprintf("event-%d", i+1),
+					Type:          model.EventUserUpdated,
+					AggregateType: model.AggregateUser,
+					AggregateID:   1,
+				}
+				if err := webhooks.HandleEvent(context.Background(), event); err != nil {
+					t.Fatalf("HandleEvent: %v", err)
+				}
+			}
+
+			for _, offset := range tt.rounds {
+				now = start.Add(offset)
+				if _, err := dispatcher.DispatchOnce(context.Background()); err != nil {
+					t.Fatalf("DispatchOnce at %v: %v", offset, err)
+				}
+			}
+
+			if got := receiver.receivedDeliveries(); fmt.Sprint(got) != fmt.Sprint(tt.wantReceived) {
+				t.Errorf("received deliveries = %v, want %v", got, tt.wantReceived)
+			}
+			pending := 0
+			for i, status := range tt.wantStatus {
+				delivery, err := repo.GetDelivery(context.Background(), i+1)
+				if err != nil {
+					t.Fatalf("GetDelivery(%d): %v", i+1, err)
+				}
+				if delivery.Status != status {
+					t.Errorf("delivery %d status = %s, want %s", i+1, delivery.Status, status)
+				}
+				if len(delivery.Attempts) != tt.wantAttempts[i] {
+					t.Errorf("delivery %d attempts = %d, want %d", i+1, len(delivery.Attempts), tt.wantAttempts[i])
+				}
+				if status == model.DeliveryPending {
+					if want := start.Add(tt.wantNext[pending]); !delivery.NextAttemptAt.Equal(want) {
+						t.Errorf("delivery %d next attempt = %v, want %v", i+1, delivery.NextAttemptAt, want)
+					}
+					pending++
+				}
+			}
+
+			got, err := repo.GetByID(context.Background(), hook.ID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if got.Active != tt.wantActive {
+				t.Errorf("active = %v, want %v", got.Active, tt.wantActive)
+			}
+			if got.ConsecutiveFailures != tt.wantFailures {
+				t.Errorf("consecutive failures = %d, want %d", got.ConsecutiveFailures, tt.wantFailures)
+			}
+			if !tt.wantActive && (got.DisabledAt == nil || got.DisabledReason == "") {
+				t.Errorf("disabled webhook has no reason or time: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+	"go-boilerplate/internal/domain/port"
+)
+
+// WebhookService implements the WebhookServicePort interface. Users manage
+// their own webhooks, which receive the events of their todos and account.
+type WebhookService struct {
+	repo port.WebhookRepositoryPort
+	// allowInternal accepts URLs pointing at internal addresses
+	allowInternal bool
+	now           func() time.Time
+}
+
+// WebhookServiceOption configures optional WebhookService behavior
+type WebhookServiceOption func(*WebhookService)
+
+// WithInternalWebhookURLs accepts webhook URLs that point at loopback, private
+// or link-local addresses, for receivers on a development machine. The
+// sender has to allow them too.
+func WithInternalWebhookURLs(allow bool) WebhookServiceOption {
+	return func(s *WebhookService) {
+		s.allowInternal = allow
+	}
+}
+
+// NewWebhookService creates a new WebhookService
+func NewWebhookService(repo port.WebhookRepositoryPort, opts ...WebhookServiceOption) *WebhookService {
+	s := &WebhookService{
+		repo: repo,
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// validateWebhookURL checks that a webhook URL is an absolute http or https
+// URL and, unless internal URLs are allowed, that it does not name an internal
+// address. Host names are resolved only when a delivery is sent, where the
+// sender checks the address again.
+func (s *WebhookService) validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ErrInvalidWebhookURL
+	}
+	if s.allowInternal {
+		return nil
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return domain.ErrInternalWebhookURL
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && model.IsInternalAddress(addr) {
+		return domain.ErrInternalWebhookURL
+	}
+	return nil
+}
+
+// validateEventTypes checks that every subscribed event type is known
+func validateEventTypes(types []model.EventType) error {
+	for _, eventType := range types {
+		if !eventType.IsValid() {
+			return domain.ErrInvalidEventType
+		}
+	}
+	return nil
+}
+
+// generateWebhookSecret returns a random signing secret
+func generateWebhookSecret() string {
+	secret := make([]byte, 32)
+	_, _ = rand.Read(secret)
+	return hex.EncodeToString(secret)
+}
+
+// redactWebhooks hides the signing secrets of webhooks read back by their owner
+func redactWebhooks(webhooks ...*model.Webhook) {
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+}
+
+// getOwnWebhook retrieves a webhook of the acting user. Other users' webhooks
+// are reported as not found.
+func (s *WebhookService) getOwnWebhook(ctx context.Context, id int) (*model.Webhook, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	webhook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.OwnerID != actorID {
+		return nil, domain.ErrNotFound
+	}
+	return webhook, nil
+}
+
+// CreateWebhook subscribes a URL to the acting user's events. The response is
+// the only time the signing secret is returned.
+func (s *WebhookService) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.Webhook, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+	if err := s.validateWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret = generateWebhookSecret()
+	}
+	eventTypes := req.EventTypes
+	if eventTypes == nil {
+		eventTypes = []model.EventType{}
+	}
+
+	now := s.now()
+	webhook := &model.Webhook{
+		OwnerID:    actorID,
+		URL:        req.URL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// ListWebhooks retrieves the acting user's webhooks
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	actorID, ok := domain.ActorFromContext(ctx)
+	if !ok {
+		return nil, domain.ErrUnauthenticated
+	}
+
+	webhooks, err := s.repo.ListByOwner(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	redactWebhooks(webhooks...)
+	return webhooks, nil
+}
+
+// GetWebhook retrieves one of the acting user's webhooks
+func (s *WebhookService) GetWebhook(ctx context.Context, id int) (*model.Webhook, error) {
+	webhook, err := s.getOwnWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	redactWebhooks(webhook)
+	return webhook, nil
+}
+
+// UpdateWebhook updates one of the acting user's webhooks. Reactivating a
+// webhook that was disabled resets its failure count.
+func (s *WebhookService) UpdateWebhook(ctx context.Context, id int, req *model.UpdateWebhookRequest) (*model.Webhook, error) {
+	webhook, err := s.getOwnWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if req.URL != "" {
+		if err := s.validateWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = req.URL
+	}
+	if req.EventTypes != nil {
+		if err := validateEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+		webhook.EventTypes = req.EventTypes
+	}
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil && *req.Active != webhook.Active {
+		webhook.Active = *req.Active
+		if webhook.Active {
+			webhook.ConsecutiveFailures = 0
+			webhook.DisabledAt = nil
+			webhook.DisabledReason = ""
+		} else {
+			webhook.DisabledAt = &now
+			webhook.DisabledReason = "disabled by owner"
+		}
+	}
+	webhook.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	redactWebhooks(webhook)
+	return webhook, nil
+}
+
+// DeleteWebhook removes one of the acting user's webhooks and its deliveries
+func (s *WebhookService) DeleteWebhook(ctx context.Context, id int) error {
+	if _, err := s.getOwnWebhook(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// ListDeliveries retrieves a webhook's deliveries, newest first. Filtering by
+// the dead status lists the webhook's dead-letter queue.
+func (s *WebhookService) ListDeliveries(ctx context.Context, id int, status model.DeliveryStatus) ([]*model.WebhookDelivery, error) {
+	if status != "" && !status.IsValid() {
+		return nil, domain.ErrInvalidDeliveryStatus
+	}
+	if _, err := s.getOwnWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, id, status)
+}
+
+// GetDelivery retrieves a delivery of one of the acting user's webhooks
+func (s *WebhookService) GetDelivery(ctx context.Context, id int, deliveryID int) (*model.WebhookDelivery, error) {
+	if _, err := s.getOwnWebhook(ctx, id); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookID != id {
+		return nil, domain.ErrNotFound
+	}
+	return delivery, nil
+}
+
+// ReplayDelivery queues a new delivery of the same event, typically to retry
+// one from the dead-letter queue once the receiver is fixed
+func (s *WebhookService) ReplayDelivery(ctx context.Context, id int, deliveryID int) (*model.WebhookDelivery, error) {
+	original, err := s.GetDelivery(ctx, id, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	replay := &model.WebhookDelivery{
+		WebhookID:     id,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        model.DeliveryPending,
+		ReplayOf:      &original.ID,
+		Attempts:      []model.DeliveryAttempt{},
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := s.repo.CreateDelivery(ctx, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// eventOwner returns the user whose webhooks receive an event
+func eventOwner(event model.Event) (int, bool) {
+	switch {
+	case event.Todo != nil:
+		return event.Todo.OwnerID, true
+	case event.AggregateType == model.AggregateUser:
+		return event.AggregateID, true
+	case event.Member != nil:
+		return event.Member.UserID, true
+	default:
+		return 0, false
+	}
+}
+
+// HandleEvent queues a delivery of the event for every active webhook of the
+// event's owner that subscribes to it
+func (s *WebhookService) HandleEvent(ctx context.Context, event model.Event) error {
+	ownerID, ok := eventOwner(event)
+	if !ok {
+		return nil
+	}
+
+	webhooks, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if webhook.OwnerID != ownerID || !webhook.Wants(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return err
+			}
+		}
+
+		now := s.now()
+		delivery := &model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryPending,
+			Attempts:      []model.DeliveryAttempt{},
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain"
+	"go-boilerplate/internal/domain/model"
+)
+
+func TestCreateWebhookValidatesURL(t *testing.T) {
+	tests := []struct {
+		name          string
+		url           string
+		allowInternal bool
+		wantErr       error
+	}{
+		{name: "public https url", url: "https://example.com/hooks"},
+		{name: "public ip", url: "http://93.184.216.34:8080/hooks"},
+		{name: "relative url", url: "/hooks", wantErr: domain.ErrInvalidWebhookURL},
+		{name: "unsupported scheme", url: "ftp://example.com/hooks", wantErr: domain.ErrInvalidWebhookURL},
+		{name: "localhost", url: "http://localhost:8080/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "localhost subdomain", url: "http://api.localhost./hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "loopback", url: "http://127.0.0.1/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "ipv6 loopback", url: "http://[::1]/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "ipv4-mapped loopback", url: "http://[::ffff:127.0.0.1]/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "private", url: "http://10.1.2.3/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "shared address space", url: "http://100.64.0.1/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "link-local metadata", url: "http://169.254.169.254/latest/meta-data", wantErr: domain.ErrInternalWebhookURL},
+		{name: "unspecified", url: "http://0.0.0.0/hooks", wantErr: domain.ErrInternalWebhookURL},
+		{name: "loopback when internal urls are allowed", url: "http://127.0.0.1:9000/hooks", allowInternal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewWebhookService(persistence.NewWebhookRepository(), WithInternalWebhookURLs(tt.allowInternal))
+			ctx := domain.ContextWithActor(context.Background(), 1)
+
+			_, err := s.CreateWebhook(ctx, &model.CreateWebhookRequest{URL: tt.url})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateWebhook(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+			}
+
+			hook, err := s.CreateWebhook(ctx, &model.CreateWebhookRequest{URL: "https://example.com/hooks"})
+			if err != nil {
+				t.Fatalf("CreateWebhook: %v", err)
+			}
+			_, err = s.UpdateWebhook(ctx, hook.ID, &model.UpdateWebhookRequest{URL: tt.url})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateWebhook(%q) error = %v, want %v", tt.url, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// apiPrefix is the path of the API version the client speaks
+const apiPrefix = "/v1"
+
+// Client calls version 1 of the todo and user HTTP API. Its methods mirror the
+// todo and user services, with request and response types of its own. Requests
+// act as the user whose bearer token is set with WithToken, or as an
+// administrator with the key set with WithAdminKey.
+type Client struct {
+	baseURL      string
+	token        string
+	adminKey     string
+	httpClient   *http.Client
+	timeout      time.Duration
+	maxRetries   int
+	retryWait    time.Duration
+	maxRetryWait time.Duration
+}
+
+// Option configures optional Client behavior
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client requests are sent with
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken authenticates requests with a bearer token issued by the server
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithAdminKey authenticates requests with the server's admin key instead of
+// a token. They act as the administrator named with ContextWithActor, or as
+// the server's first administrator.
+func WithAdminKey(key string) Option {
+	return func(c *Client) {
+		c.adminKey = key
+	}
+}
+
+// WithTimeout bounds calls whose context has no deadline of its own. Zero
+// leaves such calls unbounded.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithRetries sets how often failed calls are retried, and the wait before the
+// first retry; the wait doubles with every further retry up to maxWait. Calls
+// are retried when the request could not be sent. Reads are also retried when
+// no response arrived or the server was unavailable; writes are not, since the
+// server may have carried them out.
+func WithRetries(n int, wait, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+		c.retryWait = wait
+		c.maxRetryWait = maxWait
+	}
+}
+
+// New creates a Client for the server at baseURL, e.g. "http://localhost:8080"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   http.DefaultClient,
+		timeout:      30 * time.Second,
+		maxRetries:   3,
+		retryWait:    100 * time.Millisecond,
+		maxRetryWait: 2 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type (
+	actorKey     struct{}
+	requestIDKey struct{}
+)
+
+// ContextWithActor returns a copy of ctx whose requests name the user they act
+// as in the X-User-ID header. That is not authentication; servers only honor
+// it in development, or to pick the administrator an admin key acts as. Use
+// WithToken otherwise.
+func ContextWithActor(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+// ContextWithRequestID returns a copy of ctx whose requests carry the request
+// ID, correlating them with the server's logs and audit trail
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// isSafe reports whether a request only reads, so repeating it after an
+// ambiguous failure cannot change anything
+func isSafe(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable reports whether a failed attempt may be repeated; sent reports
+// whether the request was sent. A write that may have reached the server is
+// not retried, even when idempotent: repeating a delete or transition that was
+// carried out would report a spurious 404 or 409.
+func isRetryable(ctx context.Context, method string, sent bool, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		// No response was received
+		return !sent || isSafe(method)
+	}
+	if !isSafe(method) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into out, if given. Failed attempts are retried when isRetryable allows.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	target := c.baseURL + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		sent, err := c.send(ctx, method, target, payload, out)
+		if err == nil || attempt >= c.maxRetries || !isRetryable(ctx, method, sent, err) {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		wait = min(2*wait, c.maxRetryWait)
+	}
+}
+
+// send makes a single attempt at a request. It reports whether the request
+// was sent, even partially, so that the server may have acted on it.
+func (c *Client) send(ctx context.Context, method, target string, payload []byte, out any) (bool, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	var sent atomic.Bool
+	trace := &httptrace.ClientTrace{
+		WroteHeaders: func() { sent.Store(true) },
+	}
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), method, target, body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.adminKey != "":
+		req.Header.Set("Authorization", "AdminKey "+c.adminKey)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if userID, ok := ctx.Value(actorKey{}).(int); ok {
+		req.Header.Set("X-User-ID", strconv.Itoa(userID))
+	}
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok && requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return sent.Load(), err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return true, decodeError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return true, nil
+	}
+	return true, json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,401 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go-boilerplate/internal/adapter/inbound/auth"
+	httpadapter "go-boilerplate/internal/adapter/inbound/http"
+	"go-boilerplate/internal/adapter/outbound/persistence"
+	"go-boilerplate/internal/domain/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newRouterServer serves the REST API's todo and user routes as the server
+// does, with users alice (1) and bob (2) and alice's todo "dishes" (1). It
+// returns the server and a signer for its tokens.
+func newRouterServer(t *testing.T) (*httptest.Server, *auth.TokenSigner) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	todoRepo := persistence.NewTodoRepository()
+	tagRepo := persistence.NewTagRepository()
+	userRepo := persistence.NewUserRepository()
+	tx := persistence.NewTxManager(todoRepo, tagRepo, userRepo)
+	handlers := httpadapter.Handlers{
+		Todo: httpadapter.NewTodoHandler(service.NewTodoService(todoRepo, tagRepo, tx)),
+		User: httpadapter.NewUserHandler(service.NewUserService(userRepo, tx)),
+	}
+
+	signer := auth.NewTokenSigner([]byte("secret"), time.Hour)
+	authenticator := auth.NewAuthenticator(auth.WithTokens(signer))
+	r := gin.New()
+	r.Use(httpadapter.RequestIDMiddleware(), httpadapter.ActorMiddleware(authenticator))
+	httpadapter.RegisterRoutes(r.Group("/v1"), handlers, authenticator)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	c := New(server.URL, WithToken(signer.Issue(1)))
+	ctx := context.Background()
+	for _, name := range []string{"alice", "bob"} {
+		if _, err := c.CreateUser(ctx, &CreateUserRequest{Username: name, Email: name + "@example.com", Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.CreateTodo(ctx, &CreateTodoRequest{OwnerID: 1, Title: "dishes"}); err != nil {
+		t.Fatal(err)
+	}
+	return server, signer
+}
+
+// describeTodo summarizes the fields of a todo the tests check
+func describeTodo(todo *Todo) string {
+	return fmt.Sprintf("%d:%s:%s:%s:owner=%d", todo.ID, todo.Title, todo.Status, todo.Priority, todo.OwnerID)
+}
+
+func TestClientAgainstRouter(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		// op calls the client and summarizes the result
+		op func(ctx context.Context, c *Client) (string, error)
+
+		want     string
+		wantErr  error
+		wantCode string
+	}{
+		{
+			name: "create a todo for the caller",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				todo, err := c.CreateTodo(ctx, &CreateTodoRequest{OwnerID: 2, Title: "laundry", Priority: PriorityHigh})
+				if err != nil {
+					return "", err
+				}
+				return describeTodo(todo), nil
+			},
+			want: "2:laundry:open:high:owner=1",
+		},
+		{
+			name: "list todos by priority",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				if _, err := c.CreateTodo(ctx, &CreateTodoRequest{OwnerID: 1, Title: "taxes", Priority: PriorityUrgent}); err != nil {
+					return "", err
+				}
+				todos, err := c.ListTodos(ctx, TodoFilter{Priority: PriorityUrgent})
+				if err != nil || len(todos) != 1 {
+					return fmt.Sprint(len(todos)), err
+				}
+				return describeTodo(todos[0]), nil
+			},
+			want: "2:taxes:open:urgent:owner=1",
+		},
+		{
+			name: "complete a todo",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				todo, err := c.TransitionTodo(ctx, 1, &TransitionTodoRequest{To: TodoStatusDone})
+				if err != nil {
+					return "", err
+				}
+				return describeTodo(todo), nil
+			},
+			want: "1:dishes:done:medium:owner=1",
+		},
+		{
+			name: "trash and list deleted",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				if err := c.DeleteTodo(ctx, 1); err != nil {
+					return "", err
+				}
+				todos, err := c.ListTodos(ctx, TodoFilter{Deleted: true})
+				if err != nil || len(todos) != 1 {
+					return fmt.Sprint(len(todos)), err
+				}
+				return describeTodo(todos[0]), nil
+			},
+			want: "1:dishes:open:medium:owner=1",
+		},
+		{
+			name: "find a user by username",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				user, err := c.GetUserByUsername(ctx, "bob")
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d:%s:%s", user.ID, user.Username, user.Email), nil
+			},
+			want: "2:bob:bob@example.com",
+		},
+		{
+			name: "missing todo",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.GetTodo(ctx, 9)
+				return "", err
+			},
+			wantErr:  ErrNotFound,
+			wantCode: "not_found",
+		},
+		{
+			name: "blank title",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.CreateTodo(ctx, &CreateTodoRequest{OwnerID: 1, Title: " "})
+				return "", err
+			},
+			wantErr:  ErrInvalidTodoTitle,
+			wantCode: "invalid_todo_title",
+		},
+		{
+			name: "illegal transition",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				if _, err := c.TransitionTodo(ctx, 1, &TransitionTodoRequest{To: TodoStatusCancelled}); err != nil {
+					return "", err
+				}
+				_, err := c.TransitionTodo(ctx, 1, &TransitionTodoRequest{To: TodoStatusDone})
+				return "", err
+			},
+			wantErr:  ErrIllegalTransition,
+			wantCode: "illegal_transition",
+		},
+		{
+			name: "duplicate username",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.CreateUser(ctx, &CreateUserRequest{Username: "bob", Email: "b@example.com", Name: "Bob"})
+				return "", err
+			},
+			wantErr:  ErrUsernameDuplicate,
+			wantCode: "username_duplicate",
+		},
+		{
+			name: "rename to the same username",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.RenameUser(ctx, 2, &RenameUserRequest{Username: "bob"})
+				return "", err
+			},
+			wantErr:  ErrUsernameUnchanged,
+			wantCode: "username_unchanged",
+		},
+		{
+			name: "history of an in-memory todo",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.GetTodoHistory(ctx, 1)
+				return "", err
+			},
+			wantErr:  ErrHistoryUnavailable,
+			wantCode: "history_unavailable",
+		},
+		{
+			name: "invalid filter",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.ListTodos(ctx, TodoFilter{Tags: []string{"home"}, TagMatch: "some"})
+				return "", err
+			},
+			wantErr:  ErrInvalidTagMatch,
+			wantCode: "invalid_tag_match",
+		},
+		{
+			name:  "forged token",
+			token: "1.9999999999.forged",
+			op: func(ctx context.Context, c *Client) (string, error) {
+				_, err := c.ListTodos(ctx, TodoFilter{})
+				return "", err
+			},
+			wantErr:  ErrUnauthenticated,
+			wantCode: "unauthenticated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, signer := newRouterServer(t)
+			token := tt.token
+			if token == "" {
+				token = signer.Issue(1)
+			}
+			c := New(server.URL, WithToken(token))
+
+			got, err := tt.op(context.Background(), c)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantCode != "" {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != tt.wantCode {
+					t.Errorf("error = %#v, want code %q", err, tt.wantCode)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// scriptedServer answers each attempt as scripted: "ok", a status code such
+// as "503", or "drop" to close the connection after reading the request. The
+// last entry repeats.
+type scriptedServer struct {
+	t      *testing.T
+	script []string
+
+	mu       sync.Mutex
+	attempts int
+	headers  http.Header
+}
+
+func (s *scriptedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.attempts++
+	s.headers = r.Header.Clone()
+	step := s.script[min(s.attempts, len(s.script))-1]
+	s.mu.Unlock()
+
+	switch step {
+	case "ok":
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":1,"title":"dishes"}`))
+	case "drop":
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			s.t.Error(err)
+			return
+		}
+		conn.Close()
+	default:
+		var status int
+		fmt.Sscan(step, &status)
+		w.WriteHeader(status)
+	}
+}
+
+// attemptCount returns how many requests reached the server
+func (s *scriptedServer) attemptCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts
+}
+
+func TestRetries(t *testing.T) {
+	calls := map[string]func(ctx context.Context, c *Client) error{
+		http.MethodGet: func(ctx context.Context, c *Client) error {
+			_, err := c.GetTodo(ctx, 1)
+			return err
+		},
+		http.MethodPost: func(ctx context.Context, c *Client) error {
+			_, err := c.CreateTodo(ctx, &CreateTodoRequest{OwnerID: 1, Title: "dishes"})
+			return err
+		},
+		http.MethodPut: func(ctx context.Context, c *Client) error {
+			_, err := c.UpdateTodo(ctx, 1, &UpdateTodoRequest{Title: "dishes"})
+			return err
+		},
+		http.MethodDelete: func(ctx context.Context, c *Client) error {
+			return c.DeleteTodo(ctx, 1)
+		},
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		script []string
+		// failedDials is how many connection attempts fail before one succeeds
+		failedDials int
+
+		wantAttempts int
+		wantErr      bool
+	}{
+		{name: "read retried while the server is unavailable", method: http.MethodGet, script: []string{"503", "503", "ok"}, wantAttempts: 3},
+		{name: "read retried after a lost response", method: http.MethodGet, script: []string{"drop", "ok"}, wantAttempts: 2},
+		{name: "read gives up after the last retry", method: http.MethodGet, script: []string{"503"}, wantAttempts: 4, wantErr: true},
+		{name: "read not retried after a client error", method: http.MethodGet, script: []string{"404"}, wantAttempts: 1, wantErr: true},
+		{name: "update not retried after a lost response", method: http.MethodPut, script: []string{"drop", "ok"}, wantAttempts: 1, wantErr: true},
+		{name: "update not retried while the server is unavailable", method: http.MethodPut, script: []string{"503", "ok"}, wantAttempts: 1, wantErr: true},
+		{name: "delete not retried after a lost response", method: http.MethodDelete, script: []string{"drop", "ok"}, wantAttempts: 1, wantErr: true},
+		{name: "create not retried after a lost response", method: http.MethodPost, script: []string{"drop", "ok"}, wantAttempts: 1, wantErr: true},
+		{name: "create retried when it could not be sent", method: http.MethodPost, script: []string{"ok"}, failedDials: 2, wantAttempts: 1},
+		{name: "delete retried when it could not be sent", method: http.MethodDelete, script: []string{"ok"}, failedDials: 1, wantAttempts: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scripted := &scriptedServer{t: t, script: tt.script}
+			server := httptest.NewServer(scripted)
+			defer server.Close()
+
+			var dialer net.Dialer
+			dials := 0
+			transport := &http.Transport{
+				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+					dials++
+					if dials <= tt.failedDials {
+						return nil, errors.New("connection refused")
+					}
+					return dialer.DialContext(ctx, network, addr)
+				},
+			}
+			defer transport.CloseIdleConnections()
+			c := New(server.URL,
+				WithHTTPClient(&http.Client{Transport: transport}),
+				WithRetries(3, time.Millisecond, time.Millisecond),
+			)
+
+			err := calls[tt.method](context.Background(), c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := scripted.attemptCount(); got != tt.wantAttempts {
+				t.Errorf("server received %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		token       string
+		adminKey    string
+		wantHeaders map[string]string
+	}{
+		{
+			name:        "bearer token",
+			ctx:         context.Background(),
+			token:       "t0ken",
+			wantHeaders: map[string]string{"Authorization": "Bearer t0ken", "X-User-ID": "", "X-Request-ID": ""},
+		},
+		{
+			name:        "acting user and request ID",
+			ctx:         ContextWithRequestID(ContextWithActor(context.Background(), 7), "req-1"),
+			wantHeaders: map[string]string{"Authorization": "", "X-User-ID": "7", "X-Request-ID": "req-1"},
+		},
+		{
+			name:        "admin key names the administrator",
+			ctx:         ContextWithActor(context.Background(), 3),
+			adminKey:    "k3y",
+			wantHeaders: map[string]string{"Authorization": "AdminKey k3y", "X-User-ID": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scripted := &scriptedServer{t: t, script: []string{"ok"}}
+			server := httptest.NewServer(scripted)
+			defer server.Close()
+
+			if _, err := New(server.URL, WithToken(tt.token), WithAdminKey(tt.adminKey)).GetTodo(tt.ctx, 1); err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range tt.wantHeaders {
+				if got := scripted.headers.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors reported by the server. Use errors.Is to check for them.
+var (
+	ErrNotFound                  = errors.New("resource not found")
+	ErrDuplicate                 = errors.New("resource already exists")
+	ErrUnauthenticated           = errors.New("authentication required")
+	ErrForbidden                 = errors.New("permission denied")
+	ErrInvalidTodoTitle          = errors.New("todo title cannot be empty")
+	ErrTodoAlreadyCompleted      = errors.New("todo is already completed")
+	ErrInvalidPriority           = errors.New("invalid todo priority")
+	ErrInvalidTimezone           = errors.New("invalid timezone")
+	ErrDueDateInPast             = errors.New("due date cannot be before the todo was created")
+	ErrInvalidTodoStatus         = errors.New("invalid todo status")
+	ErrIllegalTransition         = errors.New("illegal todo status transition")
+	ErrInvalidParentTodo         = errors.New("invalid parent todo")
+	ErrTodoCycle                 = errors.New("todo cannot be nested under its own subtask")
+	ErrMaxDepthExceeded          = errors.New("maximum subtask depth exceeded")
+	ErrOpenSubtasks              = errors.New("todo has open subtasks")
+	ErrClosedParent              = errors.New("open subtask cannot be placed under a closed todo")
+	ErrInvalidChecklistItem      = errors.New("checklist item text cannot be empty")
+	ErrInvalidRecurrence         = errors.New("invalid recurrence rule")
+	ErrRecurrenceRequiresDueDate = errors.New("recurring todos need a due date")
+	ErrNotRecurring              = errors.New("todo is not recurring")
+	ErrHistoryUnavailable        = errors.New("todo history is not recorded by this store")
+	ErrTagOwnerMismatch          = errors.New("tag belongs to a different owner")
+	ErrInvalidTagMatch           = errors.New("tag match must be all or any")
+	ErrInvalidUsername           = errors.New("username cannot be empty")
+	ErrInvalidEmail              = errors.New("invalid email format")
+	ErrUsernameDuplicate         = errors.New("username already exists")
+	ErrUsernameUnchanged         = errors.New("new username is the same as the current one")
+	ErrUsernameReserved          = errors.New("username is reserved by a recent rename")
+	ErrUsernameInTrash           = errors.New("username belongs to a deleted user")
+	ErrInvalidRequest            = errors.New("invalid request")
+)
+
+// codeErrors maps the code field of error responses to the errors they report.
+// Codes are part of the API, unlike the messages next to them.
+var codeErrors = map[string]error{
+	"not_found":                    ErrNotFound,
+	"duplicate":                    ErrDuplicate,
+	"unauthenticated":              ErrUnauthenticated,
+	"forbidden":                    ErrForbidden,
+	"invalid_todo_title":           ErrInvalidTodoTitle,
+	"todo_already_completed":       ErrTodoAlreadyCompleted,
+	"invalid_priority":             ErrInvalidPriority,
+	"invalid_timezone":             ErrInvalidTimezone,
+	"due_date_in_past":             ErrDueDateInPast,
+	"invalid_todo_status":          ErrInvalidTodoStatus,
+	"illegal_transition":           ErrIllegalTransition,
+	"invalid_parent_todo":          ErrInvalidParentTodo,
+	"todo_cycle":                   ErrTodoCycle,
+	"max_depth_exceeded":           ErrMaxDepthExceeded,
+	"open_subtasks":                ErrOpenSubtasks,
+	"closed_parent":                ErrClosedParent,
+	"invalid_checklist_item":       ErrInvalidChecklistItem,
+	"invalid_recurrence":           ErrInvalidRecurrence,
+	"recurrence_requires_due_date": ErrRecurrenceRequiresDueDate,
+	"not_recurring":                ErrNotRecurring,
+	"history_unavailable":          ErrHistoryUnavailable,
+	"tag_owner_mismatch":           ErrTagOwnerMismatch,
+	"invalid_tag_match":            ErrInvalidTagMatch,
+	"invalid_username":             ErrInvalidUsername,
+	"invalid_email":                ErrInvalidEmail,
+	"username_duplicate":           ErrUsernameDuplicate,
+	"username_unchanged":           ErrUsernameUnchanged,
+	"username_reserved":            ErrUsernameReserved,
+	"username_in_trash":            ErrUsernameInTrash,
+	"invalid_request":              ErrInvalidRequest,
+}
+
+// APIError is an error response from the server. It matches the error the
+// server reported with errors.Is, when the client knows its code.
+type APIError struct {
+	StatusCode int
+	// Code identifies the error; it is empty when the response had none
+	Code    string
+	Message string
+	err     error
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// Unwrap returns the error the server reported, or nil if it is not known
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// decodeError reads an error response into an APIError
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+		body.Error = http.StatusText(resp.StatusCode)
+	}
+	return &APIError{
+		StatusCode: resp.StatusCode,
+		Code:       body.Code,
+		Message:    body.Error,
+		err:        codeErrors[body.Code],
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// todoPath returns the path of a todo, or of a resource below it
+func todoPath(id int, sub ...string) string {
+	return "/todos/" + strings.Join(append([]string{strconv.Itoa(id)}, sub...), "/")
+}
+
+// getTodo sends a request answered with a todo
+func (c *Client) getTodo(ctx context.Context, method, path string, query url.Values, body any) (*Todo, error) {
+	var todo Todo
+	if err := c.do(ctx, method, path, query, body, &todo); err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// getTodos sends a request answered with a list of todos
+func (c *Client) getTodos(ctx context.Context, path string, query url.Values) ([]*Todo, error) {
+	var todos []*Todo
+	if err := c.do(ctx, http.MethodGet, path, query, nil, &todos); err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// CreateTodo creates a new todo
+func (c *Client) CreateTodo(ctx context.Context, req *CreateTodoRequest) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPost, "/todos", nil, req)
+}
+
+// GetTodo retrieves a todo by ID
+func (c *Client) GetTodo(ctx context.Context, id int) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodGet, todoPath(id), nil, nil)
+}
+
+// GetTodoAsOf returns a todo as it was at the given time
+func (c *Client) GetTodoAsOf(ctx context.Context, id int, at time.Time) (*Todo, error) {
+	query := url.Values{"as_of": {at.Format(time.RFC3339)}}
+	return c.getTodo(ctx, http.MethodGet, todoPath(id), query, nil)
+}
+
+// GetTodoHistory retrieves every recorded change to a todo, oldest first
+func (c *Client) GetTodoHistory(ctx context.Context, id int) ([]*TodoEvent, error) {
+	var events []*TodoEvent
+	if err := c.do(ctx, http.MethodGet, todoPath(id, "history"), nil, nil, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// ListTodos retrieves the todos matching the filter
+func (c *Client) ListTodos(ctx context.Context, filter TodoFilter) ([]*Todo, error) {
+	query := url.Values{}
+	if filter.OwnerID != nil {
+		query.Set("owner_id", strconv.Itoa(*filter.OwnerID))
+	}
+	if filter.ParentID != nil {
+		query.Set("parent_id", strconv.Itoa(*filter.ParentID))
+	}
+	if filter.Completed != nil {
+		query.Set("completed", strconv.FormatBool(*filter.Completed))
+	}
+	if filter.Overdue != nil {
+		query.Set("overdue", strconv.FormatBool(*filter.Overdue))
+	}
+	if filter.DueBefore != nil {
+		query.Set("due_before", filter.DueBefore.Format(time.RFC3339))
+	}
+	if filter.DueAfter != nil {
+		query.Set("due_after", filter.DueAfter.Format(time.RFC3339))
+	}
+	if filter.Status != "" {
+		query.Set("status", string(filter.Status))
+	}
+	if filter.Priority != "" {
+		query.Set("priority", string(filter.Priority))
+	}
+	if len(filter.Tags) > 0 {
+		query.Set("tags", strings.Join(filter.Tags, ","))
+	}
+	if filter.TagMatch != "" {
+		query.Set("match", string(filter.TagMatch))
+	}
+
+	if filter.Deleted {
+		return c.ListDeletedTodos(ctx, filter)
+	}
+	return c.getTodos(ctx, "/todos", query)
+}
+
+// UpdateTodo updates an existing todo
+func (c *Client) UpdateTodo(ctx context.Context, id int, req *UpdateTodoRequest) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPut, todoPath(id), nil, req)
+}
+
+// DeleteTodo moves a todo to the trash
+func (c *Client) DeleteTodo(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, todoPath(id), nil, nil, nil)
+}
+
+// ListDeletedTodos retrieves the todos in the trash. Only the filter's
+// OwnerID is supported over HTTP.
+func (c *Client) ListDeletedTodos(ctx context.Context, filter TodoFilter) ([]*Todo, error) {
+	query := url.Values{}
+	if filter.OwnerID != nil {
+		query.Set("owner_id", strconv.Itoa(*filter.OwnerID))
+	}
+	return c.getTodos(ctx, "/todos/trash", query)
+}
+
+// RestoreTodo moves a todo out of the trash
+func (c *Client) RestoreTodo(ctx context.Context, id int) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPost, todoPath(id, "restore"), nil, nil)
+}
+
+// TransitionTodo moves a todo to another status
+func (c *Client) TransitionTodo(ctx context.Context, id int, req *TransitionTodoRequest) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPost, todoPath(id, "transitions"), nil, req)
+}
+
+// ListTodoTransitions retrieves a todo's status changes, oldest first
+func (c *Client) ListTodoTransitions(ctx context.Context, id int) ([]*TodoTransition, error) {
+	var transitions []*TodoTransition
+	if err := c.do(ctx, http.MethodGet, todoPath(id, "transitions"), nil, nil, &transitions); err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// AttachTag attaches a tag to a todo
+func (c *Client) AttachTag(ctx context.Context, id int, tagID int) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPut, todoPath(id, "tags", strconv.Itoa(tagID)), nil, nil)
+}
+
+// DetachTag detaches a tag from a todo
+func (c *Client) DetachTag(ctx context.Context, id int, tagID int) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodDelete, todoPath(id, "tags", strconv.Itoa(tagID)), nil, nil)
+}
+
+// ListSubtasks retrieves the direct subtasks of a todo
+func (c *Client) ListSubtasks(ctx context.Context, id int) ([]*Todo, error) {
+	return c.getTodos(ctx, todoPath(id, "subtasks"), nil)
+}
+
+// AddChecklistItem adds an item to a todo's checklist
+func (c *Client) AddChecklistItem(ctx context.Context, id int, req *CreateChecklistItemRequest) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPost, todoPath(id, "checklist"), nil, req)
+}
+
+// UpdateChecklistItem updates an item of a todo's checklist
+func (c *Client) UpdateChecklistItem(ctx context.Context, id int, itemID int, req *UpdateChecklistItemRequest) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPut, todoPath(id, "checklist", strconv.Itoa(itemID)), nil, req)
+}
+
+// DeleteChecklistItem removes an item from a todo's checklist
+func (c *Client) DeleteChecklistItem(ctx context.Context, id int, itemID int) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodDelete, todoPath(id, "checklist", strconv.Itoa(itemID)), nil, nil)
+}
+
+// SetRecurrence makes a todo repeat
+func (c *Client) SetRecurrence(ctx context.Context, id int, req *RecurrenceRequest) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodPut, todoPath(id, "recurrence"), nil, req)
+}
+
+// ClearRecurrence stops a todo from repeating
+func (c *Client) ClearRecurrence(ctx context.Context, id int) (*Todo, error) {
+	return c.getTodo(ctx, http.MethodDelete, todoPath(id, "recurrence"), nil, nil)
+}
+
+// PreviewOccurrences returns the next due dates of a recurring todo
+func (c *Client) PreviewOccurrences(ctx context.Context, id int, count int) ([]time.Time, error) {
+	query := url.Values{"count": {strconv.Itoa(count)}}
+	var occurrences []time.Time
+	if err := c.do(ctx, http.MethodGet, todoPath(id, "occurrences"), query, nil, &occurrences); err != nil {
+		return nil, err
+	}
+	return occurrences, nil
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// TodoStatus is where a todo is in its lifecycle
+type TodoStatus string
+
+// Todo statuses
+const (
+	TodoStatusOpen       TodoStatus = "open"
+	TodoStatusInProgress TodoStatus = "in_progress"
+	TodoStatusBlocked    TodoStatus = "blocked"
+	TodoStatusDone       TodoStatus = "done"
+	TodoStatusCancelled  TodoStatus = "cancelled"
+)
+
+// Priority is how urgent a todo is
+type Priority string
+
+// Priority levels
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+// TagMatch is how a todo filter combines several tags
+type TagMatch string
+
+// Tag match modes
+const (
+	TagMatchAll TagMatch = "all"
+	TagMatchAny TagMatch = "any"
+)
+
+// Todo is a todo as the API returns it
+type Todo struct {
+	ID          int             `json:"id"`
+	OwnerID     int             `json:"owner_id"`
+	ParentID    *int            `json:"parent_id,omitempty"`
+	ListID      *int            `json:"list_id,omitempty"`
+	Position    string          `json:"position,omitempty"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Completed   bool            `json:"completed"`
+	Status      TodoStatus      `json:"status"`
+	Priority    Priority        `json:"priority"`
+	DueDate     *time.Time      `json:"due_date,omitempty"`
+	Timezone    string          `json:"timezone,omitempty"`
+	TagIDs      []int           `json:"tag_ids"`
+	Checklist   []ChecklistItem `json:"checklist"`
+	Recurrence  *Recurrence     `json:"recurrence,omitempty"`
+	Progress    float64         `json:"progress"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	DeletedAt   *time.Time      `json:"deleted_at,omitempty"`
+}
+
+// ChecklistItem is an item of a todo's checklist
+type ChecklistItem struct {
+	ID      int    `json:"id"`
+	Text    string `json:"text"`
+	Checked bool   `json:"checked"`
+}
+
+// Recurrence is the rule a recurring todo repeats by
+type Recurrence struct {
+	RRule          string      `json:"rrule"`
+	Start          time.Time   `json:"start"`
+	ExceptionDates []time.Time `json:"exception_dates"`
+	NextTodoID     *int        `json:"next_todo_id,omitempty"`
+}
+
+// TodoTransition is a recorded status change of a todo
+type TodoTransition struct {
+	TodoID int        `json:"todo_id"`
+	From   TodoStatus `json:"from"`
+	To     TodoStatus `json:"to"`
+	Reason string     `json:"reason,omitempty"`
+	At     time.Time  `json:"at"`
+}
+
+// TodoEvent is a recorded change to a todo
+type TodoEvent struct {
+	TodoID  int    `json:"todo_id"`
+	Version int    `json:"version"`
+	Type    string `json:"type"`
+	// Changes holds the fields the event set, keyed by their JSON name;
+	// a null value clears the field
+	Changes    map[string]json.RawMessage `json:"changes"`
+	ActorID    *int                       `json:"actor_id,omitempty"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+}
+
+// TodoFilter narrows down the todos ListTodos returns. Unset fields do not
+// filter.
+type TodoFilter struct {
+	OwnerID   *int
+	ParentID  *int
+	Completed *bool
+	Status    TodoStatus
+	// Overdue selects open todos past their due date
+	Overdue   *bool
+	DueBefore *time.Time
+	DueAfter  *time.Time
+	Priority  Priority
+	// Tags are tag names; TagMatch says whether a todo needs all or any of them
+	Tags     []string
+	TagMatch TagMatch
+	// Deleted selects todos in the trash instead of live todos
+	Deleted bool
+}
+
+// CreateTodoRequest is the body of a request to create a todo
+type CreateTodoRequest struct {
+	OwnerID          int                `json:"owner_id"`
+	ParentID         *int               `json:"parent_id,omitempty"`
+	Title            string             `json:"title"`
+	Description      string             `json:"description,omitempty"`
+	Priority         Priority           `json:"priority,omitempty"`
+	DueDate          *time.Time         `json:"due_date,omitempty"`
+	Timezone         string             `json:"timezone,omitempty"`
+	AllowPastDueDate bool               `json:"allow_past_due_date,omitempty"`
+	Recurrence       *RecurrenceRequest `json:"recurrence,omitempty"`
+}
+
+// UpdateTodoRequest is the body of a request to update a todo. Empty fields
+// are left unchanged.
+type UpdateTodoRequest struct {
+	Title            string     `json:"title,omitempty"`
+	Description      string     `json:"description,omitempty"`
+	Completed        *bool      `json:"completed,omitempty"`
+	Priority         Priority   `json:"priority,omitempty"`
+	DueDate          *time.Time `json:"due_date,omitempty"`
+	Timezone         string     `json:"timezone,omitempty"`
+	ClearDueDate     bool       `json:"clear_due_date,omitempty"`
+	ParentID         *int       `json:"parent_id,omitempty"`
+	ClearParent      bool       `json:"clear_parent,omitempty"`
+	AllowPastDueDate bool       `json:"allow_past_due_date,omitempty"`
+}
+
+// TransitionTodoRequest is the body of a request to move a todo to another status
+type TransitionTodoRequest struct {
+	To     TodoStatus `json:"to"`
+	Reason string     `json:"reason,omitempty"`
+}
+
+// CreateChecklistItemRequest is the body of a request to add a checklist item
+type CreateChecklistItemRequest struct {
+	Text string `json:"text"`
+}
+
+// UpdateChecklistItemRequest is the body of a request to edit or check a checklist item
+type UpdateChecklistItemRequest struct {
+	Text    string `json:"text,omitempty"`
+	Checked *bool  `json:"checked,omitempty"`
+}
+
+// RecurrenceRequest is the body of a request to make a todo repeat
+type RecurrenceRequest struct {
+	RRule          string      `json:"rrule"`
+	ExceptionDates []time.Time `json:"exception_dates,omitempty"`
+}
+
+// User is a user as the API returns them
+type User struct {
+	ID        int        `json:"id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CreateUserRequest is the body of a request to create a user
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+}
+
+// UpdateUserRequest is the body of a request to update a user. Empty fields
+// are left unchanged.
+type UpdateUserRequest struct {
+	Email string `json:"email,omitempty"`
+	Name  string `json:"name,omitempty"`
+}
+
+// RenameUserRequest is the body of a request to change a user's username
+type RenameUserRequest struct {
+	Username string `json:"username"`
+}
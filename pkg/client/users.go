@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// userPath returns the path of a user, or of a resource below them
+func userPath(id int, sub string) string {
+	path := "/users/" + strconv.Itoa(id)
+	if sub != "" {
+		path += "/" + sub
+	}
+	return path
+}
+
+// getUser sends a request answered with a user
+func (c *Client) getUser(ctx context.Context, method, path string, body any) (*User, error) {
+	var user User
+	if err := c.do(ctx, method, path, nil, body, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// getUsers sends a request answered with a list of users
+func (c *Client) getUsers(ctx context.Context, path string) ([]*User, error) {
+	var users []*User
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CreateUser creates a new user
+func (c *Client) CreateUser(ctx context.Context, req *CreateUserRequest) (*User, error) {
+	return c.getUser(ctx, http.MethodPost, "/users", req)
+}
+
+// GetUser retrieves a user by ID
+func (c *Client) GetUser(ctx context.Context, id int) (*User, error) {
+	return c.getUser(ctx, http.MethodGet, userPath(id, ""), nil)
+}
+
+// GetUserByUsername retrieves a user by username. A username released by a
+// recent rename is followed to the user now holding it under their new name.
+func (c *Client) GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	return c.getUser(ctx, http.MethodGet, "/users/by-username/"+url.PathEscape(username), nil)
+}
+
+// ListUsers retrieves all users
+func (c *Client) ListUsers(ctx context.Context) ([]*User, error) {
+	return c.getUsers(ctx, "/users")
+}
+
+// UpdateUser updates an existing user
+func (c *Client) UpdateUser(ctx context.Context, id int, req *UpdateUserRequest) (*User, error) {
+	return c.getUser(ctx, http.MethodPut, userPath(id, ""), req)
+}
+
+// RenameUser changes a user's username
+func (c *Client) RenameUser(ctx context.Context, id int, req *RenameUserRequest) (*User, error) {
+	return c.getUser(ctx, http.MethodPut, userPath(id, "username"), req)
+}
+
+// DeleteUser moves a user to the trash
+func (c *Client) DeleteUser(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, userPath(id, ""), nil, nil, nil)
+}
+
+// ListDeletedUsers retrieves the users in the trash
+func (c *Client) ListDeletedUsers(ctx context.Context) ([]*User, error) {
+	return c.getUsers(ctx, "/users/trash")
+}
+
+// RestoreUser moves a user out of the trash
+func (c *Client) RestoreUser(ctx context.Context, id int) (*User, error) {
+	return c.getUser(ctx, http.MethodPost, userPath(id, "restore"), nil)
+}
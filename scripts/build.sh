@@ -16,5 +16,6 @@ echo "Generating Swagger documentation..."
 
 # Go 빌드
 go build -o bin/go-boilerplate ./cmd/main.go
+go build -o bin/todoctl ./cmd/cli
 
 echo "Build completed!" 
\ No newline at end of file